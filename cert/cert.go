@@ -0,0 +1,133 @@
+// Package cert creates and loads the self-signed tls certificate that secures
+// the peerswapd grpc and rest interfaces.
+package cert
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	organization = "peerswap autogenerated cert"
+
+	// DefaultValidity is the time a generated certificate is valid for.
+	DefaultValidity = 14 * 30 * 24 * time.Hour
+)
+
+// LoadOrCreate loads the key pair from `certPath` and `keyPath`. If one of the
+// files is missing or the certificate expired a new self-signed key pair
+// that is valid for localhost, the hostname and the extra ips and domains is
+// created first.
+func LoadOrCreate(certPath, keyPath string, extraIPs, extraDomains []string) (tls.Certificate, error) {
+	if !fileExists(certPath) || !fileExists(keyPath) {
+		err := GenCertPair(certPath, keyPath, extraIPs, extraDomains, DefaultValidity)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	keyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	parsed, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if time.Now().After(parsed.NotAfter) {
+		err := GenCertPair(certPath, keyPath, extraIPs, extraDomains, DefaultValidity)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		return tls.LoadX509KeyPair(certPath, keyPath)
+	}
+
+	return keyPair, nil
+}
+
+// GenCertPair creates a self-signed certificate and its key and writes them
+// pem encoded to `certPath` and `keyPath`.
+func GenCertPair(certPath, keyPath string, extraIPs, extraDomains []string, validity time.Duration) error {
+	ips := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+	for _, s := range extraIPs {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return fmt.Errorf("invalid tls extra ip: %s", s)
+		}
+		ips = append(ips, ip)
+	}
+
+	domains := []string{"localhost"}
+	host, err := os.Hostname()
+	if err == nil && host != "localhost" {
+		domains = append(domains, host)
+	}
+	domains = append(domains, extraDomains...)
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{organization},
+			CommonName:   domains[len(domains)-1],
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		DNSNames:              domains,
+		IPAddresses:           ips,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return err
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return err
+	}
+
+	var certBuf, keyBuf bytes.Buffer
+	if err := pem.Encode(&certBuf, &pem.Block{Type: "CERTIFICATE", Bytes: derBytes}); err != nil {
+		return err
+	}
+	if err := pem.Encode(&keyBuf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(certPath, certBuf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(keyPath, keyBuf.Bytes(), 0600)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
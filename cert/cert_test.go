@@ -0,0 +1,55 @@
+package cert
+
+import (
+	"crypto/x509"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadOrCreate(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.cert")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	keyPair, err := LoadOrCreate(certPath, keyPath, []string{"10.0.0.1"}, []string{"peerswap.example.com"})
+	require.NoError(t, err)
+
+	parsed, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+	assert.NoError(t, parsed.VerifyHostname("localhost"))
+	assert.NoError(t, parsed.VerifyHostname("peerswap.example.com"))
+	assert.NoError(t, parsed.VerifyHostname("10.0.0.1"))
+	assert.Contains(t, parsed.IPAddresses, net.ParseIP("127.0.0.1").To4())
+
+	// A second call loads the existing pair.
+	keyPair2, err := LoadOrCreate(certPath, keyPath, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, keyPair.Certificate, keyPair2.Certificate)
+}
+
+func Test_LoadOrCreate_Expired(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.cert")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	// Validity is shorter than the backdated NotBefore, the cert is expired.
+	require.NoError(t, GenCertPair(certPath, keyPath, nil, nil, -time.Minute))
+
+	keyPair, err := LoadOrCreate(certPath, keyPath, nil, nil)
+	require.NoError(t, err)
+
+	parsed, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+	assert.True(t, parsed.NotAfter.After(time.Now()))
+}
+
+func Test_GenCertPair_InvalidIP(t *testing.T) {
+	dir := t.TempDir()
+	err := GenCertPair(filepath.Join(dir, "tls.cert"), filepath.Join(dir, "tls.key"), []string{"not-an-ip"}, nil, DefaultValidity)
+	assert.Error(t, err)
+}
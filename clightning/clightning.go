@@ -9,6 +9,7 @@ import (
 	"fmt"
 	log2 "log"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/elementsproject/peerswap/log"
@@ -41,18 +42,23 @@ var methods = []peerswaprpcMethod{
 	&LiquidSendToAddress{},
 	&GetSwap{},
 	&ListActiveSwaps{},
+	&Drain{},
 	&AllowSwapRequests{},
 	&AddPeer{},
 	&RemovePeer{},
 	&AddSuspiciousPeer{},
 	&RemoveSuspiciousPeer{},
 	&SwapIn{},
+	&SwapInBatch{},
+	&SubmitOpeningTx{},
 	&SwapOut{},
 	&ListSwaps{},
 	&LiquidGetAddress{},
 	&LiquidGetBalance{},
+	&GetBalances{},
 	&ReloadPolicyFile{},
 	&GetRequestedSwaps{},
+	&GetPeerHistory{},
 	&ListConfig{},
 }
 
@@ -95,12 +101,23 @@ type ClightningClient struct {
 	isReady bool
 
 	peerswapConfig PeerswapClightningConfig
+
+	// shutdown stops the plugin. drainShutdown is set if the plugin stops
+	// once the last active swap completed.
+	shutdown      func()
+	drainMu       sync.Mutex
+	drainShutdown bool
 }
 
 func (cl *ClightningClient) SetReady() {
 	cl.isReady = true
 }
 
+// SetShutdown sets the function that stops the plugin.
+func (cl *ClightningClient) SetShutdown(shutdown func()) {
+	cl.shutdown = shutdown
+}
+
 func (cl *ClightningClient) AddPaymentCallback(f func(swapId string, invoiceType swap.InvoiceType)) {
 	cl.paymenthandlers = append(cl.paymenthandlers, f)
 }
@@ -141,6 +158,7 @@ func NewClightningClient(ctx context.Context) (*ClightningClient, <-chan interfa
 		return nil, nil, err
 	}
 	cl.Plugin.SubscribeConnect(cl.OnConnect)
+	cl.Plugin.SubscribeDisconnect(cl.OnDisconnect)
 
 	cl.glightning = glightning.NewLightning()
 	cl.glightning.SetTimeout(40)
@@ -259,6 +277,10 @@ func (cl *ClightningClient) SetPeerswapConfig(config *Config) {
 		BitcoinRpcHost:         config.Bitcoin.RpcHost,
 		BitcoinRpcPort:         config.Bitcoin.RpcPort,
 		BitcoinCookieFilePath:  config.Bitcoin.RpcPasswordFile,
+		BitcoinZmqPubRawBlock:  config.Bitcoin.ZmqPubRawBlock,
+		BitcoinZmqPubHashBlock: config.Bitcoin.ZmqPubHashBlock,
+		BitcoinZmqPubRawTx:     config.Bitcoin.ZmqPubRawTx,
+		BitcoinEsploraUrl:      config.Bitcoin.EsploraUrl,
 		LiquidRpcUser:          config.Liquid.RpcUser,
 		LiquidRpcPassword:      config.Liquid.RpcPassword,
 		LiquidRpcPasswordFile:  config.Liquid.RpcPasswordFile,
@@ -266,7 +288,14 @@ func (cl *ClightningClient) SetPeerswapConfig(config *Config) {
 		LiquidRpcPort:          config.Liquid.RpcPort,
 		LiquidRpcWallet:        config.Liquid.RpcWallet,
 		LiquidDisabled:         config.Liquid.Disabled,
+		LiquidZmqPubRawBlock:   config.Liquid.ZmqPubRawBlock,
+		LiquidZmqPubHashBlock:  config.Liquid.ZmqPubHashBlock,
+		LiquidZmqPubRawTx:      config.Liquid.ZmqPubRawTx,
+		LiquidEsploraUrl:       config.Liquid.EsploraUrl,
 		PeerswapDir:            config.PeerswapDir,
+		MetricsHost:            config.MetricsHost,
+		LogLevel:               config.LogLevel.String(),
+		LogJson:                config.LogJson,
 	}
 }
 
@@ -433,19 +462,47 @@ func (cl *ClightningClient) onInit(plugin *glightning.Plugin, options map[string
 
 // OnConnect is called after the connect event. The
 // handler sends out a poll to the peer it connected
-// to.
+// to and resumes the swaps with the peer.
 func (cl *ClightningClient) OnConnect(connectEvent *glightning.ConnectEvent) {
 	go func() {
 		for {
 			time.Sleep(10 * time.Second)
 			if cl.pollService != nil {
 				cl.pollService.RequestPoll(connectEvent.PeerId)
+				if cl.swaps != nil {
+					cl.swaps.OnPeerOnline(connectEvent.PeerId)
+				}
 				return
 			}
 		}
 	}()
 }
 
+// OnDisconnect is called after the disconnect event. The
+// handler pauses the swap timeouts while the peer is
+// offline.
+func (cl *ClightningClient) OnDisconnect(disconnectEvent *glightning.DisconnectEvent) {
+	if cl.swaps != nil {
+		go cl.swaps.OnPeerOffline(disconnectEvent.PeerId)
+	}
+}
+
+// connectRequest connects to a peer by its id only, core-lightning looks up
+// the addresses of the peer in the gossip.
+type connectRequest struct {
+	PeerId string `json:"id"`
+}
+
+func (r connectRequest) Name() string {
+	return "connect"
+}
+
+// ConnectPeer connects to the peer on one of its known addresses.
+func (cl *ClightningClient) ConnectPeer(peerId string) error {
+	var res glightning.ConnectResult
+	return cl.glightning.Request(&connectRequest{PeerId: peerId}, &res)
+}
+
 // RegisterMethods registeres rpc methods to c-lightning
 func (cl *ClightningClient) RegisterMethods() error {
 	for _, v := range methods {
@@ -497,18 +554,53 @@ func (cl *ClightningClient) GetPeers() []string {
 	return peerlist
 }
 
+// Glightninglogger writes log entries to the core-lightning log.
 type Glightninglogger struct {
-	plugin *glightning.Plugin
+	plugin   *glightning.Plugin
+	maxLevel log.Level
+	json     bool
 }
 
-func NewGlightninglogger(plugin *glightning.Plugin) *Glightninglogger {
-	return &Glightninglogger{plugin: plugin}
+// NewGlightninglogger returns a logger that passes all entries up to
+// `maxLevel` to core-lightning. If `jsonOutput` is set the entries are written
+// as json objects.
+func NewGlightninglogger(plugin *glightning.Plugin, maxLevel log.Level, jsonOutput bool) *Glightninglogger {
+	return &Glightninglogger{plugin: plugin, maxLevel: maxLevel, json: jsonOutput}
 }
 
-func (g *Glightninglogger) Infof(format string, v ...interface{}) {
-	g.plugin.Log(fmt.Sprintf(format, v...), glightning.Info)
+func (g *Glightninglogger) Log(level log.Level, msg string, fields log.Fields) {
+	if level > g.maxLevel {
+		return
+	}
+
+	if g.json {
+		entry := make(map[string]interface{}, len(fields)+2)
+		for k, v := range fields {
+			entry[k] = v
+		}
+		entry["level"] = level.String()
+		entry["msg"] = msg
+		b, err := json.Marshal(entry)
+		if err == nil {
+			msg = string(b)
+		}
+	} else if len(fields) > 0 {
+		msg = msg + " " + fields.String()
+	}
+
+	g.plugin.Log(msg, clnLogLevel(level))
 }
 
-func (g *Glightninglogger) Debugf(format string, v ...interface{}) {
-	g.plugin.Log(fmt.Sprintf(format, v...), glightning.Debug)
+// clnLogLevel maps the peerswap log level to the closest core-lightning log
+// level. Core-lightning has no dedicated trace level, so trace entries are
+// logged as debug.
+func clnLogLevel(level log.Level) glightning.LogLevel {
+	switch level {
+	case log.LevelError, log.LevelWarn:
+		return glightning.Unusual
+	case log.LevelInfo:
+		return glightning.Info
+	default:
+		return glightning.Debug
+	}
 }
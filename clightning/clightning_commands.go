@@ -17,6 +17,7 @@ import (
 	"github.com/elementsproject/glightning/glightning"
 	"github.com/elementsproject/glightning/jrpc2"
 	"github.com/elementsproject/peerswap/policy"
+	"github.com/elementsproject/peerswap/poll"
 	"github.com/elementsproject/peerswap/swap"
 )
 
@@ -182,13 +183,79 @@ func (s *LiquidSendToAddress) LongDescription() string {
 	return "'"
 }
 
+// GetBalances returns the btc and lbtc balances that are available for swaps
+type GetBalances struct {
+	cl *ClightningClient
+}
+
+func (g *GetBalances) Name() string {
+	return "peerswap-getbalances"
+}
+
+func (g *GetBalances) New() interface{} {
+	return &GetBalances{
+		cl: g.cl,
+	}
+}
+
+func (g *GetBalances) Call() (jrpc2.Result, error) {
+	if !g.cl.isReady {
+		return nil, ErrWaitingForReady
+	}
+
+	var balances []*peerswaprpc.WalletBalance
+
+	btcBalance, err := g.cl.GetOnchainBalance()
+	if err != nil {
+		return nil, err
+	}
+	balances = append(balances, g.walletBalance("btc", btcBalance))
+
+	if g.cl.liquidWallet != nil {
+		liquidBalance, err := g.cl.liquidWallet.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, g.walletBalance("lbtc", liquidBalance))
+	}
+
+	return &peerswaprpc.GetBalancesResponse{Balances: balances}, nil
+}
+
+func (g *GetBalances) walletBalance(asset string, balance uint64) *peerswaprpc.WalletBalance {
+	reserve, committed, available := g.cl.swaps.OnchainBalanceSplit(asset, balance)
+	return &peerswaprpc.WalletBalance{
+		Asset:        asset,
+		OnchainSat:   balance,
+		ReserveSat:   reserve,
+		CommittedSat: committed,
+		AvailableSat: available,
+	}
+}
+
+func (g *GetBalances) Description() string {
+	return "Returns the btc and lbtc balances that are available for swaps"
+}
+
+func (g *GetBalances) LongDescription() string {
+	return "The available amount is the on-chain balance minus the policy reserve and the funds committed to the opening transactions of active swaps."
+}
+
+func (g *GetBalances) Get(client *ClightningClient) jrpc2.ServerMethod {
+	return &GetBalances{
+		cl: client,
+	}
+}
+
 // SwapOut starts a new swapout (paying an Invoice for onchain liquidity)
 type SwapOut struct {
-	ShortChannelId string            `json:"short_channel_id"`
-	SatAmt         uint64            `json:"amt_sat"`
-	Asset          string            `json:"asset"`
-	Force          bool              `json:"force"`
-	cl             *ClightningClient `json:"-"`
+	ShortChannelId    string            `json:"short_channel_id"`
+	SatAmt            uint64            `json:"amt_sat"`
+	Asset             string            `json:"asset"`
+	Force             bool              `json:"force"`
+	ClaimAddress      string            `json:"claim_address,omitempty"`
+	ClaimToDescriptor bool              `json:"claim_to_descriptor,omitempty"`
+	cl                *ClightningClient `json:"-"`
 }
 
 func (l *SwapOut) New() interface{} {
@@ -240,6 +307,13 @@ func (l *SwapOut) Call() (jrpc2.Result, error) {
 		return nil, fmt.Errorf("peer does not run peerswap")
 	}
 
+	// The peers capabilities are only a hint, skip them when `force` is set.
+	if !l.Force {
+		if err := l.cl.pollService.CheckCapabilities(fundingChannels.Id, l.Asset, swap.SWAPTYPE_OUT, l.SatAmt); err != nil {
+			return nil, err
+		}
+	}
+
 	if !l.cl.isPeerConnected(fundingChannels.Id) {
 		return nil, fmt.Errorf("peer is not connected")
 	}
@@ -260,8 +334,19 @@ func (l *SwapOut) Call() (jrpc2.Result, error) {
 		return nil, errors.New("invalid asset (btc or lbtc)")
 	}
 
+	claimAddress := l.ClaimAddress
+	if l.ClaimToDescriptor {
+		if claimAddress != "" {
+			return nil, errors.New("claim_address and claim_to_descriptor can not be used together")
+		}
+		claimAddress, err = l.cl.swaps.NewClaimAddress(l.Asset)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	pk := l.cl.GetNodeId()
-	swapOut, err := l.cl.swaps.SwapOut(fundingChannels.Id, l.Asset, l.ShortChannelId, pk, l.SatAmt)
+	swapOut, err := l.cl.swaps.SwapOut(fundingChannels.Id, l.Asset, l.ShortChannelId, pk, l.SatAmt, claimAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -291,7 +376,8 @@ func (l *SwapOut) Description() string {
 }
 
 func (l *SwapOut) LongDescription() string {
-	return ""
+	return `The swap is claimed to a new wallet address, to claim_address if set,
+		or to a fresh address of the configured claim descriptor if claim_to_descriptor is set.`
 }
 
 func (g *SwapOut) Get(client *ClightningClient) jrpc2.ServerMethod {
@@ -306,6 +392,9 @@ type SwapIn struct {
 	SatAmt         uint64 `json:"amt_sat"`
 	Asset          string `json:"asset"`
 	Force          bool   `json:"force"`
+	// ExternalFunding returns an unsigned PSBT of the opening tx instead of
+	// funding it from the wallet.
+	ExternalFunding bool `json:"external_funding,omitempty"`
 
 	cl *ClightningClient `json:"-"`
 }
@@ -359,6 +448,13 @@ func (l *SwapIn) Call() (jrpc2.Result, error) {
 		return nil, fmt.Errorf("peer does not run peerswap")
 	}
 
+	// The peers capabilities are only a hint, skip them when `force` is set.
+	if !l.Force {
+		if err := l.cl.pollService.CheckCapabilities(fundingChannels.Id, l.Asset, swap.SWAPTYPE_IN, l.SatAmt); err != nil {
+			return nil, err
+		}
+	}
+
 	if !l.cl.isPeerConnected(fundingChannels.Id) {
 		return nil, fmt.Errorf("peer is not connected")
 	}
@@ -369,35 +465,40 @@ func (l *SwapIn) Call() (jrpc2.Result, error) {
 		if l.cl.Gelements == nil {
 			return nil, errors.New("peerswap was not started with liquid node config")
 		}
-		liquidBalance, err := l.cl.liquidWallet.GetBalance()
-		if err != nil {
-			return nil, err
-		}
-		if liquidBalance < l.SatAmt {
-			return nil, errors.New("Not enough balance on liquid liquidWallet")
+		// An externally funded opening tx does not spend from the wallet.
+		if !l.ExternalFunding {
+			liquidBalance, err := l.cl.liquidWallet.GetBalance()
+			if err != nil {
+				return nil, err
+			}
+			if liquidBalance < l.SatAmt {
+				return nil, errors.New("Not enough balance on liquid liquidWallet")
+			}
 		}
 	} else if l.Asset == "btc" {
 		if !l.cl.swaps.BitcoinEnabled {
 			return nil, errors.New("bitcoin swaps are not enabled")
 		}
-		funds, err := l.cl.glightning.ListFunds()
-		if err != nil {
-			return nil, err
-		}
-		sats := uint64(0)
-		for _, v := range funds.Outputs {
-			sats += v.Value
-		}
+		if !l.ExternalFunding {
+			funds, err := l.cl.glightning.ListFunds()
+			if err != nil {
+				return nil, err
+			}
+			sats := uint64(0)
+			for _, v := range funds.Outputs {
+				sats += v.Value
+			}
 
-		if sats < l.SatAmt+2000 {
-			return nil, errors.New("Not enough balance on c-lightning onchain liquidWallet")
+			if sats < l.SatAmt+2000 {
+				return nil, errors.New("Not enough balance on c-lightning onchain liquidWallet")
+			}
 		}
 	} else {
 		return nil, errors.New("invalid asset (btc or lbtc)")
 	}
 
 	pk := l.cl.GetNodeId()
-	swapIn, err := l.cl.swaps.SwapIn(fundingChannels.Id, l.Asset, l.ShortChannelId, pk, l.SatAmt)
+	swapIn, err := l.cl.swaps.SwapIn(fundingChannels.Id, l.Asset, l.ShortChannelId, pk, l.SatAmt, l.ExternalFunding)
 	if err != nil {
 		return nil, err
 	}
@@ -415,7 +516,8 @@ func (l *SwapIn) Call() (jrpc2.Result, error) {
 				return nil, SwapCanceledError(swapIn.Data.GetCancelMessage())
 
 			}
-			if swapIn.Current == swap.State_SwapInSender_SendTxBroadcastedMessage {
+			if swapIn.Current == swap.State_SwapInSender_SendTxBroadcastedMessage ||
+				swapIn.Current == swap.State_SwapInSender_AwaitExternalFunding {
 				return peerswaprpc.PrettyprintFromServiceSwap(swapIn), nil
 			}
 		}
@@ -427,7 +529,9 @@ func (l *SwapIn) Description() string {
 }
 
 func (l *SwapIn) LongDescription() string {
-	return ""
+	return `If external_funding is set the opening tx is not funded from the wallet. The
+		swap returns an unsigned PSBT (PSET on liquid) in opening_psbt that has to be
+		funded, signed and submitted with peerswap-submitopeningtx within 9 minutes.`
 }
 
 func (g *SwapIn) Get(client *ClightningClient) jrpc2.ServerMethod {
@@ -436,6 +540,176 @@ func (g *SwapIn) Get(client *ClightningClient) jrpc2.ServerMethod {
 	}
 }
 
+// SwapInBatch starts swap-ins on several channels whose opening outputs are
+// paid by a single opening tx.
+type SwapInBatch struct {
+	Swaps []*SwapInBatchEntry `json:"swaps"`
+	Asset string              `json:"asset"`
+	Force bool                `json:"force,omitempty"`
+
+	cl *ClightningClient `json:"-"`
+}
+
+type SwapInBatchEntry struct {
+	ShortChannelId string `json:"short_channel_id"`
+	SatAmt         uint64 `json:"amt_sat"`
+}
+
+type SwapInBatchResult struct {
+	ShortChannelId string                       `json:"short_channel_id"`
+	Swap           *peerswaprpc.PrettyPrintSwap `json:"swap,omitempty"`
+	Error          string                       `json:"error,omitempty"`
+}
+
+func (l *SwapInBatch) New() interface{} {
+	return &SwapInBatch{
+		cl: l.cl,
+	}
+}
+
+func (l *SwapInBatch) Name() string {
+	return "peerswap-swap-in-batch"
+}
+
+func (l *SwapInBatch) Call() (jrpc2.Result, error) {
+	if !l.cl.isReady {
+		return nil, ErrWaitingForReady
+	}
+
+	if len(l.Swaps) == 0 {
+		return nil, errors.New("Missing required swaps parameter")
+	}
+
+	var total uint64
+	for _, s := range l.Swaps {
+		if s.SatAmt <= 0 {
+			return nil, errors.New("Missing required amt_sat parameter")
+		}
+		if s.ShortChannelId == "" {
+			return nil, errors.New("Missing required short_channel_id parameter")
+		}
+		total += s.SatAmt
+	}
+
+	funds, err := l.cl.glightning.ListFunds()
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Asset == "lbtc" {
+		if !l.cl.swaps.LiquidEnabled {
+			return nil, errors.New("liquid swaps are not enabled")
+		}
+		if l.cl.Gelements == nil {
+			return nil, errors.New("peerswap was not started with liquid node config")
+		}
+		liquidBalance, err := l.cl.liquidWallet.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		if liquidBalance < total {
+			return nil, errors.New("Not enough balance on liquid liquidWallet")
+		}
+	} else if l.Asset == "btc" {
+		if !l.cl.swaps.BitcoinEnabled {
+			return nil, errors.New("bitcoin swaps are not enabled")
+		}
+		sats := uint64(0)
+		for _, v := range funds.Outputs {
+			sats += v.Value
+		}
+		if sats < total+2000 {
+			return nil, errors.New("Not enough balance on c-lightning onchain liquidWallet")
+		}
+	} else {
+		return nil, errors.New("invalid asset (btc or lbtc)")
+	}
+
+	results := make([]*SwapInBatchResult, len(l.Swaps))
+	var requests []swap.SwapInBatchRequest
+	var requested []int
+	for i, s := range l.Swaps {
+		results[i] = &SwapInBatchResult{ShortChannelId: s.ShortChannelId}
+		peerId, err := l.checkChannel(funds.Channels, s)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		requests = append(requests, swap.SwapInBatchRequest{
+			PeerId:    peerId,
+			ChannelId: s.ShortChannelId,
+			AmtSat:    s.SatAmt,
+		})
+		requested = append(requested, i)
+	}
+	if len(requests) == 0 {
+		return results, nil
+	}
+
+	swapResults, err := l.cl.swaps.SwapInBatch(l.Asset, l.cl.GetNodeId(), requests)
+	if err != nil {
+		return nil, err
+	}
+	for j, r := range swapResults {
+		i := requested[j]
+		if r.Err != nil {
+			results[i].Error = r.Err.Error()
+			continue
+		}
+		results[i].Swap = peerswaprpc.PrettyprintFromServiceSwap(r.Swap)
+	}
+	return results, nil
+}
+
+// checkChannel runs the checks of peerswap-swap-in on a channel of the batch
+// and returns the peer of the channel.
+func (l *SwapInBatch) checkChannel(channels []*glightning.FundingChannel, s *SwapInBatchEntry) (string, error) {
+	var fundingChannels *glightning.FundingChannel
+	for _, v := range channels {
+		if v.ShortChannelId == s.ShortChannelId {
+			fundingChannels = v
+			break
+		}
+	}
+	if fundingChannels == nil {
+		return "", errors.New("fundingChannels not found")
+	}
+	if fundingChannels.ChannelTotalSatoshi-fundingChannels.ChannelSatoshi < s.SatAmt {
+		return "", errors.New("not enough inbound capacity to perform swap")
+	}
+	if !fundingChannels.Connected {
+		return "", errors.New("fundingChannels is not connected")
+	}
+	if !l.Force && !l.cl.peerRunsPeerSwap(fundingChannels.Id) {
+		return "", fmt.Errorf("peer does not run peerswap")
+	}
+	if !l.Force {
+		if err := l.cl.pollService.CheckCapabilities(fundingChannels.Id, l.Asset, swap.SWAPTYPE_IN, s.SatAmt); err != nil {
+			return "", err
+		}
+	}
+	if !l.cl.isPeerConnected(fundingChannels.Id) {
+		return "", fmt.Errorf("peer is not connected")
+	}
+	return fundingChannels.Id, nil
+}
+
+func (l *SwapInBatch) Description() string {
+	return "Initiates swap ins on several channels with a single opening tx"
+}
+
+func (l *SwapInBatch) LongDescription() string {
+	return `swaps is a list of {"short_channel_id", "amt_sat"}. The opening outputs of
+		all swaps that are agreed within a minute are paid by a single opening tx,
+		the others are opened on their own. Each channel reports its swap or error.`
+}
+
+func (g *SwapInBatch) Get(client *ClightningClient) jrpc2.ServerMethod {
+	return &SwapInBatch{
+		cl: client,
+	}
+}
+
 // ListSwaps list all active and finished swaps
 type ListSwaps struct {
 	DetailedPrint bool              `json:"detailed,omitempty"`
@@ -603,10 +877,15 @@ func (l *ListPeers) Call() (jrpc2.Result, error) {
 				return nil, err
 			}
 
-			var paidFees uint64
+			var paidFees, feeOvercharges uint64
 			var ReceiverSwapsOut, ReceiverSwapsIn, ReceiverSatsOut, ReceiverSatsIn uint64
 			var SenderSwapsOut, SenderSwapsIn, SenderSatsOut, SenderSatsIn uint64
 			for _, s := range swaps {
+				// Fee invoices of the peer that we rejected count against
+				// its reputation.
+				if s.Role == swap.SWAPROLE_SENDER && s.Data.FeeOvercharge > 0 {
+					feeOvercharges++
+				}
 				// We only list successful swaps. They all end in an
 				// State_ClaimedPreimage state.
 				if s.Current == swap.State_ClaimedPreimage {
@@ -647,7 +926,9 @@ func (l *ListPeers) Call() (jrpc2.Result, error) {
 					SatsOut:  ReceiverSatsOut,
 					SatsIn:   ReceiverSatsIn,
 				},
-				PaidFee: paidFees,
+				PaidFee:        paidFees,
+				FeeOvercharges: feeOvercharges,
+				Capabilities:   p.Capabilities,
 			}
 
 			peerSwapPeerChannels := []*PeerSwapPeerChannel{}
@@ -715,6 +996,62 @@ func (g *GetSwap) LongDescription() string {
 	return ""
 }
 
+// SubmitOpeningTx broadcasts the signed opening tx of an externally funded
+// swap in.
+type SubmitOpeningTx struct {
+	SwapId   string `json:"swap_id"`
+	SignedTx string `json:"signed_tx"`
+
+	cl *ClightningClient `json:"-"`
+}
+
+func (s *SubmitOpeningTx) Name() string {
+	return "peerswap-submitopeningtx"
+}
+
+func (s *SubmitOpeningTx) New() interface{} {
+	return &SubmitOpeningTx{
+		cl: s.cl,
+	}
+}
+
+func (s *SubmitOpeningTx) Call() (jrpc2.Result, error) {
+	if !s.cl.isReady {
+		return nil, ErrWaitingForReady
+	}
+
+	if s.SwapId == "" {
+		return nil, errors.New("swap_id required")
+	}
+	if s.SignedTx == "" {
+		return nil, errors.New("signed_tx required")
+	}
+	swapIn, err := s.cl.swaps.SubmitOpeningTx(s.SwapId, s.SignedTx)
+	if err != nil {
+		return nil, err
+	}
+	if swapIn.Current == swap.State_SwapCanceled {
+		return nil, SwapCanceledError(swapIn.Data.GetCancelMessage())
+	}
+	return peerswaprpc.PrettyprintFromServiceSwap(swapIn), nil
+}
+
+func (s *SubmitOpeningTx) Get(client *ClightningClient) jrpc2.ServerMethod {
+	return &SubmitOpeningTx{
+		cl: client,
+	}
+}
+
+func (s *SubmitOpeningTx) Description() string {
+	return "submits the signed opening tx of an externally funded swap in"
+}
+
+func (s *SubmitOpeningTx) LongDescription() string {
+	return `signed_tx is the signed PSBT (PSET on liquid) returned by peerswap-swap-in
+		with external_funding or the raw hex of the signed tx. The tx must pay the
+		swap amount to the opening output.`
+}
+
 type PolicyReloader interface {
 	AddToAllowlist(pubkey string) error
 	RemoveFromAllowlist(pubkey string) error
@@ -770,23 +1107,35 @@ func (c *ReloadPolicyFile) Get(client *ClightningClient) jrpc2.ServerMethod {
 }
 
 type GetRequestedSwaps struct {
-	cl *ClightningClient
+	PeerPubkey string            `json:"peer_pubkey,omitempty"`
+	Since      int64             `json:"since,omitempty"`
+	Until      int64             `json:"until,omitempty"`
+	cl         *ClightningClient `json:"-"`
 }
 
-func (c GetRequestedSwaps) Name() string {
+func (c *GetRequestedSwaps) Name() string {
 	return "peerswap-listswaprequests"
 }
 
-func (c GetRequestedSwaps) New() interface{} {
-	return c
+func (c *GetRequestedSwaps) New() interface{} {
+	return &GetRequestedSwaps{
+		cl: c.cl,
+	}
 }
 
-func (c GetRequestedSwaps) Call() (jrpc2.Result, error) {
+func (c *GetRequestedSwaps) Call() (jrpc2.Result, error) {
 	if !c.cl.isReady {
 		return nil, ErrWaitingForReady
 	}
 
-	requestedSwaps, err := c.cl.requestedSwaps.Get()
+	filter := swap.RequestedSwapsFilter{PeerId: c.PeerPubkey}
+	if c.Since > 0 {
+		filter.Since = time.Unix(c.Since, 0)
+	}
+	if c.Until > 0 {
+		filter.Until = time.Unix(c.Until, 0)
+	}
+	requestedSwaps, err := c.cl.requestedSwaps.Get(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -799,7 +1148,8 @@ func (c GetRequestedSwaps) Description() string {
 
 func (c GetRequestedSwaps) LongDescription() string {
 	return `This command can give you insight of swaps requested by peer nodes that could not have
-		been performed because either the peer is not in the allowlist or the asset is not set.`
+		been performed because either the peer is not in the allowlist or the asset is not set.
+		The requests can be filtered by peer_pubkey and by the unix times since and until.`
 }
 
 func (c *GetRequestedSwaps) Get(client *ClightningClient) jrpc2.ServerMethod {
@@ -853,6 +1203,78 @@ func (c ListActiveSwaps) LongDescription() string {
 	return `This command can give you information if you are ready to upgrade your peerswap daemon.`
 }
 
+// Drain disables new swaps and reports the active swaps that are left.
+type Drain struct {
+	Shutdown bool `json:"shutdown,omitempty"`
+
+	cl *ClightningClient
+}
+
+func (g *Drain) Name() string {
+	return "peerswap-drain"
+}
+
+func (g *Drain) New() interface{} {
+	return &Drain{
+		cl:       g.cl,
+		Shutdown: g.Shutdown,
+	}
+}
+
+func (g *Drain) Call() (jrpc2.Result, error) {
+	if !g.cl.isReady {
+		return nil, ErrWaitingForReady
+	}
+
+	err := g.cl.policy.DisableSwaps()
+	if err != nil {
+		return nil, err
+	}
+
+	swaps, err := g.cl.swaps.ListDrainingSwaps()
+	if err != nil {
+		return nil, err
+	}
+
+	g.cl.drainMu.Lock()
+	defer g.cl.drainMu.Unlock()
+	if g.Shutdown && !g.cl.drainShutdown && g.cl.shutdown != nil {
+		g.cl.drainShutdown = true
+		go func() {
+			err := g.cl.swaps.WaitForDrain(g.cl.ctx)
+			if err != nil {
+				log.Infof("[Drain] Could not wait for active swaps: %v", err)
+				g.cl.drainMu.Lock()
+				g.cl.drainShutdown = false
+				g.cl.drainMu.Unlock()
+				return
+			}
+			log.Infof("[Drain] All active swaps completed, shutting down")
+			g.cl.shutdown()
+		}()
+	}
+
+	return &peerswaprpc.DrainResponse{
+		Swaps:    peerswaprpc.DrainingSwapsFromService(swaps),
+		Shutdown: g.cl.drainShutdown,
+	}, nil
+}
+
+func (g *Drain) Get(client *ClightningClient) jrpc2.ServerMethod {
+	return &Drain{
+		cl:       client,
+		Shutdown: g.Shutdown,
+	}
+}
+
+func (c Drain) Description() string {
+	return "Stops new swaps and reports the active swaps that are left."
+}
+
+func (c Drain) LongDescription() string {
+	return `Disables new swaps in both directions and lists the active swaps with the block height by which they complete at the latest. If shutdown is set, the plugin stops once the last active swap completed. Use peerswap-allowswaprequests to allow swaps again after the upgrade.`
+}
+
 type AllowSwapRequests struct {
 	AllowSwapRequestsString string `json:"allow_swap_requests"`
 
@@ -1115,6 +1537,68 @@ func (c ListConfig) LongDescription() string {
 	return c.Description()
 }
 
+type GetPeerHistory struct {
+	PeerPubkey string `json:"peer_pubkey"`
+	cl         *ClightningClient
+}
+
+func (g *GetPeerHistory) Name() string {
+	return "peerswap-peerhistory"
+}
+
+func (g *GetPeerHistory) New() interface{} {
+	return &GetPeerHistory{
+		cl:         g.cl,
+		PeerPubkey: g.PeerPubkey,
+	}
+}
+
+func (g *GetPeerHistory) Call() (jrpc2.Result, error) {
+	if !g.cl.isReady {
+		return nil, ErrWaitingForReady
+	}
+	if g.PeerPubkey == "" {
+		return nil, errors.New("missing required peer_pubkey parameter")
+	}
+
+	history, err := g.cl.pollService.GetPeerHistory(g.PeerPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return &PeerHistory{
+		NodeId:       g.PeerPubkey,
+		FirstSeen:    history.FirstSeen,
+		LastSeen:     history.LastSeen,
+		Uptime:       history.Uptimes(time.Now()),
+		Versions:     history.Versions,
+		AssetChanges: history.AssetChanges,
+	}, nil
+}
+
+func (g *GetPeerHistory) Get(client *ClightningClient) jrpc2.ServerMethod {
+	return &GetPeerHistory{
+		cl:         client,
+		PeerPubkey: g.PeerPubkey,
+	}
+}
+
+func (c GetPeerHistory) Description() string {
+	return "Returns the poll history of a peer"
+}
+
+func (c GetPeerHistory) LongDescription() string {
+	return `This command returns when a peer was first and last seen, its uptime and the protocol versions and assets it announced`
+}
+
+type PeerHistory struct {
+	NodeId       string              `json:"nodeid"`
+	FirstSeen    time.Time           `json:"first_seen"`
+	LastSeen     time.Time           `json:"last_seen"`
+	Uptime       []poll.WindowUptime `json:"uptime"`
+	Versions     []poll.VersionSeen  `json:"versions"`
+	AssetChanges []poll.AssetChange  `json:"asset_changes"`
+}
+
 type PeerSwapPeerChannel struct {
 	ChannelId       string  `json:"short_channel_id"`
 	LocalBalance    uint64  `json:"local_balance"`
@@ -1138,6 +1622,8 @@ type PeerSwapPeer struct {
 	AsSender        *SwapStats             `json:"sent,omitempty"`
 	AsReceiver      *SwapStats             `json:"received,omitempty"`
 	PaidFee         uint64                 `json:"total_fee_paid"`
+	FeeOvercharges  uint64                 `json:"fee_overcharges,omitempty"`
+	Capabilities    *poll.Capabilities     `json:"capabilities,omitempty"`
 }
 
 // checkFeatures checks if a node runs the peerswap Plugin
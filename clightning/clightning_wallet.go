@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/elementsproject/glightning/glightning"
 	"github.com/elementsproject/peerswap/lightning"
 	"github.com/elementsproject/peerswap/onchain"
@@ -41,6 +43,103 @@ func (cl *ClightningClient) CreateOpeningTransaction(swapParams *swap.OpeningPar
 	return prepRes.UnsignedTx, fee, vout, nil
 }
 
+// signPsbtRequest signs the inputs of a psbt that belong to the wallet.
+type signPsbtRequest struct {
+	Psbt string `json:"psbt"`
+}
+
+func (r signPsbtRequest) Name() string {
+	return "signpsbt"
+}
+
+type signPsbtResult struct {
+	SignedPsbt string `json:"signed_psbt"`
+}
+
+func (cl *ClightningClient) CreateBatchOpeningTransaction(swapParams []*swap.OpeningParams) (txId, txHex string, vouts []uint32, err error) {
+	var outputs []*glightning.Outputs
+	for _, params := range swapParams {
+		addr, err := cl.bitcoinChain.CreateOpeningAddress(params, onchain.BitcoinCsv)
+		if err != nil {
+			return "", "", nil, err
+		}
+		outputs = append(outputs, &glightning.Outputs{
+			Address: addr,
+			Satoshi: params.Amount,
+		})
+	}
+	prepRes, err := cl.glightning.PrepareTx(outputs, &glightning.FeeRate{Directive: glightning.Urgent}, nil)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// Sign the tx without sending it, the swaps persist it first.
+	var signRes signPsbtResult
+	err = cl.glightning.Request(&signPsbtRequest{Psbt: prepRes.Psbt}, &signRes)
+	if err != nil {
+		return "", "", nil, err
+	}
+	txHex, _, err = cl.bitcoinChain.ExtractOpeningTx(swapParams[0], signRes.SignedPsbt)
+	if err != nil {
+		return "", "", nil, err
+	}
+	txId, err = cl.bitcoinChain.TxIdFromHex(txHex)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	vouts = make([]uint32, len(swapParams))
+	for i, params := range swapParams {
+		ok, vout, err := cl.bitcoinChain.GetVoutAndVerify(txHex, params)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if !ok {
+			return "", "", nil, errors.New("opening output not found in prepared tx")
+		}
+		vouts[i] = vout
+	}
+	return txId, txHex, vouts, nil
+}
+
+// unreserveInputsRequest releases the reservation of the inputs of a psbt.
+type unreserveInputsRequest struct {
+	Psbt string `json:"psbt"`
+}
+
+func (r *unreserveInputsRequest) Name() string {
+	return "unreserveinputs"
+}
+
+// ReleaseBatchOpeningTransaction releases the reservation that txprepare
+// took on the inputs of the tx.
+func (cl *ClightningClient) ReleaseBatchOpeningTransaction(txHex string) error {
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		return err
+	}
+	tx := wire.NewMsgTx(2)
+	err = tx.Deserialize(bytes.NewReader(txBytes))
+	if err != nil {
+		return err
+	}
+	// Only the inputs are needed, the psbt is built from the unsigned tx.
+	for _, in := range tx.TxIn {
+		in.SignatureScript = nil
+		in.Witness = nil
+	}
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return err
+	}
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return err
+	}
+	var res interface{}
+	return cl.glightning.Request(&unreserveInputsRequest{Psbt: b64}, &res)
+}
+
 func (cl *ClightningClient) BroadcastOpeningTx(unpreparedTxHex string) (txId, txHex string, error error) {
 	var unpreparedTxId string
 	var ok bool
@@ -55,6 +154,18 @@ func (cl *ClightningClient) BroadcastOpeningTx(unpreparedTxHex string) (txId, tx
 	return sendRes.TxId, sendRes.SignedTx, nil
 }
 
+func (cl *ClightningClient) CreateOpeningPsbt(swapParams *swap.OpeningParams) (string, error) {
+	return cl.bitcoinChain.CreateOpeningPsbt(swapParams)
+}
+
+func (cl *ClightningClient) ExtractOpeningTx(swapParams *swap.OpeningParams, signedTx string) (string, uint32, error) {
+	return cl.bitcoinChain.ExtractOpeningTx(swapParams, signedTx)
+}
+
+func (cl *ClightningClient) BroadcastTx(txHex string) (string, error) {
+	return cl.gbitcoin.SendRawTx(txHex)
+}
+
 func (cl *ClightningClient) CreatePreimageSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (txId, txHex string, err error) {
 
 	_, vout, err := cl.bitcoinChain.GetVoutAndVerify(claimParams.OpeningTxHex, swapParams)
@@ -62,7 +173,7 @@ func (cl *ClightningClient) CreatePreimageSpendingTransaction(swapParams *swap.O
 		return "", "", err
 	}
 
-	newAddr, err := cl.glightning.NewAddr()
+	newAddr, err := cl.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -99,8 +210,36 @@ func (cl *ClightningClient) CreatePreimageSpendingTransaction(swapParams *swap.O
 	return txId, txHex, nil
 }
 
+// SweepPreimageClaims spends the opening outputs of the claims with their
+// preimages in a single tx to a new wallet address.
+func (cl *ClightningClient) SweepPreimageClaims(claims []*swap.PreimageClaim) (txId, txHex string, err error) {
+	newAddr, err := cl.NewAddress()
+	if err != nil {
+		return "", "", err
+	}
+
+	tx, err := cl.bitcoinChain.CreatePreimageSweepTransaction(claims, newAddr)
+	if err != nil {
+		return "", "", err
+	}
+
+	bytesBuffer := new(bytes.Buffer)
+	err = tx.Serialize(bytesBuffer)
+	if err != nil {
+		return "", "", err
+	}
+
+	txHex = hex.EncodeToString(bytesBuffer.Bytes())
+
+	txId, err = cl.gbitcoin.SendRawTx(txHex)
+	if err != nil {
+		return "", "", err
+	}
+	return txId, txHex, nil
+}
+
 func (cl *ClightningClient) CreateCsvSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (txId, txHex string, error error) {
-	newAddr, err := cl.glightning.NewAddr()
+	newAddr, err := cl.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -139,7 +278,7 @@ func (cl *ClightningClient) CreateCsvSpendingTransaction(swapParams *swap.Openin
 }
 
 func (cl *ClightningClient) CreateCoopSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams, takerSigner swap.Signer) (txId, txHex string, error error) {
-	refundAddr, err := cl.NewAddress()
+	refundAddr, err := cl.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -183,6 +322,19 @@ func (cl *ClightningClient) CreateCoopSpendingTransaction(swapParams *swap.Openi
 	return spendingTx.TxHash().String(), txHex, nil
 }
 
+// claimAddress returns the address the swap is claimed to, a new wallet
+// address if none is set.
+func (cl *ClightningClient) claimAddress(claimParams *swap.ClaimParams) (string, error) {
+	if claimParams.ClaimAddress != "" {
+		return claimParams.ClaimAddress, nil
+	}
+	return cl.NewAddress()
+}
+
+func (cl *ClightningClient) ValidateAddress(address string) error {
+	return cl.bitcoinChain.ValidateAddress(address)
+}
+
 func (cl *ClightningClient) NewAddress() (string, error) {
 	newAddr, err := cl.glightning.NewAddr()
 	if err != nil {
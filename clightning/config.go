@@ -11,6 +11,7 @@ import (
 
 	"github.com/elementsproject/glightning/glightning"
 	"github.com/elementsproject/peerswap/log"
+	"github.com/elementsproject/peerswap/swap"
 	"github.com/pelletier/go-toml/v2"
 )
 
@@ -34,6 +35,19 @@ type BitcoinConf struct {
 	RpcPort         uint
 	Network         string
 	DataDir         string
+	ZmqPubRawBlock  string
+	ZmqPubHashBlock string
+	ZmqPubRawTx     string
+	EsploraUrl      string
+	// ClaimDescriptor is the descriptor whose addresses swap-outs can be
+	// claimed to.
+	ClaimDescriptor string
+	// ClaimBatching queues claims that are not time critical and sweeps
+	// them in a single tx. It is only available for bitcoin. ClaimBatchMaxDelay (a duration like "6h") and
+	// ClaimBatchCsvMargin (in blocks) bound how long a claim is queued.
+	ClaimBatching       bool
+	ClaimBatchMaxDelay  string
+	ClaimBatchCsvMargin uint32
 }
 
 type LiquidConf struct {
@@ -46,6 +60,13 @@ type LiquidConf struct {
 	Network         string
 	DataDir         string
 	Disabled        bool
+	ZmqPubRawBlock  string
+	ZmqPubHashBlock string
+	ZmqPubRawTx     string
+	EsploraUrl      string
+	// ClaimDescriptor is the descriptor whose addresses swap-outs can be
+	// claimed to.
+	ClaimDescriptor string
 }
 
 type Config struct {
@@ -53,8 +74,16 @@ type Config struct {
 	PeerswapDir  string
 	DbPath       string
 	PolicyPath   string
-	Bitcoin      *BitcoinConf
-	Liquid       *LiquidConf
+	MetricsHost  string
+	LogLevel     log.Level
+	LogJson      bool
+	// RequestedSwapsRetentionDays and RequestedSwapsMaxPerPeer bound the
+	// log of rejected swap requests. A retention of 0 days keeps them
+	// forever, the number per peer is capped to swap.MaxRequestedSwapsPerPeer.
+	RequestedSwapsRetentionDays uint
+	RequestedSwapsMaxPerPeer    uint
+	Bitcoin                     *BitcoinConf
+	Liquid                      *LiquidConf
 }
 
 func (c *Config) String() string {
@@ -135,8 +164,14 @@ func ReadFromFile() Processor {
 		}
 
 		var fileConf struct {
-			Bitcoin *BitcoinConf
-			Liquid  *LiquidConf
+			MetricsHost string
+			LogLevel    string
+			LogJson     bool
+			// Pointers to tell an explicit 0 from an unset value.
+			RequestedSwapsRetentionDays *uint
+			RequestedSwapsMaxPerPeer    *uint
+			Bitcoin                     *BitcoinConf
+			Liquid                      *LiquidConf
 		}
 
 		err = toml.Unmarshal(data, &fileConf)
@@ -144,12 +179,39 @@ func ReadFromFile() Processor {
 			return nil, err
 		}
 
+		c.MetricsHost = fileConf.MetricsHost
+		c.LogJson = fileConf.LogJson
+		if fileConf.RequestedSwapsRetentionDays != nil {
+			c.RequestedSwapsRetentionDays = *fileConf.RequestedSwapsRetentionDays
+		}
+		if fileConf.RequestedSwapsMaxPerPeer != nil {
+			c.RequestedSwapsMaxPerPeer = *fileConf.RequestedSwapsMaxPerPeer
+		}
+		if fileConf.LogLevel != "" {
+			c.LogLevel, err = log.ParseLevel(fileConf.LogLevel)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		if fileConf.Bitcoin != nil {
 			c.Bitcoin.RpcUser = fileConf.Bitcoin.RpcUser
 			c.Bitcoin.RpcPassword = fileConf.Bitcoin.RpcPassword
 			c.Bitcoin.RpcPasswordFile = fileConf.Bitcoin.RpcPasswordFile
 			c.Bitcoin.RpcHost = fileConf.Bitcoin.RpcHost
 			c.Bitcoin.RpcPort = fileConf.Bitcoin.RpcPort
+			c.Bitcoin.ZmqPubRawBlock = fileConf.Bitcoin.ZmqPubRawBlock
+			c.Bitcoin.ZmqPubHashBlock = fileConf.Bitcoin.ZmqPubHashBlock
+			c.Bitcoin.ZmqPubRawTx = fileConf.Bitcoin.ZmqPubRawTx
+			c.Bitcoin.EsploraUrl = fileConf.Bitcoin.EsploraUrl
+			c.Bitcoin.ClaimDescriptor = fileConf.Bitcoin.ClaimDescriptor
+			c.Bitcoin.ClaimBatching = fileConf.Bitcoin.ClaimBatching
+			if fileConf.Bitcoin.ClaimBatchMaxDelay != "" {
+				c.Bitcoin.ClaimBatchMaxDelay = fileConf.Bitcoin.ClaimBatchMaxDelay
+			}
+			if fileConf.Bitcoin.ClaimBatchCsvMargin != 0 {
+				c.Bitcoin.ClaimBatchCsvMargin = fileConf.Bitcoin.ClaimBatchCsvMargin
+			}
 		}
 
 		if fileConf.Liquid != nil {
@@ -160,6 +222,11 @@ func ReadFromFile() Processor {
 			c.Liquid.RpcPort = fileConf.Liquid.RpcPort
 			c.Liquid.RpcWallet = fileConf.Liquid.RpcWallet
 			c.Liquid.Disabled = fileConf.Liquid.Disabled
+			c.Liquid.ZmqPubRawBlock = fileConf.Liquid.ZmqPubRawBlock
+			c.Liquid.ZmqPubHashBlock = fileConf.Liquid.ZmqPubHashBlock
+			c.Liquid.ZmqPubRawTx = fileConf.Liquid.ZmqPubRawTx
+			c.Liquid.EsploraUrl = fileConf.Liquid.EsploraUrl
+			c.Liquid.ClaimDescriptor = fileConf.Liquid.ClaimDescriptor
 		}
 
 		return c, nil
@@ -176,6 +243,10 @@ func PeerSwapFallback() Processor {
 			c.Liquid.RpcWallet = defaultLiquidWalletName
 		}
 
+		if c.LogLevel == 0 {
+			c.LogLevel = log.LevelDebug
+		}
+
 		return c, nil
 	}
 }
@@ -424,7 +495,15 @@ func (p *Pipeline) Add(pr Processor) *Pipeline {
 
 func (p *Pipeline) Run() (*Config, error) {
 	var err error
-	c := &Config{Bitcoin: &BitcoinConf{}, Liquid: &LiquidConf{}}
+	c := &Config{
+		RequestedSwapsRetentionDays: swap.DefaultRequestedSwapsRetentionDays,
+		RequestedSwapsMaxPerPeer:    swap.DefaultRequestedSwapsMaxPerPeer,
+		Bitcoin: &BitcoinConf{
+			ClaimBatchMaxDelay:  swap.DefaultClaimBatchMaxDelay.String(),
+			ClaimBatchCsvMargin: swap.DefaultClaimBatchCsvMargin,
+		},
+		Liquid: &LiquidConf{},
+	}
 	for _, pr := range p.processors {
 		c, err = pr(c)
 		if err != nil {
@@ -18,6 +18,8 @@ func Test_ReadFromFile(t *testing.T) {
 	rpchost="rpchost"
 	rpcport=1234
 	cookiefilepath="cookiefilepath"
+	zmqpubrawblock="tcp://127.0.0.1:28332"
+	zmqpubrawtx="tcp://127.0.0.1:28333"
 
 	[Liquid]
 	rpcuser="rpcuser"
@@ -27,6 +29,8 @@ func Test_ReadFromFile(t *testing.T) {
 	rpcport=1234
 	rpcwallet="rpcwallet"
 	enabled=true
+	zmqpubhashblock="tcp://127.0.0.1:28334"
+	esploraurl="http://127.0.0.1:3000"
 	`
 
 	dir := t.TempDir()
@@ -49,6 +53,8 @@ func Test_ReadFromFile(t *testing.T) {
 			RpcPort:         1234,
 			Network:         "",
 			DataDir:         "",
+			ZmqPubRawBlock:  "tcp://127.0.0.1:28332",
+			ZmqPubRawTx:     "tcp://127.0.0.1:28333",
 		},
 		Liquid: &LiquidConf{
 			RpcUser:         "rpcuser",
@@ -60,6 +66,8 @@ func Test_ReadFromFile(t *testing.T) {
 			Network:         "",
 			DataDir:         "",
 			Disabled:        false,
+			ZmqPubHashBlock: "tcp://127.0.0.1:28334",
+			EsploraUrl:      "http://127.0.0.1:3000",
 		},
 	}
 
@@ -46,6 +46,10 @@ type PeerswapClightningConfig struct {
 	BitcoinRpcHost         string `json:"bitcoin.rpchost"`
 	BitcoinRpcPort         uint   `json:"bitcoin.rpcport"`
 	BitcoinCookieFilePath  string `json:"bitcoin.rpccookiefilepath"`
+	BitcoinZmqPubRawBlock  string `json:"bitcoin.zmqpubrawblock"`
+	BitcoinZmqPubHashBlock string `json:"bitcoin.zmqpubhashblock"`
+	BitcoinZmqPubRawTx     string `json:"bitcoin.zmqpubrawtx"`
+	BitcoinEsploraUrl      string `json:"bitcoin.esploraurl"`
 
 	LiquidRpcUser         string `json:"liquid.rpcuser"`
 	LiquidRpcPassword     string `json:"liquid.rpcpassword"`
@@ -54,8 +58,15 @@ type PeerswapClightningConfig struct {
 	LiquidRpcPort         uint   `json:"liquid.rpcport"`
 	LiquidRpcWallet       string `json:"liquid.rpcwallet"`
 	LiquidDisabled        bool   `json:"liquid.disabled"`
+	LiquidZmqPubRawBlock  string `json:"liquid.zmqpubrawblock"`
+	LiquidZmqPubHashBlock string `json:"liquid.zmqpubhashblock"`
+	LiquidZmqPubRawTx     string `json:"liquid.zmqpubrawtx"`
+	LiquidEsploraUrl      string `json:"liquid.esploraurl"`
 
 	PeerswapDir string `json:"peerswap-dir"`
+	MetricsHost string `json:"metrics-host"`
+	LogLevel    string `json:"log-level"`
+	LogJson     bool   `json:"log-json"`
 }
 
 func (c PeerswapClightningConfig) String() string {
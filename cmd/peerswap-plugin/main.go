@@ -10,6 +10,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/elementsproject/peerswap/esplora"
 	"github.com/elementsproject/peerswap/isdev"
 	"github.com/elementsproject/peerswap/log"
 	"github.com/elementsproject/peerswap/version"
@@ -23,6 +24,7 @@ import (
 	"github.com/elementsproject/glightning/glightning"
 	"github.com/elementsproject/peerswap/clightning"
 	"github.com/elementsproject/peerswap/messages"
+	"github.com/elementsproject/peerswap/metrics"
 	"github.com/elementsproject/peerswap/onchain"
 	"github.com/elementsproject/peerswap/policy"
 	"github.com/elementsproject/peerswap/poll"
@@ -69,6 +71,7 @@ func outer() error {
 	if err != nil {
 		return err
 	}
+	plugin.SetShutdown(cancel)
 
 	err = plugin.RegisterOptions()
 	if err != nil {
@@ -94,7 +97,7 @@ func outer() error {
 
 	// Now we can set the logger to the core lightning log. From here on we can
 	// use the log in all inner and the rest of this routine.
-	log.SetLogger(clightning.NewGlightninglogger(plugin.Plugin))
+	log.SetLogger(clightning.NewGlightninglogger(plugin.Plugin, log.LevelDebug, false))
 
 	// Start PeerSwap.
 	err = run(ctx, plugin)
@@ -133,6 +136,9 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 	}
 	log.Debugf("Starting with config: %s", config)
 
+	// Apply the log settings from the config.
+	log.SetLogger(clightning.NewGlightninglogger(lightningPlugin.Plugin, config.LogLevel, config.LogJson))
+
 	// Inject the config into the core lightning plugin.
 	lightningPlugin.SetPeerswapConfig(config)
 
@@ -187,7 +193,35 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 			return err
 		}
 
-		liquidTxWatcher = txwatcher.NewBlockchainRpcTxWatcher(ctx, txwatcher.NewElementsCli(liquidCli), onchain.LiquidConfs, onchain.LiquidCsv)
+		elementsRpc := txwatcher.NewElementsCli(liquidCli)
+		// gelements can not list the mempool, elementsd speaks the bitcoind
+		// rpc for the mempool lookups.
+		var liquidRawRpc *gbitcoin.Bitcoin
+		liquidRawRpc, err = getElementsRawRpc(config)
+		if err != nil {
+			return err
+		}
+		elementsRpc.EnableMempoolLookups(liquidRawRpc)
+		var liquidBlockchain txwatcher.BlockchainRpc = elementsRpc
+		var liquidEsplora *esplora.Client
+		if config.Liquid.EsploraUrl != "" {
+			log.Infof("Using esplora api at %s for liquid", config.Liquid.EsploraUrl)
+			liquidEsplora = esplora.NewClient(config.Liquid.EsploraUrl)
+			liquidBlockchain = txwatcher.NewEsploraBlockchainRpc(liquidEsplora, "lbtc")
+		}
+		liquidTxWatcher = txwatcher.NewBlockchainRpcTxWatcher(ctx, liquidBlockchain, onchain.LiquidConfs, onchain.LiquidCsv)
+		liquidZmq := &txwatcher.ZmqConfig{
+			RawBlock:  config.Liquid.ZmqPubRawBlock,
+			HashBlock: config.Liquid.ZmqPubHashBlock,
+			RawTx:     config.Liquid.ZmqPubRawTx,
+		}
+		if liquidZmq.Enabled() {
+			err = liquidTxWatcher.EnableZmq(liquidZmq)
+			if err != nil {
+				return err
+			}
+			log.Infof("Liquid zmq notifications enabled")
+		}
 
 		// LiquidChain
 		liquidChain, err := getLiquidChain(liquidCli)
@@ -196,6 +230,17 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 		}
 
 		liquidOnChainService = onchain.NewLiquidOnChain(liquidCli, liquidRpcWallet, liquidChain)
+		if liquidEsplora != nil {
+			liquidEstimator, err := onchain.NewEsploraEstimator(
+				liquidEsplora,
+				onchain.LiquidFeePerKwFloor,
+				onchain.LiquidFeePerKwFloor,
+			)
+			if err != nil {
+				return err
+			}
+			liquidOnChainService.SetFeeEstimator(liquidEstimator)
+		}
 		supportedAssets = append(supportedAssets, "lbtc")
 		log.Infof("Liquid swaps enabled")
 	} else {
@@ -227,7 +272,26 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 		supportedAssets = append(supportedAssets, "btc")
 		log.Infof("Bitcoin swaps enabled")
 		bitcoinEnabled = true
-		bitcoinTxWatcher = txwatcher.NewBlockchainRpcTxWatcher(ctx, txwatcher.NewBitcoinRpc(bitcoinCli), onchain.BitcoinMinConfs, onchain.BitcoinCsv)
+		var bitcoinBlockchain txwatcher.BlockchainRpc = txwatcher.NewBitcoinRpc(bitcoinCli)
+		var bitcoinEsplora *esplora.Client
+		if config.Bitcoin.EsploraUrl != "" {
+			log.Infof("Using esplora api at %s for bitcoin", config.Bitcoin.EsploraUrl)
+			bitcoinEsplora = esplora.NewClient(config.Bitcoin.EsploraUrl)
+			bitcoinBlockchain = txwatcher.NewEsploraBlockchainRpc(bitcoinEsplora, "btc")
+		}
+		bitcoinTxWatcher = txwatcher.NewBlockchainRpcTxWatcher(ctx, bitcoinBlockchain, onchain.BitcoinMinConfs, onchain.BitcoinCsv)
+		bitcoinZmq := &txwatcher.ZmqConfig{
+			RawBlock:  config.Bitcoin.ZmqPubRawBlock,
+			HashBlock: config.Bitcoin.ZmqPubHashBlock,
+			RawTx:     config.Bitcoin.ZmqPubRawTx,
+		}
+		if bitcoinZmq.Enabled() {
+			err = bitcoinTxWatcher.EnableZmq(bitcoinZmq)
+			if err != nil {
+				return err
+			}
+			log.Infof("Bitcoin zmq notifications enabled")
+		}
 
 		// We set the default Estimator to the static regtest estimator.
 		var bitcoinEstimator onchain.Estimator
@@ -235,7 +299,17 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 
 		// If we use a network different than regtest we override the Estimator
 		// with the useful GBitcoindEstimator.
-		if chain.Name != "regtest" {
+		if chain.Name != "regtest" && bitcoinEsplora != nil {
+			log.Infof("Using esplora estimator")
+			bitcoinEstimator, err = onchain.NewEsploraEstimator(
+				bitcoinEsplora,
+				onchain.FeePerKwFloor,
+				btcutil.Amount(6250),
+			)
+			if err != nil {
+				return err
+			}
+		} else if chain.Name != "regtest" {
 			log.Infof("Using gbitcoind estimator")
 
 			// Initiate the GBitcoinEstimator with the "ECONOMICAL" estimation
@@ -294,19 +368,37 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 		return err
 	}
 
-	requestedSwapStore, err := swap.NewRequestedSwapsStore(swapDb)
+	requestedSwapStore, err := swap.NewRequestedSwapsStore(swapDb, swap.RequestedSwapsRetention{
+		MaxAge:     time.Duration(config.RequestedSwapsRetentionDays) * 24 * time.Hour,
+		MaxPerPeer: int(config.RequestedSwapsMaxPerPeer),
+	})
+	if err != nil {
+		return err
+	}
+	if err := requestedSwapStore.Prune(); err != nil {
+		log.Infof("error pruning requested swaps: %v", err)
+	}
+
+	// The poll service tells which features our peers support.
+	pollStore, err := poll.NewStore(swapDb)
 	if err != nil {
 		return err
 	}
+	pollService := poll.NewService(1*time.Hour, 2*time.Hour, pollStore, lightningPlugin, pol, lightningPlugin, supportedAssets)
 
-	// Manager for send message retry.
-	mesmgr := messages.NewManager()
+	// Outbox to resend messages until they are acknowledged.
+	outboxStore, err := messages.NewOutboxStore(swapDb)
+	if err != nil {
+		return err
+	}
+	outbox := messages.NewOutbox(lightningPlugin, outboxStore, 10*time.Second, 10*time.Minute)
 
 	swapServices := swap.NewSwapServices(swapStore,
 		requestedSwapStore,
 		lightningPlugin,
 		lightningPlugin,
-		mesmgr,
+		outbox,
+		pollService,
 		pol,
 		bitcoinEnabled,
 		lightningPlugin,
@@ -318,6 +410,68 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 		liquidTxWatcher,
 	)
 	swapService := swap.NewSwapService(swapServices)
+	pollService.SetSwapLimits(swapService)
+	pollService.SetRateLimiter(swapService)
+
+	seenStore, err := messages.NewSeenStore(swapDb)
+	if err != nil {
+		return err
+	}
+	err = swapService.PersistSeenMessages(seenStore)
+	if err != nil {
+		return err
+	}
+
+	// Swap-outs can be claimed to fresh addresses of a descriptor.
+	if config.Bitcoin.ClaimDescriptor != "" {
+		if bitcoinOnChainService == nil {
+			return errors.New("Bitcoin.claimdescriptor is set but bitcoin swaps are disabled")
+		}
+		descriptor, err := bitcoinOnChainService.NewClaimDescriptor(config.Bitcoin.ClaimDescriptor)
+		if err != nil {
+			return fmt.Errorf("Bitcoin.claimdescriptor: %w", err)
+		}
+		claimAddresses, err := swap.NewClaimAddresses(swapDb, descriptor)
+		if err != nil {
+			return err
+		}
+		swapService.SetClaimAddresses("btc", claimAddresses)
+	}
+	if config.Liquid.ClaimDescriptor != "" {
+		if liquidOnChainService == nil {
+			return errors.New("Liquid.claimdescriptor is set but liquid swaps are disabled")
+		}
+		descriptor, err := liquidOnChainService.NewClaimDescriptor(config.Liquid.ClaimDescriptor)
+		if err != nil {
+			return fmt.Errorf("Liquid.claimdescriptor: %w", err)
+		}
+		claimAddresses, err := swap.NewClaimAddresses(swapDb, descriptor)
+		if err != nil {
+			return err
+		}
+		swapService.SetClaimAddresses("lbtc", claimAddresses)
+	}
+
+	// Bitcoin claims that are not time critical can be swept in batches.
+	// There is no sweeper for liquid, liquid claims are spent on their own.
+	var claimBatcher *swap.ClaimBatcher
+	if config.Bitcoin.ClaimBatching {
+		if !bitcoinEnabled {
+			return errors.New("Bitcoin.claimbatching is set but bitcoin swaps are disabled")
+		}
+		maxDelay, err := time.ParseDuration(config.Bitcoin.ClaimBatchMaxDelay)
+		if err != nil {
+			return fmt.Errorf("Bitcoin.claimbatchmaxdelay: %w", err)
+		}
+		claimBatcher = swap.NewClaimBatcher(lightningPlugin, swap.ClaimBatchConfig{
+			MaxDelay:        maxDelay,
+			CsvSafetyMargin: config.Bitcoin.ClaimBatchCsvMargin,
+		})
+		err = swapService.SetClaimBatcher("btc", claimBatcher)
+		if err != nil {
+			return err
+		}
+	}
 
 	if liquidTxWatcher != nil && liquidEnabled {
 		go func() {
@@ -344,11 +498,6 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 		return err
 	}
 
-	pollStore, err := poll.NewStore(swapDb)
-	if err != nil {
-		return err
-	}
-	pollService := poll.NewService(1*time.Hour, 2*time.Hour, pollStore, lightningPlugin, pol, lightningPlugin, supportedAssets)
 	pollService.Start()
 	defer pollService.Stop()
 
@@ -376,6 +525,38 @@ func run(ctx context.Context, lightningPlugin *clightning.ClightningClient) erro
 		return err
 	}
 
+	if claimBatcher != nil {
+		claimBatcher.Start()
+		defer claimBatcher.Stop()
+	}
+
+	err = outbox.Start()
+	if err != nil {
+		return err
+	}
+	defer outbox.Stop()
+
+	if config.MetricsHost != "" {
+		wallets := map[string]metrics.BalanceGetter{}
+		if bitcoinEnabled {
+			wallets["btc"] = lightningPlugin
+		}
+		if liquidEnabled {
+			wallets["lbtc"] = liquidOnChainService
+		}
+		err = metrics.Register(metrics.NewCollector(swapService, wallets))
+		if err != nil {
+			return err
+		}
+		go func() {
+			err := metrics.ListenAndServe(config.MetricsHost)
+			if err != nil {
+				log.Infof("metrics server stopped: %v", err)
+			}
+		}()
+		log.Infof("Metrics listening on %v", config.MetricsHost)
+	}
+
 	log.Infof("peerswap initialized")
 
 	// Wait for context to finish up
@@ -432,6 +613,18 @@ func getElementsClient(li *glightning.Lightning, config *clightning.Config) (*ge
 
 	return elementsCli, nil
 }
+
+// getElementsRawRpc returns a bitcoind rpc client that is connected to
+// elementsd.
+func getElementsRawRpc(config *clightning.Config) (*gbitcoin.Bitcoin, error) {
+	elementsRawRpc := gbitcoin.NewBitcoin(config.Liquid.RpcUser, config.Liquid.RpcPassword)
+	elementsRawRpc.SetTimeout(10)
+	err := elementsRawRpc.StartUp(config.Liquid.RpcHost, "", config.Liquid.RpcPort)
+	if err != nil {
+		return nil, err
+	}
+	return elementsRawRpc, nil
+}
 func getBitcoinClient(li *glightning.Lightning, pluginConfig *clightning.Config) (*gbitcoin.Bitcoin, error) {
 	rpcUser := pluginConfig.Bitcoin.RpcUser
 	rpcPassword := pluginConfig.Bitcoin.RpcPassword
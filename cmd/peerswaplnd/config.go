@@ -7,15 +7,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
-)
-
-type LogLevel uint8
-
-const (
-	LOGLEVEL_INFO = LogLevel(iota + 1)
-	LOGLEVEL_DEBUG
+	"github.com/elementsproject/peerswap/log"
+	"github.com/elementsproject/peerswap/swap"
 )
 
 var (
@@ -29,19 +25,42 @@ var (
 	DefaultDatadir        = btcutil.AppDataDir("peerswap", false)
 	DefaultLiquidwallet   = "swap"
 	DefaultBitcoinEnabled = true
-	DefaultLogLevel       = LOGLEVEL_DEBUG
+	DefaultLogLevel       = log.LevelDebug
 	DefaultPolicyFile     = filepath.Join(DefaultDatadir, "policy.conf")
 
+	DefaultRequestedSwapsRetentionDays uint = swap.DefaultRequestedSwapsRetentionDays
+	DefaultRequestedSwapsMaxPerPeer    uint = swap.DefaultRequestedSwapsMaxPerPeer
+
+	DefaultTlsCertFilename = "tls.cert"
+	DefaultTlsKeyFilename  = "tls.key"
+
 	defaultLndDir = btcutil.AppDataDir("lnd", false)
 )
 
 type PeerSwapConfig struct {
-	Host       string   `long:"host" description:"host to listen on for grpc connections"`
-	RestHost   string   `long:"resthost" description:"host to listen for rest connection"`
-	ConfigFile string   `long:"configfile" description:"path to configfile"`
-	PolicyFile string   `long:"policyfile" description:"path to policyfile"`
-	DataDir    string   `long:"datadir" description:"peerswap datadir"`
-	LogLevel   LogLevel `long:"loglevel" description:"loglevel (1=Info, 2=Debug)"`
+	Host        string    `long:"host" description:"host to listen on for grpc connections"`
+	RestHost    string    `long:"resthost" description:"host to listen for rest connection"`
+	MetricsHost string    `long:"metricshost" description:"host to serve prometheus metrics on, disabled if empty"`
+	ConfigFile  string    `long:"configfile" description:"path to configfile"`
+	PolicyFile  string    `long:"policyfile" description:"path to policyfile"`
+	DataDir     string    `long:"datadir" description:"peerswap datadir"`
+	LogLevel    log.Level `long:"loglevel" description:"loglevel (error, warn, info, debug, trace)"`
+	LogJson     bool      `long:"logjson" description:"write log entries as json objects"`
+
+	RequestedSwapsRetentionDays uint `long:"requestedswapsretentiondays" description:"days to keep rejected swap requests of peers, 0 keeps them forever"`
+	RequestedSwapsMaxPerPeer    uint `long:"requestedswapsmaxperpeer" description:"number of rejected swap requests to keep per peer, at most 1000"`
+
+	BitcoinClaimDescriptor string `long:"bitcoinclaimdescriptor" description:"wpkh descriptor or xpub whose addresses bitcoin swap-outs can be claimed to"`
+	LiquidClaimDescriptor  string `long:"liquidclaimdescriptor" description:"ct(slip77(...),elwpkh(...)) descriptor whose addresses liquid swap-outs can be claimed to"`
+
+	BitcoinClaimBatching       bool          `long:"bitcoinclaimbatching" description:"queue bitcoin claims that are not time critical and sweep them in a single tx, liquid claims are not batched"`
+	BitcoinClaimBatchMaxDelay  time.Duration `long:"bitcoinclaimbatchmaxdelay" description:"longest time a bitcoin claim is queued before the batch is swept"`
+	BitcoinClaimBatchCsvMargin uint32        `long:"bitcoinclaimbatchcsvmargin" description:"blocks before the csv limit of the opening tx at which a bitcoin claim is no longer queued"`
+
+	TlsCertPath     string   `long:"tlscertpath" description:"path to the tls certificate of the grpc and rest interface, created if missing"`
+	TlsKeyPath      string   `long:"tlskeypath" description:"path to the tls key of the grpc and rest interface, created if missing"`
+	TlsExtraIPs     []string `long:"tlsextraip" description:"additional ip address for the generated tls certificate, can be set multiple times"`
+	TlsExtraDomains []string `long:"tlsextradomain" description:"additional domain for the generated tls certificate, can be set multiple times"`
 
 	LndConfig      *LndConfig     `group:"Lnd Grpc config" namespace:"lnd"`
 	ElementsConfig *OnchainConfig `group:"Elements Rpc Config" namespace:"elementsd"`
@@ -68,6 +87,12 @@ func (p *PeerSwapConfig) String() string {
 }
 
 func (p *PeerSwapConfig) Validate() error {
+	if p.TlsCertPath == "" {
+		p.TlsCertPath = filepath.Join(p.DataDir, DefaultTlsCertFilename)
+	}
+	if p.TlsKeyPath == "" {
+		p.TlsKeyPath = filepath.Join(p.DataDir, DefaultTlsKeyFilename)
+	}
 	if p.ElementsConfig.RpcHost != "" {
 		err := p.ElementsConfig.Validate()
 		if err != nil {
@@ -86,6 +111,10 @@ type OnchainConfig struct {
 	RpcHost           string `long:"rpchost" description:"host to connect to"`
 	RpcPort           uint   `long:"rpcport" description:"port to connect to"`
 	RpcWallet         string `long:"rpcwallet" description:"wallet to use for swaps (elements only)"`
+	ZmqPubRawBlock    string `long:"zmqpubrawblock" description:"zmq endpoint for raw block notifications, e.g. tcp://127.0.0.1:28332"`
+	ZmqPubHashBlock   string `long:"zmqpubhashblock" description:"zmq endpoint for block hash notifications"`
+	ZmqPubRawTx       string `long:"zmqpubrawtx" description:"zmq endpoint for raw transaction notifications"`
+	EsploraUrl        string `long:"esploraurl" description:"esplora api to watch transactions and estimate fees with instead of the node, e.g. http://127.0.0.1:3000 (elements only)"`
 }
 
 func (o *OnchainConfig) Validate() error {
@@ -144,6 +173,12 @@ func DefaultConfig() *PeerSwapConfig {
 		BitcoinEnabled: DefaultBitcoinEnabled,
 		ElementsConfig: defaultLiquidConfig(),
 		LogLevel:       DefaultLogLevel,
+
+		RequestedSwapsRetentionDays: DefaultRequestedSwapsRetentionDays,
+		RequestedSwapsMaxPerPeer:    DefaultRequestedSwapsMaxPerPeer,
+
+		BitcoinClaimBatchMaxDelay:  swap.DefaultClaimBatchMaxDelay,
+		BitcoinClaimBatchCsvMargin: swap.DefaultClaimBatchCsvMargin,
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/elementsproject/peerswap/esplora"
 	"github.com/elementsproject/peerswap/isdev"
 	"github.com/elementsproject/peerswap/lnd"
 	"github.com/elementsproject/peerswap/log"
@@ -26,9 +27,12 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/elementsproject/glightning/gbitcoin"
 	"github.com/elementsproject/glightning/gelements"
+	"github.com/elementsproject/peerswap/cert"
 	"github.com/elementsproject/peerswap/cmd/peerswaplnd"
 	lnd_internal "github.com/elementsproject/peerswap/lnd"
+	"github.com/elementsproject/peerswap/macaroons"
 	"github.com/elementsproject/peerswap/messages"
+	"github.com/elementsproject/peerswap/metrics"
 	"github.com/elementsproject/peerswap/onchain"
 	"github.com/elementsproject/peerswap/peerswaprpc"
 	"github.com/elementsproject/peerswap/policy"
@@ -42,7 +46,7 @@ import (
 	"github.com/vulpemventures/go-elements/network"
 	"go.etcd.io/bbolt"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -216,7 +220,35 @@ func run() error {
 		}
 
 		// txwatcher
-		liquidTxWatcher = txwatcher.NewBlockchainRpcTxWatcher(ctx, txwatcher.NewElementsCli(liquidCli), onchain.LiquidConfs, onchain.LiquidCsv)
+		elementsRpc := txwatcher.NewElementsCli(liquidCli)
+		// gelements can not list the mempool, elementsd speaks the bitcoind
+		// rpc for the mempool lookups.
+		var liquidRawRpc *gbitcoin.Bitcoin
+		liquidRawRpc, err = getBitcoinClient(liquidConfig)
+		if err != nil {
+			return err
+		}
+		elementsRpc.EnableMempoolLookups(liquidRawRpc)
+		var liquidBlockchain txwatcher.BlockchainRpc = elementsRpc
+		var liquidEsplora *esplora.Client
+		if liquidConfig.EsploraUrl != "" {
+			log.Infof("Using esplora api at %s for liquid", liquidConfig.EsploraUrl)
+			liquidEsplora = esplora.NewClient(liquidConfig.EsploraUrl)
+			liquidBlockchain = txwatcher.NewEsploraBlockchainRpc(liquidEsplora, "lbtc")
+		}
+		liquidTxWatcher = txwatcher.NewBlockchainRpcTxWatcher(ctx, liquidBlockchain, onchain.LiquidConfs, onchain.LiquidCsv)
+		liquidZmq := &txwatcher.ZmqConfig{
+			RawBlock:  liquidConfig.ZmqPubRawBlock,
+			HashBlock: liquidConfig.ZmqPubHashBlock,
+			RawTx:     liquidConfig.ZmqPubRawTx,
+		}
+		if liquidZmq.Enabled() {
+			err = liquidTxWatcher.EnableZmq(liquidZmq)
+			if err != nil {
+				return err
+			}
+			log.Infof("Liquid zmq notifications enabled")
+		}
 
 		// LiquidChain
 		liquidChain, err := getLiquidChain(liquidCli)
@@ -224,6 +256,17 @@ func run() error {
 			return err
 		}
 		liquidOnChainService = onchain.NewLiquidOnChain(liquidCli, liquidRpcWallet, liquidChain)
+		if liquidEsplora != nil {
+			liquidEstimator, err := onchain.NewEsploraEstimator(
+				liquidEsplora,
+				onchain.LiquidFeePerKwFloor,
+				onchain.LiquidFeePerKwFloor,
+			)
+			if err != nil {
+				return err
+			}
+			liquidOnChainService.SetFeeEstimator(liquidEstimator)
+		}
 	} else {
 		log.Infof("Liquid swaps disabled")
 	}
@@ -280,19 +323,37 @@ func run() error {
 	if err != nil {
 		return err
 	}
-	requestedSwapStore, err := swap.NewRequestedSwapsStore(swapDb)
+	requestedSwapStore, err := swap.NewRequestedSwapsStore(swapDb, swap.RequestedSwapsRetention{
+		MaxAge:     time.Duration(cfg.RequestedSwapsRetentionDays) * 24 * time.Hour,
+		MaxPerPeer: int(cfg.RequestedSwapsMaxPerPeer),
+	})
 	if err != nil {
 		return err
 	}
+	if err := requestedSwapStore.Prune(); err != nil {
+		log.Infof("error pruning requested swaps: %v", err)
+	}
+
+	// The poll service tells which features our peers support.
+	pollStore, err := poll.NewStore(swapDb)
+	if err != nil {
+		return err
+	}
+	pollService := poll.NewService(1*time.Hour, 2*time.Hour, pollStore, lnd, pol, lnd, supportedAssets)
 
-	// Manager for send message retry.
-	mesmgr := messages.NewManager()
+	// Outbox to resend messages until they are acknowledged.
+	outboxStore, err := messages.NewOutboxStore(swapDb)
+	if err != nil {
+		return err
+	}
+	outbox := messages.NewOutbox(lnd, outboxStore, 10*time.Second, 10*time.Minute)
 
 	swapServices := swap.NewSwapServices(swapStore,
 		requestedSwapStore,
 		lnd,
 		lnd,
-		mesmgr,
+		outbox,
+		pollService,
 		pol,
 		cfg.BitcoinEnabled,
 		lnd,
@@ -304,6 +365,64 @@ func run() error {
 		liquidTxWatcher,
 	)
 	swapService := swap.NewSwapService(swapServices)
+	pollService.SetSwapLimits(swapService)
+	pollService.SetRateLimiter(swapService)
+
+	seenStore, err := messages.NewSeenStore(swapDb)
+	if err != nil {
+		return err
+	}
+	err = swapService.PersistSeenMessages(seenStore)
+	if err != nil {
+		return err
+	}
+
+	// Swap-outs can be claimed to fresh addresses of a descriptor.
+	if cfg.BitcoinClaimDescriptor != "" {
+		if bitcoinOnChainService == nil {
+			return errors.New("bitcoinclaimdescriptor is set but bitcoin swaps are disabled")
+		}
+		descriptor, err := bitcoinOnChainService.NewClaimDescriptor(cfg.BitcoinClaimDescriptor)
+		if err != nil {
+			return fmt.Errorf("bitcoinclaimdescriptor: %w", err)
+		}
+		claimAddresses, err := swap.NewClaimAddresses(swapDb, descriptor)
+		if err != nil {
+			return err
+		}
+		swapService.SetClaimAddresses("btc", claimAddresses)
+	}
+	if cfg.LiquidClaimDescriptor != "" {
+		if liquidOnChainService == nil {
+			return errors.New("liquidclaimdescriptor is set but liquid swaps are disabled")
+		}
+		descriptor, err := liquidOnChainService.NewClaimDescriptor(cfg.LiquidClaimDescriptor)
+		if err != nil {
+			return fmt.Errorf("liquidclaimdescriptor: %w", err)
+		}
+		claimAddresses, err := swap.NewClaimAddresses(swapDb, descriptor)
+		if err != nil {
+			return err
+		}
+		swapService.SetClaimAddresses("lbtc", claimAddresses)
+	}
+
+	// Bitcoin claims that are not time critical can be swept in batches.
+	// There is no sweeper for liquid, liquid claims are spent on their own.
+	var claimBatcher *swap.ClaimBatcher
+	if cfg.BitcoinClaimBatching {
+		if !cfg.BitcoinEnabled {
+			return errors.New("bitcoinclaimbatching is set but bitcoin swaps are disabled")
+		}
+		claimBatcher = swap.NewClaimBatcher(lnd, swap.ClaimBatchConfig{
+			MaxDelay:        cfg.BitcoinClaimBatchMaxDelay,
+			CsvSafetyMargin: cfg.BitcoinClaimBatchCsvMargin,
+		})
+		err = swapService.SetClaimBatcher("btc", claimBatcher)
+		if err != nil {
+			return err
+		}
+	}
 
 	if liquidTxWatcher != nil {
 		go func() {
@@ -335,11 +454,17 @@ func run() error {
 		return err
 	}
 
-	pollStore, err := poll.NewStore(swapDb)
+	if claimBatcher != nil {
+		claimBatcher.Start()
+		defer claimBatcher.Stop()
+	}
+
+	err = outbox.Start()
 	if err != nil {
 		return err
 	}
-	pollService := poll.NewService(1*time.Hour, 2*time.Hour, pollStore, lnd, pol, lnd, supportedAssets)
+	defer outbox.Stop()
+
 	pollService.Start()
 	defer pollService.Stop()
 
@@ -349,6 +474,17 @@ func run() error {
 		return err
 	}
 
+	// Pause the swap timeouts while a peer is offline and resume the swaps
+	// on reconnect.
+	err = peerListener.AddHandler(lnrpc.PeerEvent_PEER_OFFLINE, swapService.OnPeerOffline)
+	if err != nil {
+		return err
+	}
+	err = peerListener.AddHandler(lnrpc.PeerEvent_PEER_ONLINE, swapService.OnPeerOnline)
+	if err != nil {
+		return err
+	}
+
 	// Start internal lnd listener.
 	lnd.StartListening()
 
@@ -356,6 +492,7 @@ func run() error {
 	sp := swap.NewRequestedSwapsPrinter(requestedSwapStore)
 	peerswaprpcServer := peerswaprpc.NewPeerswapServer(
 		liquidRpcWallet,
+		lnd,
 		swapService,
 		sp,
 		pollService,
@@ -371,7 +508,28 @@ func run() error {
 	}
 	defer lis.Close()
 
-	grpcSrv := grpc.NewServer()
+	// Mint the default macaroons on first start and check the macaroon of
+	// every rpc call.
+	macaroonService, err := macaroons.NewService(swapDb)
+	if err != nil {
+		return err
+	}
+	err = macaroonService.WriteDefaultMacaroons(cfg.DataDir)
+	if err != nil {
+		return err
+	}
+
+	// Serve grpc and rest over tls, the self-signed certificate is created
+	// on first start.
+	tlsKeyPair, err := cert.LoadOrCreate(cfg.TlsCertPath, cfg.TlsKeyPath, cfg.TlsExtraIPs, cfg.TlsExtraDomains)
+	if err != nil {
+		return err
+	}
+
+	grpcSrv := grpc.NewServer(
+		grpc.Creds(credentials.NewServerTLSFromCert(&tlsKeyPair)),
+		grpc.UnaryInterceptor(macaroonService.UnaryServerInterceptor(peerswaprpc.RPCPermissions)),
+	)
 
 	peerswaprpc.RegisterPeerSwapServer(grpcSrv, peerswaprpcServer)
 
@@ -395,13 +553,19 @@ func run() error {
 				},
 			}),
 		)
-		opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-		err := peerswaprpc.RegisterPeerSwapHandlerFromEndpoint(ctx, mux, cfg.Host, opts)
+		// The gateway always dials the local grpc server, localhost is part
+		// of every generated certificate.
+		creds, err := credentials.NewClientTLSFromFile(cfg.TlsCertPath, "localhost")
+		if err != nil {
+			return err
+		}
+		opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+		err = peerswaprpc.RegisterPeerSwapHandlerFromEndpoint(ctx, mux, cfg.Host, opts)
 		if err != nil {
 			return err
 		}
 		go func() {
-			err := http.ListenAndServe(cfg.RestHost, mux)
+			err := http.ListenAndServeTLS(cfg.RestHost, cfg.TlsCertPath, cfg.TlsKeyPath, mux)
 			if err != nil {
 				core_log.Fatal(err)
 			}
@@ -409,6 +573,27 @@ func run() error {
 
 		log.Infof("peerswapd rest listening on %v", cfg.RestHost)
 	}
+	if cfg.MetricsHost != "" {
+		wallets := map[string]metrics.BalanceGetter{}
+		if cfg.BitcoinEnabled {
+			wallets["btc"] = lnd
+		}
+		if cfg.LiquidEnabled {
+			wallets["lbtc"] = liquidOnChainService
+		}
+		err = metrics.Register(metrics.NewCollector(swapService, wallets))
+		if err != nil {
+			return err
+		}
+		go func() {
+			err := metrics.ListenAndServe(cfg.MetricsHost)
+			if err != nil {
+				core_log.Fatal(err)
+			}
+		}()
+
+		log.Infof("peerswapd metrics listening on %v", cfg.MetricsHost)
+	}
 	<-shutdown
 	return nil
 }
@@ -526,11 +711,9 @@ func checkLndVersion(fullVersionString string) error {
 	return nil
 }
 
-type LndLogger struct {
-	loglevel peerswaplnd.LogLevel
-}
-
-func NewLndLogger(cfg *peerswaplnd.PeerSwapConfig) (*LndLogger, func() error, error) {
+// NewLndLogger returns a logger that writes to stdout and the log file in the
+// data dir.
+func NewLndLogger(cfg *peerswaplnd.PeerSwapConfig) (*log.WriterLogger, func() error, error) {
 	logFile, err := os.OpenFile(filepath.Join(cfg.DataDir, "log"), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
 		return nil, nil, err
@@ -539,17 +722,7 @@ func NewLndLogger(cfg *peerswaplnd.PeerSwapConfig) (*LndLogger, func() error, er
 	core_log.SetFlags(core_log.LstdFlags | core_log.LUTC)
 	core_log.SetOutput(w)
 
-	return &LndLogger{loglevel: cfg.LogLevel}, logFile.Close, nil
-}
-
-func (l *LndLogger) Infof(format string, v ...interface{}) {
-	core_log.Printf("[INFO] "+format, v...)
-}
-
-func (l *LndLogger) Debugf(format string, v ...interface{}) {
-	if l.loglevel == peerswaplnd.LOGLEVEL_DEBUG {
-		core_log.Printf("[DEBUG] "+format, v...)
-	}
+	return log.NewWriterLogger(w, cfg.LogLevel, cfg.LogJson), logFile.Close, nil
 }
 
 // waitForLndSynced waits until cln is synced to the blockchain and the network.
@@ -5,10 +5,16 @@ import (
 	"fmt"
 	log2 "log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/elementsproject/peerswap/cmd/peerswaplnd"
+	"github.com/elementsproject/peerswap/macaroons"
 	"github.com/elementsproject/peerswap/peerswaprpc"
 	"github.com/urfave/cli"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
@@ -25,13 +31,24 @@ func main() {
 			Value: "localhost:42069",
 			Usage: "peerswapd grpc address host:port",
 		},
+		cli.StringFlag{
+			Name:  "macaroonpath",
+			Value: filepath.Join(peerswaplnd.DefaultDatadir, macaroons.AdminMacaroonFile),
+			Usage: "path to the macaroon that is used to authenticate to peerswapd",
+		},
+		cli.StringFlag{
+			Name:  "tlscertpath",
+			Value: filepath.Join(peerswaplnd.DefaultDatadir, peerswaplnd.DefaultTlsCertFilename),
+			Usage: "path to the tls certificate of peerswapd",
+		},
 	}
 	app.Commands = []cli.Command{
-		swapOutCommand, swapInCommand, getSwapCommand, listSwapsCommand,
+		swapOutCommand, swapInCommand, swapInBatchCommand, submitOpeningTxCommand, getSwapCommand, listSwapsCommand,
 		listPeersCommand, reloadPolicyFileCommand, listRequestedSwapsCommand,
 		liquidGetBalanceCommand, liquidGetAddressCommand, liquidSendToAddressCommand,
+		btcGetBalanceCommand, btcGetAddressCommand, btcSendToAddressCommand, getBalancesCommand,
 		stopCommand, listActiveSwapsCommand, allowSwapRequestsCommand, addPeerCommand, removePeerCommand,
-		addSusPeerCommand, removeSusPeerCommand,
+		addSusPeerCommand, removeSusPeerCommand, drainCommand, peerHistoryCommand,
 	}
 	app.Version = fmt.Sprintf("commit: %s", GitCommit)
 	err := app.Run(os.Args)
@@ -61,7 +78,7 @@ var (
 		Name:     "id",
 		Required: true,
 	}
-	liquidAddressFlag = cli.StringFlag{
+	addressFlag = cli.StringFlag{
 		Name:     "address",
 		Required: true,
 	}
@@ -69,10 +86,52 @@ var (
 		Name:     "allow_swaps",
 		Required: true,
 	}
+	shutdownFlag = cli.BoolFlag{
+		Name:  "shutdown",
+		Usage: "stop the daemon once the last active swap completed",
+	}
 	pubkeyFlag = cli.StringFlag{
 		Name:     "peer_pubkey",
 		Required: true,
 	}
+	claimAddressFlag = cli.StringFlag{
+		Name:  "claim_address",
+		Usage: "address the swap is claimed to instead of a new wallet address",
+	}
+	claimToDescriptorFlag = cli.BoolFlag{
+		Name:  "claim_to_descriptor",
+		Usage: "claim the swap to a fresh address of the configured claim descriptor",
+	}
+	externalFundingFlag = cli.BoolFlag{
+		Name:  "external_funding",
+		Usage: "return an unsigned psbt of the opening tx that is funded and signed by an external wallet",
+	}
+	signedTxFlag = cli.StringFlag{
+		Name:     "signed_tx",
+		Usage:    "the signed psbt (pset on liquid) or the raw hex of the signed opening tx",
+		Required: true,
+	}
+	batchSwapFlag = cli.StringSliceFlag{
+		Name:     "swap",
+		Usage:    "a swap-in of the batch as channel_id:sat_amt, can be given multiple times",
+		Required: true,
+	}
+	forceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "skip the checks whether the peers run peerswap and support the swaps",
+	}
+	filterPubkeyFlag = cli.StringFlag{
+		Name:  "peer_pubkey",
+		Usage: "only list requests of this peer",
+	}
+	sinceFlag = cli.Int64Flag{
+		Name:  "since",
+		Usage: "only list requests received at or after this unix time",
+	}
+	untilFlag = cli.Int64Flag{
+		Name:  "until",
+		Usage: "only list requests received at or before this unix time",
+	}
 
 	swapOutCommand = cli.Command{
 		Name:  "swapout",
@@ -81,6 +140,8 @@ var (
 			satAmountFlag,
 			channelIdFlag,
 			assetFlag,
+			claimAddressFlag,
+			claimToDescriptorFlag,
 		},
 		Action: swapOut,
 	}
@@ -92,10 +153,32 @@ var (
 			satAmountFlag,
 			channelIdFlag,
 			assetFlag,
+			externalFundingFlag,
 		},
 		Action: swapIn,
 	}
 
+	swapInBatchCommand = cli.Command{
+		Name:  "swapinbatch",
+		Usage: "Perform swap-ins on several channels that are opened by a single onchain tx",
+		Flags: []cli.Flag{
+			batchSwapFlag,
+			assetFlag,
+			forceFlag,
+		},
+		Action: swapInBatch,
+	}
+
+	submitOpeningTxCommand = cli.Command{
+		Name:  "submitopeningtx",
+		Usage: "Broadcast the signed opening tx of an externally funded swap-in",
+		Flags: []cli.Flag{
+			swapIdFlag,
+			signedTxFlag,
+		},
+		Action: submitOpeningTx,
+	}
+
 	getSwapCommand = cli.Command{
 		Name:  "getswap",
 		Usage: "Get a swap by its id",
@@ -125,9 +208,13 @@ var (
 		Action: reloadPolicyFile,
 	}
 	listRequestedSwapsCommand = cli.Command{
-		Name:   "listswaprequests",
-		Usage:  "lists requested swaps by peers",
-		Flags:  []cli.Flag{},
+		Name:  "listswaprequests",
+		Usage: "lists requested swaps by peers",
+		Flags: []cli.Flag{
+			filterPubkeyFlag,
+			sinceFlag,
+			untilFlag,
+		},
 		Action: listRequestedSwaps,
 	}
 	liquidGetAddressCommand = cli.Command{
@@ -147,10 +234,37 @@ var (
 		Usage: "sends the sat amount to a lbtc address",
 		Flags: []cli.Flag{
 			satAmountFlag,
-			liquidAddressFlag,
+			addressFlag,
 		},
 		Action: liquidSendToAddress,
 	}
+	btcGetAddressCommand = cli.Command{
+		Name:   "btc-getaddress",
+		Usage:  "gets a new btc address of the lnd wallet",
+		Flags:  []cli.Flag{},
+		Action: btcGetAddress,
+	}
+	btcGetBalanceCommand = cli.Command{
+		Name:   "btc-getbalance",
+		Usage:  "gets the current btc balance of the lnd wallet",
+		Flags:  []cli.Flag{},
+		Action: btcGetBalance,
+	}
+	btcSendToAddressCommand = cli.Command{
+		Name:  "btc-sendtoaddress",
+		Usage: "sends the sat amount to a btc address",
+		Flags: []cli.Flag{
+			satAmountFlag,
+			addressFlag,
+		},
+		Action: btcSendToAddress,
+	}
+	getBalancesCommand = cli.Command{
+		Name:   "getbalances",
+		Usage:  "shows the btc and lbtc balances that are available for swaps",
+		Flags:  []cli.Flag{},
+		Action: getBalances,
+	}
 	listActiveSwapsCommand = cli.Command{
 		Name:   "listactiveswaps",
 		Usage:  "list active swaps",
@@ -180,6 +294,14 @@ var (
 		},
 		Action: removePeer,
 	}
+	peerHistoryCommand = cli.Command{
+		Name:  "peerhistory",
+		Usage: "Shows when a peer was seen, its uptime and the versions and assets it announced",
+		Flags: []cli.Flag{
+			pubkeyFlag,
+		},
+		Action: getPeerHistory,
+	}
 	addSusPeerCommand = cli.Command{
 		Name:  "addsuspeer",
 		Usage: "Adds a peer to the suspicious peer list",
@@ -202,6 +324,14 @@ var (
 		Flags:  []cli.Flag{},
 		Action: stopPeerswap,
 	}
+	drainCommand = cli.Command{
+		Name:  "drain",
+		Usage: "stops new swaps and shows the active swaps with their completion heights (used for updating)",
+		Flags: []cli.Flag{
+			shutdownFlag,
+		},
+		Action: drain,
+	}
 )
 
 func swapIn(ctx *cli.Context) error {
@@ -213,9 +343,67 @@ func swapIn(ctx *cli.Context) error {
 	defer cleanup()
 
 	res, err := client.SwapIn(context.Background(), &peerswaprpc.SwapInRequest{
-		ChannelId:  ctx.Uint64(channelIdFlag.Name),
-		SwapAmount: ctx.Uint64(satAmountFlag.Name),
-		Asset:      ctx.String(assetFlag.Name),
+		ChannelId:       ctx.Uint64(channelIdFlag.Name),
+		SwapAmount:      ctx.Uint64(satAmountFlag.Name),
+		Asset:           ctx.String(assetFlag.Name),
+		ExternalFunding: ctx.Bool(externalFundingFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
+func swapInBatch(ctx *cli.Context) error {
+	var swaps []*peerswaprpc.SwapInBatchEntry
+	for _, s := range ctx.StringSlice(batchSwapFlag.Name) {
+		parts := strings.Split(s, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid swap %s, expected channel_id:sat_amt", s)
+		}
+		channelId, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid channel id %s: %v", parts[0], err)
+		}
+		amt, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount %s: %v", parts[1], err)
+		}
+		swaps = append(swaps, &peerswaprpc.SwapInBatchEntry{
+			ChannelId:  channelId,
+			SwapAmount: amt,
+		})
+	}
+
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.SwapInBatch(context.Background(), &peerswaprpc.SwapInBatchRequest{
+		Swaps: swaps,
+		Asset: ctx.String(assetFlag.Name),
+		Force: ctx.Bool(forceFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
+func submitOpeningTx(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.SubmitOpeningTx(context.Background(), &peerswaprpc.SubmitOpeningTxRequest{
+		SwapId:   ctx.String(swapIdFlag.Name),
+		SignedTx: ctx.String(signedTxFlag.Name),
 	})
 	if err != nil {
 		return err
@@ -233,9 +421,11 @@ func swapOut(ctx *cli.Context) error {
 	defer cleanup()
 
 	res, err := client.SwapOut(context.Background(), &peerswaprpc.SwapOutRequest{
-		ChannelId:  ctx.Uint64(channelIdFlag.Name),
-		SwapAmount: ctx.Uint64(satAmountFlag.Name),
-		Asset:      ctx.String(assetFlag.Name),
+		ChannelId:         ctx.Uint64(channelIdFlag.Name),
+		SwapAmount:        ctx.Uint64(satAmountFlag.Name),
+		Asset:             ctx.String(assetFlag.Name),
+		ClaimAddress:      ctx.String(claimAddressFlag.Name),
+		ClaimToDescriptor: ctx.Bool(claimToDescriptorFlag.Name),
 	})
 	if err != nil {
 		return err
@@ -313,7 +503,11 @@ func listRequestedSwaps(ctx *cli.Context) error {
 	}
 	defer cleanup()
 
-	res, err := client.ListRequestedSwaps(context.Background(), &peerswaprpc.ListRequestedSwapsRequest{})
+	res, err := client.ListRequestedSwaps(context.Background(), &peerswaprpc.ListRequestedSwapsRequest{
+		PeerPubkey: ctx.String(filterPubkeyFlag.Name),
+		Since:      ctx.Int64(sinceFlag.Name),
+		Until:      ctx.Int64(untilFlag.Name),
+	})
 	if err != nil {
 		return err
 	}
@@ -359,7 +553,7 @@ func liquidSendToAddress(ctx *cli.Context) error {
 	defer cleanup()
 
 	res, err := client.LiquidSendToAddress(context.Background(), &peerswaprpc.SendToAddressRequest{
-		Address:   ctx.String(liquidAddressFlag.Name),
+		Address:   ctx.String(addressFlag.Name),
 		SatAmount: ctx.Uint64(satAmountFlag.Name),
 	})
 	if err != nil {
@@ -369,6 +563,69 @@ func liquidSendToAddress(ctx *cli.Context) error {
 	return nil
 }
 
+func btcGetAddress(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.BtcGetAddress(context.Background(), &peerswaprpc.GetAddressRequest{})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
+func btcGetBalance(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.BtcGetBalance(context.Background(), &peerswaprpc.GetBalanceRequest{})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
+func btcSendToAddress(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.BtcSendToAddress(context.Background(), &peerswaprpc.SendToAddressRequest{
+		Address:   ctx.String(addressFlag.Name),
+		SatAmount: ctx.Uint64(satAmountFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
+func getBalances(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.GetBalances(context.Background(), &peerswaprpc.GetBalancesRequest{})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
 func listActiveSwaps(ctx *cli.Context) error {
 	client, cleanup, err := getClient(ctx)
 	if err != nil {
@@ -418,6 +675,22 @@ func addPeer(ctx *cli.Context) error {
 	return nil
 }
 
+func getPeerHistory(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	res, err := client.GetPeerHistory(context.Background(), &peerswaprpc.GetPeerHistoryRequest{
+		PeerPubkey: ctx.String(pubkeyFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
 func removePeer(ctx *cli.Context) error {
 	client, cleanup, err := getClient(ctx)
 	if err != nil {
@@ -481,10 +754,37 @@ func stopPeerswap(ctx *cli.Context) error {
 	return nil
 }
 
+func drain(ctx *cli.Context) error {
+	client, cleanup, err := getClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	res, err := client.Drain(context.Background(), &peerswaprpc.DrainRequest{
+		Shutdown: ctx.Bool(shutdownFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	printRespJSON(res)
+	return nil
+}
+
 func getClient(ctx *cli.Context) (peerswaprpc.PeerSwapClient, func(), error) {
 	rpcServer := ctx.GlobalString("rpchost")
 
-	conn, err := getClientConn(rpcServer)
+	cred, err := macaroons.NewCredentialFromFile(ctx.GlobalString("macaroonpath"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsCreds, err := credentials.NewClientTLSFromFile(ctx.GlobalString("tlscertpath"), "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := getClientConn(rpcServer, tlsCreds, cred)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -494,13 +794,14 @@ func getClient(ctx *cli.Context) (peerswaprpc.PeerSwapClient, func(), error) {
 	return psClient, cleanup, nil
 }
 
-func getClientConn(address string) (*grpc.ClientConn,
+func getClientConn(address string, tlsCreds credentials.TransportCredentials, cred *macaroons.Credential) (*grpc.ClientConn,
 	error) {
 
 	maxMsgRecvSize := grpc.MaxCallRecvMsgSize(1 * 1024 * 1024 * 200)
 	opts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(maxMsgRecvSize),
-		grpc.WithInsecure(),
+		grpc.WithTransportCredentials(tlsCreds),
+		grpc.WithPerRPCCredentials(cred),
 	}
 
 	conn, err := grpc.Dial(address, opts...)
@@ -0,0 +1,181 @@
+// Package esplora implements a client for the Esplora http api that is served
+// by blockstream's electrs and mempool.space. It is used as a lightweight
+// alternative to a full bitcoind or elementsd backend.
+package esplora
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// ErrNotFound is returned if the requested tx or block is unknown to the
+// esplora backend.
+var ErrNotFound = errors.New("not found")
+
+// Client queries an esplora http api.
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the esplora api at `baseUrl`, e.g.
+// `http://127.0.0.1:3000` or `https://blockstream.info/liquid/api`.
+func NewClient(baseUrl string) *Client {
+	return &Client{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// TxStatus is the confirmation status of a transaction.
+type TxStatus struct {
+	Confirmed   bool   `json:"confirmed"`
+	BlockHeight uint32 `json:"block_height"`
+	BlockHash   string `json:"block_hash"`
+}
+
+// TxOut is an output of a transaction. Value is nil for confidential
+// outputs.
+type TxOut struct {
+	ScriptPubKey string  `json:"scriptpubkey"`
+	Value        *uint64 `json:"value"`
+}
+
+// Tx is a transaction as returned by `GET /tx/:txid`.
+type Tx struct {
+	TxId   string   `json:"txid"`
+	Vout   []TxOut  `json:"vout"`
+	Status TxStatus `json:"status"`
+}
+
+// Outspend is the spending status of an output. Status is the confirmation
+// status of the spending tx.
+type Outspend struct {
+	Spent  bool     `json:"spent"`
+	TxId   string   `json:"txid"`
+	Status TxStatus `json:"status"`
+}
+
+// Block is a block header as returned by `GET /block/:hash`.
+type Block struct {
+	Id     string `json:"id"`
+	Height uint32 `json:"height"`
+}
+
+// GetTipHeight returns the height of the best block.
+func (c *Client) GetTipHeight() (uint64, error) {
+	res, err := c.getText("/blocks/tip/height")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(res, 10, 64)
+}
+
+// GetTipHash returns the hash of the best block.
+func (c *Client) GetTipHash() (string, error) {
+	return c.getText("/blocks/tip/hash")
+}
+
+// GetBlockHash returns the hash of the block at `height` in the best chain.
+func (c *Client) GetBlockHash(height uint32) (string, error) {
+	return c.getText(fmt.Sprintf("/block-height/%d", height))
+}
+
+// GetBlock returns the block with `blockHash`.
+func (c *Client) GetBlock(blockHash string) (*Block, error) {
+	var b Block
+	err := c.getJson("/block/"+blockHash, &b)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetTx returns the transaction with `txId`.
+func (c *Client) GetTx(txId string) (*Tx, error) {
+	var tx Tx
+	err := c.getJson("/tx/"+txId, &tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// GetTxHex returns the raw transaction with `txId` hex encoded.
+func (c *Client) GetTxHex(txId string) (string, error) {
+	return c.getText("/tx/" + txId + "/hex")
+}
+
+// GetOutspend returns the spending status of the output `txId:vout`.
+func (c *Client) GetOutspend(txId string, vout uint32) (*Outspend, error) {
+	var o Outspend
+	err := c.getJson(fmt.Sprintf("/tx/%s/outspend/%d", txId, vout), &o)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GetFeeEstimates returns the fee estimates in sat/vb by confirmation target
+// in blocks.
+func (c *Client) GetFeeEstimates() (map[uint32]float64, error) {
+	var res map[string]float64
+	err := c.getJson("/fee-estimates", &res)
+	if err != nil {
+		return nil, err
+	}
+	estimates := make(map[uint32]float64, len(res))
+	for k, v := range res {
+		target, err := strconv.ParseUint(k, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee estimate target %s: %w", k, err)
+		}
+		estimates[uint32(target)] = v
+	}
+	return estimates, nil
+}
+
+func (c *Client) getJson(path string, v interface{}) error {
+	body, err := c.get(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (c *Client) getText(path string) (string, error) {
+	body, err := c.get(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func (c *Client) get(path string) ([]byte, error) {
+	res, err := c.httpClient.Get(c.baseUrl + path)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("esplora GET %s: %w", path, ErrNotFound)
+	case res.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("esplora GET %s: %s: %s", path, res.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,65 @@
+package esplora
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubServer(t *testing.T, routes map[string]string) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.Error(w, "Transaction not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL + "/")
+}
+
+func Test_Client(t *testing.T) {
+	c := newStubServer(t, map[string]string{
+		"/blocks/tip/height":  "105\n",
+		"/block-height/100":   "blockhash100",
+		"/block/blockhash100": `{"id":"blockhash100","height":100}`,
+		"/tx/txid":            `{"txid":"txid","vout":[{"scriptpubkey":"0020ab","value":5000},{"scriptpubkey":"0020cd"}],"status":{"confirmed":true,"block_height":100,"block_hash":"blockhash100"}}`,
+		"/tx/txid/outspend/1": `{"spent":true,"txid":"spender"}`,
+		"/fee-estimates":      `{"1":20.5,"6":10,"144":1.1}`,
+	})
+
+	height, err := c.GetTipHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 105, height)
+
+	hash, err := c.GetBlockHash(100)
+	require.NoError(t, err)
+	assert.Equal(t, "blockhash100", hash)
+
+	block, err := c.GetBlock(hash)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, block.Height)
+
+	tx, err := c.GetTx("txid")
+	require.NoError(t, err)
+	assert.True(t, tx.Status.Confirmed)
+	require.Len(t, tx.Vout, 2)
+	assert.EqualValues(t, 5000, *tx.Vout[0].Value)
+	assert.Nil(t, tx.Vout[1].Value)
+
+	outspend, err := c.GetOutspend("txid", 1)
+	require.NoError(t, err)
+	assert.True(t, outspend.Spent)
+	assert.Equal(t, "spender", outspend.TxId)
+
+	estimates, err := c.GetFeeEstimates()
+	require.NoError(t, err)
+	assert.Equal(t, map[uint32]float64{1: 20.5, 6: 10, 144: 1.1}, estimates)
+
+	_, err = c.GetTx("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
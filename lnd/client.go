@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -283,6 +284,30 @@ func (l *Client) GetPeers() []string {
 	return peerlist
 }
 
+// ConnectPeer connects to the peer on one of the addresses that are announced
+// in the graph.
+func (l *Client) ConnectPeer(peerId string) error {
+	info, err := l.lndClient.GetNodeInfo(l.ctx, &lnrpc.NodeInfoRequest{PubKey: peerId})
+	if err != nil {
+		return err
+	}
+	if info.Node == nil || len(info.Node.Addresses) == 0 {
+		return fmt.Errorf("no known address for peer %s", peerId)
+	}
+
+	for _, addr := range info.Node.Addresses {
+		_, err = l.lndClient.ConnectPeer(l.ctx, &lnrpc.ConnectPeerRequest{
+			Addr:    &lnrpc.LightningAddress{Pubkey: peerId, Host: addr.Addr},
+			Timeout: 30,
+		})
+		if err == nil || strings.Contains(err.Error(), "already connected") {
+			return nil
+		}
+		log.Debugf("could not connect to %s@%s: %v", peerId, addr.Addr, err)
+	}
+	return err
+}
+
 func LndShortChannelIdToCLShortChannelId(lndCI lnwire.ShortChannelID) string {
 	return fmt.Sprintf("%dx%dx%d", lndCI.BlockHeight, lndCI.TxIndex, lndCI.TxPosition)
 }
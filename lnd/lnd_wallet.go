@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/wire"
@@ -64,6 +65,80 @@ func (l *Client) CreateOpeningTransaction(swapParams *swap.OpeningParams) (unpre
 	return rawTxHex, fee, vout, nil
 }
 
+func (l *Client) CreateBatchOpeningTransaction(swapParams []*swap.OpeningParams) (string, string, []uint32, error) {
+	outputs := map[string]uint64{}
+	for _, params := range swapParams {
+		addr, err := l.bitcoinOnChain.CreateOpeningAddress(params, onchain.BitcoinCsv)
+		if err != nil {
+			return "", "", nil, err
+		}
+		outputs[addr] = params.Amount
+	}
+
+	fundRes, err := l.walletClient.FundPsbt(l.ctx, &walletrpc.FundPsbtRequest{
+		Template: &walletrpc.FundPsbtRequest_Raw{Raw: &walletrpc.TxTemplate{Outputs: outputs}},
+		Fees:     &walletrpc.FundPsbtRequest_TargetConf{TargetConf: 3},
+	})
+	if err != nil {
+		return "", "", nil, err
+	}
+	finalizeRes, err := l.walletClient.FinalizePsbt(l.ctx, &walletrpc.FinalizePsbtRequest{
+		FundedPsbt: fundRes.FundedPsbt,
+	})
+	if err != nil {
+		return "", "", nil, err
+	}
+	rawTxHex := hex.EncodeToString(finalizeRes.RawFinalTx)
+	txId, err := l.bitcoinOnChain.TxIdFromHex(rawTxHex)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	vouts := make([]uint32, len(swapParams))
+	for i, params := range swapParams {
+		ok, vout, err := l.bitcoinOnChain.GetVoutAndVerify(rawTxHex, params)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if !ok {
+			return "", "", nil, errors.New("opening output not found in funded tx")
+		}
+		vouts[i] = vout
+	}
+	return txId, rawTxHex, vouts, nil
+}
+
+// lndInternalLockId is the id of the leases lnd takes on the inputs of a
+// funded psbt.
+var lndInternalLockId, _ = hex.DecodeString("ede19a92ed321a4705f8a1cccc1d4f6182545d4bb4fae08bd5937831b7e38f98")
+
+// ReleaseBatchOpeningTransaction releases the leases that FundPsbt took on
+// the inputs of the tx.
+func (l *Client) ReleaseBatchOpeningTransaction(txHex string) error {
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		return err
+	}
+	tx := wire.NewMsgTx(2)
+	err = tx.Deserialize(bytes.NewReader(txBytes))
+	if err != nil {
+		return err
+	}
+	for _, in := range tx.TxIn {
+		_, err = l.walletClient.ReleaseOutput(l.ctx, &walletrpc.ReleaseOutputRequest{
+			Id: lndInternalLockId,
+			Outpoint: &lnrpc.OutPoint{
+				TxidStr:     in.PreviousOutPoint.Hash.String(),
+				OutputIndex: in.PreviousOutPoint.Index,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (l *Client) BroadcastOpeningTx(unpreparedTxHex string) (txId, txHex string, error error) {
 	txBytes, err := hex.DecodeString(unpreparedTxHex)
 	if err != nil {
@@ -82,13 +157,26 @@ func (l *Client) BroadcastOpeningTx(unpreparedTxHex string) (txId, txHex string,
 	return openingTx.TxHash().String(), unpreparedTxHex, nil
 }
 
+func (l *Client) CreateOpeningPsbt(swapParams *swap.OpeningParams) (string, error) {
+	return l.bitcoinOnChain.CreateOpeningPsbt(swapParams)
+}
+
+func (l *Client) ExtractOpeningTx(swapParams *swap.OpeningParams, signedTx string) (string, uint32, error) {
+	return l.bitcoinOnChain.ExtractOpeningTx(swapParams, signedTx)
+}
+
+func (l *Client) BroadcastTx(txHex string) (string, error) {
+	txId, _, err := l.BroadcastOpeningTx(txHex)
+	return txId, err
+}
+
 func (l *Client) CreatePreimageSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (string, string, error) {
 	_, vout, err := l.bitcoinOnChain.GetVoutAndVerify(claimParams.OpeningTxHex, swapParams)
 	if err != nil {
 		return "", "", err
 	}
 
-	newAddr, err := l.NewAddress()
+	newAddr, err := l.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -125,11 +213,39 @@ func (l *Client) CreatePreimageSpendingTransaction(swapParams *swap.OpeningParam
 	return tx.TxHash().String(), txHex, nil
 }
 
-func (l *Client) CreateCsvSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (string, string, error) {
+// SweepPreimageClaims spends the opening outputs of the claims with their
+// preimages in a single tx to a new wallet address.
+func (l *Client) SweepPreimageClaims(claims []*swap.PreimageClaim) (string, string, error) {
 	newAddr, err := l.NewAddress()
 	if err != nil {
 		return "", "", err
 	}
+
+	tx, err := l.bitcoinOnChain.CreatePreimageSweepTransaction(claims, newAddr)
+	if err != nil {
+		return "", "", err
+	}
+
+	bytesBuffer := new(bytes.Buffer)
+	err = tx.Serialize(bytesBuffer)
+	if err != nil {
+		return "", "", err
+	}
+
+	txHex := hex.EncodeToString(bytesBuffer.Bytes())
+
+	_, err = l.walletClient.PublishTransaction(l.ctx, &walletrpc.Transaction{TxHex: bytesBuffer.Bytes()})
+	if err != nil {
+		return "", "", err
+	}
+	return tx.TxHash().String(), txHex, nil
+}
+
+func (l *Client) CreateCsvSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (string, string, error) {
+	newAddr, err := l.claimAddress(claimParams)
+	if err != nil {
+		return "", "", err
+	}
 	_, vout, err := l.bitcoinOnChain.GetVoutAndVerify(claimParams.OpeningTxHex, swapParams)
 	if err != nil {
 		return "", "", err
@@ -163,7 +279,7 @@ func (l *Client) CreateCsvSpendingTransaction(swapParams *swap.OpeningParams, cl
 }
 
 func (l *Client) CreateCoopSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams, takerSigner swap.Signer) (txId, txHex string, error error) {
-	refundAddr, err := l.NewAddress()
+	refundAddr, err := l.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -220,6 +336,19 @@ func (l *Client) GetOutputScript(params *swap.OpeningParams) ([]byte, error) {
 	return l.bitcoinOnChain.GetOutputScript(params)
 }
 
+// claimAddress returns the address the swap is claimed to, a new wallet
+// address if none is set.
+func (l *Client) claimAddress(claimParams *swap.ClaimParams) (string, error) {
+	if claimParams.ClaimAddress != "" {
+		return claimParams.ClaimAddress, nil
+	}
+	return l.NewAddress()
+}
+
+func (l *Client) ValidateAddress(address string) error {
+	return l.bitcoinOnChain.ValidateAddress(address)
+}
+
 func (l *Client) NewAddress() (string, error) {
 	res, err := l.lndClient.NewAddress(l.ctx, &lnrpc.NewAddressRequest{Type: lnrpc.AddressType_WITNESS_PUBKEY_HASH})
 	if err != nil {
@@ -228,6 +357,16 @@ func (l *Client) NewAddress() (string, error) {
 	return res.Address, nil
 }
 
+// SendToAddress sends `amountSat` from the lnd wallet to `address` and
+// returns the txid.
+func (l *Client) SendToAddress(address string, amountSat uint64) (string, error) {
+	res, err := l.lndClient.SendCoins(l.ctx, &lnrpc.SendCoinsRequest{Addr: address, Amount: int64(amountSat)})
+	if err != nil {
+		return "", err
+	}
+	return res.Txid, nil
+}
+
 func (l *Client) GetRefundFee() (uint64, error) {
 	return l.bitcoinOnChain.GetFee(250)
 }
@@ -39,6 +39,8 @@ type TxWatcher struct {
 
 	confirmationCallback func(swapId, txHex string) error
 	csvPassedCallback    func(swapId string) error
+	reorgCallback        func(swapId string) error
+	spendCallback        func(swapId, txHex string) error
 
 	confirmationWatchers map[string]bool
 	waitForCsvWatchers   map[string]bool
@@ -85,8 +87,11 @@ func (t *TxWatcher) Stop() error {
 	return nil
 }
 
-func (t *TxWatcher) addTxWatcher(ctx context.Context, swapId string, txId string, numConfs, heightHint uint32, script []byte) (
-	chan confirmationEvent, chan error, error) {
+// addTxWatcher subscribes to the confirmation of a tx. If onReorg is set the
+// subscription is kept open after the confirmation and onReorg is called if
+// the tx is reorged out of the chain before the context is canceled.
+func (t *TxWatcher) addTxWatcher(ctx context.Context, swapId string, txId string, numConfs, heightHint uint32, script []byte,
+	onReorg func()) (chan confirmationEvent, chan error, error) {
 
 	txIdHash, err := chainhash.NewHashFromStr(txId)
 	if err != nil {
@@ -113,29 +118,42 @@ func (t *TxWatcher) addTxWatcher(ctx context.Context, swapId string, txId string
 	go func() {
 		defer t.wg.Done()
 
+		var confirmed bool
 		for {
 			res, err := stream.Recv()
 			if err == io.EOF {
 				// EOF means the stream was closed and returned.
 				return
 			} else if err != nil {
-				errChan <- err
+				// The channels might be closed already once the
+				// confirmation was handled.
+				if !confirmed {
+					errChan <- err
+				}
 				return
 			}
 
 			switch event := res.Event.(type) {
 			case *chainrpc.ConfEvent_Conf:
+				if confirmed {
+					continue
+				}
 				confChan <- confirmationEvent{
 					swapId:      swapId,
 					rawTx:       event.Conf.RawTx,
 					blockHeight: event.Conf.BlockHeight,
 				}
-				return
+				if onReorg == nil {
+					return
+				}
+				confirmed = true
 
 			case *chainrpc.ConfEvent_Reorg:
-				// Not supported yet, we can continue as we return on conf and
-				// should never get an reorg event in return.
 				log.Debugf("[TxWatcher] Swap: %s: Got an reorg event", swapId)
+				if confirmed {
+					onReorg()
+					return
+				}
 				continue
 
 			default:
@@ -149,10 +167,9 @@ func (t *TxWatcher) addTxWatcher(ctx context.Context, swapId string, txId string
 }
 
 // AddWaitForConfirmationTx subscribes to the lnd onchain tx watcher and calls
-// the callback as soon as the tx is confirmed. The empty uint32 parameter is
-// due to the Watcher interface of swap expecting a signature with a vout
-// parameter.
-func (t *TxWatcher) AddWaitForConfirmationTx(swapId string, txId string, _ uint32, heightHint uint32, script []byte) {
+// the callback as soon as the tx is confirmed. The output `txId:vout` is
+// watched for spends until the confirmation callback returned.
+func (t *TxWatcher) AddWaitForConfirmationTx(swapId string, txId string, vout uint32, heightHint uint32, script []byte) {
 	t.Lock()
 	if _, ok := t.confirmationWatchers[swapId]; ok {
 		log.Debugf("[TxWatcher] Swap: %s: Tried to resubscribe to tx watcher for tx %s", swapId, txId)
@@ -167,8 +184,25 @@ func (t *TxWatcher) AddWaitForConfirmationTx(swapId string, txId string, _ uint3
 	t.confirmationWatchers[swapId] = true
 	t.Unlock()
 
+	// The subscription stays open while the confirmation callback is
+	// running, so that the swap learns about a reorg before it paid the
+	// claim invoice. On a reorg the swap awaits the confirmation again and
+	// resubscribes.
+	var reorged bool
+	onReorg := func() {
+		t.Lock()
+		reorged = true
+		delete(t.confirmationWatchers, swapId)
+		cb := t.reorgCallback
+		t.Unlock()
+		log.Infof("[TxWatcher] Wait for confirmation on swap %s: tx %s was reorged out of the chain", swapId, txId)
+		if cb != nil {
+			_ = cb(swapId)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(t.ctx)
-	confChan, errChan, err := t.addTxWatcher(ctx, swapId, txId, t.targetConfs, heightHint, script)
+	confChan, errChan, err := t.addTxWatcher(ctx, swapId, txId, t.targetConfs, heightHint, script, onReorg)
 	if err != nil {
 		// TODO: Add error return to somehow handle error in swap. Else this
 		// could lead to stale swaps that might not resolve.
@@ -177,12 +211,18 @@ func (t *TxWatcher) AddWaitForConfirmationTx(swapId string, txId string, _ uint3
 		return
 	}
 
+	// A spend of the output by the maker while we wait for the
+	// confirmation or pay the claim invoice is a double-spend attempt.
+	t.addSpendWatcher(ctx, cancel, swapId, txId, vout, heightHint, script)
+
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
 		defer func() {
 			t.Lock()
-			delete(t.confirmationWatchers, swapId)
+			if !reorged {
+				delete(t.confirmationWatchers, swapId)
+			}
 			t.Unlock()
 		}()
 		defer cancel()
@@ -230,7 +270,7 @@ func (t *TxWatcher) AddWaitForConfirmationTx(swapId string, txId string, _ uint3
 				return
 			case err := <-errChan:
 				if err == io.EOF {
-					log.Infof("[TxWatcher] Wait for confirmation on swap %s: Stream closed by server: %s", swapId)
+					log.Infof("[TxWatcher] Wait for confirmation on swap %s: Stream closed by server", swapId)
 					return
 				}
 				if IsContextError(err) {
@@ -272,7 +312,7 @@ func (t *TxWatcher) AddWaitForCsvTx(swapId string, txId string, vout uint32, hei
 	// for a tx to be reorganized out of the chain.
 	// This means that we have to count the blocks after this by our self.
 	// TODO: Ask lnd why we can not listen longer?
-	confChan, errChan, err := t.addTxWatcher(ctx, swapId, txId, 144, heightHint, script)
+	confChan, errChan, err := t.addTxWatcher(ctx, swapId, txId, 144, heightHint, script, nil)
 	if err != nil {
 		// TODO: Add error return to somehow handle error in swap. Else this
 		// could lead to stale swaps that might not resolve.
@@ -281,6 +321,11 @@ func (t *TxWatcher) AddWaitForCsvTx(swapId string, txId string, vout uint32, hei
 		return
 	}
 
+	// The opening output is watched for spends in parallel, so that a claim
+	// with the preimage settles the swap even if the claim invoice payment
+	// was missed.
+	t.addSpendWatcher(ctx, cancel, swapId, txId, vout, heightHint, script)
+
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
@@ -316,7 +361,7 @@ func (t *TxWatcher) AddWaitForCsvTx(swapId string, txId string, vout uint32, hei
 				for {
 					be, err := stream.Recv()
 					if err == io.EOF {
-						log.Infof("[TxWatcher] Wait for csv limit on swap %s: Block stream closed by server: %s", swapId)
+						log.Infof("[TxWatcher] Wait for csv limit on swap %s: Block stream closed by server", swapId)
 						return
 					}
 					if IsContextError(err) {
@@ -346,7 +391,7 @@ func (t *TxWatcher) AddWaitForCsvTx(swapId string, txId string, vout uint32, hei
 				}
 			case err := <-errChan:
 				if err == io.EOF {
-					log.Infof("[TxWatcher] Wait for csv limit on swap %s: Stream closed by server: %s", swapId)
+					log.Infof("[TxWatcher] Wait for csv limit on swap %s: Stream closed by server", swapId)
 					return
 				}
 				if IsContextError(err) {
@@ -365,6 +410,77 @@ func (t *TxWatcher) AddWaitForCsvTx(swapId string, txId string, vout uint32, hei
 	}()
 }
 
+// addSpendWatcher subscribes to the spend of the output `txId:vout` and calls
+// the spend callback with the spending tx. The watcher that belongs to `ctx`
+// is canceled once the spend was handled. Note that lnd's chain notifier only
+// notifies about spends that are mined, mempool spends are not reported.
+func (t *TxWatcher) addSpendWatcher(ctx context.Context, cancel context.CancelFunc, swapId, txId string, vout, heightHint uint32, script []byte) {
+	t.Lock()
+	cb := t.spendCallback
+	t.Unlock()
+	if cb == nil {
+		return
+	}
+
+	txIdHash, err := chainhash.NewHashFromStr(txId)
+	if err != nil {
+		log.Infof("[TxWatcher] Swap: %s: Could not subscribe spend watcher to tx %s, %v", swapId, txId, err)
+		return
+	}
+
+	stream, err := t.chainrpcClient.RegisterSpendNtfn(
+		ctx,
+		&chainrpc.SpendRequest{
+			Outpoint: &chainrpc.Outpoint{
+				Hash:  txIdHash.CloneBytes(),
+				Index: vout,
+			},
+			Script:     script,
+			HeightHint: heightHint,
+		},
+	)
+	if err != nil {
+		log.Infof("[TxWatcher] Swap: %s: Could not subscribe spend watcher to tx %s, %v", swapId, txId, err)
+		return
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				log.Infof("[TxWatcher] Wait for spend on swap %s: Stream closed by server", swapId)
+				return
+			}
+			if IsContextError(err) {
+				return
+			}
+			if err != nil {
+				log.Infof("[TxWatcher] Wait for spend on swap: %s: Stream closed with err: %v", swapId, err)
+				return
+			}
+
+			// Reorgs of the spending tx are of no interest, as the swap
+			// only cares about the revealed preimage.
+			spend := res.GetSpend()
+			if spend == nil {
+				continue
+			}
+
+			log.Infof("[TxWatcher] Wait for spend on swap %s: Output %s:%d was spent", swapId, txId, vout)
+			err = cb(swapId, hex.EncodeToString(spend.RawSpendingTx))
+			if err != nil {
+				log.Infof("[TxWatcher] Wait for spend on swap %s: spend callback error %v", swapId, err)
+				continue
+			}
+			cancel()
+			return
+		}
+	}()
+}
+
 // AddConfirmationCallback adds a callback to the watcher that will be called in
 // the case that an active "wait for confirmation" watcher reached the
 // confirmation limit for a swap.
@@ -383,6 +499,24 @@ func (t *TxWatcher) AddCsvCallback(cb func(swapId string) error) {
 	t.csvPassedCallback = cb
 }
 
+// AddReorgCallback adds a callback to the watcher that will be called in the
+// case that a confirmed tx is reorged out of the chain while the
+// confirmation callback is running.
+func (t *TxWatcher) AddReorgCallback(cb func(swapId string) error) {
+	t.Lock()
+	defer t.Unlock()
+	t.reorgCallback = cb
+}
+
+// AddSpendCallback adds a callback to the watcher that will be called in the
+// case that the output of an active "wait for csv limit reached" watcher is
+// spent.
+func (t *TxWatcher) AddSpendCallback(cb func(swapId, txHex string) error) {
+	t.Lock()
+	defer t.Unlock()
+	t.spendCallback = cb
+}
+
 // GetBlockHeight returns the current best block from the GetInfo call. Beware
 // that this hight is the best block from the nodes view.
 func (t *TxWatcher) GetBlockHeight() (uint32, error) {
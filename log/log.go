@@ -1,36 +1,190 @@
 package log
 
 import (
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 )
 
 var (
 	logger PeerswapLogger
 )
 
+// Level is the severity of a log entry.
+type Level uint8
+
+const (
+	LevelError Level = iota + 1
+	LevelWarn
+	LevelInfo
+	LevelDebug
+	LevelTrace
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelError:
+		return "error"
+	case LevelWarn:
+		return "warn"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	case LevelTrace:
+		return "trace"
+	}
+	return "unknown"
+}
+
+// ParseLevel returns the Level for its string representation. For backwards
+// compatibility the legacy numeric levels "1" (info) and "2" (debug) are
+// accepted as well.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "error":
+		return LevelError, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "info", "1":
+		return LevelInfo, nil
+	case "debug", "2":
+		return LevelDebug, nil
+	case "trace":
+		return LevelTrace, nil
+	}
+	return 0, fmt.Errorf("unknown log level: %s", s)
+}
+
+// UnmarshalFlag implements the go-flags Unmarshaler interface.
+func (l *Level) UnmarshalFlag(value string) error {
+	level, err := ParseLevel(value)
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// MarshalFlag implements the go-flags Marshaler interface.
+func (l Level) MarshalFlag() (string, error) {
+	return l.String(), nil
+}
+
+// Field keys that are used to attach context to log entries.
+const (
+	SwapIdField = "swap_id"
+	PeerField   = "peer"
+	StateField  = "state"
+	ChainField  = "chain"
+)
+
+// Fields are key-value pairs that are attached to a log entry.
+type Fields map[string]interface{}
+
+// String returns the fields as space separated key=value pairs, sorted by
+// key.
+func (f Fields) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, f[k]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// PeerswapLogger writes a single log entry with the given level, message and
+// fields.
 type PeerswapLogger interface {
-	Infof(format string, v ...interface{})
-	Debugf(format string, v ...interface{})
+	Log(level Level, msg string, fields Fields)
 }
 
 func SetLogger(peerswapLogger PeerswapLogger) {
 	logger = peerswapLogger
 }
 
-func Infof(format string, v ...interface{}) {
+func write(level Level, fields Fields, format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
 	if logger != nil {
-		logger.Infof(format, v...)
-	} else {
-		log.Printf("[INFO] "+format, v...)
+		logger.Log(level, msg, fields)
+		return
+	}
+	if level == LevelTrace {
+		return
+	}
+	if len(fields) > 0 {
+		msg = msg + " " + fields.String()
 	}
+	log.Printf("[%s] %s", strings.ToUpper(level.String()), msg)
+}
+
+func Errorf(format string, v ...interface{}) {
+	write(LevelError, nil, format, v...)
+}
+
+func Warnf(format string, v ...interface{}) {
+	write(LevelWarn, nil, format, v...)
+}
+
+func Infof(format string, v ...interface{}) {
+	write(LevelInfo, nil, format, v...)
 }
 
 func Debugf(format string, v ...interface{}) {
-	if logger != nil {
-		logger.Debugf(format, v...)
-	} else {
-		log.Printf("[DEBUG] "+format, v...)
+	write(LevelDebug, nil, format, v...)
+}
+
+func Tracef(format string, v ...interface{}) {
+	write(LevelTrace, nil, format, v...)
+}
+
+// Entry is a log entry with attached fields.
+type Entry struct {
+	fields Fields
+}
+
+// WithFields returns an Entry that attaches the fields to every log message.
+func WithFields(fields Fields) *Entry {
+	return &Entry{fields: fields}
+}
+
+// WithFields returns a new Entry that holds the fields of the entry and the
+// additional fields.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
 	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Entry{fields: merged}
+}
+
+func (e *Entry) Errorf(format string, v ...interface{}) {
+	write(LevelError, e.fields, format, v...)
+}
+
+func (e *Entry) Warnf(format string, v ...interface{}) {
+	write(LevelWarn, e.fields, format, v...)
+}
+
+func (e *Entry) Infof(format string, v ...interface{}) {
+	write(LevelInfo, e.fields, format, v...)
+}
+
+func (e *Entry) Debugf(format string, v ...interface{}) {
+	write(LevelDebug, e.fields, format, v...)
+}
+
+func (e *Entry) Tracef(format string, v ...interface{}) {
+	write(LevelTrace, e.fields, format, v...)
 }
 
 type logType int
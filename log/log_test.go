@@ -3,5 +3,5 @@ package log
 import "testing"
 
 func Test_Log(t *testing.T) {
-	Debugf("gude \n", nil)
+	Debugf("gude \n")
 }
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WriterLogger is a PeerswapLogger that writes entries up to a maximum level
+// to an io.Writer, either as text lines or as json objects.
+type WriterLogger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	maxLevel Level
+	json     bool
+	now      func() time.Time
+}
+
+// NewWriterLogger returns a logger that writes all entries with a level of at
+// most `maxLevel` to `w`. If `jsonOutput` is set every entry is written as a
+// single json object.
+func NewWriterLogger(w io.Writer, maxLevel Level, jsonOutput bool) *WriterLogger {
+	return &WriterLogger{
+		w:        w,
+		maxLevel: maxLevel,
+		json:     jsonOutput,
+		now:      time.Now,
+	}
+}
+
+func (l *WriterLogger) Log(level Level, msg string, fields Fields) {
+	if level > l.maxLevel {
+		return
+	}
+
+	ts := l.now().UTC().Format(time.RFC3339)
+	var line string
+	if l.json {
+		line = formatJson(ts, level, msg, fields)
+	} else {
+		line = fmt.Sprintf("%s [%s] %s", ts, strings.ToUpper(level.String()), strings.TrimRight(msg, "\n"))
+		if len(fields) > 0 {
+			line = line + " " + fields.String()
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintln(l.w, line)
+}
+
+// formatJson returns the entry as a json object. The fields are added as top
+// level keys next to the time, level and message.
+func formatJson(ts string, level Level, msg string, fields Fields) string {
+	entry := make(map[string]interface{}, len(fields)+3)
+	for k, v := range fields {
+		entry[k] = v
+	}
+	entry["time"] = ts
+	entry["level"] = level.String()
+	entry["msg"] = strings.TrimRight(msg, "\n")
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"time":%q,"level":"error","msg":%q}`, ts, "could not marshal log entry: "+err.Error())
+	}
+	return string(b)
+}
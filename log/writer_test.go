@@ -0,0 +1,69 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger(maxLevel Level, jsonOutput bool) (*WriterLogger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l := NewWriterLogger(&buf, maxLevel, jsonOutput)
+	l.now = func() time.Time { return time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC) }
+	return l, &buf
+}
+
+func Test_WriterLogger_Text(t *testing.T) {
+	l, buf := newTestLogger(LevelInfo, false)
+
+	l.Log(LevelInfo, "swap started", Fields{SwapIdField: "abc", PeerField: "02aa"})
+	l.Log(LevelDebug, "filtered", nil)
+
+	assert.Equal(t, "2022-10-01T12:00:00Z [INFO] swap started peer=02aa swap_id=abc\n", buf.String())
+}
+
+func Test_WriterLogger_Json(t *testing.T) {
+	l, buf := newTestLogger(LevelTrace, true)
+
+	l.Log(LevelTrace, "payload", Fields{ChainField: "btc"})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, map[string]interface{}{
+		"time":     "2022-10-01T12:00:00Z",
+		"level":    "trace",
+		"msg":      "payload",
+		ChainField: "btc",
+	}, entry)
+}
+
+func Test_ParseLevel(t *testing.T) {
+	for s, expected := range map[string]Level{
+		"error": LevelError,
+		"WARN":  LevelWarn,
+		"1":     LevelInfo,
+		"2":     LevelDebug,
+		"trace": LevelTrace,
+	} {
+		level, err := ParseLevel(s)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, level)
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func Test_EntryWithFields(t *testing.T) {
+	l, buf := newTestLogger(LevelDebug, false)
+	SetLogger(l)
+	defer SetLogger(nil)
+
+	WithFields(Fields{SwapIdField: "abc"}).WithFields(Fields{StateField: "State_SwapCanceled"}).Debugf("canceled %d", 1)
+
+	assert.Equal(t, "2022-10-01T12:00:00Z [DEBUG] canceled 1 state=State_SwapCanceled swap_id=abc\n", buf.String())
+}
@@ -0,0 +1,83 @@
+package macaroons
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the grpc metadata key that carries the hex encoded macaroon.
+// Rest clients can pass the macaroon in the `Grpc-Metadata-Macaroon` header.
+const MetadataKey = "macaroon"
+
+// UnaryServerInterceptor returns a grpc interceptor that checks the macaroon of
+// every call against the permission that `permissions` maps the full method
+// name to. Methods that are missing in the map are rejected.
+func (s *Service) UnaryServerInterceptor(permissions map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		required, ok := permissions[info.FullMethod]
+		if !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "no permissions defined for method %s", info.FullMethod)
+		}
+
+		macBytes, err := macaroonFromContext(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if err := s.Verify(macBytes, required); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "%s requires %s: %v", info.FullMethod, required, err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func macaroonFromContext(ctx context.Context) ([]byte, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, ErrMissingMacaroon
+	}
+
+	values := md.Get(MetadataKey)
+	if len(values) != 1 {
+		return nil, fmt.Errorf("expected 1 macaroon, got %d", len(values))
+	}
+
+	macBytes, err := hex.DecodeString(values[0])
+	if err != nil {
+		return nil, fmt.Errorf("macaroon is not hex encoded: %w", err)
+	}
+	return macBytes, nil
+}
+
+// Credential implements grpc.PerRPCCredentials and attaches a macaroon to
+// every call.
+type Credential struct {
+	macHex string
+}
+
+// NewCredentialFromFile reads a serialized macaroon from `path`.
+func NewCredentialFromFile(path string) (*Credential, error) {
+	macBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read macaroon %s: %w", path, err)
+	}
+	return &Credential{macHex: hex.EncodeToString(macBytes)}, nil
+}
+
+func (c *Credential) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{MetadataKey: c.macHex}, nil
+}
+
+// RequireTransportSecurity returns true, the macaroon is only sent over tls
+// connections.
+func (c *Credential) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,169 @@
+// Package macaroons implements the macaroon based authentication of the
+// peerswapd rpc interface. Every macaroon carries a caveat with the list of
+// permissions it grants. A permission is an `entity:action` pair, e.g.
+// `swaps:write`, that every rpc method maps to.
+package macaroons
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.etcd.io/bbolt"
+	"gopkg.in/macaroon.v2"
+)
+
+const (
+	location = "peerswap"
+
+	permissionsCaveatPrefix = "permissions "
+
+	rootKeyLen = 32
+)
+
+const (
+	AdminMacaroonFile    = "admin.macaroon"
+	ReadonlyMacaroonFile = "readonly.macaroon"
+	SwapMacaroonFile     = "swap.macaroon"
+)
+
+var (
+	macaroonBucket = []byte("macaroons")
+	rootKeyKey     = []byte("root-key")
+)
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrMissingMacaroon  = errors.New("expected 1 macaroon, got none")
+)
+
+// Permissions
+const (
+	SwapsRead   = "swaps:read"
+	SwapsWrite  = "swaps:write"
+	PeersRead   = "peers:read"
+	PolicyWrite = "policy:write"
+	WalletRead  = "wallet:read"
+	WalletWrite = "wallet:write"
+	DaemonWrite = "daemon:write"
+)
+
+var (
+	// ReadonlyPermissions allow to query the state of the daemon.
+	ReadonlyPermissions = []string{SwapsRead, PeersRead, WalletRead}
+
+	// SwapPermissions allow to query the state of the daemon and to start
+	// new swaps.
+	SwapPermissions = []string{SwapsRead, PeersRead, WalletRead, SwapsWrite}
+
+	// AdminPermissions allow everything.
+	AdminPermissions = []string{SwapsRead, PeersRead, WalletRead, SwapsWrite, PolicyWrite, WalletWrite, DaemonWrite}
+)
+
+// Service mints and verifies macaroons with a root key that is stored in the
+// peerswap db.
+type Service struct {
+	rootKey []byte
+}
+
+// NewService returns a macaroon service. A new root key is created and stored
+// in the db on first start.
+func NewService(db *bbolt.DB) (*Service, error) {
+	var rootKey []byte
+	err := db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(macaroonBucket)
+		if err != nil {
+			return err
+		}
+
+		if k := b.Get(rootKeyKey); k != nil {
+			rootKey = make([]byte, len(k))
+			copy(rootKey, k)
+			return nil
+		}
+
+		rootKey = make([]byte, rootKeyLen)
+		if _, err := rand.Read(rootKey); err != nil {
+			return err
+		}
+		return b.Put(rootKeyKey, rootKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{rootKey: rootKey}, nil
+}
+
+// NewMacaroon mints a new macaroon that grants the given permissions.
+func (s *Service) NewMacaroon(permissions []string) (*macaroon.Macaroon, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	mac, err := macaroon.New(s.rootKey, id, location, macaroon.LatestVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mac.AddFirstPartyCaveat([]byte(permissionsCaveatPrefix + strings.Join(permissions, " ")))
+	if err != nil {
+		return nil, err
+	}
+	return mac, nil
+}
+
+// Verify checks that the serialized macaroon was minted with our root key and
+// that all of its permission caveats grant the `required` permission.
+func (s *Service) Verify(macBytes []byte, required string) error {
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return fmt.Errorf("invalid macaroon: %w", err)
+	}
+
+	return mac.Verify(s.rootKey, func(caveat string) error {
+		if !strings.HasPrefix(caveat, permissionsCaveatPrefix) {
+			return fmt.Errorf("unknown caveat: %s", caveat)
+		}
+		for _, p := range strings.Fields(strings.TrimPrefix(caveat, permissionsCaveatPrefix)) {
+			if p == required {
+				return nil
+			}
+		}
+		return ErrPermissionDenied
+	}, nil)
+}
+
+// WriteDefaultMacaroons mints the admin, readonly and swap macaroons and
+// writes them to `dir`. Macaroon files that already exist are not touched.
+func (s *Service) WriteDefaultMacaroons(dir string) error {
+	defaults := map[string][]string{
+		AdminMacaroonFile:    AdminPermissions,
+		ReadonlyMacaroonFile: ReadonlyPermissions,
+		SwapMacaroonFile:     SwapPermissions,
+	}
+
+	for file, permissions := range defaults {
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		mac, err := s.NewMacaroon(permissions)
+		if err != nil {
+			return err
+		}
+		macBytes, err := mac.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, macBytes, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package macaroons
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestService(t *testing.T) (*Service, *bbolt.DB) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "swaps"), 0700, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	s, err := NewService(db)
+	require.NoError(t, err)
+	return s, db
+}
+
+func Test_RootKeyIsPersisted(t *testing.T) {
+	s, db := newTestService(t)
+
+	s2, err := NewService(db)
+	require.NoError(t, err)
+	assert.Equal(t, s.rootKey, s2.rootKey)
+}
+
+func Test_Verify(t *testing.T) {
+	s, _ := newTestService(t)
+	dir := t.TempDir()
+	require.NoError(t, s.WriteDefaultMacaroons(dir))
+
+	read := func(file string) []byte {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		return b
+	}
+
+	admin := read(AdminMacaroonFile)
+	readonly := read(ReadonlyMacaroonFile)
+	swap := read(SwapMacaroonFile)
+
+	for _, p := range AdminPermissions {
+		assert.NoError(t, s.Verify(admin, p))
+	}
+
+	assert.NoError(t, s.Verify(readonly, SwapsRead))
+	assert.ErrorIs(t, s.Verify(readonly, SwapsWrite), ErrPermissionDenied)
+
+	assert.NoError(t, s.Verify(swap, SwapsWrite))
+	assert.ErrorIs(t, s.Verify(swap, WalletWrite), ErrPermissionDenied)
+
+	// A macaroon minted with a different root key is rejected.
+	other, _ := newTestService(t)
+	assert.Error(t, other.Verify(admin, SwapsRead))
+}
+
+func Test_AttenuatedMacaroon(t *testing.T) {
+	s, _ := newTestService(t)
+
+	mac, err := s.NewMacaroon(AdminPermissions)
+	require.NoError(t, err)
+
+	// Adding a caveat can only restrict the permissions.
+	require.NoError(t, mac.AddFirstPartyCaveat([]byte(permissionsCaveatPrefix+SwapsRead)))
+	macBytes, err := mac.MarshalBinary()
+	require.NoError(t, err)
+
+	assert.NoError(t, s.Verify(macBytes, SwapsRead))
+	assert.ErrorIs(t, s.Verify(macBytes, DaemonWrite), ErrPermissionDenied)
+}
+
+func Test_UnaryServerInterceptor(t *testing.T) {
+	s, _ := newTestService(t)
+	interceptor := s.UnaryServerInterceptor(map[string]string{
+		"/test/Read":  SwapsRead,
+		"/test/Write": SwapsWrite,
+	})
+
+	mac, err := s.NewMacaroon(ReadonlyPermissions)
+	require.NoError(t, err)
+	macBytes, err := mac.MarshalBinary()
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, hex.EncodeToString(macBytes)))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	call := func(ctx context.Context, method string) codes.Code {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return status.Code(err)
+	}
+
+	assert.Equal(t, codes.OK, call(ctx, "/test/Read"))
+	assert.Equal(t, codes.PermissionDenied, call(ctx, "/test/Write"))
+	assert.Equal(t, codes.PermissionDenied, call(ctx, "/test/Unknown"))
+	assert.Equal(t, codes.Unauthenticated, call(context.Background(), "/test/Read"))
+}
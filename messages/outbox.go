@@ -0,0 +1,305 @@
+package messages
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elementsproject/peerswap/log"
+)
+
+// FEATURE_ACK is announced in the poll feature set by peers that acknowledge
+// the swap messages.
+const FEATURE_ACK = "ack"
+
+type Messenger interface {
+	SendMessage(peerId string, message []byte, messageType int) error
+}
+
+// MessageId returns the id of a message. The id is derived from the message
+// type and payload, so that the receiver can acknowledge a message and
+// recognize resent messages without any change to the message itself.
+func MessageId(messageType MessageType, payload []byte) string {
+	h := sha256.New()
+	binary.Write(h, binary.BigEndian, uint16(messageType))
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// OutboxMessage is a message that is resent until the peer acknowledges it.
+type OutboxMessage struct {
+	Id          string `json:"id"`
+	SwapId      string `json:"swap_id"`
+	PeerId      string `json:"peer_id"`
+	MessageType int    `json:"message_type"`
+	Payload     []byte `json:"payload"`
+	// Attempts is the number of times the message was sent.
+	Attempts int `json:"attempts"`
+}
+
+type OutboxStore interface {
+	Put(msg *OutboxMessage) error
+	Delete(id string) error
+	ListAll() ([]*OutboxMessage, error)
+}
+
+type pendingMessage struct {
+	msg  *OutboxMessage
+	stop chan struct{}
+}
+
+// Outbox resends messages with an exponential backoff until the peer
+// acknowledges them. The messages are persisted, so that resending continues
+// after a restart.
+type Outbox struct {
+	sync.Mutex
+	messenger  Messenger
+	store      OutboxStore
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	pending    map[string]*pendingMessage
+}
+
+func NewOutbox(messenger Messenger, store OutboxStore, minBackoff, maxBackoff time.Duration) *Outbox {
+	return &Outbox{
+		messenger:  messenger,
+		store:      store,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		pending:    map[string]*pendingMessage{},
+	}
+}
+
+// Start resends the messages that were not acknowledged before we shut down.
+func (o *Outbox) Start() error {
+	msgs, err := o.store.ListAll()
+	if err != nil {
+		return err
+	}
+
+	o.Lock()
+	defer o.Unlock()
+	for _, msg := range msgs {
+		if _, ok := o.pending[msg.Id]; ok {
+			continue
+		}
+		log.Debugf("[Outbox]\tresume sending message %s of type %d to %s", msg.Id, msg.MessageType, msg.PeerId)
+		o.schedule(msg)
+	}
+	return nil
+}
+
+// Add puts a message that was already sent once into the outbox. The message
+// is resent until the peer acknowledges it or the messages of the swap are
+// removed.
+func (o *Outbox) Add(swapId, peerId string, payload []byte, messageType int) error {
+	msg := &OutboxMessage{
+		Id:          MessageId(MessageType(messageType), payload),
+		SwapId:      swapId,
+		PeerId:      peerId,
+		MessageType: messageType,
+		Payload:     payload,
+		Attempts:    1,
+	}
+
+	o.Lock()
+	defer o.Unlock()
+	if _, ok := o.pending[msg.Id]; ok {
+		return nil
+	}
+	err := o.store.Put(msg)
+	if err != nil {
+		return err
+	}
+	o.schedule(msg)
+	return nil
+}
+
+// Ack removes the message with the given id if it was sent to the peer.
+func (o *Outbox) Ack(peerId, messageId string) {
+	o.Lock()
+	defer o.Unlock()
+	p, ok := o.pending[messageId]
+	if !ok || p.msg.PeerId != peerId {
+		return
+	}
+	log.Debugf("[Outbox]\tmessage %s acknowledged by %s", messageId, peerId)
+	o.remove(p)
+}
+
+// Remove removes all messages of a swap.
+func (o *Outbox) Remove(swapId string) {
+	o.Lock()
+	defer o.Unlock()
+	for _, p := range o.pending {
+		if p.msg.SwapId == swapId {
+			o.remove(p)
+		}
+	}
+}
+
+// Stop stops resending. The messages stay in the store and are resent on the
+// next start.
+func (o *Outbox) Stop() {
+	o.Lock()
+	defer o.Unlock()
+	for id, p := range o.pending {
+		close(p.stop)
+		delete(o.pending, id)
+	}
+}
+
+// schedule starts resending a message. The lock must be held.
+func (o *Outbox) schedule(msg *OutboxMessage) {
+	p := &pendingMessage{msg: msg, stop: make(chan struct{})}
+	o.pending[msg.Id] = p
+	go o.resend(p, msg.Attempts)
+}
+
+// remove stops resending a message and deletes it from the store. The lock
+// must be held.
+func (o *Outbox) remove(p *pendingMessage) {
+	close(p.stop)
+	delete(o.pending, p.msg.Id)
+	err := o.store.Delete(p.msg.Id)
+	if err != nil {
+		log.Infof("[Outbox]\tcould not delete message %s: %v", p.msg.Id, err)
+	}
+}
+
+func (o *Outbox) resend(p *pendingMessage, attempts int) {
+	for {
+		timer := time.NewTimer(o.backoff(attempts))
+		select {
+		case <-p.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		err := o.messenger.SendMessage(p.msg.PeerId, p.msg.Payload, p.msg.MessageType)
+		if err != nil {
+			log.Debugf("[Outbox]\tresend message %s: %v", p.msg.Id, err)
+		}
+		attempts++
+
+		o.Lock()
+		if o.pending[p.msg.Id] != p {
+			// Acknowledged or removed in the meantime.
+			o.Unlock()
+			return
+		}
+		p.msg.Attempts = attempts
+		err = o.store.Put(p.msg)
+		if err != nil {
+			log.Infof("[Outbox]\tcould not update message %s: %v", p.msg.Id, err)
+		}
+		o.Unlock()
+	}
+}
+
+// backoff returns the time to wait after a message was sent the given number
+// of times. It doubles with every attempt up to maxBackoff.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	d := o.minBackoff
+	for i := 1; i < attempts && d < o.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > o.maxBackoff {
+		d = o.maxBackoff
+	}
+	return d
+}
+
+// SeenStore persists the ids of the received messages.
+type SeenStore interface {
+	Put(id string, seenAt time.Time) error
+	Delete(id string) error
+	ListAll() (map[string]time.Time, error)
+}
+
+// SeenMessages remembers the ids of the last received messages, so that
+// resent messages are handled only once. The ids are persisted if a store is
+// set, as the outbox of the peer resends its messages after a restart.
+type SeenMessages struct {
+	sync.Mutex
+	size  int
+	ids   map[string]struct{}
+	order []string
+	store SeenStore
+}
+
+func NewSeenMessages(size int) *SeenMessages {
+	return &SeenMessages{size: size, ids: map[string]struct{}{}}
+}
+
+// Load adds the ids of the store and persists the ids that are added from
+// now on. It must be called before the first id is added.
+func (s *SeenMessages) Load(store SeenStore) error {
+	seen, err := store.ListAll()
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return seen[ids[i]].Before(seen[ids[j]])
+	})
+	for len(ids) > s.size {
+		err = store.Delete(ids[0])
+		if err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	for _, id := range ids {
+		s.ids[id] = struct{}{}
+	}
+	s.order = ids
+	s.store = store
+	return nil
+}
+
+// Contains returns true if the id was added before.
+func (s *SeenMessages) Contains(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+	_, ok := s.ids[id]
+	return ok
+}
+
+// Add adds an id and forgets the oldest one if more than size ids are known.
+func (s *SeenMessages) Add(id string) {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.ids[id]; ok {
+		return
+	}
+	s.ids[id] = struct{}{}
+	s.order = append(s.order, id)
+	if s.store != nil {
+		err := s.store.Put(id, time.Now())
+		if err != nil {
+			log.Infof("[Outbox]\tcould not persist seen message %s: %v", id, err)
+		}
+	}
+	if len(s.order) > s.size {
+		forgotten := s.order[0]
+		delete(s.ids, forgotten)
+		s.order = s.order[1:]
+		if s.store != nil {
+			err := s.store.Delete(forgotten)
+			if err != nil {
+				log.Infof("[Outbox]\tcould not delete seen message %s: %v", forgotten, err)
+			}
+		}
+	}
+}
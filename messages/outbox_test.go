@@ -0,0 +1,161 @@
+package messages
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func newTestOutboxStore(t *testing.T) *outboxStore {
+	dir := t.TempDir()
+	db, err := bbolt.Open(path.Join(dir, "outbox-db"), os.ModePerm, nil)
+	if err != nil {
+		t.Fatalf("could not open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewOutboxStore(db)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+	return store
+}
+
+func TestOutbox_ResendUntilAcked(t *testing.T) {
+	store := newTestOutboxStore(t)
+	msgr := &MessengerStub{}
+	outbox := NewOutbox(msgr, store, time.Millisecond, 5*time.Millisecond)
+	defer outbox.Stop()
+
+	payload := []byte("opening_tx_broadcasted")
+	err := outbox.Add("swap_id", "peer_id", payload, int(MESSAGETYPE_OPENINGTXBROADCASTED))
+	require.NoError(t, err)
+
+	// Should resend the message.
+	require.Eventually(t, func() bool { return msgr.Called() > 2 }, time.Second, time.Millisecond)
+
+	// An ack by an other peer does not stop resending.
+	id := MessageId(MESSAGETYPE_OPENINGTXBROADCASTED, payload)
+	outbox.Ack("other_peer", id)
+	msgs, err := store.ListAll()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, id, msgs[0].Id)
+
+	// Check it is not sending anymore after the ack.
+	outbox.Ack("peer_id", id)
+	nMsgs := msgr.Called()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, nMsgs, msgr.Called())
+
+	msgs, err = store.ListAll()
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+}
+
+func TestOutbox_ResumeOnStart(t *testing.T) {
+	store := newTestOutboxStore(t)
+	outbox := NewOutbox(&MessengerStub{}, store, time.Hour, time.Hour)
+	err := outbox.Add("swap_id", "peer_id", []byte("msg"), int(MESSAGETYPE_COOPCLOSE))
+	require.NoError(t, err)
+	outbox.Stop()
+
+	// The message is resent after a restart.
+	msgr := &MessengerStub{}
+	outbox = NewOutbox(msgr, store, time.Millisecond, time.Millisecond)
+	defer outbox.Stop()
+	require.NoError(t, outbox.Start())
+	require.Eventually(t, func() bool { return msgr.Called() > 0 }, time.Second, time.Millisecond)
+}
+
+func TestOutbox_Remove(t *testing.T) {
+	store := newTestOutboxStore(t)
+	outbox := NewOutbox(&MessengerStub{}, store, time.Hour, time.Hour)
+	defer outbox.Stop()
+
+	for i, swapId := range []string{"swap_1", "swap_1", "swap_2"} {
+		err := outbox.Add(swapId, "peer_id", []byte(fmt.Sprintf("msg_%d", i)), int(MESSAGETYPE_COOPCLOSE))
+		require.NoError(t, err)
+	}
+
+	outbox.Remove("swap_1")
+	msgs, err := store.ListAll()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "swap_2", msgs[0].SwapId)
+}
+
+func TestOutbox_Backoff(t *testing.T) {
+	outbox := NewOutbox(&MessengerStub{}, nil, 10*time.Second, time.Minute)
+	assert.Equal(t, 10*time.Second, outbox.backoff(1))
+	assert.Equal(t, 20*time.Second, outbox.backoff(2))
+	assert.Equal(t, 40*time.Second, outbox.backoff(3))
+	assert.Equal(t, time.Minute, outbox.backoff(4))
+	assert.Equal(t, time.Minute, outbox.backoff(100))
+}
+
+func TestSeenMessages(t *testing.T) {
+	seen := NewSeenMessages(2)
+	seen.Add("a")
+	seen.Add("b")
+	assert.True(t, seen.Contains("a"))
+	assert.True(t, seen.Contains("b"))
+
+	// The oldest id is forgotten.
+	seen.Add("c")
+	assert.False(t, seen.Contains("a"))
+	assert.True(t, seen.Contains("b"))
+	assert.True(t, seen.Contains("c"))
+}
+
+func TestSeenMessages_Persisted(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bbolt.Open(path.Join(dir, "seen-db"), os.ModePerm, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	store, err := NewSeenStore(db)
+	require.NoError(t, err)
+
+	seen := NewSeenMessages(2)
+	require.NoError(t, seen.Load(store))
+	seen.Add("a")
+	seen.Add("b")
+	seen.Add("c")
+
+	// The ids survive a restart, forgotten ids are deleted.
+	restarted := NewSeenMessages(2)
+	require.NoError(t, restarted.Load(store))
+	assert.False(t, restarted.Contains("a"))
+	assert.True(t, restarted.Contains("b"))
+	assert.True(t, restarted.Contains("c"))
+
+	restarted.Add("d")
+	assert.False(t, restarted.Contains("b"))
+	ids, err := store.ListAll()
+	require.NoError(t, err)
+	assert.Len(t, ids, 2)
+}
+
+type MessengerStub struct {
+	sync.Mutex
+	called int
+}
+
+func (s *MessengerStub) SendMessage(peerId string, message []byte, messageType int) error {
+	s.Lock()
+	defer s.Unlock()
+	s.called++
+	return nil
+}
+
+func (s *MessengerStub) Called() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.called
+}
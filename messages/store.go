@@ -0,0 +1,131 @@
+package messages
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var (
+	OUTBOX_BUCKET        = []byte("outbox")
+	SEEN_MESSAGES_BUCKET = []byte("seen-messages")
+)
+
+type outboxStore struct {
+	db *bbolt.DB
+}
+
+func NewOutboxStore(db *bbolt.DB) (*outboxStore, error) {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	_, err = tx.CreateBucketIfNotExists(OUTBOX_BUCKET)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &outboxStore{db: db}, nil
+}
+
+func (s *outboxStore) Put(msg *OutboxMessage) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		msgBytes, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		b := tx.Bucket(OUTBOX_BUCKET)
+		return b.Put([]byte(msg.Id), msgBytes)
+	})
+}
+
+func (s *outboxStore) Delete(id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(OUTBOX_BUCKET)
+		return b.Delete([]byte(id))
+	})
+}
+
+func (s *outboxStore) ListAll() ([]*OutboxMessage, error) {
+	var msgs []*OutboxMessage
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(OUTBOX_BUCKET)
+		return b.ForEach(func(k, v []byte) error {
+			var msg *OutboxMessage
+			err := json.Unmarshal(v, &msg)
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, msg)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+type seenStore struct {
+	db *bbolt.DB
+}
+
+func NewSeenStore(db *bbolt.DB) (*seenStore, error) {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	_, err = tx.CreateBucketIfNotExists(SEEN_MESSAGES_BUCKET)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &seenStore{db: db}, nil
+}
+
+func (s *seenStore) Put(id string, seenAt time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, uint64(seenAt.UnixNano()))
+		b := tx.Bucket(SEEN_MESSAGES_BUCKET)
+		return b.Put([]byte(id), v)
+	})
+}
+
+func (s *seenStore) Delete(id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(SEEN_MESSAGES_BUCKET)
+		return b.Delete([]byte(id))
+	})
+}
+
+func (s *seenStore) ListAll() (map[string]time.Time, error) {
+	seen := map[string]time.Time{}
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(SEEN_MESSAGES_BUCKET)
+		return b.ForEach(func(k, v []byte) error {
+			if len(v) != 8 {
+				return nil
+			}
+			seen[string(k)] = time.Unix(0, int64(binary.BigEndian.Uint64(v)))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return seen, nil
+}
@@ -0,0 +1,387 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FEATURE_TLV is announced in the poll feature set by peers that understand
+// tlv encoded messages.
+const FEATURE_TLV = "tlv"
+
+var (
+	ErrNonMinimalBigSize  = errors.New("bigsize is not minimally encoded")
+	ErrNonMinimalInt      = errors.New("truncated integer is not minimally encoded")
+	ErrUnorderedTlvStream = errors.New("tlv records are not strictly increasing")
+	ErrTlvRecordTooLong   = errors.New("tlv record exceeds the stream")
+)
+
+type ErrUnknownEvenType uint64
+
+func (e ErrUnknownEvenType) Error() string {
+	return fmt.Sprintf("unknown even tlv type %d", uint64(e))
+}
+
+// jsonPrefix is the first byte of a json encoded message. It is reserved as
+// tlv record type, so that the first byte tells the encoding.
+const jsonPrefix = '{'
+
+// Marshal encodes a message as tlv stream if tlv is set, as json otherwise.
+func Marshal(msg interface{}, tlv bool) ([]byte, error) {
+	if tlv {
+		return MarshalTLV(msg)
+	}
+	return json.Marshal(msg)
+}
+
+// Unmarshal decodes a json or tlv encoded message.
+func Unmarshal(payload []byte, msg interface{}) error {
+	if len(payload) > 0 && payload[0] == jsonPrefix {
+		return json.Unmarshal(payload, msg)
+	}
+	return UnmarshalTLV(payload, msg)
+}
+
+// PayloadString returns a printable form of a json or tlv encoded payload.
+func PayloadString(payload []byte) string {
+	if len(payload) > 0 && payload[0] == jsonPrefix {
+		return string(payload)
+	}
+	return hex.EncodeToString(payload)
+}
+
+// WriteBigSize writes a BOLT #1 bigsize integer.
+func WriteBigSize(w io.Writer, n uint64) error {
+	var buf []byte
+	switch {
+	case n < 0xfd:
+		buf = []byte{byte(n)}
+	case n <= 0xffff:
+		buf = make([]byte, 3)
+		buf[0] = 0xfd
+		binary.BigEndian.PutUint16(buf[1:], uint16(n))
+	case n <= 0xffffffff:
+		buf = make([]byte, 5)
+		buf[0] = 0xfe
+		binary.BigEndian.PutUint32(buf[1:], uint32(n))
+	default:
+		buf = make([]byte, 9)
+		buf[0] = 0xff
+		binary.BigEndian.PutUint64(buf[1:], n)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// ReadBigSize reads a BOLT #1 bigsize integer and rejects encodings that are
+// not minimal.
+func ReadBigSize(r io.Reader) (uint64, error) {
+	var prefix [1]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, err
+	}
+
+	var n, min uint64
+	switch prefix[0] {
+	case 0xfd:
+		var buf [2]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		n, min = uint64(binary.BigEndian.Uint16(buf[:])), 0xfd
+	case 0xfe:
+		var buf [4]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		n, min = uint64(binary.BigEndian.Uint32(buf[:])), 0x10000
+	case 0xff:
+		var buf [8]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		n, min = binary.BigEndian.Uint64(buf[:]), 0x100000000
+	default:
+		return uint64(prefix[0]), nil
+	}
+	if n < min {
+		return 0, ErrNonMinimalBigSize
+	}
+	return n, nil
+}
+
+// tlvField is a struct field that is encoded as tlv record.
+type tlvField struct {
+	index   int
+	tlvType uint64
+	hex     bool
+}
+
+// tlvFields returns the tagged fields of a struct type ordered by their tlv
+// type.
+func tlvFields(t reflect.Type) ([]tlvField, error) {
+	var fields []tlvField
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("tlv")
+		if !ok {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		tlvType, err := strconv.ParseUint(opts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tlv tag of field %s: %w", t.Field(i).Name, err)
+		}
+		if tlvType == jsonPrefix {
+			return nil, fmt.Errorf("tlv type %d of field %s is reserved", tlvType, t.Field(i).Name)
+		}
+		field := tlvField{index: i, tlvType: tlvType}
+		for _, opt := range opts[1:] {
+			if opt == "hex" {
+				field.hex = true
+			}
+		}
+		if len(fields) > 0 && fields[len(fields)-1].tlvType >= tlvType {
+			return nil, fmt.Errorf("tlv types of %s are not strictly increasing", t.Name())
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func structValue(msg interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(msg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, errors.New("tlv: nil message")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("tlv: unsupported message kind %s", v.Kind())
+	}
+	return v, nil
+}
+
+// MarshalTLV encodes a struct as BOLT #1 tlv stream. Fields are mapped to
+// records by the `tlv:"<type>[,hex]"` tag, hex strings are encoded as raw
+// bytes. Nested structs are encoded as tlv stream and list entries are
+// prefixed by their length. Fields with a zero value are omitted.
+func MarshalTLV(msg interface{}) ([]byte, error) {
+	v, err := structValue(msg)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := tlvFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, field := range fields {
+		fv := v.Field(field.index)
+		if fv.IsZero() {
+			continue
+		}
+		value, err := encodeValue(fv, field.hex)
+		if err != nil {
+			return nil, fmt.Errorf("tlv: field %s: %w", v.Type().Field(field.index).Name, err)
+		}
+		WriteBigSize(&buf, field.tlvType)
+		WriteBigSize(&buf, uint64(len(value)))
+		buf.Write(value)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalTLV decodes a BOLT #1 tlv stream into a struct, see MarshalTLV.
+// Unknown odd records are ignored, unknown even records are an error.
+func UnmarshalTLV(data []byte, msg interface{}) error {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("tlv: message must be a non nil pointer")
+	}
+	// Allocate nil pointers like json.Unmarshal does.
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("tlv: unsupported message kind %s", v.Kind())
+	}
+	fields, err := tlvFields(v.Type())
+	if err != nil {
+		return err
+	}
+	byType := map[uint64]tlvField{}
+	for _, field := range fields {
+		byType[field.tlvType] = field
+	}
+
+	r := bytes.NewReader(data)
+	first := true
+	var lastType uint64
+	for r.Len() > 0 {
+		tlvType, err := ReadBigSize(r)
+		if err != nil {
+			return err
+		}
+		if !first && tlvType <= lastType {
+			return ErrUnorderedTlvStream
+		}
+		first, lastType = false, tlvType
+
+		length, err := ReadBigSize(r)
+		if err != nil {
+			return err
+		}
+		if length > uint64(r.Len()) {
+			return ErrTlvRecordTooLong
+		}
+		value := make([]byte, length)
+		r.Read(value)
+
+		field, ok := byType[tlvType]
+		if !ok {
+			if tlvType%2 == 0 {
+				return ErrUnknownEvenType(tlvType)
+			}
+			continue
+		}
+		err = decodeValue(v.Field(field.index), value, field.hex)
+		if err != nil {
+			return fmt.Errorf("tlv: field %s: %w", v.Type().Field(field.index).Name, err)
+		}
+	}
+	return nil
+}
+
+func encodeValue(v reflect.Value, isHex bool) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		return encodeValue(v.Elem(), isHex)
+	case reflect.Bool:
+		return []byte{1}, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], v.Uint())
+		return bytes.TrimLeft(buf[:], "\x00"), nil
+	case reflect.String:
+		if isHex {
+			return hex.DecodeString(v.String())
+		}
+		return []byte(v.String()), nil
+	case reflect.Struct:
+		return MarshalTLV(v.Interface())
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		buf := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(buf), v)
+		return buf, nil
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Uint8:
+			return v.Bytes(), nil
+		case reflect.String, reflect.Struct:
+			var buf bytes.Buffer
+			for i := 0; i < v.Len(); i++ {
+				elem, err := encodeValue(v.Index(i), isHex)
+				if err != nil {
+					return nil, err
+				}
+				WriteBigSize(&buf, uint64(len(elem)))
+				buf.Write(elem)
+			}
+			return buf.Bytes(), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported kind %s", v.Type())
+}
+
+func decodeValue(v reflect.Value, value []byte, isHex bool) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(elem.Elem(), value, isHex); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Bool:
+		if len(value) != 1 || value[0] != 1 {
+			return errors.New("invalid bool")
+		}
+		v.SetBool(true)
+		return nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if len(value) > int(v.Type().Size()) {
+			return fmt.Errorf("integer exceeds %d bytes", v.Type().Size())
+		}
+		if len(value) > 0 && value[0] == 0 {
+			return ErrNonMinimalInt
+		}
+		var n uint64
+		for _, b := range value {
+			n = n<<8 | uint64(b)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.String:
+		if isHex {
+			v.SetString(hex.EncodeToString(value))
+			return nil
+		}
+		v.SetString(string(value))
+		return nil
+	case reflect.Struct:
+		return UnmarshalTLV(value, v.Addr().Interface())
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		if len(value) != v.Len() {
+			return fmt.Errorf("expected %d bytes, got %d", v.Len(), len(value))
+		}
+		reflect.Copy(v, reflect.ValueOf(value))
+		return nil
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Uint8:
+			v.SetBytes(value)
+			return nil
+		case reflect.String, reflect.Struct:
+			r := bytes.NewReader(value)
+			elems := reflect.MakeSlice(v.Type(), 0, 0)
+			for r.Len() > 0 {
+				length, err := ReadBigSize(r)
+				if err != nil {
+					return err
+				}
+				if length > uint64(r.Len()) {
+					return ErrTlvRecordTooLong
+				}
+				b := make([]byte, length)
+				r.Read(b)
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := decodeValue(elem, b, isHex); err != nil {
+					return err
+				}
+				elems = reflect.Append(elems, elem)
+			}
+			v.Set(elems)
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported kind %s", v.Type())
+}
@@ -0,0 +1,156 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigSize(t *testing.T) {
+	// Test vectors from BOLT #1.
+	tests := []struct {
+		value   uint64
+		encoded string
+	}{
+		{0, "00"},
+		{252, "fc"},
+		{253, "fd00fd"},
+		{65535, "fdffff"},
+		{65536, "fe00010000"},
+		{4294967295, "feffffffff"},
+		{4294967296, "ff0000000100000000"},
+		{18446744073709551615, "ffffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		require.NoError(t, WriteBigSize(&buf, tt.value))
+		assert.Equal(t, tt.encoded, hex.EncodeToString(buf.Bytes()))
+
+		b, _ := hex.DecodeString(tt.encoded)
+		n, err := ReadBigSize(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, tt.value, n)
+	}
+}
+
+func TestBigSize_NonMinimal(t *testing.T) {
+	for _, encoded := range []string{"fd00fc", "fe0000ffff", "ff00000000ffffffff"} {
+		b, _ := hex.DecodeString(encoded)
+		_, err := ReadBigSize(bytes.NewReader(b))
+		assert.ErrorIs(t, err, ErrNonMinimalBigSize, encoded)
+	}
+}
+
+type tlvTestMessage struct {
+	Id       *[4]byte `json:"id" tlv:"0"`
+	Message  string   `json:"message" tlv:"2"`
+	Pubkey   string   `json:"pubkey" tlv:"4,hex"`
+	Amount   uint64   `json:"amount" tlv:"6"`
+	Vout     uint32   `json:"vout" tlv:"8"`
+	Allowed  bool     `json:"allowed" tlv:"10"`
+	Assets   []string `json:"assets" tlv:"12"`
+	Untagged string   `json:"untagged"`
+}
+
+func TestTLV_Vector(t *testing.T) {
+	msg := &tlvTestMessage{
+		Id:       &[4]byte{1, 2, 3, 4},
+		Message:  "hi",
+		Pubkey:   "02ab",
+		Amount:   100000,
+		Allowed:  true,
+		Assets:   []string{"btc", "lbtc"},
+		Untagged: "not encoded",
+	}
+	encoded := "00040102030402026869040202ab06030186a00a01010c0903627463046c627463"
+
+	b, err := MarshalTLV(msg)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, hex.EncodeToString(b))
+
+	var decoded *tlvTestMessage
+	require.NoError(t, Unmarshal(b, &decoded))
+	msg.Untagged = ""
+	assert.Equal(t, msg, decoded)
+}
+
+func TestMarshal_Json(t *testing.T) {
+	msg := &tlvTestMessage{Message: "hi", Amount: 1}
+	b, err := Marshal(msg, false)
+	require.NoError(t, err)
+
+	var decoded tlvTestMessage
+	require.NoError(t, Unmarshal(b, &decoded))
+	assert.Equal(t, msg, &decoded)
+}
+
+func TestTLV_Empty(t *testing.T) {
+	b, err := MarshalTLV(&tlvTestMessage{})
+	require.NoError(t, err)
+	assert.Empty(t, b)
+
+	var decoded tlvTestMessage
+	require.NoError(t, UnmarshalTLV(b, &decoded))
+	assert.Equal(t, tlvTestMessage{}, decoded)
+}
+
+func TestTLV_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		err     error
+	}{
+		{"unknown even type", "0e0100", ErrUnknownEvenType(14)},
+		{"unordered", "02016806030186a0020168", ErrUnorderedTlvStream},
+		{"duplicate", "0201680201", ErrUnorderedTlvStream},
+		{"too long", "020568", ErrTlvRecordTooLong},
+		{"non minimal type", "fd00020168", ErrNonMinimalBigSize},
+		{"non minimal int", "0604000186a0", ErrNonMinimalInt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := hex.DecodeString(tt.encoded)
+			var decoded tlvTestMessage
+			assert.ErrorIs(t, UnmarshalTLV(b, &decoded), tt.err)
+		})
+	}
+}
+
+func TestTLV_UnknownOddType(t *testing.T) {
+	b, _ := hex.DecodeString("020168050201020603ffffff")
+	var decoded tlvTestMessage
+	require.NoError(t, UnmarshalTLV(b, &decoded))
+	assert.Equal(t, "h", decoded.Message)
+	assert.Equal(t, uint64(0xffffff), decoded.Amount)
+}
+
+type tlvTestLimit struct {
+	Asset  string `tlv:"0"`
+	MinSat uint64 `tlv:"2"`
+}
+
+type tlvTestNested struct {
+	Limit  *tlvTestLimit  `tlv:"1"`
+	Limits []tlvTestLimit `tlv:"3"`
+}
+
+func TestTLV_Nested(t *testing.T) {
+	msg := &tlvTestNested{
+		Limit:  &tlvTestLimit{Asset: "btc", MinSat: 1},
+		Limits: []tlvTestLimit{{Asset: "btc", MinSat: 256}, {Asset: "lbtc"}},
+	}
+	// 01 08 [00 03 "btc" 02 01 01]
+	// 03 11 [09 [00 03 "btc" 02 02 0100] 06 [00 04 "lbtc"]]
+	encoded := "010800036274630201010311" + "0900036274630202010006" + "00046c627463"
+
+	b, err := MarshalTLV(msg)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, hex.EncodeToString(b))
+
+	var decoded tlvTestNested
+	require.NoError(t, UnmarshalTLV(b, &decoded))
+	assert.Equal(t, msg, &decoded)
+}
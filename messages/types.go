@@ -35,6 +35,9 @@ const (
 	MESSAGETYPE_POLL
 	_
 	MESSAGETYPE_REQUEST_POLL
+	_
+	// MESSAGETYPE_ACK acknowledges the receipt of a swap message.
+	MESSAGETYPE_ACK
 	UPPER_MESSAGE_BOUND
 )
 
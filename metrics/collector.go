@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"github.com/elementsproject/peerswap/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	activeSwapsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "active_swaps"),
+		"Number of active swaps by fsm state.",
+		[]string{"state"}, nil,
+	)
+
+	walletBalanceDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "wallet_balance_sat"),
+		"Onchain wallet balance in sat.",
+		[]string{"chain"}, nil,
+	)
+)
+
+// ActiveSwapsGetter returns the number of active swaps per fsm state.
+type ActiveSwapsGetter interface {
+	ActiveSwapsByState() map[string]int
+}
+
+// BalanceGetter returns the onchain balance of a wallet in sat.
+type BalanceGetter interface {
+	GetOnchainBalance() (uint64, error)
+}
+
+// Collector collects the metrics that are read from the current state of the
+// node on every scrape.
+type Collector struct {
+	swaps   ActiveSwapsGetter
+	wallets map[string]BalanceGetter
+}
+
+// NewCollector returns a collector for the active swaps and the wallet
+// balances. The wallets are keyed by the chain they are used for.
+func NewCollector(swaps ActiveSwapsGetter, wallets map[string]BalanceGetter) *Collector {
+	return &Collector{
+		swaps:   swaps,
+		wallets: wallets,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeSwapsDesc
+	ch <- walletBalanceDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for state, n := range c.swaps.ActiveSwapsByState() {
+		ch <- prometheus.MustNewConstMetric(activeSwapsDesc, prometheus.GaugeValue, float64(n), state)
+	}
+
+	for chain, wallet := range c.wallets {
+		balance, err := wallet.GetOnchainBalance()
+		if err != nil {
+			log.Debugf("[Metrics] could not get %s balance: %v", chain, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(walletBalanceDesc, prometheus.GaugeValue, float64(balance), chain)
+	}
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type activeSwapsStub map[string]int
+
+func (a activeSwapsStub) ActiveSwapsByState() map[string]int {
+	return a
+}
+
+type balanceStub struct {
+	balance uint64
+	err     error
+}
+
+func (b *balanceStub) GetOnchainBalance() (uint64, error) {
+	return b.balance, b.err
+}
+
+func Test_Collector(t *testing.T) {
+	c := NewCollector(
+		activeSwapsStub{"State_SwapOutSender_AwaitAgreement": 2},
+		map[string]BalanceGetter{
+			"btc":  &balanceStub{balance: 100000},
+			"lbtc": &balanceStub{err: errors.New("elementsd unavailable")},
+		},
+	)
+
+	expected := `
+# HELP peerswap_active_swaps Number of active swaps by fsm state.
+# TYPE peerswap_active_swaps gauge
+peerswap_active_swaps{state="State_SwapOutSender_AwaitAgreement"} 2
+# HELP peerswap_wallet_balance_sat Onchain wallet balance in sat.
+# TYPE peerswap_wallet_balance_sat gauge
+peerswap_wallet_balance_sat{chain="btc"} 100000
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func Test_SwapCounters(t *testing.T) {
+	SwapStarted("swap-out", "btc", "sender")
+	SwapCanceled("swap-out", "btc", "sender", "State_SwapCanceled", 0)
+	SwapRequestRejected("swap-in", "lbtc", "peer_not_allowed")
+	SetPollPeers(3, 1)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(swapsStarted.WithLabelValues("swap-out", "btc", "sender")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(swapsCanceled.WithLabelValues("swap-out", "btc", "sender")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(swapRequestsRejected.WithLabelValues("swap-in", "lbtc", "peer_not_allowed")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(pollPeers.WithLabelValues("compatible")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(pollPeers.WithLabelValues("incompatible")))
+}
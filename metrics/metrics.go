@@ -0,0 +1,122 @@
+// Package metrics exports peerswap statistics in the prometheus exposition
+// format. Swap and poll events are recorded by the services that observe
+// them, values that can be read from the current state of the node (active
+// swaps, wallet balances, ...) are collected on every scrape.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "peerswap"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	swapsStarted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "swaps_started_total",
+			Help:      "Number of swaps that were started.",
+		},
+		[]string{"type", "asset", "role"},
+	)
+
+	swapsCompleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "swaps_completed_total",
+			Help:      "Number of swaps that were claimed, labeled by the claim path.",
+		},
+		[]string{"type", "asset", "role", "state"},
+	)
+
+	swapsCanceled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "swaps_canceled_total",
+			Help:      "Number of swaps that were canceled.",
+		},
+		[]string{"type", "asset", "role"},
+	)
+
+	swapDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "swap_duration_seconds",
+			Help:      "Time from the creation of a swap until it finished.",
+			// 1 minute up to ~11 days.
+			Buckets: prometheus.ExponentialBuckets(60, 2, 15),
+		},
+		[]string{"type", "asset", "role", "state"},
+	)
+
+	swapRequestsRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "swap_requests_rejected_total",
+			Help:      "Number of incoming swap requests that were rejected.",
+		},
+		[]string{"type", "asset", "reason"},
+	)
+
+	pollPeers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "poll_peers",
+			Help:      "Number of peers that sent a poll, by protocol compatibility.",
+		},
+		[]string{"compatibility"},
+	)
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		swapsStarted,
+		swapsCompleted,
+		swapsCanceled,
+		swapDuration,
+		swapRequestsRejected,
+		pollPeers,
+	)
+}
+
+// Register adds a collector to the peerswap metrics registry.
+func Register(c prometheus.Collector) error {
+	return registry.Register(c)
+}
+
+// SwapStarted counts a new swap.
+func SwapStarted(swapType, asset, role string) {
+	swapsStarted.WithLabelValues(swapType, asset, role).Inc()
+}
+
+// SwapCompleted counts a swap that was claimed via the claim path `state` and
+// observes its duration.
+func SwapCompleted(swapType, asset, role, state string, duration time.Duration) {
+	swapsCompleted.WithLabelValues(swapType, asset, role, state).Inc()
+	swapDuration.WithLabelValues(swapType, asset, role, state).Observe(duration.Seconds())
+}
+
+// SwapCanceled counts a canceled swap and observes its duration.
+func SwapCanceled(swapType, asset, role, state string, duration time.Duration) {
+	swapsCanceled.WithLabelValues(swapType, asset, role).Inc()
+	swapDuration.WithLabelValues(swapType, asset, role, state).Observe(duration.Seconds())
+}
+
+// SwapRequestRejected counts an incoming swap request that we rejected.
+func SwapRequestRejected(swapType, asset, reason string) {
+	swapRequestsRejected.WithLabelValues(swapType, asset, reason).Inc()
+}
+
+// SetPollPeers sets the number of compatible and incompatible peers we
+// received a poll from.
+func SetPollPeers(compatible, incompatible int) {
+	pollPeers.WithLabelValues("compatible").Set(float64(compatible))
+	pollPeers.WithLabelValues("incompatible").Set(float64(incompatible))
+}
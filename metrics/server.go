@@ -0,0 +1,15 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ListenAndServe serves the metrics on `host` under the path `/metrics`. It
+// blocks until the http server returns.
+func ListenAndServe(host string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return http.ListenAndServe(host, mux)
+}
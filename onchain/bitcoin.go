@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/elementsproject/peerswap/lightning"
 	"github.com/elementsproject/peerswap/log"
 	"github.com/elementsproject/peerswap/swap"
 )
@@ -79,6 +83,19 @@ func (b *BitcoinOnChain) GetChain() *chaincfg.Params {
 	return b.chain
 }
 
+// ValidateAddress returns an error if address is not a valid address on the
+// network of the chain.
+func (b *BitcoinOnChain) ValidateAddress(address string) error {
+	addr, err := btcutil.DecodeAddress(address, b.chain)
+	if err != nil {
+		return err
+	}
+	if !addr.IsForNet(b.chain) {
+		return fmt.Errorf("address is not for network %s", b.chain.Name)
+	}
+	return nil
+}
+
 func (b *BitcoinOnChain) ValidateTx(swapParams *swap.OpeningParams, openingTxHex string) (bool, error) {
 	msgTx := wire.NewMsgTx(2)
 
@@ -91,36 +108,18 @@ func (b *BitcoinOnChain) ValidateTx(swapParams *swap.OpeningParams, openingTxHex
 		return false, err
 	}
 
-	var scriptOut *wire.TxOut
-
-	for _, out := range msgTx.TxOut {
-		if out.Value == int64(swapParams.Amount) {
-			scriptOut = out
-			break
-		}
-	}
-	if scriptOut == nil {
-		return false, nil
-	}
-
-	redeemScript, err := ParamsToTxScript(swapParams, BitcoinCsv)
-	if err != nil {
-		return false, err
-	}
-	witnessProgram := sha256.Sum256(redeemScript)
-	addr, err := btcutil.NewAddressWitnessScriptHash(witnessProgram[:], b.chain)
-	if err != nil {
-		return false, err
-	}
-	wantScript, err := txscript.NewScriptBuilder().AddData([]byte{0x00}).AddData(addr.ScriptAddress()).Script()
+	wantScript, err := b.GetOutputScript(swapParams)
 	if err != nil {
 		return false, err
 	}
 
-	if bytes.Compare(wantScript, scriptOut.PkScript) != 0 {
-		return false, err
+	// A batched opening tx pays several swaps, possibly of the same amount.
+	for _, out := range msgTx.TxOut {
+		if out.Value == int64(swapParams.Amount) && bytes.Equal(wantScript, out.PkScript) {
+			return true, nil
+		}
 	}
-	return true, nil
+	return false, nil
 }
 
 func (b *BitcoinOnChain) TxIdFromHex(txHex string) (string, error) {
@@ -139,41 +138,60 @@ func (b *BitcoinOnChain) TxIdFromHex(txHex string) (string, error) {
 	return msgTx.TxHash().String(), nil
 }
 
-func (b *BitcoinOnChain) GetVoutAndVerify(txHex string, params *swap.OpeningParams) (bool, uint32, error) {
+// GetPreimageFromSpendingTx returns the txid of the tx that spends the
+// opening output `openingTxId:vout` and the preimage revealed by the
+// spending witness. The preimage is empty if the output is spent via the csv
+// or the cooperative path.
+func (b *BitcoinOnChain) GetPreimageFromSpendingTx(openingTxId string, vout uint32, txHex string) (string, string, error) {
 	msgTx := wire.NewMsgTx(2)
 
 	txBytes, err := hex.DecodeString(txHex)
 	if err != nil {
-		return false, 0, err
+		return "", "", err
 	}
+
 	err = msgTx.Deserialize(bytes.NewReader(txBytes))
 	if err != nil {
-		return false, 0, err
+		return "", "", err
 	}
 
-	var scriptOut *wire.TxOut
-	var vout uint32
-	for i, out := range msgTx.TxOut {
-		if out.Value == int64(params.Amount) {
-			scriptOut = out
-			vout = uint32(i)
-			break
+	for _, in := range msgTx.TxIn {
+		if in.PreviousOutPoint.Hash.String() != openingTxId || in.PreviousOutPoint.Index != vout {
+			continue
 		}
+		preimage, ok := GetPreimageFromWitness(in.Witness)
+		if !ok {
+			return msgTx.TxHash().String(), "", nil
+		}
+		return msgTx.TxHash().String(), hex.EncodeToString(preimage), nil
 	}
-	if scriptOut == nil {
+	return "", "", fmt.Errorf("tx %s does not spend %s:%d", msgTx.TxHash().String(), openingTxId, vout)
+}
+
+func (b *BitcoinOnChain) GetVoutAndVerify(txHex string, params *swap.OpeningParams) (bool, uint32, error) {
+	msgTx := wire.NewMsgTx(2)
+
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
 		return false, 0, err
 	}
-
-	wantScript, err := b.GetOutputScript(params)
+	err = msgTx.Deserialize(bytes.NewReader(txBytes))
 	if err != nil {
 		return false, 0, err
 	}
 
-	if bytes.Compare(wantScript, scriptOut.PkScript) != 0 {
+	wantScript, err := b.GetOutputScript(params)
+	if err != nil {
 		return false, 0, err
 	}
 
-	return true, vout, nil
+	// A batched opening tx pays several swaps, possibly of the same amount.
+	for i, out := range msgTx.TxOut {
+		if out.Value == int64(params.Amount) && bytes.Equal(wantScript, out.PkScript) {
+			return true, uint32(i), nil
+		}
+	}
+	return false, 0, nil
 }
 
 func (b *BitcoinOnChain) GetOutputScript(params *swap.OpeningParams) ([]byte, error) {
@@ -254,6 +272,88 @@ func (b *BitcoinOnChain) PrepareSpendingTransaction(swapParams *swap.OpeningPara
 	return spendingTx, sigHash, redeemScript, nil
 }
 
+// CreatePreimageSweepTransaction returns a signed tx that spends the opening
+// outputs of all claims with their preimages to spendingAddr.
+func (b *BitcoinOnChain) CreatePreimageSweepTransaction(claims []*swap.PreimageClaim, spendingAddr string) (*wire.MsgTx, error) {
+	if len(claims) == 0 {
+		return nil, errors.New("no claims to sweep")
+	}
+
+	addr, err := btcutil.DecodeAddress(spendingAddr, b.chain)
+	if err != nil {
+		return nil, err
+	}
+	spendingScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	sweepTx := wire.NewMsgTx(2)
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	redeemScripts := make([][]byte, len(claims))
+	var total int64
+	for i, claim := range claims {
+		openingMsgTx := wire.NewMsgTx(2)
+		txBytes, err := hex.DecodeString(claim.ClaimParams.OpeningTxHex)
+		if err != nil {
+			return nil, err
+		}
+		err = openingMsgTx.Deserialize(bytes.NewReader(txBytes))
+		if err != nil {
+			return nil, err
+		}
+		ok, vout, err := b.GetVoutAndVerify(claim.ClaimParams.OpeningTxHex, claim.OpeningParams)
+		if err != nil {
+			return nil, fmt.Errorf("swap %s: %w", claim.SwapId, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("swap %s: opening output not found", claim.SwapId)
+		}
+
+		redeemScripts[i], err = ParamsToTxScript(claim.OpeningParams, BitcoinCsv)
+		if err != nil {
+			return nil, err
+		}
+
+		prevHash := openingMsgTx.TxHash()
+		prevOut := wire.NewOutPoint(&prevHash, vout)
+		prevOuts.AddPrevOut(*prevOut, openingMsgTx.TxOut[vout])
+		sweepTx.AddTxIn(wire.NewTxIn(prevOut, nil, [][]byte{}))
+		total += openingMsgTx.TxOut[vout].Value
+	}
+	sweepTx.AddTxOut(wire.NewTxOut(total, spendingScript))
+
+	// assume largest witness per input
+	fee, err := b.GetFee(int64(sweepTx.SerializeSizeStripped() + 74*len(claims)))
+	if err != nil {
+		return nil, err
+	}
+	sweepTx.TxOut[0].Value -= int64(fee)
+	if sweepTx.TxOut[0].Value <= 0 {
+		return nil, fmt.Errorf("claims of %d sat do not cover the fee of %d sat", total, fee)
+	}
+
+	sigHashes := txscript.NewTxSigHashes(sweepTx, prevOuts)
+	for i, claim := range claims {
+		prevOut := prevOuts.FetchPrevOutput(sweepTx.TxIn[i].PreviousOutPoint)
+		sigHash, err := txscript.CalcWitnessSigHash(redeemScripts[i], sigHashes, txscript.SigHashAll, sweepTx, i, prevOut.Value)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := claim.ClaimParams.Signer.Sign(sigHash)
+		if err != nil {
+			return nil, err
+		}
+		preimage, err := lightning.MakePreimageFromStr(claim.ClaimParams.Preimage)
+		if err != nil {
+			return nil, err
+		}
+		sweepTx.TxIn[i].Witness = GetPreimageWitness(sig.Serialize(), preimage[:], redeemScripts[i])
+	}
+
+	return sweepTx, nil
+}
+
 func (b *BitcoinOnChain) CreateOpeningAddress(params *swap.OpeningParams, csv uint32) (string, error) {
 	redeemScript, err := ParamsToTxScript(params, csv)
 	if err != nil {
@@ -267,6 +367,55 @@ func (b *BitcoinOnChain) CreateOpeningAddress(params *swap.OpeningParams, csv ui
 	return addr.EncodeAddress(), nil
 }
 
+// CreateOpeningPsbt returns an unsigned base64 PSBT without inputs that pays
+// the swap amount to the opening address.
+func (b *BitcoinOnChain) CreateOpeningPsbt(params *swap.OpeningParams) (string, error) {
+	script, err := b.GetOutputScript(params)
+	if err != nil {
+		return "", err
+	}
+	packet, err := psbt.New(nil, []*wire.TxOut{wire.NewTxOut(int64(params.Amount), script)}, 2, 0, nil)
+	if err != nil {
+		return "", err
+	}
+	return packet.B64Encode()
+}
+
+// ExtractOpeningTx returns the hex of a signed opening tx and its opening
+// output. signedTx is either a signed base64 PSBT or a raw tx hex.
+func (b *BitcoinOnChain) ExtractOpeningTx(params *swap.OpeningParams, signedTx string) (string, uint32, error) {
+	txHex := strings.TrimSpace(signedTx)
+	if _, err := hex.DecodeString(txHex); err != nil {
+		packet, err := psbt.NewFromRawBytes(strings.NewReader(txHex), true)
+		if err != nil {
+			return "", 0, fmt.Errorf("expected a signed psbt or tx hex: %w", err)
+		}
+		err = psbt.MaybeFinalizeAll(packet)
+		if err != nil {
+			return "", 0, fmt.Errorf("could not finalize psbt: %w", err)
+		}
+		tx, err := psbt.Extract(packet)
+		if err != nil {
+			return "", 0, err
+		}
+		bytesBuffer := new(bytes.Buffer)
+		err = tx.Serialize(bytesBuffer)
+		if err != nil {
+			return "", 0, err
+		}
+		txHex = hex.EncodeToString(bytesBuffer.Bytes())
+	}
+
+	ok, vout, err := b.GetVoutAndVerify(txHex, params)
+	if err != nil {
+		return "", 0, err
+	}
+	if !ok {
+		return "", 0, errors.New("tx does not pay the swap amount to the opening address")
+	}
+	return txHex, vout, nil
+}
+
 func (b *BitcoinOnChain) GetFeeSatsFromTx(psbtString, txHex string) (uint64, error) {
 	rawPsbt, err := psbt.NewFromRawBytes(bytes.NewReader([]byte(psbtString)), true)
 	if err != nil {
@@ -1,10 +1,21 @@
 package onchain
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/elementsproject/peerswap/swap"
 	"github.com/stretchr/testify/require"
 )
 
@@ -94,3 +105,172 @@ func (e *EstimatorMock) EstimateFeePerKW(targetBlocks uint32) (btcutil.Amount, e
 func (e *EstimatorMock) Start() error {
 	panic("not implemented") // We dont need this function.
 }
+
+func TestBitcoinOnChain_GetPreimageFromSpendingTx(t *testing.T) {
+	btcOnChain := NewBitcoinOnChain(&EstimatorMock{}, 0, &chaincfg.RegressionNetParams)
+
+	openingTxId := "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	openingHash, err := chainhash.NewHashFromStr(openingTxId)
+	require.NoError(t, err)
+	preimage := bytes.Repeat([]byte{0x01}, 32)
+	sig := bytes.Repeat([]byte{0x02}, 71)
+	redeemScript := []byte{0x03}
+
+	spendingTx := func(witness [][]byte) string {
+		tx := wire.NewMsgTx(2)
+		in := wire.NewTxIn(wire.NewOutPoint(openingHash, 1), nil, witness)
+		tx.AddTxIn(in)
+		tx.AddTxOut(wire.NewTxOut(1000, []byte{0x00}))
+		var buf bytes.Buffer
+		require.NoError(t, tx.Serialize(&buf))
+		return hex.EncodeToString(buf.Bytes())
+	}
+
+	// Preimage path.
+	_, gotPreimage, err := btcOnChain.GetPreimageFromSpendingTx(openingTxId, 1, spendingTx(GetPreimageWitness(sig, preimage, redeemScript)))
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(preimage), gotPreimage)
+
+	// Csv and cooperative path.
+	_, gotPreimage, err = btcOnChain.GetPreimageFromSpendingTx(openingTxId, 1, spendingTx(GetCsvWitness(sig, redeemScript)))
+	require.NoError(t, err)
+	require.Empty(t, gotPreimage)
+	_, gotPreimage, err = btcOnChain.GetPreimageFromSpendingTx(openingTxId, 1, spendingTx(GetCooperativeWitness(sig, sig, redeemScript)))
+	require.NoError(t, err)
+	require.Empty(t, gotPreimage)
+
+	// Other outputs.
+	_, _, err = btcOnChain.GetPreimageFromSpendingTx(openingTxId, 0, spendingTx(GetPreimageWitness(sig, preimage, redeemScript)))
+	require.Error(t, err)
+}
+
+func TestBitcoinOnChain_OpeningPsbt(t *testing.T) {
+	btcOnChain := NewBitcoinOnChain(&EstimatorMock{}, 0, &chaincfg.RegressionNetParams)
+	swapParams := &swap.OpeningParams{
+		TakerPubkey:      "02752e1beeeeb6472959117a0aa5d172900680c033ddf86b1a8318311e2b10223f",
+		MakerPubkey:      "02c30ff537639962f493d326a77f1c6cb591ee3d21ca8d89194bb69cb288f497e8",
+		ClaimPaymentHash: "b94f26d422d5ce3a1e65dd4abb398d0d369aefe8f71d112c5591aa45eea1e75c",
+		Amount:           5000,
+	}
+	wantScript, err := btcOnChain.GetOutputScript(swapParams)
+	require.NoError(t, err)
+
+	b64, err := btcOnChain.CreateOpeningPsbt(swapParams)
+	require.NoError(t, err)
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader([]byte(b64)), true)
+	require.NoError(t, err)
+	require.Empty(t, packet.UnsignedTx.TxIn)
+	require.Len(t, packet.UnsignedTx.TxOut, 1)
+	require.Equal(t, int64(5000), packet.UnsignedTx.TxOut[0].Value)
+	require.Equal(t, wantScript, packet.UnsignedTx.TxOut[0].PkScript)
+
+	// Fund the psbt with a change output in front of the opening output and
+	// sign it.
+	fundingHash, err := chainhash.NewHashFromStr("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	require.NoError(t, err)
+	packet.UnsignedTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(fundingHash, 0), nil, nil))
+	packet.UnsignedTx.TxOut = append([]*wire.TxOut{wire.NewTxOut(1000, []byte{0x00, 0x14})}, packet.UnsignedTx.TxOut...)
+	var witness bytes.Buffer
+	require.NoError(t, psbt.WriteTxWitness(&witness, [][]byte{bytes.Repeat([]byte{0x01}, 71)}))
+	packet.Inputs = append(packet.Inputs, psbt.PInput{FinalScriptWitness: witness.Bytes()})
+	packet.Outputs = append([]psbt.POutput{{}}, packet.Outputs...)
+	signed, err := packet.B64Encode()
+	require.NoError(t, err)
+
+	txHex, vout, err := btcOnChain.ExtractOpeningTx(swapParams, signed)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), vout)
+
+	// The raw tx is accepted as well.
+	_, vout, err = btcOnChain.ExtractOpeningTx(swapParams, txHex)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), vout)
+
+	// A tx that pays another amount is rejected.
+	swapParams.Amount = 4000
+	_, _, err = btcOnChain.ExtractOpeningTx(swapParams, signed)
+	require.Error(t, err)
+}
+
+type testSigner struct {
+	key *btcec.PrivateKey
+}
+
+func (s *testSigner) Sign(hash []byte) (*ecdsa.Signature, error) {
+	return ecdsa.Sign(s.key, hash), nil
+}
+
+func TestBitcoinOnChain_CreatePreimageSweepTransaction(t *testing.T) {
+	btcOnChain := NewBitcoinOnChain(&EstimatorMock{EstimateFeePerKWReturn: 1000}, 0, &chaincfg.RegressionNetParams)
+
+	makerKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	var claims []*swap.PreimageClaim
+	for i := 0; i < 2; i++ {
+		takerKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		preimage := bytes.Repeat([]byte{byte(i + 1)}, 32)
+		paymentHash := sha256.Sum256(preimage)
+		openingParams := &swap.OpeningParams{
+			TakerPubkey:      hex.EncodeToString(takerKey.PubKey().SerializeCompressed()),
+			MakerPubkey:      hex.EncodeToString(makerKey.PubKey().SerializeCompressed()),
+			ClaimPaymentHash: hex.EncodeToString(paymentHash[:]),
+			Amount:           uint64(100000 * (i + 1)),
+		}
+		script, err := btcOnChain.GetOutputScript(openingParams)
+		require.NoError(t, err)
+
+		// The opening output is not the first output of the opening tx.
+		openingTx := wire.NewMsgTx(2)
+		openingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(i)}, 0), nil, nil))
+		openingTx.AddTxOut(wire.NewTxOut(5000, []byte{0x00, 0x14}))
+		openingTx.AddTxOut(wire.NewTxOut(int64(openingParams.Amount), script))
+		var buf bytes.Buffer
+		require.NoError(t, openingTx.Serialize(&buf))
+
+		claims = append(claims, &swap.PreimageClaim{
+			SwapId:        fmt.Sprintf("swap%d", i),
+			OpeningParams: openingParams,
+			ClaimParams: &swap.ClaimParams{
+				Preimage:     hex.EncodeToString(preimage),
+				Signer:       &testSigner{takerKey},
+				OpeningTxHex: hex.EncodeToString(buf.Bytes()),
+			},
+		})
+	}
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(bytes.Repeat([]byte{0x01}, 20), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	sweepTx, err := btcOnChain.CreatePreimageSweepTransaction(claims, addr.EncodeAddress())
+	require.NoError(t, err)
+	require.Len(t, sweepTx.TxIn, 2)
+	require.Len(t, sweepTx.TxOut, 1)
+	require.Less(t, sweepTx.TxOut[0].Value, int64(300000))
+	require.Greater(t, sweepTx.TxOut[0].Value, int64(298000))
+
+	// All inputs spend their opening output with a valid preimage witness.
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for _, claim := range claims {
+		openingTx := wire.NewMsgTx(2)
+		txBytes, err := hex.DecodeString(claim.ClaimParams.OpeningTxHex)
+		require.NoError(t, err)
+		require.NoError(t, openingTx.Deserialize(bytes.NewReader(txBytes)))
+		hash := openingTx.TxHash()
+		prevOuts.AddPrevOut(*wire.NewOutPoint(&hash, 1), openingTx.TxOut[1])
+	}
+	sigHashes := txscript.NewTxSigHashes(sweepTx, prevOuts)
+	for i, in := range sweepTx.TxIn {
+		prevOut := prevOuts.FetchPrevOutput(in.PreviousOutPoint)
+		require.NotNil(t, prevOut)
+		vm, err := txscript.NewEngine(prevOut.PkScript, sweepTx, i, txscript.StandardVerifyFlags, nil, sigHashes, prevOut.Value, prevOuts)
+		require.NoError(t, err)
+		require.NoError(t, vm.Execute())
+	}
+
+	// A claim with an opening tx that does not pay to its opening output is
+	// rejected.
+	claims[1].OpeningParams.Amount = 1
+	_, err = btcOnChain.CreatePreimageSweepTransaction(claims, addr.EncodeAddress())
+	require.Error(t, err)
+}
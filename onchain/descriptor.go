@@ -0,0 +1,249 @@
+package onchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/vulpemventures/go-elements/network"
+	"github.com/vulpemventures/go-elements/payment"
+	"github.com/vulpemventures/go-elements/slip77"
+)
+
+// ClaimDescriptor derives fresh addresses from a ranged wpkh output
+// descriptor, e.g. of a cold storage wallet, that swap-outs are claimed to.
+type ClaimDescriptor struct {
+	// key is the extended public key derived up to the ranged element.
+	key *hdkeychain.ExtendedKey
+	// address returns the address of the pubkey at an index.
+	address func(pubkey *btcec.PublicKey) (string, error)
+}
+
+// NewClaimDescriptor parses a bitcoin descriptor of the form
+// `wpkh(xpub/0/*)`. A bare extended public key is read as `wpkh(xpub/0/*)`.
+func (b *BitcoinOnChain) NewClaimDescriptor(descriptor string) (*ClaimDescriptor, error) {
+	desc, err := trimDescriptorChecksum(descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyExpr string
+	if !strings.Contains(desc, "(") {
+		keyExpr = desc + "/0/*"
+	} else if keyExpr, err = unwrapDescriptor(desc, "wpkh"); err != nil {
+		return nil, err
+	}
+
+	key, err := parseRangedKey(keyExpr, b.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClaimDescriptor{
+		key: key,
+		address: func(pubkey *btcec.PublicKey) (string, error) {
+			addr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubkey.SerializeCompressed()), b.chain)
+			if err != nil {
+				return "", err
+			}
+			return addr.EncodeAddress(), nil
+		},
+	}, nil
+}
+
+// NewClaimDescriptor parses a liquid descriptor of the form
+// `ct(slip77(<master blinding key>),elwpkh(xpub/0/*))`. The claim output must
+// be blinded, so a descriptor without a blinding key is not accepted.
+func (l *LiquidOnChain) NewClaimDescriptor(descriptor string) (*ClaimDescriptor, error) {
+	desc, err := trimDescriptorChecksum(descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	inner, err := unwrapDescriptor(desc, "ct")
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(inner, ",", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid descriptor: expected ct(slip77(<key>),elwpkh(<key>))")
+	}
+	masterKeyHex, err := unwrapDescriptor(parts[0], "slip77")
+	if err != nil {
+		return nil, err
+	}
+	masterKey, err := hex.DecodeString(masterKeyHex)
+	if err != nil || len(masterKey) != 32 {
+		return nil, fmt.Errorf("invalid descriptor: slip77 master blinding key must be 32 bytes hex")
+	}
+	blinding, err := slip77.FromMasterKey(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	keyExpr, err := unwrapDescriptor(parts[1], "elwpkh")
+	if err != nil {
+		return nil, err
+	}
+
+	// Liquid uses the extended key versions of bitcoin mainnet, the test
+	// networks use the testnet versions.
+	chain := &chaincfg.TestNet3Params
+	if l.network.Name == network.Liquid.Name {
+		chain = &chaincfg.MainNetParams
+	}
+	key, err := parseRangedKey(keyExpr, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClaimDescriptor{
+		key: key,
+		address: func(pubkey *btcec.PublicKey) (string, error) {
+			script := payment.FromPublicKey(pubkey, l.network, nil).WitnessScript
+			_, blindingPubkey, err := blinding.DeriveKey(script)
+			if err != nil {
+				return "", err
+			}
+			return payment.FromPublicKey(pubkey, l.network, blindingPubkey).ConfidentialWitnessPubKeyHash()
+		},
+	}, nil
+}
+
+// Address returns the address at index of the ranged descriptor.
+func (d *ClaimDescriptor) Address(index uint32) (string, error) {
+	child, err := d.key.Derive(index)
+	if err != nil {
+		return "", err
+	}
+	pubkey, err := child.ECPubKey()
+	if err != nil {
+		return "", err
+	}
+	return d.address(pubkey)
+}
+
+// unwrapDescriptor returns the argument of the descriptor function fn.
+func unwrapDescriptor(desc, fn string) (string, error) {
+	desc = strings.TrimSpace(desc)
+	if !strings.HasPrefix(desc, fn+"(") || !strings.HasSuffix(desc, ")") {
+		return "", fmt.Errorf("invalid descriptor: expected %s(...), got %s", fn, desc)
+	}
+	return strings.TrimSpace(desc[len(fn)+1 : len(desc)-1]), nil
+}
+
+// parseRangedKey parses a key expression `[origin]xpub/<path>/*` and returns
+// the extended key derived along path. Only public keys and unhardened
+// derivation are accepted as the addresses are derived without the private
+// key.
+func parseRangedKey(keyExpr string, chain *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+	// The key origin is only informational.
+	if strings.HasPrefix(keyExpr, "[") {
+		end := strings.Index(keyExpr, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid descriptor: unterminated key origin")
+		}
+		keyExpr = keyExpr[end+1:]
+	}
+
+	elements := strings.Split(keyExpr, "/")
+	if len(elements) < 2 || elements[len(elements)-1] != "*" {
+		return nil, fmt.Errorf("invalid descriptor: key must be ranged and end with /*")
+	}
+
+	key, err := hdkeychain.NewKeyFromString(elements[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor: %w", err)
+	}
+	if key.IsPrivate() {
+		return nil, fmt.Errorf("invalid descriptor: use an extended public key")
+	}
+	if !key.IsForNet(chain) {
+		return nil, fmt.Errorf("invalid descriptor: key is not for network %s", chain.Name)
+	}
+
+	for _, element := range elements[1 : len(elements)-1] {
+		index, err := strconv.ParseUint(element, 10, 32)
+		if err != nil || index >= hdkeychain.HardenedKeyStart {
+			return nil, fmt.Errorf("invalid descriptor: invalid unhardened path element %s", element)
+		}
+		key, err = key.Derive(uint32(index))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+const (
+	descriptorInputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// trimDescriptorChecksum verifies the checksum of the descriptor if it has
+// one and returns the descriptor without it.
+func trimDescriptorChecksum(descriptor string) (string, error) {
+	desc, checksum, found := strings.Cut(strings.TrimSpace(descriptor), "#")
+	if !found {
+		return desc, nil
+	}
+	want, err := descriptorChecksum(desc)
+	if err != nil {
+		return "", err
+	}
+	if checksum != want {
+		return "", errors.New("invalid descriptor checksum")
+	}
+	return desc, nil
+}
+
+// descriptorChecksum returns the checksum of a descriptor as defined in
+// BIP-380.
+func descriptorChecksum(desc string) (string, error) {
+	polymod := func(c uint64, val uint64) uint64 {
+		c0 := c >> 35
+		c = ((c & 0x7ffffffff) << 5) ^ val
+		for i, g := range []uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd} {
+			if (c0>>i)&1 == 1 {
+				c ^= g
+			}
+		}
+		return c
+	}
+
+	c := uint64(1)
+	cls := uint64(0)
+	clscount := 0
+	for _, ch := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid descriptor character %q", ch)
+		}
+		c = polymod(c, uint64(pos)&31)
+		cls = cls*3 + uint64(pos)>>5
+		clscount++
+		if clscount == 3 {
+			c = polymod(c, cls)
+			cls = 0
+			clscount = 0
+		}
+	}
+	if clscount > 0 {
+		c = polymod(c, cls)
+	}
+	for i := 0; i < 8; i++ {
+		c = polymod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*(7-i)))&31]
+	}
+	return string(checksum), nil
+}
@@ -0,0 +1,128 @@
+package onchain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulpemventures/go-elements/address"
+	"github.com/vulpemventures/go-elements/network"
+	"github.com/vulpemventures/go-elements/payment"
+)
+
+// bip84Xpub is the account xpub of the BIP-84 test vector.
+const bip84Xpub = "xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V"
+
+func TestDescriptorChecksum(t *testing.T) {
+	// Test vectors of BIP-380.
+	checksum, err := descriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	assert.Equal(t, "89f8spxm", checksum)
+
+	checksum, err = descriptorChecksum("addr(mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j)")
+	require.NoError(t, err)
+	assert.Equal(t, "02wpgw69", checksum)
+
+	_, err = trimDescriptorChecksum("raw(deadbeef)#89f8spxx")
+	assert.Error(t, err)
+}
+
+func TestBitcoinOnChain_NewClaimDescriptor(t *testing.T) {
+	b := NewBitcoinOnChain(nil, 0, &chaincfg.MainNetParams)
+
+	desc := "wpkh([73c5da0a/84h/0h/0h]" + bip84Xpub + "/0/*)"
+	checksum, err := descriptorChecksum(desc)
+	require.NoError(t, err)
+
+	for _, d := range []string{desc, desc + "#" + checksum, bip84Xpub} {
+		descriptor, err := b.NewClaimDescriptor(d)
+		require.NoError(t, err, d)
+
+		// Receive addresses of the BIP-84 test vector.
+		addr, err := descriptor.Address(0)
+		require.NoError(t, err)
+		assert.Equal(t, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", addr)
+		addr, err = descriptor.Address(1)
+		require.NoError(t, err)
+		assert.Equal(t, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", addr)
+	}
+
+	for _, d := range []string{
+		"pkh(" + bip84Xpub + "/0/*)",
+		"wpkh(" + bip84Xpub + "/0/0)",
+		"wpkh(" + bip84Xpub + "/0h/*)",
+		"wpkh(" + bip84Xpub + "/0/*h)",
+	} {
+		_, err := b.NewClaimDescriptor(d)
+		assert.Error(t, err, d)
+	}
+
+	// The key must match the network.
+	_, err = NewBitcoinOnChain(nil, 0, &chaincfg.RegressionNetParams).NewClaimDescriptor(desc)
+	assert.Error(t, err)
+}
+
+func TestLiquidOnChain_NewClaimDescriptor(t *testing.T) {
+	l := NewLiquidOnChain(nil, nil, &network.Regtest)
+
+	master, err := hdkeychain.NewMaster(bytes.Repeat([]byte{1}, 32), &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	tpub, err := master.Neuter()
+	require.NoError(t, err)
+	masterBlindingKey := strings.Repeat("ab", 32)
+
+	// The blinding key is mandatory.
+	_, err = l.NewClaimDescriptor("elwpkh(" + tpub.String() + "/0/*)")
+	assert.Error(t, err)
+
+	descriptor, err := l.NewClaimDescriptor("ct(slip77(" + masterBlindingKey + "),elwpkh(" + tpub.String() + "/0/*))")
+	require.NoError(t, err)
+
+	addr, err := descriptor.Address(3)
+	require.NoError(t, err)
+	assert.NoError(t, l.ValidateAddress(addr))
+
+	// The address pays to the derived key.
+	child, err := tpub.Derive(0)
+	require.NoError(t, err)
+	child, err = child.Derive(3)
+	require.NoError(t, err)
+	pubkey, err := child.ECPubKey()
+	require.NoError(t, err)
+	script, err := address.ToOutputScript(addr)
+	require.NoError(t, err)
+	assert.Equal(t, payment.FromPublicKey(pubkey, &network.Regtest, nil).WitnessScript, script)
+
+	// Mainnet keys are rejected on regtest.
+	_, err = l.NewClaimDescriptor("ct(slip77(" + masterBlindingKey + "),elwpkh(" + bip84Xpub + "/0/*))")
+	assert.Error(t, err)
+}
+
+func TestValidateAddress(t *testing.T) {
+	b := NewBitcoinOnChain(nil, 0, &chaincfg.MainNetParams)
+	assert.NoError(t, b.ValidateAddress("bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"))
+	assert.Error(t, b.ValidateAddress("mkmZxiEcEd8ZqjQWVZuC6so5dFMKEFpN2j"))
+	assert.Error(t, b.ValidateAddress("invalid"))
+
+	l := NewLiquidOnChain(nil, nil, &network.Regtest)
+	master, err := hdkeychain.NewMaster(bytes.Repeat([]byte{1}, 32), &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	key, err := master.ECPubKey()
+	require.NoError(t, err)
+
+	unconfidential, err := payment.FromPublicKey(key, &network.Regtest, nil).WitnessPubKeyHash()
+	require.NoError(t, err)
+	assert.Error(t, l.ValidateAddress(unconfidential))
+
+	confidential, err := payment.FromPublicKey(key, &network.Regtest, key).ConfidentialWitnessPubKeyHash()
+	require.NoError(t, err)
+	assert.NoError(t, l.ValidateAddress(confidential))
+
+	mainnet, err := payment.FromPublicKey(key, &network.Liquid, key).ConfidentialWitnessPubKeyHash()
+	require.NoError(t, err)
+	assert.Error(t, l.ValidateAddress(mainnet))
+}
@@ -217,6 +217,94 @@ func (l *LndEstimator) Start() error {
 	return nil
 }
 
+// EsploraBackend is fulfilled by the esplora client.
+type EsploraBackend interface {
+	// GetFeeEstimates returns the fee estimates in sat/vb by confirmation
+	// target in blocks.
+	GetFeeEstimates() (map[uint32]float64, error)
+}
+
+// EsploraEstimator uses the fee estimates of an esplora http api, e.g. a
+// self-hosted electrs.
+type EsploraEstimator struct {
+	esplora EsploraBackend
+
+	// floorFeeRate is the lowest fee rate in sat/kw that is returned.
+	floorFeeRate btcutil.Amount
+
+	// fallbackFeeRate is returned in case that the estimator has an error or not
+	// enough information to calculate a fee. This value is in sat/kw.
+	fallbackFeeRate btcutil.Amount
+}
+
+func NewEsploraEstimator(
+	esplora EsploraBackend,
+	floorFeeRate btcutil.Amount,
+	fallbackFeeRate btcutil.Amount,
+) (*EsploraEstimator, error) {
+	return &EsploraEstimator{
+		esplora:         esplora,
+		floorFeeRate:    floorFeeRate,
+		fallbackFeeRate: fallbackFeeRate,
+	}, nil
+}
+
+// EstimateFeePerKw returns the estimated fee in sat/kw for a transaction
+// that should be confirmed in targetBlocks. Esplora only returns estimates
+// for some targets, the estimate of the highest target that is not above
+// targetBlocks is used.
+func (e *EsploraEstimator) EstimateFeePerKW(targetBlocks uint32) (btcutil.Amount, error) {
+	estimates, err := e.esplora.GetFeeEstimates()
+	if err != nil {
+		log.Infof("Could not fetch on-chain fee from esplora: %v", err)
+		return e.fallbackFeeRate, nil
+	}
+
+	satPerVb, ok := pickFeeEstimate(estimates, targetBlocks)
+	if !ok || satPerVb == 0 {
+		log.Debugf("Estimated fee is 0, using fallback fee %d", e.fallbackFeeRate)
+		return e.fallbackFeeRate, nil
+	}
+
+	// Convert sat/vb to sat/kw. A vbyte is 4 weight units.
+	satPerKw := btcutil.Amount(satPerVb * 1000 / witnessScaleFactor)
+	if satPerKw < e.floorFeeRate {
+		log.Debugf("Estimated fee rate %v sat/kw is too low, using floor %v sat/kw", satPerKw, e.floorFeeRate)
+		satPerKw = e.floorFeeRate
+	}
+	return satPerKw, nil
+}
+
+// pickFeeEstimate returns the estimate of the highest target that is not
+// above targetBlocks. If there is none, the estimate of the lowest target is
+// returned.
+func pickFeeEstimate(estimates map[uint32]float64, targetBlocks uint32) (float64, bool) {
+	var best, lowest uint32
+	var found bool
+	for target := range estimates {
+		if target <= targetBlocks && target > best {
+			best = target
+			found = true
+		}
+		if lowest == 0 || target < lowest {
+			lowest = target
+		}
+	}
+	if found {
+		return estimates[best], true
+	}
+	if lowest != 0 {
+		return estimates[lowest], true
+	}
+	return 0, false
+}
+
+// Start is necessary to implement the Estimator interface but is noop for the
+// EsploraEstimator.
+func (e *EsploraEstimator) Start() error {
+	return nil
+}
+
 // RegtestFeeEstimator is used as the Estimator when the bitcoin network is set
 // to "regtest". We need this fee estimator for cln as lnd uses a static fee
 // estimator on regtest that uses a constant fee rate of 12500 sat/kw. See
@@ -2,10 +2,13 @@ package onchain
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/elementsproject/glightning/gbitcoin"
+	"github.com/elementsproject/peerswap/esplora"
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,6 +60,54 @@ func TestGBitcoin_Estimator(t *testing.T) {
 	require.Equal(t, feeRateSatPerKw, fee)
 }
 
+func TestEsplora_Estimator(t *testing.T) {
+	feeEstimates := `{"1":20.5,"6":10,"144":0.5}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fee-estimates" || feeEstimates == "" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(feeEstimates))
+	}))
+	defer srv.Close()
+
+	fallbackFeeRate := btcutil.Amount(6250)
+	feeEstimator, err := NewEsploraEstimator(esplora.NewClient(srv.URL), FeePerKwFloor, fallbackFeeRate)
+	require.NoError(t, err)
+	require.NoError(t, feeEstimator.Start())
+
+	// The highest target that is not above the requested target is used and
+	// converted from sat/vb to sat/kw.
+	fee, err := feeEstimator.EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(2500), fee)
+
+	fee, err = feeEstimator.EstimateFeePerKW(3)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(5125), fee)
+
+	// Below the lowest target the lowest target is used.
+	fee, err = feeEstimator.EstimateFeePerKW(0)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(5125), fee)
+
+	// Check that the fee is floored.
+	fee, err = feeEstimator.EstimateFeePerKW(200)
+	require.NoError(t, err)
+	require.Equal(t, FeePerKwFloor, fee)
+
+	// Check that the fallback fee is returned on errors and empty estimates.
+	feeEstimates = "{}"
+	fee, err = feeEstimator.EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.Equal(t, fallbackFeeRate, fee)
+
+	feeEstimates = ""
+	fee, err = feeEstimator.EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.Equal(t, fallbackFeeRate, fee)
+}
+
 // GBitcoinBackendMock is a mock for the GBitcoinBackend interface that is the
 // RPC proxy we use to connect to bitcoind.
 type GBitcoinBackendMock struct {
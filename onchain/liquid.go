@@ -7,12 +7,15 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/elementsproject/peerswap/log"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/vulpemventures/go-elements/confidential"
 	"github.com/vulpemventures/go-elements/pset"
+	"github.com/vulpemventures/go-elements/psetv2"
 
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/elementsproject/glightning/gelements"
@@ -31,6 +34,9 @@ const (
 	LiquidCsv          = 60
 	LiquidConfs        = 2
 	LiquidTargetBlocks = 7
+
+	// LiquidFeePerKwFloor is the lowest fee rate of 0.1 sat/vb in sat/kw.
+	LiquidFeePerKwFloor btcutil.Amount = 25
 )
 
 type LiquidOnChain struct {
@@ -38,6 +44,9 @@ type LiquidOnChain struct {
 	liquidWallet wallet.Wallet
 	network      *network.Network
 	asset        []byte
+
+	// estimator replaces the fee estimation of elementsd if set.
+	estimator Estimator
 }
 
 func NewLiquidOnChain(elements *gelements.Elements, wallet wallet.Wallet, network *network.Network) *LiquidOnChain {
@@ -49,6 +58,12 @@ func NewLiquidOnChain(elements *gelements.Elements, wallet wallet.Wallet, networ
 	return &LiquidOnChain{elements: elements, liquidWallet: wallet, network: network, asset: lbtc}
 }
 
+// SetFeeEstimator sets an Estimator that is used instead of the fee
+// estimation of elementsd, e.g. an EsploraEstimator.
+func (l *LiquidOnChain) SetFeeEstimator(estimator Estimator) {
+	l.estimator = estimator
+}
+
 func (l *LiquidOnChain) GetCSVHeight() uint32 {
 	return LiquidCsv
 }
@@ -98,6 +113,66 @@ func (l *LiquidOnChain) CreateOpeningTransaction(swapParams *swap.OpeningParams)
 	return unpreparedTxHex, fee, vout, nil
 }
 
+func (l *LiquidOnChain) CreateBatchOpeningTransaction(swapParams []*swap.OpeningParams) (string, string, []uint32, error) {
+	tx := transaction.NewTx(2)
+	redeemScripts := make([][]byte, len(swapParams))
+	for i, params := range swapParams {
+		redeemScript, err := ParamsToTxScript(params, LiquidCsv)
+		if err != nil {
+			return "", "", nil, err
+		}
+		redeemScripts[i] = redeemScript
+
+		blindedScriptAddr, err := l.CreateBlindedOpeningAddress(redeemScript, params.BlindingKey.PubKey())
+		if err != nil {
+			return "", "", nil, err
+		}
+		params.OpeningAddress = blindedScriptAddr
+		outputscript, err := address.ToOutputScript(blindedScriptAddr)
+		if err != nil {
+			return "", "", nil, err
+		}
+		sats, err := elementsutil.ValueToBytes(params.Amount)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		output := transaction.NewTxOutput(l.asset, sats, outputscript)
+		output.Nonce = params.BlindingKey.PubKey().SerializeCompressed()
+		tx.Outputs = append(tx.Outputs, output)
+	}
+
+	unpreparedTxHex, _, err := l.liquidWallet.CreateFundedTransaction(tx)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	vouts := make([]uint32, len(swapParams))
+	for i, redeemScript := range redeemScripts {
+		vouts[i], err = l.VoutFromTxHex(unpreparedTxHex, redeemScript)
+		if err != nil {
+			return "", "", nil, err
+		}
+	}
+
+	// Blind and sign the tx without sending it, the swaps persist it first.
+	txHex, err := l.liquidWallet.FinalizeFundedTransaction(unpreparedTxHex)
+	if err != nil {
+		return "", "", nil, err
+	}
+	txId, err := l.TxIdFromHex(txHex)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return txId, txHex, vouts, nil
+}
+
+// ReleaseBatchOpeningTransaction does nothing, elementsd does not lock the
+// inputs of a funded tx.
+func (l *LiquidOnChain) ReleaseBatchOpeningTransaction(txHex string) error {
+	return nil
+}
+
 func (l *LiquidOnChain) BroadcastOpeningTx(unpreparedTxHex string) (string, string, error) {
 	txHex, err := l.liquidWallet.FinalizeFundedTransaction(unpreparedTxHex)
 	if err != nil {
@@ -111,8 +186,76 @@ func (l *LiquidOnChain) BroadcastOpeningTx(unpreparedTxHex string) (string, stri
 	return txId, txHex, nil
 }
 
+// CreateOpeningPsbt returns an unsigned base64 PSET without inputs that pays
+// the swap amount to the blinded opening address. The external wallet blinds
+// the output when it funds the PSET.
+func (l *LiquidOnChain) CreateOpeningPsbt(swapParams *swap.OpeningParams) (string, error) {
+	redeemScript, err := ParamsToTxScript(swapParams, LiquidCsv)
+	if err != nil {
+		return "", err
+	}
+	addr, err := l.CreateBlindedOpeningAddress(redeemScript, swapParams.BlindingKey.PubKey())
+	if err != nil {
+		return "", err
+	}
+	pset, err := psetv2.New(nil, []psetv2.OutputArgs{{
+		Asset:   l.network.AssetID,
+		Amount:  swapParams.Amount,
+		Address: addr,
+	}}, nil)
+	if err != nil {
+		return "", err
+	}
+	return pset.ToBase64()
+}
+
+// ExtractOpeningTx returns the hex of a signed opening tx and its opening
+// output. signedTx is either a signed base64 PSET or a raw tx hex.
+func (l *LiquidOnChain) ExtractOpeningTx(swapParams *swap.OpeningParams, signedTx string) (string, uint32, error) {
+	txHex := strings.TrimSpace(signedTx)
+	if _, err := hex.DecodeString(txHex); err != nil {
+		pset, err := psetv2.NewPsetFromBase64(txHex)
+		if err != nil {
+			return "", 0, fmt.Errorf("expected a signed pset or tx hex: %w", err)
+		}
+		err = psetv2.MaybeFinalizeAll(pset)
+		if err != nil {
+			return "", 0, fmt.Errorf("could not finalize pset: %w", err)
+		}
+		tx, err := psetv2.Extract(pset)
+		if err != nil {
+			return "", 0, err
+		}
+		txHex, err = tx.ToHex()
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	ok, err := l.ValidateTx(swapParams, txHex)
+	if err != nil {
+		return "", 0, err
+	}
+	if !ok {
+		return "", 0, errors.New("tx does not pay the swap amount to the opening address")
+	}
+	redeemScript, err := ParamsToTxScript(swapParams, LiquidCsv)
+	if err != nil {
+		return "", 0, err
+	}
+	vout, err := l.VoutFromTxHex(txHex, redeemScript)
+	if err != nil {
+		return "", 0, err
+	}
+	return txHex, vout, nil
+}
+
+func (l *LiquidOnChain) BroadcastTx(txHex string) (string, error) {
+	return l.elements.SendRawTx(txHex)
+}
+
 func (l *LiquidOnChain) CreatePreimageSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (string, string, error) {
-	newAddr, err := l.liquidWallet.GetAddress()
+	newAddr, err := l.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -156,7 +299,7 @@ func (l *LiquidOnChain) CreatePreimageSpendingTransaction(swapParams *swap.Openi
 }
 
 func (l *LiquidOnChain) CreateCsvSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams) (txId, txHex string, error error) {
-	newAddr, err := l.liquidWallet.GetAddress()
+	newAddr, err := l.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -178,7 +321,7 @@ func (l *LiquidOnChain) CreateCsvSpendingTransaction(swapParams *swap.OpeningPar
 }
 
 func (l *LiquidOnChain) CreateCoopSpendingTransaction(swapParams *swap.OpeningParams, claimParams *swap.ClaimParams, takerSigner swap.Signer) (txId, txHex string, error error) {
-	refundAddr, err := l.NewAddress()
+	refundAddr, err := l.claimAddress(claimParams)
 	if err != nil {
 		return "", "", err
 	}
@@ -230,6 +373,36 @@ func (l *LiquidOnChain) AddBlindingRandomFactors(claimParams *swap.ClaimParams)
 	return nil
 }
 
+// claimAddress returns the address the swap is claimed to, a new wallet
+// address if none is set.
+func (l *LiquidOnChain) claimAddress(claimParams *swap.ClaimParams) (string, error) {
+	if claimParams.ClaimAddress != "" {
+		return claimParams.ClaimAddress, nil
+	}
+	return l.NewAddress()
+}
+
+// ValidateAddress returns an error if address is not a confidential address
+// on the network of the chain. Claim outputs are always blinded.
+func (l *LiquidOnChain) ValidateAddress(addr string) error {
+	net, err := address.NetworkForAddress(addr)
+	if err != nil {
+		return err
+	}
+	if net.Name != l.network.Name {
+		return fmt.Errorf("address is not for network %s", l.network.Name)
+	}
+	isConfidential, err := address.IsConfidential(addr)
+	if err != nil {
+		return err
+	}
+	if !isConfidential {
+		return errors.New("address is not confidential")
+	}
+	_, err = address.ToOutputScript(addr)
+	return err
+}
+
 func (l *LiquidOnChain) NewAddress() (string, error) {
 	addr, err := l.liquidWallet.GetAddress()
 	if err != nil {
@@ -417,6 +590,29 @@ func (l *LiquidOnChain) TxIdFromHex(txHex string) (string, error) {
 	return openingTx.TxHash().String(), nil
 }
 
+// GetPreimageFromSpendingTx returns the txid of the tx that spends the
+// opening output `openingTxId:vout` and the preimage revealed by the
+// spending witness. The preimage is empty if the output is spent via the csv
+// or the cooperative path.
+func (l *LiquidOnChain) GetPreimageFromSpendingTx(openingTxId string, vout uint32, txHex string) (string, string, error) {
+	tx, err := transaction.NewTxFromHex(txHex)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, in := range tx.Inputs {
+		if elementsutil.TxIDFromBytes(in.Hash) != openingTxId || in.Index != vout {
+			continue
+		}
+		preimage, ok := GetPreimageFromWitness(in.Witness)
+		if !ok {
+			return tx.TxHash().String(), "", nil
+		}
+		return tx.TxHash().String(), hex.EncodeToString(preimage), nil
+	}
+	return "", "", fmt.Errorf("tx %s does not spend %s:%d", tx.TxHash().String(), openingTxId, vout)
+}
+
 func (l *LiquidOnChain) ValidateTx(openingParams *swap.OpeningParams, txHex string) (bool, error) {
 	redeemScript, err := ParamsToTxScript(openingParams, LiquidCsv)
 	if err != nil {
@@ -553,6 +749,18 @@ func (l *LiquidOnChain) Blech32ToScript(blech32Addr string) ([]byte, error) {
 }
 
 func (l *LiquidOnChain) getFee(txSize int) (uint64, error) {
+	if l.estimator != nil {
+		satPerKw, err := l.estimator.EstimateFeePerKW(LiquidTargetBlocks)
+		if err != nil {
+			return 0, err
+		}
+		satPerByte := float64(satPerKw) * witnessScaleFactor / 1000
+		if satPerByte < 0.1 {
+			satPerByte = 0.1
+		}
+		return uint64(satPerByte * float64(txSize)), nil
+	}
+
 	feeRes, err := l.elements.EstimateFee(LiquidTargetBlocks, "ECONOMICAL")
 	if err != nil {
 		return 0, err
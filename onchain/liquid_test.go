@@ -1,10 +1,19 @@
 package onchain
 
 import (
+	"bytes"
+	"encoding/hex"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/elementsproject/peerswap/swap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vulpemventures/go-elements/elementsutil"
 	"github.com/vulpemventures/go-elements/network"
+	"github.com/vulpemventures/go-elements/psetv2"
+	"github.com/vulpemventures/go-elements/transaction"
 )
 
 func Test_ScriptAddress(t *testing.T) {
@@ -25,3 +34,91 @@ func Test_ScriptAddress(t *testing.T) {
 	}
 	t.Logf("addr %s", addr)
 }
+
+type staticEstimator struct {
+	satPerKw btcutil.Amount
+}
+
+func (s *staticEstimator) EstimateFeePerKW(targetBlocks uint32) (btcutil.Amount, error) {
+	return s.satPerKw, nil
+}
+
+func (s *staticEstimator) Start() error {
+	return nil
+}
+
+func Test_LiquidFeeEstimator(t *testing.T) {
+	liquidOnChain := NewLiquidOnChain(nil, nil, &network.Testnet)
+
+	// 250 sat/kw are 1 sat/vb.
+	liquidOnChain.SetFeeEstimator(&staticEstimator{satPerKw: 250})
+	fee, err := liquidOnChain.GetFlatSwapOutFee()
+	require.NoError(t, err)
+	assert.EqualValues(t, 3000, fee)
+
+	// The fee rate is floored at 0.1 sat/vb.
+	liquidOnChain.SetFeeEstimator(&staticEstimator{satPerKw: 1})
+	fee, err = liquidOnChain.GetFlatSwapOutFee()
+	require.NoError(t, err)
+	assert.EqualValues(t, 300, fee)
+}
+
+func Test_LiquidGetPreimageFromSpendingTx(t *testing.T) {
+	liquidOnChain := NewLiquidOnChain(nil, nil, &network.Regtest)
+
+	openingTxId := "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+	openingHash, err := elementsutil.TxIDToBytes(openingTxId)
+	require.NoError(t, err)
+	preimage := bytes.Repeat([]byte{0x01}, 32)
+	sig := bytes.Repeat([]byte{0x02}, 71)
+	redeemScript := []byte{0x03}
+
+	spendingTx := func(witness [][]byte) string {
+		tx := transaction.NewTx(2)
+		in := transaction.NewTxInput(openingHash, 1)
+		in.Witness = witness
+		tx.AddInput(in)
+		txHex, err := tx.ToHex()
+		require.NoError(t, err)
+		return txHex
+	}
+
+	// Preimage path.
+	_, gotPreimage, err := liquidOnChain.GetPreimageFromSpendingTx(openingTxId, 1, spendingTx(GetPreimageWitness(sig, preimage, redeemScript)))
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(preimage), gotPreimage)
+
+	// Csv path.
+	_, gotPreimage, err = liquidOnChain.GetPreimageFromSpendingTx(openingTxId, 1, spendingTx(GetCsvWitness(sig, redeemScript)))
+	require.NoError(t, err)
+	assert.Empty(t, gotPreimage)
+}
+
+func TestLiquidOnChain_CreateOpeningPsbt(t *testing.T) {
+	liquidOnChain := NewLiquidOnChain(nil, nil, &network.Regtest)
+	blindingKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	swapParams := &swap.OpeningParams{
+		TakerPubkey:      "02752e1beeeeb6472959117a0aa5d172900680c033ddf86b1a8318311e2b10223f",
+		MakerPubkey:      "02c30ff537639962f493d326a77f1c6cb591ee3d21ca8d89194bb69cb288f497e8",
+		ClaimPaymentHash: "b94f26d422d5ce3a1e65dd4abb398d0d369aefe8f71d112c5591aa45eea1e75c",
+		Amount:           5000,
+		BlindingKey:      blindingKey,
+	}
+	wantScript, err := liquidOnChain.GetOutputScript(swapParams)
+	require.NoError(t, err)
+
+	b64, err := liquidOnChain.CreateOpeningPsbt(swapParams)
+	require.NoError(t, err)
+	pset, err := psetv2.NewPsetFromBase64(b64)
+	require.NoError(t, err)
+	require.Empty(t, pset.Inputs)
+	require.Len(t, pset.Outputs, 1)
+	assert.Equal(t, uint64(5000), pset.Outputs[0].Value)
+	assert.Equal(t, wantScript, pset.Outputs[0].Script)
+	// The output is blinded to the blinding key of the swap.
+	assert.Equal(t, blindingKey.PubKey().SerializeCompressed(), pset.Outputs[0].BlindingPubkey)
+
+	_, _, err = liquidOnChain.ExtractOpeningTx(swapParams, "invalid")
+	assert.Error(t, err)
+}
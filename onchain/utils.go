@@ -83,3 +83,12 @@ func h2b(str string) []byte {
 	buf, _ := hex.DecodeString(str)
 	return buf
 }
+
+// GetPreimageFromWitness returns the preimage of a witness that spends the
+// opening transaction with the preimage, false for any other spending path.
+func GetPreimageFromWitness(witness [][]byte) ([]byte, bool) {
+	if len(witness) != 5 || len(witness[1]) != 32 || len(witness[2]) != 0 || len(witness[3]) != 0 {
+		return nil, false
+	}
+	return witness[1], true
+}
@@ -13,6 +13,11 @@ func GetPolicyMessage(p policy.Policy) *Policy {
 		AllowNewSwaps:      p.AllowNewSwaps,
 		AllowlistedPeers:   p.PeerAllowlist,
 		SuspiciousPeerList: p.SuspiciousPeerList,
+
+		MaxFeeInvoiceMultiple:     p.MaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          p.MaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   p.MaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: p.ExternalFundingTimeoutSec,
 	}
 }
 
@@ -20,6 +20,99 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// CancelCode is the machine readable reason why a swap was canceled or a
+// swap request was rejected. The values are the codes of the cancel message.
+type CancelCode int32
+
+const (
+	CancelCode_UNKNOWN              CancelCode = 0
+	CancelCode_SWAPS_DISABLED       CancelCode = 1
+	CancelCode_CHAIN_DISABLED       CancelCode = 2
+	CancelCode_INCOMPATIBLE_VERSION CancelCode = 3
+	CancelCode_AMOUNT_TOO_SMALL     CancelCode = 4
+	CancelCode_INVALID_ASSET        CancelCode = 5
+	CancelCode_INVALID_NETWORK      CancelCode = 6
+	CancelCode_PEER_NOT_ALLOWED     CancelCode = 7
+	CancelCode_PEER_SUSPICIOUS      CancelCode = 8
+	CancelCode_INSUFFICIENT_BALANCE CancelCode = 9
+	CancelCode_ACTIVE_SWAP          CancelCode = 10
+	CancelCode_TIMEOUT              CancelCode = 11
+	CancelCode_INVALID_TX           CancelCode = 12
+	CancelCode_INVALID_INVOICE      CancelCode = 13
+	CancelCode_FEE_TOO_HIGH         CancelCode = 14
+	CancelCode_TOO_CLOSE_TO_CSV     CancelCode = 15
+	CancelCode_TOO_MANY_SWAPS       CancelCode = 16
+)
+
+// Enum value maps for CancelCode.
+var (
+	CancelCode_name = map[int32]string{
+		0:  "UNKNOWN",
+		1:  "SWAPS_DISABLED",
+		2:  "CHAIN_DISABLED",
+		3:  "INCOMPATIBLE_VERSION",
+		4:  "AMOUNT_TOO_SMALL",
+		5:  "INVALID_ASSET",
+		6:  "INVALID_NETWORK",
+		7:  "PEER_NOT_ALLOWED",
+		8:  "PEER_SUSPICIOUS",
+		9:  "INSUFFICIENT_BALANCE",
+		10: "ACTIVE_SWAP",
+		11: "TIMEOUT",
+		12: "INVALID_TX",
+		13: "INVALID_INVOICE",
+		14: "FEE_TOO_HIGH",
+		15: "TOO_CLOSE_TO_CSV",
+		16: "TOO_MANY_SWAPS",
+	}
+	CancelCode_value = map[string]int32{
+		"UNKNOWN":              0,
+		"SWAPS_DISABLED":       1,
+		"CHAIN_DISABLED":       2,
+		"INCOMPATIBLE_VERSION": 3,
+		"AMOUNT_TOO_SMALL":     4,
+		"INVALID_ASSET":        5,
+		"INVALID_NETWORK":      6,
+		"PEER_NOT_ALLOWED":     7,
+		"PEER_SUSPICIOUS":      8,
+		"INSUFFICIENT_BALANCE": 9,
+		"ACTIVE_SWAP":          10,
+		"TIMEOUT":              11,
+		"INVALID_TX":           12,
+		"INVALID_INVOICE":      13,
+		"FEE_TOO_HIGH":         14,
+		"TOO_CLOSE_TO_CSV":     15,
+		"TOO_MANY_SWAPS":       16,
+	}
+)
+
+func (x CancelCode) Enum() *CancelCode {
+	p := new(CancelCode)
+	*p = x
+	return p
+}
+
+func (x CancelCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CancelCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_peerswaprpc_peerswaprpc_proto_enumTypes[0].Descriptor()
+}
+
+func (CancelCode) Type() protoreflect.EnumType {
+	return &file_peerswaprpc_peerswaprpc_proto_enumTypes[0]
+}
+
+func (x CancelCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CancelCode.Descriptor instead.
+func (CancelCode) EnumDescriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{0}
+}
+
 type RequestedSwap_SwapType int32
 
 const (
@@ -50,11 +143,11 @@ func (x RequestedSwap_SwapType) String() string {
 }
 
 func (RequestedSwap_SwapType) Descriptor() protoreflect.EnumDescriptor {
-	return file_peerswaprpc_peerswaprpc_proto_enumTypes[0].Descriptor()
+	return file_peerswaprpc_peerswaprpc_proto_enumTypes[1].Descriptor()
 }
 
 func (RequestedSwap_SwapType) Type() protoreflect.EnumType {
-	return &file_peerswaprpc_peerswaprpc_proto_enumTypes[0]
+	return &file_peerswaprpc_peerswaprpc_proto_enumTypes[1]
 }
 
 func (x RequestedSwap_SwapType) Number() protoreflect.EnumNumber {
@@ -63,7 +156,7 @@ func (x RequestedSwap_SwapType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use RequestedSwap_SwapType.Descriptor instead.
 func (RequestedSwap_SwapType) EnumDescriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{21, 0}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{29, 0}
 }
 
 type GetAddressRequest struct {
@@ -338,6 +431,176 @@ func (x *SendToAddressResponse) GetTxId() string {
 	return ""
 }
 
+type GetBalancesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetBalancesRequest) Reset() {
+	*x = GetBalancesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesRequest) ProtoMessage() {}
+
+func (x *GetBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{6}
+}
+
+type GetBalancesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Balances []*WalletBalance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+}
+
+func (x *GetBalancesResponse) Reset() {
+	*x = GetBalancesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesResponse) ProtoMessage() {}
+
+func (x *GetBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesResponse.ProtoReflect.Descriptor instead.
+func (*GetBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetBalancesResponse) GetBalances() []*WalletBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+type WalletBalance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// asset is either btc or lbtc.
+	Asset string `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	// onchain_sat is the on-chain balance of the wallet.
+	OnchainSat uint64 `protobuf:"varint,2,opt,name=onchain_sat,json=onchainSat,proto3" json:"onchain_sat,omitempty"`
+	// reserve_sat is the amount kept by the policy reserve.
+	ReserveSat uint64 `protobuf:"varint,3,opt,name=reserve_sat,json=reserveSat,proto3" json:"reserve_sat,omitempty"`
+	// committed_sat is the amount committed to the opening transactions of
+	// active swaps.
+	CommittedSat uint64 `protobuf:"varint,4,opt,name=committed_sat,json=committedSat,proto3" json:"committed_sat,omitempty"`
+	// available_sat is the amount that is available for new swaps.
+	AvailableSat uint64 `protobuf:"varint,5,opt,name=available_sat,json=availableSat,proto3" json:"available_sat,omitempty"`
+}
+
+func (x *WalletBalance) Reset() {
+	*x = WalletBalance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WalletBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletBalance) ProtoMessage() {}
+
+func (x *WalletBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletBalance.ProtoReflect.Descriptor instead.
+func (*WalletBalance) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WalletBalance) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *WalletBalance) GetOnchainSat() uint64 {
+	if x != nil {
+		return x.OnchainSat
+	}
+	return 0
+}
+
+func (x *WalletBalance) GetReserveSat() uint64 {
+	if x != nil {
+		return x.ReserveSat
+	}
+	return 0
+}
+
+func (x *WalletBalance) GetCommittedSat() uint64 {
+	if x != nil {
+		return x.CommittedSat
+	}
+	return 0
+}
+
+func (x *WalletBalance) GetAvailableSat() uint64 {
+	if x != nil {
+		return x.AvailableSat
+	}
+	return 0
+}
+
 type SwapOutRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -347,12 +610,17 @@ type SwapOutRequest struct {
 	SwapAmount uint64 `protobuf:"varint,2,opt,name=swap_amount,json=swapAmount,proto3" json:"swap_amount,omitempty"`
 	Asset      string `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
 	Force      bool   `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	// Address the swap is claimed to instead of a new wallet address. Liquid
+	// addresses must be confidential.
+	ClaimAddress string `protobuf:"bytes,5,opt,name=claim_address,json=claimAddress,proto3" json:"claim_address,omitempty"`
+	// Claim the swap to a fresh address of the configured claim descriptor.
+	ClaimToDescriptor bool `protobuf:"varint,6,opt,name=claim_to_descriptor,json=claimToDescriptor,proto3" json:"claim_to_descriptor,omitempty"`
 }
 
 func (x *SwapOutRequest) Reset() {
 	*x = SwapOutRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[6]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -365,7 +633,7 @@ func (x *SwapOutRequest) String() string {
 func (*SwapOutRequest) ProtoMessage() {}
 
 func (x *SwapOutRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[6]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,7 +646,7 @@ func (x *SwapOutRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwapOutRequest.ProtoReflect.Descriptor instead.
 func (*SwapOutRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{6}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *SwapOutRequest) GetChannelId() uint64 {
@@ -409,6 +677,20 @@ func (x *SwapOutRequest) GetForce() bool {
 	return false
 }
 
+func (x *SwapOutRequest) GetClaimAddress() string {
+	if x != nil {
+		return x.ClaimAddress
+	}
+	return ""
+}
+
+func (x *SwapOutRequest) GetClaimToDescriptor() bool {
+	if x != nil {
+		return x.ClaimToDescriptor
+	}
+	return false
+}
+
 type SwapOutResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -420,7 +702,7 @@ type SwapOutResponse struct {
 func (x *SwapOutResponse) Reset() {
 	*x = SwapOutResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[7]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -433,7 +715,7 @@ func (x *SwapOutResponse) String() string {
 func (*SwapOutResponse) ProtoMessage() {}
 
 func (x *SwapOutResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[7]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -446,7 +728,7 @@ func (x *SwapOutResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwapOutResponse.ProtoReflect.Descriptor instead.
 func (*SwapOutResponse) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{7}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SwapOutResponse) GetSwap() *PrettyPrintSwap {
@@ -465,12 +747,16 @@ type SwapInRequest struct {
 	SwapAmount uint64 `protobuf:"varint,2,opt,name=swap_amount,json=swapAmount,proto3" json:"swap_amount,omitempty"`
 	Asset      string `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
 	Force      bool   `protobuf:"varint,4,opt,name=force,proto3" json:"force,omitempty"`
+	// Fund the opening tx with an external wallet. The swap returns an
+	// unsigned PSBT (PSET on liquid) in opening_psbt that is submitted with
+	// SubmitOpeningTx once it is funded and signed.
+	ExternalFunding bool `protobuf:"varint,5,opt,name=external_funding,json=externalFunding,proto3" json:"external_funding,omitempty"`
 }
 
 func (x *SwapInRequest) Reset() {
 	*x = SwapInRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[8]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -483,7 +769,7 @@ func (x *SwapInRequest) String() string {
 func (*SwapInRequest) ProtoMessage() {}
 
 func (x *SwapInRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[8]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,7 +782,7 @@ func (x *SwapInRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwapInRequest.ProtoReflect.Descriptor instead.
 func (*SwapInRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{8}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SwapInRequest) GetChannelId() uint64 {
@@ -527,31 +813,42 @@ func (x *SwapInRequest) GetForce() bool {
 	return false
 }
 
-type SwapResponse struct {
+func (x *SwapInRequest) GetExternalFunding() bool {
+	if x != nil {
+		return x.ExternalFunding
+	}
+	return false
+}
+
+// SwapInBatchRequest starts swap-ins whose opening outputs are paid by a
+// single opening tx.
+type SwapInBatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Swap *PrettyPrintSwap `protobuf:"bytes,1,opt,name=swap,proto3" json:"swap,omitempty"`
+	Swaps []*SwapInBatchEntry `protobuf:"bytes,1,rep,name=swaps,proto3" json:"swaps,omitempty"`
+	Asset string              `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Force bool                `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
 }
 
-func (x *SwapResponse) Reset() {
-	*x = SwapResponse{}
+func (x *SwapInBatchRequest) Reset() {
+	*x = SwapInBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[9]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SwapResponse) String() string {
+func (x *SwapInBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SwapResponse) ProtoMessage() {}
+func (*SwapInBatchRequest) ProtoMessage() {}
 
-func (x *SwapResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[9]
+func (x *SwapInBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -562,43 +859,58 @@ func (x *SwapResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SwapResponse.ProtoReflect.Descriptor instead.
-func (*SwapResponse) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use SwapInBatchRequest.ProtoReflect.Descriptor instead.
+func (*SwapInBatchRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *SwapResponse) GetSwap() *PrettyPrintSwap {
+func (x *SwapInBatchRequest) GetSwaps() []*SwapInBatchEntry {
 	if x != nil {
-		return x.Swap
+		return x.Swaps
 	}
 	return nil
 }
 
-type GetSwapRequest struct {
+func (x *SwapInBatchRequest) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *SwapInBatchRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type SwapInBatchEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SwapId string `protobuf:"bytes,1,opt,name=swap_id,json=swapId,proto3" json:"swap_id,omitempty"`
+	ChannelId  uint64 `protobuf:"varint,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	SwapAmount uint64 `protobuf:"varint,2,opt,name=swap_amount,json=swapAmount,proto3" json:"swap_amount,omitempty"`
 }
 
-func (x *GetSwapRequest) Reset() {
-	*x = GetSwapRequest{}
+func (x *SwapInBatchEntry) Reset() {
+	*x = SwapInBatchEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[10]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetSwapRequest) String() string {
+func (x *SwapInBatchEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSwapRequest) ProtoMessage() {}
+func (*SwapInBatchEntry) ProtoMessage() {}
 
-func (x *GetSwapRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[10]
+func (x *SwapInBatchEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -609,41 +921,52 @@ func (x *GetSwapRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSwapRequest.ProtoReflect.Descriptor instead.
-func (*GetSwapRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use SwapInBatchEntry.ProtoReflect.Descriptor instead.
+func (*SwapInBatchEntry) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetSwapRequest) GetSwapId() string {
+func (x *SwapInBatchEntry) GetChannelId() uint64 {
 	if x != nil {
-		return x.SwapId
+		return x.ChannelId
 	}
-	return ""
+	return 0
 }
 
-type ListSwapsRequest struct {
+func (x *SwapInBatchEntry) GetSwapAmount() uint64 {
+	if x != nil {
+		return x.SwapAmount
+	}
+	return 0
+}
+
+// SwapInBatchResponse holds the started swap or the error of each swap-in of
+// the batch, in the order of the request.
+type SwapInBatchResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Results []*SwapInBatchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (x *ListSwapsRequest) Reset() {
-	*x = ListSwapsRequest{}
+func (x *SwapInBatchResponse) Reset() {
+	*x = SwapInBatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[11]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListSwapsRequest) String() string {
+func (x *SwapInBatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSwapsRequest) ProtoMessage() {}
+func (*SwapInBatchResponse) ProtoMessage() {}
 
-func (x *ListSwapsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[11]
+func (x *SwapInBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -654,36 +977,45 @@ func (x *ListSwapsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSwapsRequest.ProtoReflect.Descriptor instead.
-func (*ListSwapsRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use SwapInBatchResponse.ProtoReflect.Descriptor instead.
+func (*SwapInBatchResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{14}
 }
 
-type ListSwapsResponse struct {
+func (x *SwapInBatchResponse) GetResults() []*SwapInBatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SwapInBatchResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Swaps []*PrettyPrintSwap `protobuf:"bytes,1,rep,name=swaps,proto3" json:"swaps,omitempty"`
+	ChannelId uint64           `protobuf:"varint,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Swap      *PrettyPrintSwap `protobuf:"bytes,2,opt,name=swap,proto3" json:"swap,omitempty"`
+	Error     string           `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *ListSwapsResponse) Reset() {
-	*x = ListSwapsResponse{}
+func (x *SwapInBatchResult) Reset() {
+	*x = SwapInBatchResult{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[12]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListSwapsResponse) String() string {
+func (x *SwapInBatchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSwapsResponse) ProtoMessage() {}
+func (*SwapInBatchResult) ProtoMessage() {}
 
-func (x *ListSwapsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[12]
+func (x *SwapInBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -694,41 +1026,59 @@ func (x *ListSwapsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSwapsResponse.ProtoReflect.Descriptor instead.
-func (*ListSwapsResponse) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use SwapInBatchResult.ProtoReflect.Descriptor instead.
+func (*SwapInBatchResult) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *ListSwapsResponse) GetSwaps() []*PrettyPrintSwap {
+func (x *SwapInBatchResult) GetChannelId() uint64 {
 	if x != nil {
-		return x.Swaps
+		return x.ChannelId
+	}
+	return 0
+}
+
+func (x *SwapInBatchResult) GetSwap() *PrettyPrintSwap {
+	if x != nil {
+		return x.Swap
 	}
 	return nil
 }
 
-type ListPeersRequest struct {
+func (x *SwapInBatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SubmitOpeningTxRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	SwapId string `protobuf:"bytes,1,opt,name=swap_id,json=swapId,proto3" json:"swap_id,omitempty"`
+	// The signed PSBT (PSET) or the raw hex of the signed opening tx.
+	SignedTx string `protobuf:"bytes,2,opt,name=signed_tx,json=signedTx,proto3" json:"signed_tx,omitempty"`
 }
 
-func (x *ListPeersRequest) Reset() {
-	*x = ListPeersRequest{}
+func (x *SubmitOpeningTxRequest) Reset() {
+	*x = SubmitOpeningTxRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[13]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListPeersRequest) String() string {
+func (x *SubmitOpeningTxRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPeersRequest) ProtoMessage() {}
+func (*SubmitOpeningTxRequest) ProtoMessage() {}
 
-func (x *ListPeersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[13]
+func (x *SubmitOpeningTxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,36 +1089,50 @@ func (x *ListPeersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPeersRequest.ProtoReflect.Descriptor instead.
-func (*ListPeersRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use SubmitOpeningTxRequest.ProtoReflect.Descriptor instead.
+func (*SubmitOpeningTxRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{16}
 }
 
-type ListPeersResponse struct {
+func (x *SubmitOpeningTxRequest) GetSwapId() string {
+	if x != nil {
+		return x.SwapId
+	}
+	return ""
+}
+
+func (x *SubmitOpeningTxRequest) GetSignedTx() string {
+	if x != nil {
+		return x.SignedTx
+	}
+	return ""
+}
+
+type SwapResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Peers []*PeerSwapPeer `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	Swap *PrettyPrintSwap `protobuf:"bytes,1,opt,name=swap,proto3" json:"swap,omitempty"`
 }
 
-func (x *ListPeersResponse) Reset() {
-	*x = ListPeersResponse{}
+func (x *SwapResponse) Reset() {
+	*x = SwapResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[14]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListPeersResponse) String() string {
+func (x *SwapResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPeersResponse) ProtoMessage() {}
+func (*SwapResponse) ProtoMessage() {}
 
-func (x *ListPeersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[14]
+func (x *SwapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -779,41 +1143,43 @@ func (x *ListPeersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPeersResponse.ProtoReflect.Descriptor instead.
-func (*ListPeersResponse) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use SwapResponse.ProtoReflect.Descriptor instead.
+func (*SwapResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ListPeersResponse) GetPeers() []*PeerSwapPeer {
+func (x *SwapResponse) GetSwap() *PrettyPrintSwap {
 	if x != nil {
-		return x.Peers
+		return x.Swap
 	}
 	return nil
 }
 
-type ReloadPolicyFileRequest struct {
+type GetSwapRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	SwapId string `protobuf:"bytes,1,opt,name=swap_id,json=swapId,proto3" json:"swap_id,omitempty"`
 }
 
-func (x *ReloadPolicyFileRequest) Reset() {
-	*x = ReloadPolicyFileRequest{}
+func (x *GetSwapRequest) Reset() {
+	*x = GetSwapRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[15]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ReloadPolicyFileRequest) String() string {
+func (x *GetSwapRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReloadPolicyFileRequest) ProtoMessage() {}
+func (*GetSwapRequest) ProtoMessage() {}
 
-func (x *ReloadPolicyFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[15]
+func (x *GetSwapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -824,36 +1190,41 @@ func (x *ReloadPolicyFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReloadPolicyFileRequest.ProtoReflect.Descriptor instead.
-func (*ReloadPolicyFileRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use GetSwapRequest.ProtoReflect.Descriptor instead.
+func (*GetSwapRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{18}
 }
 
-type AddPeerRequest struct {
+func (x *GetSwapRequest) GetSwapId() string {
+	if x != nil {
+		return x.SwapId
+	}
+	return ""
+}
+
+type ListSwapsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	PeerPubkey string `protobuf:"bytes,1,opt,name=peer_pubkey,json=peerPubkey,proto3" json:"peer_pubkey,omitempty"`
 }
 
-func (x *AddPeerRequest) Reset() {
-	*x = AddPeerRequest{}
+func (x *ListSwapsRequest) Reset() {
+	*x = ListSwapsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[16]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AddPeerRequest) String() string {
+func (x *ListSwapsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddPeerRequest) ProtoMessage() {}
+func (*ListSwapsRequest) ProtoMessage() {}
 
-func (x *AddPeerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[16]
+func (x *ListSwapsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -864,43 +1235,36 @@ func (x *AddPeerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddPeerRequest.ProtoReflect.Descriptor instead.
-func (*AddPeerRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *AddPeerRequest) GetPeerPubkey() string {
-	if x != nil {
-		return x.PeerPubkey
-	}
-	return ""
+// Deprecated: Use ListSwapsRequest.ProtoReflect.Descriptor instead.
+func (*ListSwapsRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{19}
 }
 
-type RemovePeerRequest struct {
+type ListSwapsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PeerPubkey string `protobuf:"bytes,1,opt,name=peer_pubkey,json=peerPubkey,proto3" json:"peer_pubkey,omitempty"`
+	Swaps []*PrettyPrintSwap `protobuf:"bytes,1,rep,name=swaps,proto3" json:"swaps,omitempty"`
 }
 
-func (x *RemovePeerRequest) Reset() {
-	*x = RemovePeerRequest{}
+func (x *ListSwapsResponse) Reset() {
+	*x = ListSwapsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[17]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RemovePeerRequest) String() string {
+func (x *ListSwapsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemovePeerRequest) ProtoMessage() {}
+func (*ListSwapsResponse) ProtoMessage() {}
 
-func (x *RemovePeerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[17]
+func (x *ListSwapsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -911,41 +1275,41 @@ func (x *RemovePeerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemovePeerRequest.ProtoReflect.Descriptor instead.
-func (*RemovePeerRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ListSwapsResponse.ProtoReflect.Descriptor instead.
+func (*ListSwapsResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *RemovePeerRequest) GetPeerPubkey() string {
+func (x *ListSwapsResponse) GetSwaps() []*PrettyPrintSwap {
 	if x != nil {
-		return x.PeerPubkey
+		return x.Swaps
 	}
-	return ""
+	return nil
 }
 
-type ListRequestedSwapsRequest struct {
+type ListPeersRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *ListRequestedSwapsRequest) Reset() {
-	*x = ListRequestedSwapsRequest{}
+func (x *ListPeersRequest) Reset() {
+	*x = ListPeersRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[18]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListRequestedSwapsRequest) String() string {
+func (x *ListPeersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRequestedSwapsRequest) ProtoMessage() {}
+func (*ListPeersRequest) ProtoMessage() {}
 
-func (x *ListRequestedSwapsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[18]
+func (x *ListPeersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -956,36 +1320,36 @@ func (x *ListRequestedSwapsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRequestedSwapsRequest.ProtoReflect.Descriptor instead.
-func (*ListRequestedSwapsRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ListPeersRequest.ProtoReflect.Descriptor instead.
+func (*ListPeersRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{21}
 }
 
-type ListRequestedSwapsResponse struct {
+type ListPeersResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RequestedSwaps map[string]*RequestSwapList `protobuf:"bytes,1,rep,name=requested_swaps,json=requestedSwaps,proto3" json:"requested_swaps,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Peers []*PeerSwapPeer `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
 }
 
-func (x *ListRequestedSwapsResponse) Reset() {
-	*x = ListRequestedSwapsResponse{}
+func (x *ListPeersResponse) Reset() {
+	*x = ListPeersResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[19]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListRequestedSwapsResponse) String() string {
+func (x *ListPeersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRequestedSwapsResponse) ProtoMessage() {}
+func (*ListPeersResponse) ProtoMessage() {}
 
-func (x *ListRequestedSwapsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[19]
+func (x *ListPeersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -996,43 +1360,41 @@ func (x *ListRequestedSwapsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRequestedSwapsResponse.ProtoReflect.Descriptor instead.
-func (*ListRequestedSwapsResponse) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use ListPeersResponse.ProtoReflect.Descriptor instead.
+func (*ListPeersResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ListRequestedSwapsResponse) GetRequestedSwaps() map[string]*RequestSwapList {
+func (x *ListPeersResponse) GetPeers() []*PeerSwapPeer {
 	if x != nil {
-		return x.RequestedSwaps
+		return x.Peers
 	}
 	return nil
 }
 
-type RequestSwapList struct {
+type ReloadPolicyFileRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	RequestedSwaps []*RequestedSwap `protobuf:"bytes,1,rep,name=requested_swaps,json=requestedSwaps,proto3" json:"requested_swaps,omitempty"`
 }
 
-func (x *RequestSwapList) Reset() {
-	*x = RequestSwapList{}
+func (x *ReloadPolicyFileRequest) Reset() {
+	*x = ReloadPolicyFileRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[20]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestSwapList) String() string {
+func (x *ReloadPolicyFileRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestSwapList) ProtoMessage() {}
+func (*ReloadPolicyFileRequest) ProtoMessage() {}
 
-func (x *RequestSwapList) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[20]
+func (x *ReloadPolicyFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1043,46 +1405,719 @@ func (x *RequestSwapList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestSwapList.ProtoReflect.Descriptor instead.
-func (*RequestSwapList) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ReloadPolicyFileRequest.ProtoReflect.Descriptor instead.
+func (*ReloadPolicyFileRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *RequestSwapList) GetRequestedSwaps() []*RequestedSwap {
+type AddPeerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PeerPubkey string `protobuf:"bytes,1,opt,name=peer_pubkey,json=peerPubkey,proto3" json:"peer_pubkey,omitempty"`
+}
+
+func (x *AddPeerRequest) Reset() {
+	*x = AddPeerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddPeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPeerRequest) ProtoMessage() {}
+
+func (x *AddPeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPeerRequest.ProtoReflect.Descriptor instead.
+func (*AddPeerRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AddPeerRequest) GetPeerPubkey() string {
+	if x != nil {
+		return x.PeerPubkey
+	}
+	return ""
+}
+
+type RemovePeerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PeerPubkey string `protobuf:"bytes,1,opt,name=peer_pubkey,json=peerPubkey,proto3" json:"peer_pubkey,omitempty"`
+}
+
+func (x *RemovePeerRequest) Reset() {
+	*x = RemovePeerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemovePeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePeerRequest) ProtoMessage() {}
+
+func (x *RemovePeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePeerRequest.ProtoReflect.Descriptor instead.
+func (*RemovePeerRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RemovePeerRequest) GetPeerPubkey() string {
+	if x != nil {
+		return x.PeerPubkey
+	}
+	return ""
+}
+
+type ListRequestedSwapsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only list requests of this peer.
+	PeerPubkey string `protobuf:"bytes,1,opt,name=peer_pubkey,json=peerPubkey,proto3" json:"peer_pubkey,omitempty"`
+	// Only list requests received at or after this unix time.
+	Since int64 `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+	// Only list requests received at or before this unix time.
+	Until int64 `protobuf:"varint,3,opt,name=until,proto3" json:"until,omitempty"`
+}
+
+func (x *ListRequestedSwapsRequest) Reset() {
+	*x = ListRequestedSwapsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequestedSwapsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequestedSwapsRequest) ProtoMessage() {}
+
+func (x *ListRequestedSwapsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequestedSwapsRequest.ProtoReflect.Descriptor instead.
+func (*ListRequestedSwapsRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListRequestedSwapsRequest) GetPeerPubkey() string {
+	if x != nil {
+		return x.PeerPubkey
+	}
+	return ""
+}
+
+func (x *ListRequestedSwapsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ListRequestedSwapsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+type ListRequestedSwapsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestedSwaps map[string]*RequestSwapList `protobuf:"bytes,1,rep,name=requested_swaps,json=requestedSwaps,proto3" json:"requested_swaps,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ListRequestedSwapsResponse) Reset() {
+	*x = ListRequestedSwapsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequestedSwapsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequestedSwapsResponse) ProtoMessage() {}
+
+func (x *ListRequestedSwapsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequestedSwapsResponse.ProtoReflect.Descriptor instead.
+func (*ListRequestedSwapsResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListRequestedSwapsResponse) GetRequestedSwaps() map[string]*RequestSwapList {
+	if x != nil {
+		return x.RequestedSwaps
+	}
+	return nil
+}
+
+type RequestSwapList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestedSwaps []*RequestedSwap `protobuf:"bytes,1,rep,name=requested_swaps,json=requestedSwaps,proto3" json:"requested_swaps,omitempty"`
+	NRequests      uint64           `protobuf:"varint,2,opt,name=n_requests,json=nRequests,proto3" json:"n_requests,omitempty"`
+	TotalAmountSat uint64           `protobuf:"varint,3,opt,name=total_amount_sat,json=totalAmountSat,proto3" json:"total_amount_sat,omitempty"`
+	// Number of requests by rejection code.
+	RejectionCodes map[string]uint64 `protobuf:"bytes,4,rep,name=rejection_codes,json=rejectionCodes,proto3" json:"rejection_codes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Unix times of the oldest and the latest timestamped request.
+	FirstRequestAt int64 `protobuf:"varint,5,opt,name=first_request_at,json=firstRequestAt,proto3" json:"first_request_at,omitempty"`
+	LastRequestAt  int64 `protobuf:"varint,6,opt,name=last_request_at,json=lastRequestAt,proto3" json:"last_request_at,omitempty"`
+}
+
+func (x *RequestSwapList) Reset() {
+	*x = RequestSwapList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestSwapList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestSwapList) ProtoMessage() {}
+
+func (x *RequestSwapList) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestSwapList.ProtoReflect.Descriptor instead.
+func (*RequestSwapList) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RequestSwapList) GetRequestedSwaps() []*RequestedSwap {
+	if x != nil {
+		return x.RequestedSwaps
+	}
+	return nil
+}
+
+func (x *RequestSwapList) GetNRequests() uint64 {
+	if x != nil {
+		return x.NRequests
+	}
+	return 0
+}
+
+func (x *RequestSwapList) GetTotalAmountSat() uint64 {
+	if x != nil {
+		return x.TotalAmountSat
+	}
+	return 0
+}
+
+func (x *RequestSwapList) GetRejectionCodes() map[string]uint64 {
+	if x != nil {
+		return x.RejectionCodes
+	}
+	return nil
+}
+
+func (x *RequestSwapList) GetFirstRequestAt() int64 {
+	if x != nil {
+		return x.FirstRequestAt
+	}
+	return 0
+}
+
+func (x *RequestSwapList) GetLastRequestAt() int64 {
+	if x != nil {
+		return x.LastRequestAt
+	}
+	return 0
+}
+
+type RequestedSwap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Asset           string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	AmountSat       uint64                 `protobuf:"varint,2,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
+	SwapType        RequestedSwap_SwapType `protobuf:"varint,3,opt,name=swap_type,json=swapType,proto3,enum=peerswap.RequestedSwap_SwapType" json:"swap_type,omitempty"`
+	RejectionReason string                 `protobuf:"bytes,4,opt,name=rejection_reason,json=rejectionReason,proto3" json:"rejection_reason,omitempty"`
+	// Machine readable reason why the request was rejected.
+	RejectionCode CancelCode `protobuf:"varint,5,opt,name=rejection_code,json=rejectionCode,proto3,enum=peerswap.CancelCode" json:"rejection_code,omitempty"`
+	// Unix time the request was received, 0 for requests stored by older
+	// versions.
+	CreatedAt int64 `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *RequestedSwap) Reset() {
+	*x = RequestedSwap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestedSwap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestedSwap) ProtoMessage() {}
+
+func (x *RequestedSwap) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestedSwap.ProtoReflect.Descriptor instead.
+func (*RequestedSwap) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RequestedSwap) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *RequestedSwap) GetAmountSat() uint64 {
+	if x != nil {
+		return x.AmountSat
+	}
+	return 0
+}
+
+func (x *RequestedSwap) GetSwapType() RequestedSwap_SwapType {
+	if x != nil {
+		return x.SwapType
+	}
+	return RequestedSwap_SWAP_IN
+}
+
+func (x *RequestedSwap) GetRejectionReason() string {
+	if x != nil {
+		return x.RejectionReason
+	}
+	return ""
+}
+
+func (x *RequestedSwap) GetRejectionCode() CancelCode {
+	if x != nil {
+		return x.RejectionCode
+	}
+	return CancelCode_UNKNOWN
+}
+
+func (x *RequestedSwap) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type PrettyPrintSwap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt       int64  `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Asset           string `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
+	Type            string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Role            string `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
+	State           string `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	InitiatorNodeId string `protobuf:"bytes,7,opt,name=initiator_node_id,json=initiatorNodeId,proto3" json:"initiator_node_id,omitempty"`
+	PeerNodeId      string `protobuf:"bytes,8,opt,name=peer_node_id,json=peerNodeId,proto3" json:"peer_node_id,omitempty"`
+	Amount          uint64 `protobuf:"varint,9,opt,name=amount,proto3" json:"amount,omitempty"`
+	ChannelId       string `protobuf:"bytes,10,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	OpeningTxId     string `protobuf:"bytes,11,opt,name=opening_tx_id,json=openingTxId,proto3" json:"opening_tx_id,omitempty"`
+	ClaimTxId       string `protobuf:"bytes,12,opt,name=claim_tx_id,json=claimTxId,proto3" json:"claim_tx_id,omitempty"`
+	CancelMessage   string `protobuf:"bytes,13,opt,name=cancel_message,json=cancelMessage,proto3" json:"cancel_message,omitempty"`
+	LndChanId       uint64 `protobuf:"varint,14,opt,name=lnd_chan_id,json=lndChanId,proto3" json:"lnd_chan_id,omitempty"`
+	// The id of a tx that spent the opening output competing with the
+	// claim of the swap, if any.
+	DoubleSpendTxId string `protobuf:"bytes,15,opt,name=double_spend_tx_id,json=doubleSpendTxId,proto3" json:"double_spend_tx_id,omitempty"`
+	// Machine readable reason why the swap was canceled.
+	CancelCode CancelCode `protobuf:"varint,16,opt,name=cancel_code,json=cancelCode,proto3,enum=peerswap.CancelCode" json:"cancel_code,omitempty"`
+	// The unsigned PSBT (PSET) of an externally funded swap in, set while
+	// the swap waits for the signed opening tx.
+	OpeningPsbt string `protobuf:"bytes,17,opt,name=opening_psbt,json=openingPsbt,proto3" json:"opening_psbt,omitempty"`
+}
+
+func (x *PrettyPrintSwap) Reset() {
+	*x = PrettyPrintSwap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrettyPrintSwap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrettyPrintSwap) ProtoMessage() {}
+
+func (x *PrettyPrintSwap) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrettyPrintSwap.ProtoReflect.Descriptor instead.
+func (*PrettyPrintSwap) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PrettyPrintSwap) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *PrettyPrintSwap) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetInitiatorNodeId() string {
+	if x != nil {
+		return x.InitiatorNodeId
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetPeerNodeId() string {
+	if x != nil {
+		return x.PeerNodeId
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PrettyPrintSwap) GetChannelId() string {
+	if x != nil {
+		return x.ChannelId
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetOpeningTxId() string {
+	if x != nil {
+		return x.OpeningTxId
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetClaimTxId() string {
+	if x != nil {
+		return x.ClaimTxId
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetCancelMessage() string {
+	if x != nil {
+		return x.CancelMessage
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetLndChanId() uint64 {
+	if x != nil {
+		return x.LndChanId
+	}
+	return 0
+}
+
+func (x *PrettyPrintSwap) GetDoubleSpendTxId() string {
+	if x != nil {
+		return x.DoubleSpendTxId
+	}
+	return ""
+}
+
+func (x *PrettyPrintSwap) GetCancelCode() CancelCode {
+	if x != nil {
+		return x.CancelCode
+	}
+	return CancelCode_UNKNOWN
+}
+
+func (x *PrettyPrintSwap) GetOpeningPsbt() string {
+	if x != nil {
+		return x.OpeningPsbt
+	}
+	return ""
+}
+
+type PeerSwapPeer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId          string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	SwapsAllowed    bool                   `protobuf:"varint,2,opt,name=swaps_allowed,json=swapsAllowed,proto3" json:"swaps_allowed,omitempty"`
+	SupportedAssets []string               `protobuf:"bytes,3,rep,name=supported_assets,json=supportedAssets,proto3" json:"supported_assets,omitempty"`
+	Channels        []*PeerSwapPeerChannel `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	AsSender        *SwapStats             `protobuf:"bytes,5,opt,name=as_sender,json=asSender,proto3" json:"as_sender,omitempty"`
+	AsReceiver      *SwapStats             `protobuf:"bytes,6,opt,name=as_receiver,json=asReceiver,proto3" json:"as_receiver,omitempty"`
+	PaidFee         uint64                 `protobuf:"varint,7,opt,name=paid_fee,json=paidFee,proto3" json:"paid_fee,omitempty"`
+	Capabilities    *PeerCapabilities      `protobuf:"bytes,8,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// fee_overcharges is the number of swap-outs that were canceled because
+	// the fee invoice of the peer exceeded our limits.
+	FeeOvercharges uint64 `protobuf:"varint,9,opt,name=fee_overcharges,json=feeOvercharges,proto3" json:"fee_overcharges,omitempty"`
+}
+
+func (x *PeerSwapPeer) Reset() {
+	*x = PeerSwapPeer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerSwapPeer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerSwapPeer) ProtoMessage() {}
+
+func (x *PeerSwapPeer) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerSwapPeer.ProtoReflect.Descriptor instead.
+func (*PeerSwapPeer) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PeerSwapPeer) GetNodeId() string {
 	if x != nil {
-		return x.RequestedSwaps
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *PeerSwapPeer) GetSwapsAllowed() bool {
+	if x != nil {
+		return x.SwapsAllowed
+	}
+	return false
+}
+
+func (x *PeerSwapPeer) GetSupportedAssets() []string {
+	if x != nil {
+		return x.SupportedAssets
 	}
 	return nil
 }
 
-type RequestedSwap struct {
+func (x *PeerSwapPeer) GetChannels() []*PeerSwapPeerChannel {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *PeerSwapPeer) GetAsSender() *SwapStats {
+	if x != nil {
+		return x.AsSender
+	}
+	return nil
+}
+
+func (x *PeerSwapPeer) GetAsReceiver() *SwapStats {
+	if x != nil {
+		return x.AsReceiver
+	}
+	return nil
+}
+
+func (x *PeerSwapPeer) GetPaidFee() uint64 {
+	if x != nil {
+		return x.PaidFee
+	}
+	return 0
+}
+
+func (x *PeerSwapPeer) GetCapabilities() *PeerCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *PeerSwapPeer) GetFeeOvercharges() uint64 {
+	if x != nil {
+		return x.FeeOvercharges
+	}
+	return 0
+}
+
+type PeerCapabilities struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Asset           string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
-	AmountSat       uint64                 `protobuf:"varint,2,opt,name=amount_sat,json=amountSat,proto3" json:"amount_sat,omitempty"`
-	SwapType        RequestedSwap_SwapType `protobuf:"varint,3,opt,name=swap_type,json=swapType,proto3,enum=peerswap.RequestedSwap_SwapType" json:"swap_type,omitempty"`
-	RejectionReason string                 `protobuf:"bytes,4,opt,name=rejection_reason,json=rejectionReason,proto3" json:"rejection_reason,omitempty"`
+	Version       uint64               `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	AllowNewSwaps bool                 `protobuf:"varint,2,opt,name=allow_new_swaps,json=allowNewSwaps,proto3" json:"allow_new_swaps,omitempty"`
+	OutputTypes   []string             `protobuf:"bytes,3,rep,name=output_types,json=outputTypes,proto3" json:"output_types,omitempty"`
+	Encodings     []string             `protobuf:"bytes,4,rep,name=encodings,proto3" json:"encodings,omitempty"`
+	Assets        []*AssetCapabilities `protobuf:"bytes,5,rep,name=assets,proto3" json:"assets,omitempty"`
 }
 
-func (x *RequestedSwap) Reset() {
-	*x = RequestedSwap{}
+func (x *PeerCapabilities) Reset() {
+	*x = PeerCapabilities{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[21]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestedSwap) String() string {
+func (x *PeerCapabilities) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestedSwap) ProtoMessage() {}
+func (*PeerCapabilities) ProtoMessage() {}
 
-func (x *RequestedSwap) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[21]
+func (x *PeerCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1093,77 +2128,74 @@ func (x *RequestedSwap) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestedSwap.ProtoReflect.Descriptor instead.
-func (*RequestedSwap) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use PeerCapabilities.ProtoReflect.Descriptor instead.
+func (*PeerCapabilities) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *RequestedSwap) GetAsset() string {
+func (x *PeerCapabilities) GetVersion() uint64 {
 	if x != nil {
-		return x.Asset
+		return x.Version
 	}
-	return ""
+	return 0
 }
 
-func (x *RequestedSwap) GetAmountSat() uint64 {
+func (x *PeerCapabilities) GetAllowNewSwaps() bool {
 	if x != nil {
-		return x.AmountSat
+		return x.AllowNewSwaps
 	}
-	return 0
+	return false
 }
 
-func (x *RequestedSwap) GetSwapType() RequestedSwap_SwapType {
+func (x *PeerCapabilities) GetOutputTypes() []string {
 	if x != nil {
-		return x.SwapType
+		return x.OutputTypes
 	}
-	return RequestedSwap_SWAP_IN
+	return nil
 }
 
-func (x *RequestedSwap) GetRejectionReason() string {
+func (x *PeerCapabilities) GetEncodings() []string {
 	if x != nil {
-		return x.RejectionReason
+		return x.Encodings
 	}
-	return ""
+	return nil
 }
 
-type PrettyPrintSwap struct {
+func (x *PeerCapabilities) GetAssets() []*AssetCapabilities {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+type AssetCapabilities struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	CreatedAt       int64  `protobuf:"varint,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Asset           string `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
-	Type            string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
-	Role            string `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
-	State           string `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
-	InitiatorNodeId string `protobuf:"bytes,7,opt,name=initiator_node_id,json=initiatorNodeId,proto3" json:"initiator_node_id,omitempty"`
-	PeerNodeId      string `protobuf:"bytes,8,opt,name=peer_node_id,json=peerNodeId,proto3" json:"peer_node_id,omitempty"`
-	Amount          uint64 `protobuf:"varint,9,opt,name=amount,proto3" json:"amount,omitempty"`
-	ChannelId       string `protobuf:"bytes,10,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
-	OpeningTxId     string `protobuf:"bytes,11,opt,name=opening_tx_id,json=openingTxId,proto3" json:"opening_tx_id,omitempty"`
-	ClaimTxId       string `protobuf:"bytes,12,opt,name=claim_tx_id,json=claimTxId,proto3" json:"claim_tx_id,omitempty"`
-	CancelMessage   string `protobuf:"bytes,13,opt,name=cancel_message,json=cancelMessage,proto3" json:"cancel_message,omitempty"`
-	LndChanId       uint64 `protobuf:"varint,14,opt,name=lnd_chan_id,json=lndChanId,proto3" json:"lnd_chan_id,omitempty"`
+	Asset               string   `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	MinAmountSat        uint64   `protobuf:"varint,2,opt,name=min_amount_sat,json=minAmountSat,proto3" json:"min_amount_sat,omitempty"`
+	MaxSwapOutAmountSat uint64   `protobuf:"varint,3,opt,name=max_swap_out_amount_sat,json=maxSwapOutAmountSat,proto3" json:"max_swap_out_amount_sat,omitempty"`
+	SwapTypes           []string `protobuf:"bytes,4,rep,name=swap_types,json=swapTypes,proto3" json:"swap_types,omitempty"`
 }
 
-func (x *PrettyPrintSwap) Reset() {
-	*x = PrettyPrintSwap{}
+func (x *AssetCapabilities) Reset() {
+	*x = AssetCapabilities{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[22]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PrettyPrintSwap) String() string {
+func (x *AssetCapabilities) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PrettyPrintSwap) ProtoMessage() {}
+func (*AssetCapabilities) ProtoMessage() {}
 
-func (x *PrettyPrintSwap) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[22]
+func (x *AssetCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1174,140 +2206,258 @@ func (x *PrettyPrintSwap) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PrettyPrintSwap.ProtoReflect.Descriptor instead.
-func (*PrettyPrintSwap) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use AssetCapabilities.ProtoReflect.Descriptor instead.
+func (*AssetCapabilities) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *PrettyPrintSwap) GetId() string {
+func (x *AssetCapabilities) GetAsset() string {
 	if x != nil {
-		return x.Id
+		return x.Asset
 	}
 	return ""
 }
 
-func (x *PrettyPrintSwap) GetCreatedAt() int64 {
+func (x *AssetCapabilities) GetMinAmountSat() uint64 {
 	if x != nil {
-		return x.CreatedAt
+		return x.MinAmountSat
 	}
 	return 0
 }
 
-func (x *PrettyPrintSwap) GetAsset() string {
+func (x *AssetCapabilities) GetMaxSwapOutAmountSat() uint64 {
 	if x != nil {
-		return x.Asset
+		return x.MaxSwapOutAmountSat
 	}
-	return ""
+	return 0
 }
 
-func (x *PrettyPrintSwap) GetType() string {
+func (x *AssetCapabilities) GetSwapTypes() []string {
 	if x != nil {
-		return x.Type
+		return x.SwapTypes
 	}
-	return ""
+	return nil
 }
 
-func (x *PrettyPrintSwap) GetRole() string {
+type GetPeerHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PeerPubkey string `protobuf:"bytes,1,opt,name=peer_pubkey,json=peerPubkey,proto3" json:"peer_pubkey,omitempty"`
+}
+
+func (x *GetPeerHistoryRequest) Reset() {
+	*x = GetPeerHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerHistoryRequest) ProtoMessage() {}
+
+func (x *GetPeerHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPeerHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetPeerHistoryRequest) GetPeerPubkey() string {
 	if x != nil {
-		return x.Role
+		return x.PeerPubkey
 	}
 	return ""
 }
 
-func (x *PrettyPrintSwap) GetState() string {
+type GetPeerHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// first_seen and last_seen are the unix times of the first and the last
+	// poll we received from the peer.
+	FirstSeen    int64              `protobuf:"varint,2,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	LastSeen     int64              `protobuf:"varint,3,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Uptime       []*PeerUptime      `protobuf:"bytes,4,rep,name=uptime,proto3" json:"uptime,omitempty"`
+	Versions     []*PeerVersion     `protobuf:"bytes,5,rep,name=versions,proto3" json:"versions,omitempty"`
+	AssetChanges []*PeerAssetChange `protobuf:"bytes,6,rep,name=asset_changes,json=assetChanges,proto3" json:"asset_changes,omitempty"`
+}
+
+func (x *GetPeerHistoryResponse) Reset() {
+	*x = GetPeerHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerHistoryResponse) ProtoMessage() {}
+
+func (x *GetPeerHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetPeerHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetPeerHistoryResponse) GetNodeId() string {
 	if x != nil {
-		return x.State
+		return x.NodeId
 	}
 	return ""
 }
 
-func (x *PrettyPrintSwap) GetInitiatorNodeId() string {
+func (x *GetPeerHistoryResponse) GetFirstSeen() int64 {
 	if x != nil {
-		return x.InitiatorNodeId
+		return x.FirstSeen
 	}
-	return ""
+	return 0
 }
 
-func (x *PrettyPrintSwap) GetPeerNodeId() string {
+func (x *GetPeerHistoryResponse) GetLastSeen() int64 {
 	if x != nil {
-		return x.PeerNodeId
+		return x.LastSeen
 	}
-	return ""
+	return 0
+}
+
+func (x *GetPeerHistoryResponse) GetUptime() []*PeerUptime {
+	if x != nil {
+		return x.Uptime
+	}
+	return nil
+}
+
+func (x *GetPeerHistoryResponse) GetVersions() []*PeerVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *GetPeerHistoryResponse) GetAssetChanges() []*PeerAssetChange {
+	if x != nil {
+		return x.AssetChanges
+	}
+	return nil
+}
+
+type PeerUptime struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WindowHours uint64 `protobuf:"varint,1,opt,name=window_hours,json=windowHours,proto3" json:"window_hours,omitempty"`
+	// percentage of the hours in the window in which the peer sent a poll.
+	Percentage float64 `protobuf:"fixed64,2,opt,name=percentage,proto3" json:"percentage,omitempty"`
 }
 
-func (x *PrettyPrintSwap) GetAmount() uint64 {
-	if x != nil {
-		return x.Amount
+func (x *PeerUptime) Reset() {
+	*x = PeerUptime{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *PrettyPrintSwap) GetChannelId() string {
-	if x != nil {
-		return x.ChannelId
-	}
-	return ""
+func (x *PeerUptime) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *PrettyPrintSwap) GetOpeningTxId() string {
-	if x != nil {
-		return x.OpeningTxId
+func (*PeerUptime) ProtoMessage() {}
+
+func (x *PeerUptime) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *PrettyPrintSwap) GetClaimTxId() string {
-	if x != nil {
-		return x.ClaimTxId
-	}
-	return ""
+// Deprecated: Use PeerUptime.ProtoReflect.Descriptor instead.
+func (*PeerUptime) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *PrettyPrintSwap) GetCancelMessage() string {
+func (x *PeerUptime) GetWindowHours() uint64 {
 	if x != nil {
-		return x.CancelMessage
+		return x.WindowHours
 	}
-	return ""
+	return 0
 }
 
-func (x *PrettyPrintSwap) GetLndChanId() uint64 {
+func (x *PeerUptime) GetPercentage() float64 {
 	if x != nil {
-		return x.LndChanId
+		return x.Percentage
 	}
 	return 0
 }
 
-type PeerSwapPeer struct {
+type PeerVersion struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeId          string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	SwapsAllowed    bool                   `protobuf:"varint,2,opt,name=swaps_allowed,json=swapsAllowed,proto3" json:"swaps_allowed,omitempty"`
-	SupportedAssets []string               `protobuf:"bytes,3,rep,name=supported_assets,json=supportedAssets,proto3" json:"supported_assets,omitempty"`
-	Channels        []*PeerSwapPeerChannel `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
-	AsSender        *SwapStats             `protobuf:"bytes,5,opt,name=as_sender,json=asSender,proto3" json:"as_sender,omitempty"`
-	AsReceiver      *SwapStats             `protobuf:"bytes,6,opt,name=as_receiver,json=asReceiver,proto3" json:"as_receiver,omitempty"`
-	PaidFee         uint64                 `protobuf:"varint,7,opt,name=paid_fee,json=paidFee,proto3" json:"paid_fee,omitempty"`
+	Version   uint64 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	FirstSeen int64  `protobuf:"varint,2,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	LastSeen  int64  `protobuf:"varint,3,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
 }
 
-func (x *PeerSwapPeer) Reset() {
-	*x = PeerSwapPeer{}
+func (x *PeerVersion) Reset() {
+	*x = PeerVersion{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[23]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PeerSwapPeer) String() string {
+func (x *PeerVersion) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PeerSwapPeer) ProtoMessage() {}
+func (*PeerVersion) ProtoMessage() {}
 
-func (x *PeerSwapPeer) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[23]
+func (x *PeerVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1318,58 +2468,85 @@ func (x *PeerSwapPeer) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PeerSwapPeer.ProtoReflect.Descriptor instead.
-func (*PeerSwapPeer) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use PeerVersion.ProtoReflect.Descriptor instead.
+func (*PeerVersion) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *PeerSwapPeer) GetNodeId() string {
+func (x *PeerVersion) GetVersion() uint64 {
 	if x != nil {
-		return x.NodeId
+		return x.Version
 	}
-	return ""
+	return 0
 }
 
-func (x *PeerSwapPeer) GetSwapsAllowed() bool {
+func (x *PeerVersion) GetFirstSeen() int64 {
 	if x != nil {
-		return x.SwapsAllowed
+		return x.FirstSeen
 	}
-	return false
+	return 0
 }
 
-func (x *PeerSwapPeer) GetSupportedAssets() []string {
+func (x *PeerVersion) GetLastSeen() int64 {
 	if x != nil {
-		return x.SupportedAssets
+		return x.LastSeen
 	}
-	return nil
+	return 0
 }
 
-func (x *PeerSwapPeer) GetChannels() []*PeerSwapPeerChannel {
-	if x != nil {
-		return x.Channels
+type PeerAssetChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time   int64    `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Assets []string `protobuf:"bytes,2,rep,name=assets,proto3" json:"assets,omitempty"`
+}
+
+func (x *PeerAssetChange) Reset() {
+	*x = PeerAssetChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *PeerSwapPeer) GetAsSender() *SwapStats {
-	if x != nil {
-		return x.AsSender
+func (x *PeerAssetChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerAssetChange) ProtoMessage() {}
+
+func (x *PeerAssetChange) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *PeerSwapPeer) GetAsReceiver() *SwapStats {
+// Deprecated: Use PeerAssetChange.ProtoReflect.Descriptor instead.
+func (*PeerAssetChange) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PeerAssetChange) GetTime() int64 {
 	if x != nil {
-		return x.AsReceiver
+		return x.Time
 	}
-	return nil
+	return 0
 }
 
-func (x *PeerSwapPeer) GetPaidFee() uint64 {
+func (x *PeerAssetChange) GetAssets() []string {
 	if x != nil {
-		return x.PaidFee
+		return x.Assets
 	}
-	return 0
+	return nil
 }
 
 type PeerSwapPeerChannel struct {
@@ -1387,7 +2564,7 @@ type PeerSwapPeerChannel struct {
 func (x *PeerSwapPeerChannel) Reset() {
 	*x = PeerSwapPeerChannel{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[24]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1400,7 +2577,7 @@ func (x *PeerSwapPeerChannel) String() string {
 func (*PeerSwapPeerChannel) ProtoMessage() {}
 
 func (x *PeerSwapPeerChannel) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[24]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1413,7 +2590,7 @@ func (x *PeerSwapPeerChannel) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerSwapPeerChannel.ProtoReflect.Descriptor instead.
 func (*PeerSwapPeerChannel) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{24}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *PeerSwapPeerChannel) GetChannelId() uint64 {
@@ -1465,7 +2642,7 @@ type SwapStats struct {
 func (x *SwapStats) Reset() {
 	*x = SwapStats{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[25]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1478,7 +2655,7 @@ func (x *SwapStats) String() string {
 func (*SwapStats) ProtoMessage() {}
 
 func (x *SwapStats) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[25]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1491,7 +2668,7 @@ func (x *SwapStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SwapStats.ProtoReflect.Descriptor instead.
 func (*SwapStats) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{25}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *SwapStats) GetSwapsOut() uint64 {
@@ -1533,7 +2710,7 @@ type PeerSwapNodes struct {
 func (x *PeerSwapNodes) Reset() {
 	*x = PeerSwapNodes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[26]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1546,7 +2723,7 @@ func (x *PeerSwapNodes) String() string {
 func (*PeerSwapNodes) ProtoMessage() {}
 
 func (x *PeerSwapNodes) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[26]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1559,7 +2736,7 @@ func (x *PeerSwapNodes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerSwapNodes.ProtoReflect.Descriptor instead.
 func (*PeerSwapNodes) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{26}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *PeerSwapNodes) GetNodeId() string {
@@ -1574,18 +2751,22 @@ type Policy struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ReserveOnchainMsat uint64   `protobuf:"varint,1,opt,name=reserve_onchain_msat,json=reserveOnchainMsat,proto3" json:"reserve_onchain_msat,omitempty"`
-	MinSwapAmountMsat  uint64   `protobuf:"varint,2,opt,name=min_swap_amount_msat,json=minSwapAmountMsat,proto3" json:"min_swap_amount_msat,omitempty"`
-	AcceptAllPeers     bool     `protobuf:"varint,3,opt,name=accept_all_peers,json=acceptAllPeers,proto3" json:"accept_all_peers,omitempty"`
-	AllowNewSwaps      bool     `protobuf:"varint,4,opt,name=allow_new_swaps,json=allowNewSwaps,proto3" json:"allow_new_swaps,omitempty"`
-	AllowlistedPeers   []string `protobuf:"bytes,5,rep,name=allowlisted_peers,json=allowlistedPeers,proto3" json:"allowlisted_peers,omitempty"`
-	SuspiciousPeerList []string `protobuf:"bytes,6,rep,name=suspicious_peer_list,json=suspiciousPeerList,proto3" json:"suspicious_peer_list,omitempty"`
+	ReserveOnchainMsat        uint64   `protobuf:"varint,1,opt,name=reserve_onchain_msat,json=reserveOnchainMsat,proto3" json:"reserve_onchain_msat,omitempty"`
+	MinSwapAmountMsat         uint64   `protobuf:"varint,2,opt,name=min_swap_amount_msat,json=minSwapAmountMsat,proto3" json:"min_swap_amount_msat,omitempty"`
+	AcceptAllPeers            bool     `protobuf:"varint,3,opt,name=accept_all_peers,json=acceptAllPeers,proto3" json:"accept_all_peers,omitempty"`
+	AllowNewSwaps             bool     `protobuf:"varint,4,opt,name=allow_new_swaps,json=allowNewSwaps,proto3" json:"allow_new_swaps,omitempty"`
+	AllowlistedPeers          []string `protobuf:"bytes,5,rep,name=allowlisted_peers,json=allowlistedPeers,proto3" json:"allowlisted_peers,omitempty"`
+	SuspiciousPeerList        []string `protobuf:"bytes,6,rep,name=suspicious_peer_list,json=suspiciousPeerList,proto3" json:"suspicious_peer_list,omitempty"`
+	MaxFeeInvoiceMultiple     float64  `protobuf:"fixed64,7,opt,name=max_fee_invoice_multiple,json=maxFeeInvoiceMultiple,proto3" json:"max_fee_invoice_multiple,omitempty"`
+	MaxFeeInvoiceSat          uint64   `protobuf:"varint,8,opt,name=max_fee_invoice_sat,json=maxFeeInvoiceSat,proto3" json:"max_fee_invoice_sat,omitempty"`
+	MaxPendingIncomingSwaps   uint64   `protobuf:"varint,9,opt,name=max_pending_incoming_swaps,json=maxPendingIncomingSwaps,proto3" json:"max_pending_incoming_swaps,omitempty"`
+	ExternalFundingTimeoutSec uint64   `protobuf:"varint,10,opt,name=external_funding_timeout_sec,json=externalFundingTimeoutSec,proto3" json:"external_funding_timeout_sec,omitempty"`
 }
 
 func (x *Policy) Reset() {
 	*x = Policy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[27]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1598,7 +2779,7 @@ func (x *Policy) String() string {
 func (*Policy) ProtoMessage() {}
 
 func (x *Policy) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[27]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1611,7 +2792,7 @@ func (x *Policy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Policy.ProtoReflect.Descriptor instead.
 func (*Policy) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{27}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *Policy) GetReserveOnchainMsat() uint64 {
@@ -1639,21 +2820,221 @@ func (x *Policy) GetAllowNewSwaps() bool {
 	if x != nil {
 		return x.AllowNewSwaps
 	}
-	return false
+	return false
+}
+
+func (x *Policy) GetAllowlistedPeers() []string {
+	if x != nil {
+		return x.AllowlistedPeers
+	}
+	return nil
+}
+
+func (x *Policy) GetSuspiciousPeerList() []string {
+	if x != nil {
+		return x.SuspiciousPeerList
+	}
+	return nil
+}
+
+func (x *Policy) GetMaxFeeInvoiceMultiple() float64 {
+	if x != nil {
+		return x.MaxFeeInvoiceMultiple
+	}
+	return 0
+}
+
+func (x *Policy) GetMaxFeeInvoiceSat() uint64 {
+	if x != nil {
+		return x.MaxFeeInvoiceSat
+	}
+	return 0
+}
+
+func (x *Policy) GetMaxPendingIncomingSwaps() uint64 {
+	if x != nil {
+		return x.MaxPendingIncomingSwaps
+	}
+	return 0
+}
+
+func (x *Policy) GetExternalFundingTimeoutSec() uint64 {
+	if x != nil {
+		return x.ExternalFundingTimeoutSec
+	}
+	return 0
+}
+
+type DrainRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// shutdown stops the daemon once the last active swap completed.
+	Shutdown bool `protobuf:"varint,1,opt,name=shutdown,proto3" json:"shutdown,omitempty"`
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *DrainRequest) GetShutdown() bool {
+	if x != nil {
+		return x.Shutdown
+	}
+	return false
+}
+
+type DrainResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Swaps []*DrainingSwap `protobuf:"bytes,1,rep,name=swaps,proto3" json:"swaps,omitempty"`
+	// shutdown is true if the daemon stops once the last active swap
+	// completed.
+	Shutdown bool `protobuf:"varint,2,opt,name=shutdown,proto3" json:"shutdown,omitempty"`
+}
+
+func (x *DrainResponse) Reset() {
+	*x = DrainResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainResponse) ProtoMessage() {}
+
+func (x *DrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainResponse.ProtoReflect.Descriptor instead.
+func (*DrainResponse) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DrainResponse) GetSwaps() []*DrainingSwap {
+	if x != nil {
+		return x.Swaps
+	}
+	return nil
+}
+
+func (x *DrainResponse) GetShutdown() bool {
+	if x != nil {
+		return x.Shutdown
+	}
+	return false
+}
+
+type DrainingSwap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Swap *PrettyPrintSwap `protobuf:"bytes,1,opt,name=swap,proto3" json:"swap,omitempty"`
+	// current_height is the current block height of the swap's chain.
+	CurrentHeight uint32 `protobuf:"varint,2,opt,name=current_height,json=currentHeight,proto3" json:"current_height,omitempty"`
+	// completion_height is the height by which the swap completes at the
+	// latest, when the csv of the opening tx passes. It is 0 if the opening
+	// tx is not known yet.
+	CompletionHeight uint32 `protobuf:"varint,3,opt,name=completion_height,json=completionHeight,proto3" json:"completion_height,omitempty"`
+}
+
+func (x *DrainingSwap) Reset() {
+	*x = DrainingSwap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainingSwap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainingSwap) ProtoMessage() {}
+
+func (x *DrainingSwap) ProtoReflect() protoreflect.Message {
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainingSwap.ProtoReflect.Descriptor instead.
+func (*DrainingSwap) Descriptor() ([]byte, []int) {
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DrainingSwap) GetSwap() *PrettyPrintSwap {
+	if x != nil {
+		return x.Swap
+	}
+	return nil
 }
 
-func (x *Policy) GetAllowlistedPeers() []string {
+func (x *DrainingSwap) GetCurrentHeight() uint32 {
 	if x != nil {
-		return x.AllowlistedPeers
+		return x.CurrentHeight
 	}
-	return nil
+	return 0
 }
 
-func (x *Policy) GetSuspiciousPeerList() []string {
+func (x *DrainingSwap) GetCompletionHeight() uint32 {
 	if x != nil {
-		return x.SuspiciousPeerList
+		return x.CompletionHeight
 	}
-	return nil
+	return 0
 }
 
 type AllowSwapRequestsRequest struct {
@@ -1667,7 +3048,7 @@ type AllowSwapRequestsRequest struct {
 func (x *AllowSwapRequestsRequest) Reset() {
 	*x = AllowSwapRequestsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[28]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1680,7 +3061,7 @@ func (x *AllowSwapRequestsRequest) String() string {
 func (*AllowSwapRequestsRequest) ProtoMessage() {}
 
 func (x *AllowSwapRequestsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[28]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1693,7 +3074,7 @@ func (x *AllowSwapRequestsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AllowSwapRequestsRequest.ProtoReflect.Descriptor instead.
 func (*AllowSwapRequestsRequest) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{28}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *AllowSwapRequestsRequest) GetAllow() bool {
@@ -1714,7 +3095,7 @@ type AllowSwapRequestsResponse struct {
 func (x *AllowSwapRequestsResponse) Reset() {
 	*x = AllowSwapRequestsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[29]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[47]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1727,7 +3108,7 @@ func (x *AllowSwapRequestsResponse) String() string {
 func (*AllowSwapRequestsResponse) ProtoMessage() {}
 
 func (x *AllowSwapRequestsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[29]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[47]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1740,7 +3121,7 @@ func (x *AllowSwapRequestsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AllowSwapRequestsResponse.ProtoReflect.Descriptor instead.
 func (*AllowSwapRequestsResponse) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{29}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *AllowSwapRequestsResponse) GetAllow() bool {
@@ -1759,7 +3140,7 @@ type Empty struct {
 func (x *Empty) Reset() {
 	*x = Empty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[30]
+		mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1772,7 +3153,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[30]
+	mi := &file_peerswaprpc_peerswaprpc_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1785,7 +3166,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{30}
+	return file_peerswaprpc_peerswaprpc_proto_rawDescGZIP(), []int{48}
 }
 
 var File_peerswaprpc_peerswaprpc_proto protoreflect.FileDescriptor
@@ -1810,212 +3191,441 @@ var file_peerswaprpc_peerswaprpc_proto_rawDesc = []byte{
 	0x73, 0x61, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x2c, 0x0a, 0x15, 0x53, 0x65, 0x6e,
 	0x64, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
 	0x73, 0x65, 0x12, 0x13, 0x0a, 0x05, 0x74, 0x78, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x74, 0x78, 0x49, 0x64, 0x22, 0x7c, 0x0a, 0x0e, 0x53, 0x77, 0x61, 0x70, 0x4f,
-	0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x77, 0x61, 0x70,
-	0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73,
-	0x77, 0x61, 0x70, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73,
-	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
-	0x66, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x40, 0x0a, 0x0f, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x77, 0x61, 0x70,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
-	0x70, 0x2e, 0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61,
-	0x70, 0x52, 0x04, 0x73, 0x77, 0x61, 0x70, 0x22, 0x7b, 0x0a, 0x0d, 0x53, 0x77, 0x61, 0x70, 0x49,
-	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x77, 0x61, 0x70, 0x5f,
-	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x77,
-	0x61, 0x70, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x14,
-	0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66,
-	0x6f, 0x72, 0x63, 0x65, 0x22, 0x3d, 0x0a, 0x0c, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70,
+	0x09, 0x52, 0x04, 0x74, 0x78, 0x49, 0x64, 0x22, 0x14, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4a, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x08, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
+	0x70, 0x2e, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x08, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x22, 0xb1, 0x01, 0x0a, 0x0d, 0x57, 0x61,
+	0x6c, 0x6c, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x73, 0x61, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x53,
+	0x61, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x73, 0x61,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x53, 0x61, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64,
+	0x5f, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x64, 0x53, 0x61, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0c, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x61, 0x74, 0x22, 0xd1, 0x01,
+	0x0a, 0x0e, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12,
+	0x1f, 0x0a, 0x0b, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x77, 0x61, 0x70, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x74, 0x6f, 0x5f, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x63, 0x6c, 0x61, 0x69, 0x6d, 0x54, 0x6f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f,
+	0x72, 0x22, 0x40, 0x0a, 0x0f, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70,
 	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x77, 0x61, 0x70, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x72,
 	0x65, 0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61, 0x70, 0x52, 0x04, 0x73,
-	0x77, 0x61, 0x70, 0x22, 0x29, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x77, 0x61, 0x70, 0x49, 0x64, 0x22, 0x12,
-	0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0x44, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
-	0x70, 0x2e, 0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61,
-	0x70, 0x52, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
-	0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x11,
-	0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72,
-	0x53, 0x77, 0x61, 0x70, 0x50, 0x65, 0x65, 0x72, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x22,
-	0x19, 0x0a, 0x17, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x46,
-	0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x31, 0x0a, 0x0e, 0x41, 0x64,
-	0x64, 0x50, 0x65, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b,
-	0x70, 0x65, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0x34, 0x0a,
-	0x11, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x50, 0x65, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x22, 0x1b, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0xdd, 0x01, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x61, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x77, 0x61,
-	0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73,
-	0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65,
-	0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61,
-	0x70, 0x73, 0x1a, 0x5c, 0x0a, 0x13, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53,
-	0x77, 0x61, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65,
-	0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x77, 0x61,
-	0x70, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x22, 0x53, 0x0a, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x4c,
-	0x69, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64,
-	0x5f, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70,
-	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65,
-	0x64, 0x53, 0x77, 0x61, 0x70, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64,
-	0x53, 0x77, 0x61, 0x70, 0x73, 0x22, 0xd5, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x1d, 0x0a,
-	0x0a, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x09, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x61, 0x74, 0x12, 0x3d, 0x0a, 0x09,
-	0x73, 0x77, 0x61, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x20, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x54, 0x79, 0x70,
-	0x65, 0x52, 0x08, 0x73, 0x77, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x72,
-	0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x25, 0x0a, 0x08, 0x53, 0x77, 0x61, 0x70, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x57, 0x41, 0x50, 0x5f, 0x49, 0x4e, 0x10, 0x00, 0x12,
-	0x0c, 0x0a, 0x08, 0x53, 0x57, 0x41, 0x50, 0x5f, 0x4f, 0x55, 0x54, 0x10, 0x01, 0x22, 0xa4, 0x03,
-	0x0a, 0x0f, 0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61,
-	0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
-	0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f,
-	0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x14,
-	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f,
-	0x72, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64,
-	0x12, 0x20, 0x0a, 0x0c, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65,
-	0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x6f, 0x70, 0x65,
-	0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x49, 0x64, 0x12, 0x1e, 0x0a,
-	0x0b, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x74, 0x78, 0x5f, 0x69, 0x64, 0x18, 0x0c, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x54, 0x78, 0x49, 0x64, 0x12, 0x25, 0x0a,
-	0x0e, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
-	0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0b, 0x6c, 0x6e, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e,
-	0x5f, 0x69, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6c, 0x6e, 0x64, 0x43, 0x68,
-	0x61, 0x6e, 0x49, 0x64, 0x22, 0xb5, 0x02, 0x0a, 0x0c, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61,
-	0x70, 0x50, 0x65, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x23,
-	0x0a, 0x0d, 0x73, 0x77, 0x61, 0x70, 0x73, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x77, 0x61, 0x70, 0x73, 0x41, 0x6c, 0x6c, 0x6f,
-	0x77, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64,
-	0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x73,
-	0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x39,
-	0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x1d, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72,
-	0x53, 0x77, 0x61, 0x70, 0x50, 0x65, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
-	0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x61, 0x73, 0x5f,
-	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
-	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x53, 0x74, 0x61, 0x74,
-	0x73, 0x52, 0x08, 0x61, 0x73, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x0b, 0x61,
-	0x73, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x13, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70,
-	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x0a, 0x61, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
-	0x72, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x61, 0x69, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x61, 0x69, 0x64, 0x46, 0x65, 0x65, 0x22, 0xc3, 0x01, 0x0a,
-	0x13, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x50, 0x65, 0x65, 0x72, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f,
+	0x77, 0x61, 0x70, 0x22, 0xa6, 0x01, 0x0a, 0x0d, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x77, 0x61, 0x70, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66,
+	0x6f, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63,
+	0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x66, 0x75,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x65, 0x78, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x72, 0x0a, 0x12,
+	0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61,
+	0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x73,
+	0x77, 0x61, 0x70, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f,
+	0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65,
+	0x22, 0x52, 0x0a, 0x10, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x6c,
-	0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x6d, 0x6f,
-	0x74, 0x65, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
-	0x29, 0x0a, 0x10, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
-	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
-	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63,
-	0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
-	0x76, 0x65, 0x22, 0x77, 0x0a, 0x09, 0x53, 0x77, 0x61, 0x70, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
-	0x1b, 0x0a, 0x09, 0x73, 0x77, 0x61, 0x70, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x08, 0x73, 0x77, 0x61, 0x70, 0x73, 0x4f, 0x75, 0x74, 0x12, 0x19, 0x0a, 0x08,
-	0x73, 0x77, 0x61, 0x70, 0x73, 0x5f, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
-	0x73, 0x77, 0x61, 0x70, 0x73, 0x49, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x61, 0x74, 0x73, 0x5f,
-	0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x73, 0x61, 0x74, 0x73, 0x4f,
-	0x75, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x61, 0x74, 0x73, 0x5f, 0x69, 0x6e, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x61, 0x74, 0x73, 0x49, 0x6e, 0x22, 0x28, 0x0a, 0x0d, 0x50,
-	0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x17, 0x0a, 0x07,
-	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e,
-	0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x9c, 0x02, 0x0a, 0x06, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
-	0x12, 0x30, 0x0a, 0x14, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x6f, 0x6e, 0x63, 0x68,
-	0x61, 0x69, 0x6e, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12,
-	0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x4f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x4d, 0x73,
-	0x61, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x11, 0x6d, 0x69, 0x6e, 0x53, 0x77, 0x61, 0x70, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d,
-	0x73, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f, 0x61, 0x6c,
-	0x6c, 0x5f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x61,
-	0x63, 0x63, 0x65, 0x70, 0x74, 0x41, 0x6c, 0x6c, 0x50, 0x65, 0x65, 0x72, 0x73, 0x12, 0x26, 0x0a,
-	0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x77, 0x61, 0x70, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4e, 0x65, 0x77,
-	0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x6c, 0x69,
-	0x73, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x64, 0x50, 0x65, 0x65,
-	0x72, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73,
-	0x5f, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x12, 0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x50, 0x65, 0x65, 0x72,
-	0x4c, 0x69, 0x73, 0x74, 0x22, 0x30, 0x0a, 0x18, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x77, 0x61,
-	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x22, 0x31, 0x0a, 0x19, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x53,
-	0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x32, 0x9a, 0x09, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x12,
-	0x3b, 0x0a, 0x07, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x65, 0x65,
-	0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e,
-	0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x06,
-	0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x12, 0x17, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
-	0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x53, 0x77,
-	0x61, 0x70, 0x12, 0x18, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65,
-	0x74, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70,
-	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70,
-	0x73, 0x12, 0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73,
-	0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e,
-	0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61,
-	0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x09, 0x4c, 0x69,
-	0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
-	0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x5f, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65,
-	0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x23, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
+	0x6c, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x77, 0x61, 0x70, 0x41, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0x4c, 0x0a, 0x13, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x22, 0x77, 0x0a, 0x11, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x77, 0x61, 0x70, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e,
+	0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61, 0x70, 0x52,
+	0x04, 0x73, 0x77, 0x61, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4e, 0x0a, 0x16, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x77, 0x61, 0x70, 0x49, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x74, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x54, 0x78, 0x22, 0x3d, 0x0a, 0x0c, 0x53,
+	0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x73,
+	0x77, 0x61, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65, 0x72,
+	0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74,
+	0x53, 0x77, 0x61, 0x70, 0x52, 0x04, 0x73, 0x77, 0x61, 0x70, 0x22, 0x29, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x73, 0x77, 0x61, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x77, 0x61, 0x70, 0x49, 0x64, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61,
+	0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x44, 0x0a, 0x11, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f,
+	0x0a, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50,
+	0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61, 0x70, 0x52, 0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x22,
+	0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x41, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x50, 0x65, 0x65, 0x72, 0x52,
+	0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x22, 0x19, 0x0a, 0x17, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x31, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x50, 0x65, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x22, 0x34, 0x0a, 0x11, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x50, 0x65,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x65, 0x65,
+	0x72, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x70, 0x65, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0x68, 0x0a, 0x19, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x5f,
+	0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x65,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x75,
+	0x6e, 0x74, 0x69, 0x6c, 0x22, 0xdd, 0x01, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64,
+	0x5f, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65,
+	0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x1a, 0x5c, 0x0a, 0x13, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x53, 0x77, 0x61, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x89, 0x03, 0x0a, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x53, 0x77, 0x61, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x52, 0x0e, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x53, 0x61, 0x74, 0x12, 0x56, 0x0a, 0x0f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53,
+	0x77, 0x61, 0x70, 0x4c, 0x69, 0x73, 0x74, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x72, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x66, 0x69, 0x72, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x41, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x74, 0x1a, 0x41, 0x0a,
+	0x13, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xb1, 0x02, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77,
+	0x61, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x53, 0x61, 0x74, 0x12, 0x3d, 0x0a, 0x09, 0x73, 0x77, 0x61, 0x70, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53,
+	0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x73, 0x77,
+	0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x3b, 0x0a, 0x0e, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x70, 0x65, 0x65, 0x72,
+	0x73, 0x77, 0x61, 0x70, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x0d, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x25, 0x0a,
+	0x08, 0x53, 0x77, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x57, 0x41,
+	0x50, 0x5f, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x57, 0x41, 0x50, 0x5f, 0x4f,
+	0x55, 0x54, 0x10, 0x01, 0x22, 0xab, 0x04, 0x0a, 0x0f, 0x50, 0x72, 0x65, 0x74, 0x74, 0x79, 0x50,
+	0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f,
+	0x72, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x70, 0x65, 0x65, 0x72, 0x5f,
+	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70,
+	0x65, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x0d, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x5f, 0x69,
+	0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67,
+	0x54, 0x78, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0b, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x74, 0x78,
+	0x5f, 0x69, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x69, 0x6d,
+	0x54, 0x78, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0b, 0x6c,
+	0x6e, 0x64, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x6c, 0x6e, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x2b, 0x0a, 0x12, 0x64,
+	0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x78, 0x5f, 0x69,
+	0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x53,
+	0x70, 0x65, 0x6e, 0x64, 0x54, 0x78, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x0b, 0x63, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e,
+	0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43,
+	0x6f, 0x64, 0x65, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x21, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x50, 0x73,
+	0x62, 0x74, 0x22, 0x9e, 0x03, 0x0a, 0x0c, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x50,
+	0x65, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d,
+	0x73, 0x77, 0x61, 0x70, 0x73, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x77, 0x61, 0x70, 0x73, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65,
+	0x64, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x75, 0x70,
+	0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x08,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77,
+	0x61, 0x70, 0x50, 0x65, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x61, 0x73, 0x5f, 0x73, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x08, 0x61, 0x73, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x34, 0x0a, 0x0b, 0x61, 0x73, 0x5f,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x52, 0x0a, 0x61, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x12,
+	0x19, 0x0a, 0x08, 0x70, 0x61, 0x69, 0x64, 0x5f, 0x66, 0x65, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x70, 0x61, 0x69, 0x64, 0x46, 0x65, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x63, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x0c, 0x63, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x65,
+	0x65, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x73, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0e, 0x66, 0x65, 0x65, 0x4f, 0x76, 0x65, 0x72, 0x63, 0x68, 0x61, 0x72,
+	0x67, 0x65, 0x73, 0x22, 0xca, 0x01, 0x0a, 0x10, 0x50, 0x65, 0x65, 0x72, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6e, 0x65, 0x77, 0x5f,
+	0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x4e, 0x65, 0x77, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x33, 0x0a, 0x06, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x65,
+	0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x22, 0xaa, 0x01, 0x0a, 0x11, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x0e,
+	0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x53,
+	0x61, 0x74, 0x12, 0x34, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x77, 0x61, 0x70, 0x5f, 0x6f,
+	0x75, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x61, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x77, 0x61, 0x70,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x73, 0x77,
+	0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x22, 0x38, 0x0a,
+	0x15, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x70,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x65, 0x65,
+	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0x8e, 0x02, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x50,
+	0x65, 0x65, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c,
+	0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x2c, 0x0a, 0x06, 0x75, 0x70, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x06, 0x75,
+	0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3e, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x4f, 0x0a, 0x0a, 0x50, 0x65, 0x65, 0x72,
+	0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x5f, 0x68, 0x6f, 0x75, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x48, 0x6f, 0x75, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x22, 0x63, 0x0a, 0x0b, 0x50, 0x65, 0x65,
+	0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65,
+	0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x22, 0x3d,
+	0x0a, 0x0f, 0x50, 0x65, 0x65, 0x72, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0xc3, 0x01,
+	0x0a, 0x13, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x50, 0x65, 0x65, 0x72, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x29, 0x0a, 0x10, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x22, 0x77, 0x0a, 0x09, 0x53, 0x77, 0x61, 0x70, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x73, 0x77, 0x61, 0x70, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x77, 0x61, 0x70, 0x73, 0x4f, 0x75, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x73, 0x77, 0x61, 0x70, 0x73, 0x5f, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x73, 0x77, 0x61, 0x70, 0x73, 0x49, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x61, 0x74, 0x73,
+	0x5f, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x73, 0x61, 0x74, 0x73,
+	0x4f, 0x75, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x61, 0x74, 0x73, 0x5f, 0x69, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x61, 0x74, 0x73, 0x49, 0x6e, 0x22, 0x28, 0x0a, 0x0d,
+	0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61, 0x70, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x17, 0x0a,
+	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x82, 0x04, 0x0a, 0x06, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x30, 0x0a, 0x14, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x5f, 0x6f, 0x6e, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x12, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x4f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x4d,
+	0x73, 0x61, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x77, 0x61, 0x70, 0x5f,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x11, 0x6d, 0x69, 0x6e, 0x53, 0x77, 0x61, 0x70, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x4d, 0x73, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f, 0x61,
+	0x6c, 0x6c, 0x5f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x41, 0x6c, 0x6c, 0x50, 0x65, 0x65, 0x72, 0x73, 0x12, 0x26,
+	0x0a, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x77, 0x61, 0x70,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4e, 0x65,
+	0x77, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x6c,
+	0x69, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x64, 0x50, 0x65,
+	0x65, 0x72, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75,
+	0x73, 0x5f, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x12, 0x73, 0x75, 0x73, 0x70, 0x69, 0x63, 0x69, 0x6f, 0x75, 0x73, 0x50, 0x65, 0x65,
+	0x72, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x18, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x65, 0x65,
+	0x5f, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x6d, 0x61, 0x78, 0x46, 0x65, 0x65, 0x49,
+	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x12, 0x2d,
+	0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78,
+	0x46, 0x65, 0x65, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x53, 0x61, 0x74, 0x12, 0x3b, 0x0a,
+	0x1a, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x77, 0x61, 0x70, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x17, 0x6d, 0x61, 0x78, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x63,
+	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x3f, 0x0a, 0x1c, 0x65, 0x78,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x19, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x22, 0x2a, 0x0a, 0x0c, 0x44,
+	0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73,
+	0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x22, 0x59, 0x0a, 0x0d, 0x44, 0x72, 0x61, 0x69, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x77, 0x61, 0x70,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x2e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x77, 0x61, 0x70, 0x52,
+	0x05, 0x73, 0x77, 0x61, 0x70, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f,
+	0x77, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x68, 0x75, 0x74, 0x64, 0x6f,
+	0x77, 0x6e, 0x22, 0x91, 0x01, 0x0a, 0x0c, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53,
+	0x77, 0x61, 0x70, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x77, 0x61, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x50, 0x72, 0x65,
+	0x74, 0x74, 0x79, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x77, 0x61, 0x70, 0x52, 0x04, 0x73, 0x77,
+	0x61, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e,
+	0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x30, 0x0a, 0x18, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x53,
+	0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x22, 0x31, 0x0a, 0x19, 0x41, 0x6c, 0x6c, 0x6f,
+	0x77, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x22, 0x07, 0x0a, 0x05, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x2a, 0xdd, 0x02, 0x0a, 0x0a, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
+	0x12, 0x12, 0x0a, 0x0e, 0x53, 0x57, 0x41, 0x50, 0x53, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c,
+	0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x5f, 0x44, 0x49,
+	0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x49, 0x4e, 0x43, 0x4f,
+	0x4d, 0x50, 0x41, 0x54, 0x49, 0x42, 0x4c, 0x45, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e,
+	0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x4f,
+	0x5f, 0x53, 0x4d, 0x41, 0x4c, 0x4c, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x4e, 0x56, 0x41,
+	0x4c, 0x49, 0x44, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x49,
+	0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x10, 0x06,
+	0x12, 0x14, 0x0a, 0x10, 0x50, 0x45, 0x45, 0x52, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x41, 0x4c, 0x4c,
+	0x4f, 0x57, 0x45, 0x44, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x45, 0x45, 0x52, 0x5f, 0x53,
+	0x55, 0x53, 0x50, 0x49, 0x43, 0x49, 0x4f, 0x55, 0x53, 0x10, 0x08, 0x12, 0x18, 0x0a, 0x14, 0x49,
+	0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c, 0x41,
+	0x4e, 0x43, 0x45, 0x10, 0x09, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x5f,
+	0x53, 0x57, 0x41, 0x50, 0x10, 0x0a, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55,
+	0x54, 0x10, 0x0b, 0x12, 0x0e, 0x0a, 0x0a, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x54,
+	0x58, 0x10, 0x0c, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x49,
+	0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x10, 0x0d, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x45, 0x45, 0x5f,
+	0x54, 0x4f, 0x4f, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x10, 0x0e, 0x12, 0x14, 0x0a, 0x10, 0x54, 0x4f,
+	0x4f, 0x5f, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x5f, 0x54, 0x4f, 0x5f, 0x43, 0x53, 0x56, 0x10, 0x0f,
+	0x12, 0x12, 0x0a, 0x0e, 0x54, 0x4f, 0x4f, 0x5f, 0x4d, 0x41, 0x4e, 0x59, 0x5f, 0x53, 0x57, 0x41,
+	0x50, 0x53, 0x10, 0x10, 0x32, 0xfb, 0x0d, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x53, 0x77, 0x61,
+	0x70, 0x12, 0x3b, 0x0a, 0x07, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x12, 0x18, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x4f, 0x75, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
+	0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39,
+	0x0a, 0x06, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x12, 0x17, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61,
+	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x53, 0x77, 0x61,
+	0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1c, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
+	0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x49, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4f,
+	0x70, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x54, 0x78, 0x12, 0x20, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x77, 0x61, 0x70, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4f, 0x70, 0x65, 0x6e, 0x69, 0x6e,
+	0x67, 0x54, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x53, 0x77, 0x61, 0x70, 0x12, 0x18, 0x2e,
+	0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x77, 0x61, 0x70,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x2e, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x44, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12, 0x1a, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65,
+	0x72, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x12, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70,
+	0x73, 0x12, 0x23, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
 	0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53,
-	0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x70, 0x65,
-	0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x65, 0x64, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x4a, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x53,
-	0x77, 0x61, 0x70, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e,
-	0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1b, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x53, 0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a,
+	0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x53, 0x77, 0x61, 0x70, 0x73, 0x12,
+	0x1a, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x77, 0x61, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x65,
+	0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x77, 0x61, 0x70, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x50,
+	0x65, 0x65, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x65,
+	0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a,
 	0x11, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x73, 0x12, 0x22, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x41, 0x6c,
 	0x6c, 0x6f, 0x77, 0x53, 0x77, 0x61, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52,
@@ -2055,13 +3665,36 @@ var file_peerswaprpc_peerswaprpc_proto_rawDesc = []byte{
 	0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e,
 	0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x0f, 0x2e,
-	0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f,
-	0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42,
-	0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c,
-	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x70, 0x65,
-	0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x42, 0x74, 0x63, 0x47, 0x65, 0x74, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
+	0x70, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47,
+	0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x42, 0x74, 0x63, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x1b, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65,
+	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a,
+	0x10, 0x42, 0x74, 0x63, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x1e, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x12, 0x1c, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65, 0x74,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28,
+	0x0a, 0x04, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x0f, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61,
+	0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0f, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x05, 0x44, 0x72, 0x61, 0x69,
+	0x6e, 0x12, 0x16, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2e, 0x44, 0x72, 0x61,
+	0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x65, 0x72,
+	0x73, 0x77, 0x61, 0x70, 0x2e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x2f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77, 0x61, 0x70, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x77,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2076,94 +3709,144 @@ func file_peerswaprpc_peerswaprpc_proto_rawDescGZIP() []byte {
 	return file_peerswaprpc_peerswaprpc_proto_rawDescData
 }
 
-var file_peerswaprpc_peerswaprpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_peerswaprpc_peerswaprpc_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_peerswaprpc_peerswaprpc_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_peerswaprpc_peerswaprpc_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_peerswaprpc_peerswaprpc_proto_goTypes = []interface{}{
-	(RequestedSwap_SwapType)(0),        // 0: peerswap.RequestedSwap.SwapType
-	(*GetAddressRequest)(nil),          // 1: peerswap.GetAddressRequest
-	(*GetAddressResponse)(nil),         // 2: peerswap.GetAddressResponse
-	(*GetBalanceRequest)(nil),          // 3: peerswap.GetBalanceRequest
-	(*GetBalanceResponse)(nil),         // 4: peerswap.GetBalanceResponse
-	(*SendToAddressRequest)(nil),       // 5: peerswap.SendToAddressRequest
-	(*SendToAddressResponse)(nil),      // 6: peerswap.SendToAddressResponse
-	(*SwapOutRequest)(nil),             // 7: peerswap.SwapOutRequest
-	(*SwapOutResponse)(nil),            // 8: peerswap.SwapOutResponse
-	(*SwapInRequest)(nil),              // 9: peerswap.SwapInRequest
-	(*SwapResponse)(nil),               // 10: peerswap.SwapResponse
-	(*GetSwapRequest)(nil),             // 11: peerswap.GetSwapRequest
-	(*ListSwapsRequest)(nil),           // 12: peerswap.ListSwapsRequest
-	(*ListSwapsResponse)(nil),          // 13: peerswap.ListSwapsResponse
-	(*ListPeersRequest)(nil),           // 14: peerswap.ListPeersRequest
-	(*ListPeersResponse)(nil),          // 15: peerswap.ListPeersResponse
-	(*ReloadPolicyFileRequest)(nil),    // 16: peerswap.ReloadPolicyFileRequest
-	(*AddPeerRequest)(nil),             // 17: peerswap.AddPeerRequest
-	(*RemovePeerRequest)(nil),          // 18: peerswap.RemovePeerRequest
-	(*ListRequestedSwapsRequest)(nil),  // 19: peerswap.ListRequestedSwapsRequest
-	(*ListRequestedSwapsResponse)(nil), // 20: peerswap.ListRequestedSwapsResponse
-	(*RequestSwapList)(nil),            // 21: peerswap.RequestSwapList
-	(*RequestedSwap)(nil),              // 22: peerswap.RequestedSwap
-	(*PrettyPrintSwap)(nil),            // 23: peerswap.PrettyPrintSwap
-	(*PeerSwapPeer)(nil),               // 24: peerswap.PeerSwapPeer
-	(*PeerSwapPeerChannel)(nil),        // 25: peerswap.PeerSwapPeerChannel
-	(*SwapStats)(nil),                  // 26: peerswap.SwapStats
-	(*PeerSwapNodes)(nil),              // 27: peerswap.PeerSwapNodes
-	(*Policy)(nil),                     // 28: peerswap.Policy
-	(*AllowSwapRequestsRequest)(nil),   // 29: peerswap.AllowSwapRequestsRequest
-	(*AllowSwapRequestsResponse)(nil),  // 30: peerswap.AllowSwapRequestsResponse
-	(*Empty)(nil),                      // 31: peerswap.Empty
-	nil,                                // 32: peerswap.ListRequestedSwapsResponse.RequestedSwapsEntry
+	(CancelCode)(0),                    // 0: peerswap.CancelCode
+	(RequestedSwap_SwapType)(0),        // 1: peerswap.RequestedSwap.SwapType
+	(*GetAddressRequest)(nil),          // 2: peerswap.GetAddressRequest
+	(*GetAddressResponse)(nil),         // 3: peerswap.GetAddressResponse
+	(*GetBalanceRequest)(nil),          // 4: peerswap.GetBalanceRequest
+	(*GetBalanceResponse)(nil),         // 5: peerswap.GetBalanceResponse
+	(*SendToAddressRequest)(nil),       // 6: peerswap.SendToAddressRequest
+	(*SendToAddressResponse)(nil),      // 7: peerswap.SendToAddressResponse
+	(*GetBalancesRequest)(nil),         // 8: peerswap.GetBalancesRequest
+	(*GetBalancesResponse)(nil),        // 9: peerswap.GetBalancesResponse
+	(*WalletBalance)(nil),              // 10: peerswap.WalletBalance
+	(*SwapOutRequest)(nil),             // 11: peerswap.SwapOutRequest
+	(*SwapOutResponse)(nil),            // 12: peerswap.SwapOutResponse
+	(*SwapInRequest)(nil),              // 13: peerswap.SwapInRequest
+	(*SwapInBatchRequest)(nil),         // 14: peerswap.SwapInBatchRequest
+	(*SwapInBatchEntry)(nil),           // 15: peerswap.SwapInBatchEntry
+	(*SwapInBatchResponse)(nil),        // 16: peerswap.SwapInBatchResponse
+	(*SwapInBatchResult)(nil),          // 17: peerswap.SwapInBatchResult
+	(*SubmitOpeningTxRequest)(nil),     // 18: peerswap.SubmitOpeningTxRequest
+	(*SwapResponse)(nil),               // 19: peerswap.SwapResponse
+	(*GetSwapRequest)(nil),             // 20: peerswap.GetSwapRequest
+	(*ListSwapsRequest)(nil),           // 21: peerswap.ListSwapsRequest
+	(*ListSwapsResponse)(nil),          // 22: peerswap.ListSwapsResponse
+	(*ListPeersRequest)(nil),           // 23: peerswap.ListPeersRequest
+	(*ListPeersResponse)(nil),          // 24: peerswap.ListPeersResponse
+	(*ReloadPolicyFileRequest)(nil),    // 25: peerswap.ReloadPolicyFileRequest
+	(*AddPeerRequest)(nil),             // 26: peerswap.AddPeerRequest
+	(*RemovePeerRequest)(nil),          // 27: peerswap.RemovePeerRequest
+	(*ListRequestedSwapsRequest)(nil),  // 28: peerswap.ListRequestedSwapsRequest
+	(*ListRequestedSwapsResponse)(nil), // 29: peerswap.ListRequestedSwapsResponse
+	(*RequestSwapList)(nil),            // 30: peerswap.RequestSwapList
+	(*RequestedSwap)(nil),              // 31: peerswap.RequestedSwap
+	(*PrettyPrintSwap)(nil),            // 32: peerswap.PrettyPrintSwap
+	(*PeerSwapPeer)(nil),               // 33: peerswap.PeerSwapPeer
+	(*PeerCapabilities)(nil),           // 34: peerswap.PeerCapabilities
+	(*AssetCapabilities)(nil),          // 35: peerswap.AssetCapabilities
+	(*GetPeerHistoryRequest)(nil),      // 36: peerswap.GetPeerHistoryRequest
+	(*GetPeerHistoryResponse)(nil),     // 37: peerswap.GetPeerHistoryResponse
+	(*PeerUptime)(nil),                 // 38: peerswap.PeerUptime
+	(*PeerVersion)(nil),                // 39: peerswap.PeerVersion
+	(*PeerAssetChange)(nil),            // 40: peerswap.PeerAssetChange
+	(*PeerSwapPeerChannel)(nil),        // 41: peerswap.PeerSwapPeerChannel
+	(*SwapStats)(nil),                  // 42: peerswap.SwapStats
+	(*PeerSwapNodes)(nil),              // 43: peerswap.PeerSwapNodes
+	(*Policy)(nil),                     // 44: peerswap.Policy
+	(*DrainRequest)(nil),               // 45: peerswap.DrainRequest
+	(*DrainResponse)(nil),              // 46: peerswap.DrainResponse
+	(*DrainingSwap)(nil),               // 47: peerswap.DrainingSwap
+	(*AllowSwapRequestsRequest)(nil),   // 48: peerswap.AllowSwapRequestsRequest
+	(*AllowSwapRequestsResponse)(nil),  // 49: peerswap.AllowSwapRequestsResponse
+	(*Empty)(nil),                      // 50: peerswap.Empty
+	nil,                                // 51: peerswap.ListRequestedSwapsResponse.RequestedSwapsEntry
+	nil,                                // 52: peerswap.RequestSwapList.RejectionCodesEntry
 }
 var file_peerswaprpc_peerswaprpc_proto_depIdxs = []int32{
-	23, // 0: peerswap.SwapOutResponse.swap:type_name -> peerswap.PrettyPrintSwap
-	23, // 1: peerswap.SwapResponse.swap:type_name -> peerswap.PrettyPrintSwap
-	23, // 2: peerswap.ListSwapsResponse.swaps:type_name -> peerswap.PrettyPrintSwap
-	24, // 3: peerswap.ListPeersResponse.peers:type_name -> peerswap.PeerSwapPeer
-	32, // 4: peerswap.ListRequestedSwapsResponse.requested_swaps:type_name -> peerswap.ListRequestedSwapsResponse.RequestedSwapsEntry
-	22, // 5: peerswap.RequestSwapList.requested_swaps:type_name -> peerswap.RequestedSwap
-	0,  // 6: peerswap.RequestedSwap.swap_type:type_name -> peerswap.RequestedSwap.SwapType
-	25, // 7: peerswap.PeerSwapPeer.channels:type_name -> peerswap.PeerSwapPeerChannel
-	26, // 8: peerswap.PeerSwapPeer.as_sender:type_name -> peerswap.SwapStats
-	26, // 9: peerswap.PeerSwapPeer.as_receiver:type_name -> peerswap.SwapStats
-	21, // 10: peerswap.ListRequestedSwapsResponse.RequestedSwapsEntry.value:type_name -> peerswap.RequestSwapList
-	7,  // 11: peerswap.PeerSwap.SwapOut:input_type -> peerswap.SwapOutRequest
-	9,  // 12: peerswap.PeerSwap.SwapIn:input_type -> peerswap.SwapInRequest
-	11, // 13: peerswap.PeerSwap.GetSwap:input_type -> peerswap.GetSwapRequest
-	12, // 14: peerswap.PeerSwap.ListSwaps:input_type -> peerswap.ListSwapsRequest
-	14, // 15: peerswap.PeerSwap.ListPeers:input_type -> peerswap.ListPeersRequest
-	19, // 16: peerswap.PeerSwap.ListRequestedSwaps:input_type -> peerswap.ListRequestedSwapsRequest
-	12, // 17: peerswap.PeerSwap.ListActiveSwaps:input_type -> peerswap.ListSwapsRequest
-	29, // 18: peerswap.PeerSwap.AllowSwapRequests:input_type -> peerswap.AllowSwapRequestsRequest
-	16, // 19: peerswap.PeerSwap.ReloadPolicyFile:input_type -> peerswap.ReloadPolicyFileRequest
-	17, // 20: peerswap.PeerSwap.AddPeer:input_type -> peerswap.AddPeerRequest
-	18, // 21: peerswap.PeerSwap.RemovePeer:input_type -> peerswap.RemovePeerRequest
-	17, // 22: peerswap.PeerSwap.AddSusPeer:input_type -> peerswap.AddPeerRequest
-	18, // 23: peerswap.PeerSwap.RemoveSusPeer:input_type -> peerswap.RemovePeerRequest
-	1,  // 24: peerswap.PeerSwap.LiquidGetAddress:input_type -> peerswap.GetAddressRequest
-	3,  // 25: peerswap.PeerSwap.LiquidGetBalance:input_type -> peerswap.GetBalanceRequest
-	5,  // 26: peerswap.PeerSwap.LiquidSendToAddress:input_type -> peerswap.SendToAddressRequest
-	31, // 27: peerswap.PeerSwap.Stop:input_type -> peerswap.Empty
-	10, // 28: peerswap.PeerSwap.SwapOut:output_type -> peerswap.SwapResponse
-	10, // 29: peerswap.PeerSwap.SwapIn:output_type -> peerswap.SwapResponse
-	10, // 30: peerswap.PeerSwap.GetSwap:output_type -> peerswap.SwapResponse
-	13, // 31: peerswap.PeerSwap.ListSwaps:output_type -> peerswap.ListSwapsResponse
-	15, // 32: peerswap.PeerSwap.ListPeers:output_type -> peerswap.ListPeersResponse
-	20, // 33: peerswap.PeerSwap.ListRequestedSwaps:output_type -> peerswap.ListRequestedSwapsResponse
-	13, // 34: peerswap.PeerSwap.ListActiveSwaps:output_type -> peerswap.ListSwapsResponse
-	28, // 35: peerswap.PeerSwap.AllowSwapRequests:output_type -> peerswap.Policy
-	28, // 36: peerswap.PeerSwap.ReloadPolicyFile:output_type -> peerswap.Policy
-	28, // 37: peerswap.PeerSwap.AddPeer:output_type -> peerswap.Policy
-	28, // 38: peerswap.PeerSwap.RemovePeer:output_type -> peerswap.Policy
-	28, // 39: peerswap.PeerSwap.AddSusPeer:output_type -> peerswap.Policy
-	28, // 40: peerswap.PeerSwap.RemoveSusPeer:output_type -> peerswap.Policy
-	2,  // 41: peerswap.PeerSwap.LiquidGetAddress:output_type -> peerswap.GetAddressResponse
-	4,  // 42: peerswap.PeerSwap.LiquidGetBalance:output_type -> peerswap.GetBalanceResponse
-	6,  // 43: peerswap.PeerSwap.LiquidSendToAddress:output_type -> peerswap.SendToAddressResponse
-	31, // 44: peerswap.PeerSwap.Stop:output_type -> peerswap.Empty
-	28, // [28:45] is the sub-list for method output_type
-	11, // [11:28] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	10, // 0: peerswap.GetBalancesResponse.balances:type_name -> peerswap.WalletBalance
+	32, // 1: peerswap.SwapOutResponse.swap:type_name -> peerswap.PrettyPrintSwap
+	15, // 2: peerswap.SwapInBatchRequest.swaps:type_name -> peerswap.SwapInBatchEntry
+	17, // 3: peerswap.SwapInBatchResponse.results:type_name -> peerswap.SwapInBatchResult
+	32, // 4: peerswap.SwapInBatchResult.swap:type_name -> peerswap.PrettyPrintSwap
+	32, // 5: peerswap.SwapResponse.swap:type_name -> peerswap.PrettyPrintSwap
+	32, // 6: peerswap.ListSwapsResponse.swaps:type_name -> peerswap.PrettyPrintSwap
+	33, // 7: peerswap.ListPeersResponse.peers:type_name -> peerswap.PeerSwapPeer
+	51, // 8: peerswap.ListRequestedSwapsResponse.requested_swaps:type_name -> peerswap.ListRequestedSwapsResponse.RequestedSwapsEntry
+	31, // 9: peerswap.RequestSwapList.requested_swaps:type_name -> peerswap.RequestedSwap
+	52, // 10: peerswap.RequestSwapList.rejection_codes:type_name -> peerswap.RequestSwapList.RejectionCodesEntry
+	1,  // 11: peerswap.RequestedSwap.swap_type:type_name -> peerswap.RequestedSwap.SwapType
+	0,  // 12: peerswap.RequestedSwap.rejection_code:type_name -> peerswap.CancelCode
+	0,  // 13: peerswap.PrettyPrintSwap.cancel_code:type_name -> peerswap.CancelCode
+	41, // 14: peerswap.PeerSwapPeer.channels:type_name -> peerswap.PeerSwapPeerChannel
+	42, // 15: peerswap.PeerSwapPeer.as_sender:type_name -> peerswap.SwapStats
+	42, // 16: peerswap.PeerSwapPeer.as_receiver:type_name -> peerswap.SwapStats
+	34, // 17: peerswap.PeerSwapPeer.capabilities:type_name -> peerswap.PeerCapabilities
+	35, // 18: peerswap.PeerCapabilities.assets:type_name -> peerswap.AssetCapabilities
+	38, // 19: peerswap.GetPeerHistoryResponse.uptime:type_name -> peerswap.PeerUptime
+	39, // 20: peerswap.GetPeerHistoryResponse.versions:type_name -> peerswap.PeerVersion
+	40, // 21: peerswap.GetPeerHistoryResponse.asset_changes:type_name -> peerswap.PeerAssetChange
+	47, // 22: peerswap.DrainResponse.swaps:type_name -> peerswap.DrainingSwap
+	32, // 23: peerswap.DrainingSwap.swap:type_name -> peerswap.PrettyPrintSwap
+	30, // 24: peerswap.ListRequestedSwapsResponse.RequestedSwapsEntry.value:type_name -> peerswap.RequestSwapList
+	11, // 25: peerswap.PeerSwap.SwapOut:input_type -> peerswap.SwapOutRequest
+	13, // 26: peerswap.PeerSwap.SwapIn:input_type -> peerswap.SwapInRequest
+	14, // 27: peerswap.PeerSwap.SwapInBatch:input_type -> peerswap.SwapInBatchRequest
+	18, // 28: peerswap.PeerSwap.SubmitOpeningTx:input_type -> peerswap.SubmitOpeningTxRequest
+	20, // 29: peerswap.PeerSwap.GetSwap:input_type -> peerswap.GetSwapRequest
+	21, // 30: peerswap.PeerSwap.ListSwaps:input_type -> peerswap.ListSwapsRequest
+	23, // 31: peerswap.PeerSwap.ListPeers:input_type -> peerswap.ListPeersRequest
+	28, // 32: peerswap.PeerSwap.ListRequestedSwaps:input_type -> peerswap.ListRequestedSwapsRequest
+	21, // 33: peerswap.PeerSwap.ListActiveSwaps:input_type -> peerswap.ListSwapsRequest
+	36, // 34: peerswap.PeerSwap.GetPeerHistory:input_type -> peerswap.GetPeerHistoryRequest
+	48, // 35: peerswap.PeerSwap.AllowSwapRequests:input_type -> peerswap.AllowSwapRequestsRequest
+	25, // 36: peerswap.PeerSwap.ReloadPolicyFile:input_type -> peerswap.ReloadPolicyFileRequest
+	26, // 37: peerswap.PeerSwap.AddPeer:input_type -> peerswap.AddPeerRequest
+	27, // 38: peerswap.PeerSwap.RemovePeer:input_type -> peerswap.RemovePeerRequest
+	26, // 39: peerswap.PeerSwap.AddSusPeer:input_type -> peerswap.AddPeerRequest
+	27, // 40: peerswap.PeerSwap.RemoveSusPeer:input_type -> peerswap.RemovePeerRequest
+	2,  // 41: peerswap.PeerSwap.LiquidGetAddress:input_type -> peerswap.GetAddressRequest
+	4,  // 42: peerswap.PeerSwap.LiquidGetBalance:input_type -> peerswap.GetBalanceRequest
+	6,  // 43: peerswap.PeerSwap.LiquidSendToAddress:input_type -> peerswap.SendToAddressRequest
+	2,  // 44: peerswap.PeerSwap.BtcGetAddress:input_type -> peerswap.GetAddressRequest
+	4,  // 45: peerswap.PeerSwap.BtcGetBalance:input_type -> peerswap.GetBalanceRequest
+	6,  // 46: peerswap.PeerSwap.BtcSendToAddress:input_type -> peerswap.SendToAddressRequest
+	8,  // 47: peerswap.PeerSwap.GetBalances:input_type -> peerswap.GetBalancesRequest
+	50, // 48: peerswap.PeerSwap.Stop:input_type -> peerswap.Empty
+	45, // 49: peerswap.PeerSwap.Drain:input_type -> peerswap.DrainRequest
+	19, // 50: peerswap.PeerSwap.SwapOut:output_type -> peerswap.SwapResponse
+	19, // 51: peerswap.PeerSwap.SwapIn:output_type -> peerswap.SwapResponse
+	16, // 52: peerswap.PeerSwap.SwapInBatch:output_type -> peerswap.SwapInBatchResponse
+	19, // 53: peerswap.PeerSwap.SubmitOpeningTx:output_type -> peerswap.SwapResponse
+	19, // 54: peerswap.PeerSwap.GetSwap:output_type -> peerswap.SwapResponse
+	22, // 55: peerswap.PeerSwap.ListSwaps:output_type -> peerswap.ListSwapsResponse
+	24, // 56: peerswap.PeerSwap.ListPeers:output_type -> peerswap.ListPeersResponse
+	29, // 57: peerswap.PeerSwap.ListRequestedSwaps:output_type -> peerswap.ListRequestedSwapsResponse
+	22, // 58: peerswap.PeerSwap.ListActiveSwaps:output_type -> peerswap.ListSwapsResponse
+	37, // 59: peerswap.PeerSwap.GetPeerHistory:output_type -> peerswap.GetPeerHistoryResponse
+	44, // 60: peerswap.PeerSwap.AllowSwapRequests:output_type -> peerswap.Policy
+	44, // 61: peerswap.PeerSwap.ReloadPolicyFile:output_type -> peerswap.Policy
+	44, // 62: peerswap.PeerSwap.AddPeer:output_type -> peerswap.Policy
+	44, // 63: peerswap.PeerSwap.RemovePeer:output_type -> peerswap.Policy
+	44, // 64: peerswap.PeerSwap.AddSusPeer:output_type -> peerswap.Policy
+	44, // 65: peerswap.PeerSwap.RemoveSusPeer:output_type -> peerswap.Policy
+	3,  // 66: peerswap.PeerSwap.LiquidGetAddress:output_type -> peerswap.GetAddressResponse
+	5,  // 67: peerswap.PeerSwap.LiquidGetBalance:output_type -> peerswap.GetBalanceResponse
+	7,  // 68: peerswap.PeerSwap.LiquidSendToAddress:output_type -> peerswap.SendToAddressResponse
+	3,  // 69: peerswap.PeerSwap.BtcGetAddress:output_type -> peerswap.GetAddressResponse
+	5,  // 70: peerswap.PeerSwap.BtcGetBalance:output_type -> peerswap.GetBalanceResponse
+	7,  // 71: peerswap.PeerSwap.BtcSendToAddress:output_type -> peerswap.SendToAddressResponse
+	9,  // 72: peerswap.PeerSwap.GetBalances:output_type -> peerswap.GetBalancesResponse
+	50, // 73: peerswap.PeerSwap.Stop:output_type -> peerswap.Empty
+	46, // 74: peerswap.PeerSwap.Drain:output_type -> peerswap.DrainResponse
+	50, // [50:75] is the sub-list for method output_type
+	25, // [25:50] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_peerswaprpc_peerswaprpc_proto_init() }
@@ -2245,7 +3928,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SwapOutRequest); i {
+			switch v := v.(*GetBalancesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2257,7 +3940,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SwapOutResponse); i {
+			switch v := v.(*GetBalancesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2269,7 +3952,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SwapInRequest); i {
+			switch v := v.(*WalletBalance); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2281,7 +3964,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SwapResponse); i {
+			switch v := v.(*SwapOutRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2293,7 +3976,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetSwapRequest); i {
+			switch v := v.(*SwapOutResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2305,7 +3988,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListSwapsRequest); i {
+			switch v := v.(*SwapInRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2317,7 +4000,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListSwapsResponse); i {
+			switch v := v.(*SwapInBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2329,7 +4012,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListPeersRequest); i {
+			switch v := v.(*SwapInBatchEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2341,7 +4024,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListPeersResponse); i {
+			switch v := v.(*SwapInBatchResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2353,7 +4036,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReloadPolicyFileRequest); i {
+			switch v := v.(*SwapInBatchResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2365,7 +4048,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddPeerRequest); i {
+			switch v := v.(*SubmitOpeningTxRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2377,7 +4060,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemovePeerRequest); i {
+			switch v := v.(*SwapResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2389,7 +4072,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRequestedSwapsRequest); i {
+			switch v := v.(*GetSwapRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2401,7 +4084,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListRequestedSwapsResponse); i {
+			switch v := v.(*ListSwapsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2413,7 +4096,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestSwapList); i {
+			switch v := v.(*ListSwapsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2425,7 +4108,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestedSwap); i {
+			switch v := v.(*ListPeersRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2437,7 +4120,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrettyPrintSwap); i {
+			switch v := v.(*ListPeersResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2449,7 +4132,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeerSwapPeer); i {
+			switch v := v.(*ReloadPolicyFileRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2461,7 +4144,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeerSwapPeerChannel); i {
+			switch v := v.(*AddPeerRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2473,7 +4156,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SwapStats); i {
+			switch v := v.(*RemovePeerRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2485,7 +4168,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeerSwapNodes); i {
+			switch v := v.(*ListRequestedSwapsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2497,7 +4180,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Policy); i {
+			switch v := v.(*ListRequestedSwapsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2509,7 +4192,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AllowSwapRequestsRequest); i {
+			switch v := v.(*RequestSwapList); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2521,7 +4204,7 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AllowSwapRequestsResponse); i {
+			switch v := v.(*RequestedSwap); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2533,6 +4216,222 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 			}
 		}
 		file_peerswaprpc_peerswaprpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrettyPrintSwap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerSwapPeer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerCapabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetCapabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerUptime); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerAssetChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerSwapPeerChannel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SwapStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerSwapNodes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Policy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainingSwap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllowSwapRequestsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllowSwapRequestsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peerswaprpc_peerswaprpc_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Empty); i {
 			case 0:
 				return &v.state
@@ -2550,8 +4449,8 @@ func file_peerswaprpc_peerswaprpc_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_peerswaprpc_peerswaprpc_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   32,
+			NumEnums:      2,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
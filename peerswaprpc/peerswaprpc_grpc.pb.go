@@ -24,11 +24,14 @@ const _ = grpc.SupportPackageIsVersion7
 type PeerSwapClient interface {
 	SwapOut(ctx context.Context, in *SwapOutRequest, opts ...grpc.CallOption) (*SwapResponse, error)
 	SwapIn(ctx context.Context, in *SwapInRequest, opts ...grpc.CallOption) (*SwapResponse, error)
+	SwapInBatch(ctx context.Context, in *SwapInBatchRequest, opts ...grpc.CallOption) (*SwapInBatchResponse, error)
+	SubmitOpeningTx(ctx context.Context, in *SubmitOpeningTxRequest, opts ...grpc.CallOption) (*SwapResponse, error)
 	GetSwap(ctx context.Context, in *GetSwapRequest, opts ...grpc.CallOption) (*SwapResponse, error)
 	ListSwaps(ctx context.Context, in *ListSwapsRequest, opts ...grpc.CallOption) (*ListSwapsResponse, error)
 	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
 	ListRequestedSwaps(ctx context.Context, in *ListRequestedSwapsRequest, opts ...grpc.CallOption) (*ListRequestedSwapsResponse, error)
 	ListActiveSwaps(ctx context.Context, in *ListSwapsRequest, opts ...grpc.CallOption) (*ListSwapsResponse, error)
+	GetPeerHistory(ctx context.Context, in *GetPeerHistoryRequest, opts ...grpc.CallOption) (*GetPeerHistoryResponse, error)
 	// policy
 	AllowSwapRequests(ctx context.Context, in *AllowSwapRequestsRequest, opts ...grpc.CallOption) (*Policy, error)
 	ReloadPolicyFile(ctx context.Context, in *ReloadPolicyFileRequest, opts ...grpc.CallOption) (*Policy, error)
@@ -40,7 +43,13 @@ type PeerSwapClient interface {
 	LiquidGetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*GetAddressResponse, error)
 	LiquidGetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
 	LiquidSendToAddress(ctx context.Context, in *SendToAddressRequest, opts ...grpc.CallOption) (*SendToAddressResponse, error)
+	// Bitcoin Stuff
+	BtcGetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*GetAddressResponse, error)
+	BtcGetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	BtcSendToAddress(ctx context.Context, in *SendToAddressRequest, opts ...grpc.CallOption) (*SendToAddressResponse, error)
+	GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
 	Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
 }
 
 type peerSwapClient struct {
@@ -69,6 +78,24 @@ func (c *peerSwapClient) SwapIn(ctx context.Context, in *SwapInRequest, opts ...
 	return out, nil
 }
 
+func (c *peerSwapClient) SwapInBatch(ctx context.Context, in *SwapInBatchRequest, opts ...grpc.CallOption) (*SwapInBatchResponse, error) {
+	out := new(SwapInBatchResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/SwapInBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerSwapClient) SubmitOpeningTx(ctx context.Context, in *SubmitOpeningTxRequest, opts ...grpc.CallOption) (*SwapResponse, error) {
+	out := new(SwapResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/SubmitOpeningTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *peerSwapClient) GetSwap(ctx context.Context, in *GetSwapRequest, opts ...grpc.CallOption) (*SwapResponse, error) {
 	out := new(SwapResponse)
 	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/GetSwap", in, out, opts...)
@@ -114,6 +141,15 @@ func (c *peerSwapClient) ListActiveSwaps(ctx context.Context, in *ListSwapsReque
 	return out, nil
 }
 
+func (c *peerSwapClient) GetPeerHistory(ctx context.Context, in *GetPeerHistoryRequest, opts ...grpc.CallOption) (*GetPeerHistoryResponse, error) {
+	out := new(GetPeerHistoryResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/GetPeerHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *peerSwapClient) AllowSwapRequests(ctx context.Context, in *AllowSwapRequestsRequest, opts ...grpc.CallOption) (*Policy, error) {
 	out := new(Policy)
 	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/AllowSwapRequests", in, out, opts...)
@@ -195,6 +231,42 @@ func (c *peerSwapClient) LiquidSendToAddress(ctx context.Context, in *SendToAddr
 	return out, nil
 }
 
+func (c *peerSwapClient) BtcGetAddress(ctx context.Context, in *GetAddressRequest, opts ...grpc.CallOption) (*GetAddressResponse, error) {
+	out := new(GetAddressResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/BtcGetAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerSwapClient) BtcGetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/BtcGetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerSwapClient) BtcSendToAddress(ctx context.Context, in *SendToAddressRequest, opts ...grpc.CallOption) (*SendToAddressResponse, error) {
+	out := new(SendToAddressResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/BtcSendToAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerSwapClient) GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/GetBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *peerSwapClient) Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
 	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/Stop", in, out, opts...)
@@ -204,17 +276,29 @@ func (c *peerSwapClient) Stop(ctx context.Context, in *Empty, opts ...grpc.CallO
 	return out, nil
 }
 
+func (c *peerSwapClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, "/peerswap.PeerSwap/Drain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeerSwapServer is the server API for PeerSwap service.
 // All implementations must embed UnimplementedPeerSwapServer
 // for forward compatibility
 type PeerSwapServer interface {
 	SwapOut(context.Context, *SwapOutRequest) (*SwapResponse, error)
 	SwapIn(context.Context, *SwapInRequest) (*SwapResponse, error)
+	SwapInBatch(context.Context, *SwapInBatchRequest) (*SwapInBatchResponse, error)
+	SubmitOpeningTx(context.Context, *SubmitOpeningTxRequest) (*SwapResponse, error)
 	GetSwap(context.Context, *GetSwapRequest) (*SwapResponse, error)
 	ListSwaps(context.Context, *ListSwapsRequest) (*ListSwapsResponse, error)
 	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
 	ListRequestedSwaps(context.Context, *ListRequestedSwapsRequest) (*ListRequestedSwapsResponse, error)
 	ListActiveSwaps(context.Context, *ListSwapsRequest) (*ListSwapsResponse, error)
+	GetPeerHistory(context.Context, *GetPeerHistoryRequest) (*GetPeerHistoryResponse, error)
 	// policy
 	AllowSwapRequests(context.Context, *AllowSwapRequestsRequest) (*Policy, error)
 	ReloadPolicyFile(context.Context, *ReloadPolicyFileRequest) (*Policy, error)
@@ -226,7 +310,13 @@ type PeerSwapServer interface {
 	LiquidGetAddress(context.Context, *GetAddressRequest) (*GetAddressResponse, error)
 	LiquidGetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
 	LiquidSendToAddress(context.Context, *SendToAddressRequest) (*SendToAddressResponse, error)
+	// Bitcoin Stuff
+	BtcGetAddress(context.Context, *GetAddressRequest) (*GetAddressResponse, error)
+	BtcGetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	BtcSendToAddress(context.Context, *SendToAddressRequest) (*SendToAddressResponse, error)
+	GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error)
 	Stop(context.Context, *Empty) (*Empty, error)
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
 	mustEmbedUnimplementedPeerSwapServer()
 }
 
@@ -240,6 +330,12 @@ func (UnimplementedPeerSwapServer) SwapOut(context.Context, *SwapOutRequest) (*S
 func (UnimplementedPeerSwapServer) SwapIn(context.Context, *SwapInRequest) (*SwapResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SwapIn not implemented")
 }
+func (UnimplementedPeerSwapServer) SwapInBatch(context.Context, *SwapInBatchRequest) (*SwapInBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwapInBatch not implemented")
+}
+func (UnimplementedPeerSwapServer) SubmitOpeningTx(context.Context, *SubmitOpeningTxRequest) (*SwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitOpeningTx not implemented")
+}
 func (UnimplementedPeerSwapServer) GetSwap(context.Context, *GetSwapRequest) (*SwapResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSwap not implemented")
 }
@@ -255,6 +351,9 @@ func (UnimplementedPeerSwapServer) ListRequestedSwaps(context.Context, *ListRequ
 func (UnimplementedPeerSwapServer) ListActiveSwaps(context.Context, *ListSwapsRequest) (*ListSwapsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListActiveSwaps not implemented")
 }
+func (UnimplementedPeerSwapServer) GetPeerHistory(context.Context, *GetPeerHistoryRequest) (*GetPeerHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerHistory not implemented")
+}
 func (UnimplementedPeerSwapServer) AllowSwapRequests(context.Context, *AllowSwapRequestsRequest) (*Policy, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AllowSwapRequests not implemented")
 }
@@ -282,9 +381,24 @@ func (UnimplementedPeerSwapServer) LiquidGetBalance(context.Context, *GetBalance
 func (UnimplementedPeerSwapServer) LiquidSendToAddress(context.Context, *SendToAddressRequest) (*SendToAddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LiquidSendToAddress not implemented")
 }
+func (UnimplementedPeerSwapServer) BtcGetAddress(context.Context, *GetAddressRequest) (*GetAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BtcGetAddress not implemented")
+}
+func (UnimplementedPeerSwapServer) BtcGetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BtcGetBalance not implemented")
+}
+func (UnimplementedPeerSwapServer) BtcSendToAddress(context.Context, *SendToAddressRequest) (*SendToAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BtcSendToAddress not implemented")
+}
+func (UnimplementedPeerSwapServer) GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
 func (UnimplementedPeerSwapServer) Stop(context.Context, *Empty) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
+func (UnimplementedPeerSwapServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
 func (UnimplementedPeerSwapServer) mustEmbedUnimplementedPeerSwapServer() {}
 
 // UnsafePeerSwapServer may be embedded to opt out of forward compatibility for this service.
@@ -334,6 +448,42 @@ func _PeerSwap_SwapIn_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PeerSwap_SwapInBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapInBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).SwapInBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/SwapInBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).SwapInBatch(ctx, req.(*SwapInBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerSwap_SubmitOpeningTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitOpeningTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).SubmitOpeningTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/SubmitOpeningTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).SubmitOpeningTx(ctx, req.(*SubmitOpeningTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PeerSwap_GetSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetSwapRequest)
 	if err := dec(in); err != nil {
@@ -424,6 +574,24 @@ func _PeerSwap_ListActiveSwaps_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PeerSwap_GetPeerHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).GetPeerHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/GetPeerHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).GetPeerHistory(ctx, req.(*GetPeerHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PeerSwap_AllowSwapRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AllowSwapRequestsRequest)
 	if err := dec(in); err != nil {
@@ -586,6 +754,78 @@ func _PeerSwap_LiquidSendToAddress_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PeerSwap_BtcGetAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).BtcGetAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/BtcGetAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).BtcGetAddress(ctx, req.(*GetAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerSwap_BtcGetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).BtcGetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/BtcGetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).BtcGetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerSwap_BtcSendToAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendToAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).BtcSendToAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/BtcSendToAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).BtcSendToAddress(ctx, req.(*SendToAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerSwap_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/GetBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).GetBalances(ctx, req.(*GetBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PeerSwap_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -604,6 +844,24 @@ func _PeerSwap_Stop_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PeerSwap_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerSwapServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peerswap.PeerSwap/Drain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerSwapServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PeerSwap_ServiceDesc is the grpc.ServiceDesc for PeerSwap service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -619,6 +877,14 @@ var PeerSwap_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SwapIn",
 			Handler:    _PeerSwap_SwapIn_Handler,
 		},
+		{
+			MethodName: "SwapInBatch",
+			Handler:    _PeerSwap_SwapInBatch_Handler,
+		},
+		{
+			MethodName: "SubmitOpeningTx",
+			Handler:    _PeerSwap_SubmitOpeningTx_Handler,
+		},
 		{
 			MethodName: "GetSwap",
 			Handler:    _PeerSwap_GetSwap_Handler,
@@ -639,6 +905,10 @@ var PeerSwap_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListActiveSwaps",
 			Handler:    _PeerSwap_ListActiveSwaps_Handler,
 		},
+		{
+			MethodName: "GetPeerHistory",
+			Handler:    _PeerSwap_GetPeerHistory_Handler,
+		},
 		{
 			MethodName: "AllowSwapRequests",
 			Handler:    _PeerSwap_AllowSwapRequests_Handler,
@@ -675,10 +945,30 @@ var PeerSwap_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LiquidSendToAddress",
 			Handler:    _PeerSwap_LiquidSendToAddress_Handler,
 		},
+		{
+			MethodName: "BtcGetAddress",
+			Handler:    _PeerSwap_BtcGetAddress_Handler,
+		},
+		{
+			MethodName: "BtcGetBalance",
+			Handler:    _PeerSwap_BtcGetBalance_Handler,
+		},
+		{
+			MethodName: "BtcSendToAddress",
+			Handler:    _PeerSwap_BtcSendToAddress_Handler,
+		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _PeerSwap_GetBalances_Handler,
+		},
 		{
 			MethodName: "Stop",
 			Handler:    _PeerSwap_Stop_Handler,
 		},
+		{
+			MethodName: "Drain",
+			Handler:    _PeerSwap_Drain_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peerswaprpc/peerswaprpc.proto",
@@ -0,0 +1,33 @@
+package peerswaprpc
+
+import "github.com/elementsproject/peerswap/macaroons"
+
+// RPCPermissions maps every rpc method to the macaroon permission that is
+// required to call it.
+var RPCPermissions = map[string]string{
+	"/peerswap.PeerSwap/SwapOut":             macaroons.SwapsWrite,
+	"/peerswap.PeerSwap/SwapIn":              macaroons.SwapsWrite,
+	"/peerswap.PeerSwap/SwapInBatch":         macaroons.SwapsWrite,
+	"/peerswap.PeerSwap/SubmitOpeningTx":     macaroons.SwapsWrite,
+	"/peerswap.PeerSwap/GetSwap":             macaroons.SwapsRead,
+	"/peerswap.PeerSwap/ListSwaps":           macaroons.SwapsRead,
+	"/peerswap.PeerSwap/ListPeers":           macaroons.PeersRead,
+	"/peerswap.PeerSwap/ListRequestedSwaps":  macaroons.PeersRead,
+	"/peerswap.PeerSwap/ListActiveSwaps":     macaroons.SwapsRead,
+	"/peerswap.PeerSwap/GetPeerHistory":      macaroons.PeersRead,
+	"/peerswap.PeerSwap/AllowSwapRequests":   macaroons.PolicyWrite,
+	"/peerswap.PeerSwap/ReloadPolicyFile":    macaroons.PolicyWrite,
+	"/peerswap.PeerSwap/AddPeer":             macaroons.PolicyWrite,
+	"/peerswap.PeerSwap/RemovePeer":          macaroons.PolicyWrite,
+	"/peerswap.PeerSwap/AddSusPeer":          macaroons.PolicyWrite,
+	"/peerswap.PeerSwap/RemoveSusPeer":       macaroons.PolicyWrite,
+	"/peerswap.PeerSwap/LiquidGetAddress":    macaroons.WalletWrite,
+	"/peerswap.PeerSwap/LiquidGetBalance":    macaroons.WalletRead,
+	"/peerswap.PeerSwap/LiquidSendToAddress": macaroons.WalletWrite,
+	"/peerswap.PeerSwap/BtcGetAddress":       macaroons.WalletWrite,
+	"/peerswap.PeerSwap/BtcGetBalance":       macaroons.WalletRead,
+	"/peerswap.PeerSwap/BtcSendToAddress":    macaroons.WalletWrite,
+	"/peerswap.PeerSwap/GetBalances":         macaroons.WalletRead,
+	"/peerswap.PeerSwap/Stop":                macaroons.DaemonWrite,
+	"/peerswap.PeerSwap/Drain":               macaroons.DaemonWrite,
+}
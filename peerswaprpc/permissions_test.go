@@ -0,0 +1,17 @@
+package peerswaprpc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RPCPermissions(t *testing.T) {
+	for _, m := range PeerSwap_ServiceDesc.Methods {
+		method := fmt.Sprintf("/%s/%s", PeerSwap_ServiceDesc.ServiceName, m.MethodName)
+		_, ok := RPCPermissions[method]
+		assert.True(t, ok, "missing permission for %s", method)
+	}
+	assert.Len(t, RPCPermissions, len(PeerSwap_ServiceDesc.Methods))
+}
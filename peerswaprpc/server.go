@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/elementsproject/glightning/gelements"
@@ -20,8 +21,16 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+// BitcoinWallet is the on-chain bitcoin wallet of the lightning node.
+type BitcoinWallet interface {
+	NewAddress() (string, error)
+	GetOnchainBalance() (uint64, error)
+	SendToAddress(address string, amountSat uint64) (string, error)
+}
+
 type PeerswapServer struct {
 	liquidWallet   wallet.Wallet
+	btcWallet      BitcoinWallet
 	swaps          *swap.SwapService
 	requestedSwaps *swap.RequestedSwapsPrinter
 	pollService    *poll.Service
@@ -32,6 +41,11 @@ type PeerswapServer struct {
 
 	sigchan chan os.Signal
 
+	// drainShutdown is set if the daemon stops once the last active swap
+	// completed.
+	drainMu       sync.Mutex
+	drainShutdown bool
+
 	UnimplementedPeerSwapServer
 }
 
@@ -78,8 +92,8 @@ func (p *PeerswapServer) Stop(ctx context.Context, empty *Empty) (*Empty, error)
 	return &Empty{}, nil
 }
 
-func NewPeerswapServer(liquidWallet wallet.Wallet, swaps *swap.SwapService, requestedSwaps *swap.RequestedSwapsPrinter, pollService *poll.Service, policy *policy.Policy, gelements *gelements.Elements, lnd lnrpc.LightningClient, sigchan chan os.Signal) *PeerswapServer {
-	return &PeerswapServer{liquidWallet: liquidWallet, swaps: swaps, requestedSwaps: requestedSwaps, pollService: pollService, policy: policy, Gelements: gelements, lnd: lnd, sigchan: sigchan}
+func NewPeerswapServer(liquidWallet wallet.Wallet, btcWallet BitcoinWallet, swaps *swap.SwapService, requestedSwaps *swap.RequestedSwapsPrinter, pollService *poll.Service, policy *policy.Policy, gelements *gelements.Elements, lnd lnrpc.LightningClient, sigchan chan os.Signal) *PeerswapServer {
+	return &PeerswapServer{liquidWallet: liquidWallet, btcWallet: btcWallet, swaps: swaps, requestedSwaps: requestedSwaps, pollService: pollService, policy: policy, Gelements: gelements, lnd: lnd, sigchan: sigchan}
 }
 
 func (p *PeerswapServer) SwapOut(ctx context.Context, request *SwapOutRequest) (*SwapResponse, error) {
@@ -140,11 +154,29 @@ func (p *PeerswapServer) SwapOut(ctx context.Context, request *SwapOutRequest) (
 		return nil, fmt.Errorf("peer does not run peerswap")
 	}
 
+	// The peers capabilities are only a hint, skip them if force flag is set.
+	if !request.Force {
+		if err := p.pollService.CheckCapabilities(peerId, request.Asset, swap.SWAPTYPE_OUT, request.SwapAmount); err != nil {
+			return nil, err
+		}
+	}
+
 	if !p.isPeerConnected(ctx, peerId) {
 		return nil, fmt.Errorf("peer is not connected")
 	}
 
-	swapOut, err := p.swaps.SwapOut(peerId, request.Asset, shortId.String(), pk, request.SwapAmount)
+	claimAddress := request.ClaimAddress
+	if request.ClaimToDescriptor {
+		if claimAddress != "" {
+			return nil, errors.New("claim_address and claim_to_descriptor can not be used together")
+		}
+		claimAddress, err = p.swaps.NewClaimAddress(request.Asset)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	swapOut, err := p.swaps.SwapOut(peerId, request.Asset, shortId.String(), pk, request.SwapAmount, claimAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -227,23 +259,28 @@ func (p *PeerswapServer) SwapIn(ctx context.Context, request *SwapInRequest) (*S
 		if p.Gelements == nil {
 			return nil, errors.New("peerswap was not started with liquid node config")
 		}
-		liquidBalance, err := p.liquidWallet.GetBalance()
-		if err != nil {
-			return nil, err
-		}
-		if liquidBalance < request.SwapAmount+1000 {
-			return nil, errors.New("Not enough balance on liquid wallet")
+		// An externally funded opening tx does not spend from the wallet.
+		if !request.ExternalFunding {
+			liquidBalance, err := p.liquidWallet.GetBalance()
+			if err != nil {
+				return nil, err
+			}
+			if liquidBalance < request.SwapAmount+1000 {
+				return nil, errors.New("Not enough balance on liquid wallet")
+			}
 		}
 	} else if request.Asset == "btc" {
 		if !p.swaps.BitcoinEnabled {
 			return nil, errors.New("bitcoin swaps are not enabled")
 		}
-		walletbalance, err := p.lnd.WalletBalance(ctx, &lnrpc.WalletBalanceRequest{})
-		if err != nil {
-			return nil, err
-		}
-		if uint64(walletbalance.ConfirmedBalance) < request.SwapAmount+2000 {
-			return nil, errors.New("Not enough balance on lnd onchain liquidWallet")
+		if !request.ExternalFunding {
+			walletbalance, err := p.lnd.WalletBalance(ctx, &lnrpc.WalletBalanceRequest{})
+			if err != nil {
+				return nil, err
+			}
+			if uint64(walletbalance.ConfirmedBalance) < request.SwapAmount+2000 {
+				return nil, errors.New("Not enough balance on lnd onchain liquidWallet")
+			}
 		}
 
 	} else {
@@ -264,11 +301,18 @@ func (p *PeerswapServer) SwapIn(ctx context.Context, request *SwapInRequest) (*S
 		return nil, fmt.Errorf("peer does not run peerswap")
 	}
 
+	// The peers capabilities are only a hint, skip them if force flag is set.
+	if !request.Force {
+		if err := p.pollService.CheckCapabilities(peerId, request.Asset, swap.SWAPTYPE_IN, request.SwapAmount); err != nil {
+			return nil, err
+		}
+	}
+
 	if !p.isPeerConnected(ctx, peerId) {
 		return nil, fmt.Errorf("peer is not connected")
 	}
 
-	swapIn, err := p.swaps.SwapIn(peerId, request.Asset, shortId.String(), pk, request.SwapAmount)
+	swapIn, err := p.swaps.SwapIn(peerId, request.Asset, shortId.String(), pk, request.SwapAmount, request.ExternalFunding)
 	if err != nil {
 		return nil, err
 	}
@@ -286,7 +330,8 @@ func (p *PeerswapServer) SwapIn(ctx context.Context, request *SwapInRequest) (*S
 				return nil, errors.New(swapIn.Data.GetCancelMessage())
 
 			}
-			if swapIn.Current == swap.State_SwapInSender_SendTxBroadcastedMessage {
+			if swapIn.Current == swap.State_SwapInSender_SendTxBroadcastedMessage ||
+				swapIn.Current == swap.State_SwapInSender_AwaitExternalFunding {
 				return &SwapResponse{Swap: PrettyprintFromServiceSwap(swapIn)}, nil
 			}
 
@@ -294,6 +339,146 @@ func (p *PeerswapServer) SwapIn(ctx context.Context, request *SwapInRequest) (*S
 	}
 }
 
+// SwapInBatch starts swap-ins on several channels whose opening outputs are
+// paid by a single opening tx. Channels that fail the checks are reported in
+// the results and do not hold up the others.
+func (p *PeerswapServer) SwapInBatch(ctx context.Context, request *SwapInBatchRequest) (*SwapInBatchResponse, error) {
+	if len(request.Swaps) == 0 {
+		return nil, errors.New("no swaps in batch")
+	}
+
+	var total uint64
+	for _, s := range request.Swaps {
+		total += s.SwapAmount
+	}
+
+	if request.Asset == "lbtc" {
+		if !p.swaps.LiquidEnabled {
+			return nil, errors.New("liquid swaps are not enabled")
+		}
+		if p.Gelements == nil {
+			return nil, errors.New("peerswap was not started with liquid node config")
+		}
+		liquidBalance, err := p.liquidWallet.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		if liquidBalance < total+1000 {
+			return nil, errors.New("Not enough balance on liquid wallet")
+		}
+	} else if request.Asset == "btc" {
+		if !p.swaps.BitcoinEnabled {
+			return nil, errors.New("bitcoin swaps are not enabled")
+		}
+		walletbalance, err := p.lnd.WalletBalance(ctx, &lnrpc.WalletBalanceRequest{})
+		if err != nil {
+			return nil, err
+		}
+		if uint64(walletbalance.ConfirmedBalance) < total+2000 {
+			return nil, errors.New("Not enough balance on lnd onchain liquidWallet")
+		}
+	} else {
+		return nil, errors.New("invalid asset (btc or lbtc)")
+	}
+
+	chans, err := p.lnd.ListChannels(ctx, &lnrpc.ListChannelsRequest{ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	gi, err := p.lnd.GetInfo(ctx, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SwapInBatchResult, len(request.Swaps))
+	var requests []swap.SwapInBatchRequest
+	var requested []int
+	for i, s := range request.Swaps {
+		results[i] = &SwapInBatchResult{ChannelId: s.ChannelId}
+		req, err := p.checkSwapInChannel(ctx, chans.Channels, s, request.Asset, request.Force)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		requests = append(requests, *req)
+		requested = append(requested, i)
+	}
+	if len(requests) == 0 {
+		return &SwapInBatchResponse{Results: results}, nil
+	}
+
+	swapResults, err := p.swaps.SwapInBatch(request.Asset, gi.IdentityPubkey, requests)
+	if err != nil {
+		return nil, err
+	}
+	for j, r := range swapResults {
+		i := requested[j]
+		if r.Err != nil {
+			results[i].Error = r.Err.Error()
+			continue
+		}
+		results[i].Swap = PrettyprintFromServiceSwap(r.Swap)
+	}
+	return &SwapInBatchResponse{Results: results}, nil
+}
+
+// checkSwapInChannel runs the checks of SwapIn on a channel of a batch.
+func (p *PeerswapServer) checkSwapInChannel(ctx context.Context, chans []*lnrpc.Channel, s *SwapInBatchEntry, asset string, force bool) (*swap.SwapInBatchRequest, error) {
+	var swapchan *lnrpc.Channel
+	for _, v := range chans {
+		if v.ChanId == s.ChannelId {
+			swapchan = v
+		}
+	}
+	if swapchan == nil {
+		return nil, errors.New("channel not found")
+	}
+	if uint64(swapchan.RemoteBalance) < s.SwapAmount {
+		return nil, errors.New("not enough remote balance on channel to perform swap in")
+	}
+	if !swapchan.Active {
+		return nil, errors.New("channel is not connected")
+	}
+
+	peerId := swapchan.RemotePubkey
+	if !force && !p.peerRunsPeerSwap(peerId) {
+		return nil, fmt.Errorf("peer does not run peerswap")
+	}
+	if !force {
+		if err := p.pollService.CheckCapabilities(peerId, asset, swap.SWAPTYPE_IN, s.SwapAmount); err != nil {
+			return nil, err
+		}
+	}
+	if !p.isPeerConnected(ctx, peerId) {
+		return nil, fmt.Errorf("peer is not connected")
+	}
+
+	return &swap.SwapInBatchRequest{
+		PeerId:    peerId,
+		ChannelId: lnwire.NewShortChanIDFromInt(swapchan.ChanId).String(),
+		AmtSat:    s.SwapAmount,
+	}, nil
+}
+
+// SubmitOpeningTx broadcasts the signed opening tx of an externally funded
+// swap in.
+func (p *PeerswapServer) SubmitOpeningTx(ctx context.Context, request *SubmitOpeningTxRequest) (*SwapResponse, error) {
+	if request.SwapId == "" {
+		return nil, errors.New("SwapId required")
+	}
+	if request.SignedTx == "" {
+		return nil, errors.New("SignedTx required")
+	}
+	swapIn, err := p.swaps.SubmitOpeningTx(request.SwapId, request.SignedTx)
+	if err != nil {
+		return nil, err
+	}
+	if swapIn.Current == swap.State_SwapCanceled {
+		return nil, errors.New(swapIn.Data.GetCancelMessage())
+	}
+	return &SwapResponse{Swap: PrettyprintFromServiceSwap(swapIn)}, nil
+}
+
 func (p *PeerswapServer) GetSwap(ctx context.Context, request *GetSwapRequest) (*SwapResponse, error) {
 	if request.SwapId == "" {
 		return nil, errors.New("SwapId required")
@@ -347,10 +532,15 @@ func (p *PeerswapServer) ListPeers(ctx context.Context, request *ListPeersReques
 				return nil, err
 			}
 
-			var paidFees uint64
+			var paidFees, feeOvercharges uint64
 			var ReceiverSwapsOut, ReceiverSwapsIn, ReceiverSatsOut, ReceiverSatsIn uint64
 			var SenderSwapsOut, SenderSwapsIn, SenderSatsOut, SenderSatsIn uint64
 			for _, s := range swaps {
+				// Fee invoices of the peer that we rejected count against
+				// its reputation.
+				if s.Role == swap.SWAPROLE_SENDER && s.Data.FeeOvercharge > 0 {
+					feeOvercharges++
+				}
 				// We only list successful swaps. They all end in an
 				// State_ClaimedPreimage state.
 				if s.Current == swap.State_ClaimedPreimage {
@@ -392,7 +582,9 @@ func (p *PeerswapServer) ListPeers(ctx context.Context, request *ListPeersReques
 					SatsOut:  ReceiverSatsOut,
 					SatsIn:   ReceiverSatsIn,
 				},
-				PaidFee: paidFees,
+				PaidFee:        paidFees,
+				FeeOvercharges: feeOvercharges,
+				Capabilities:   capabilitiesToRpc(poll.Capabilities),
 			})
 		}
 
@@ -400,6 +592,28 @@ func (p *PeerswapServer) ListPeers(ctx context.Context, request *ListPeersReques
 	return &ListPeersResponse{Peers: peerSwapPeers}, nil
 }
 
+func capabilitiesToRpc(c *poll.Capabilities) *PeerCapabilities {
+	if c == nil {
+		return nil
+	}
+	var assets []*AssetCapabilities
+	for _, a := range c.Assets {
+		assets = append(assets, &AssetCapabilities{
+			Asset:               a.Asset,
+			MinAmountSat:        a.MinAmountSat,
+			MaxSwapOutAmountSat: a.MaxSwapOutAmountSat,
+			SwapTypes:           a.SwapTypes,
+		})
+	}
+	return &PeerCapabilities{
+		Version:       c.Version,
+		AllowNewSwaps: c.AllowNewSwaps,
+		OutputTypes:   c.OutputTypes,
+		Encodings:     c.Encodings,
+		Assets:        assets,
+	}
+}
+
 func getPeerSwapChannels(peerId string, channelList []*lnrpc.Channel) []*PeerSwapPeerChannel {
 	var peerswapchannels []*PeerSwapPeerChannel
 	for _, v := range findChannels(peerId, channelList) {
@@ -447,26 +661,77 @@ func (p *PeerswapServer) ReloadPolicyFile(ctx context.Context, request *ReloadPo
 }
 
 func (p *PeerswapServer) ListRequestedSwaps(ctx context.Context, request *ListRequestedSwapsRequest) (*ListRequestedSwapsResponse, error) {
-	requestedSwaps, err := p.requestedSwaps.GetRaw()
+	filter := swap.RequestedSwapsFilter{PeerId: request.PeerPubkey}
+	if request.Since > 0 {
+		filter.Since = time.Unix(request.Since, 0)
+	}
+	if request.Until > 0 {
+		filter.Until = time.Unix(request.Until, 0)
+	}
+	requestedSwaps, err := p.requestedSwaps.GetRaw(filter)
 	if err != nil {
 		return nil, err
 	}
 	swapMap := make(map[string]*RequestSwapList)
 	for k, v := range requestedSwaps {
-		var swapList []*RequestedSwap
+		list := &RequestSwapList{}
 		for _, reqSwap := range v {
-			swapList = append(swapList, &RequestedSwap{
+			list.RequestedSwaps = append(list.RequestedSwaps, &RequestedSwap{
 				Asset:           reqSwap.Asset,
 				AmountSat:       reqSwap.AmountSat,
 				SwapType:        RequestedSwap_SwapType(reqSwap.Type),
 				RejectionReason: reqSwap.RejectionReason,
+				RejectionCode:   CancelCode(reqSwap.RejectionCode),
+				CreatedAt:       reqSwap.CreatedAt,
 			})
+			list.NRequests++
+			list.TotalAmountSat += reqSwap.AmountSat
+			if reqSwap.RejectionCode > 0 {
+				if list.RejectionCodes == nil {
+					list.RejectionCodes = map[string]uint64{}
+				}
+				list.RejectionCodes[reqSwap.RejectionCode.String()]++
+			}
+			if reqSwap.CreatedAt > 0 {
+				if list.FirstRequestAt == 0 || reqSwap.CreatedAt < list.FirstRequestAt {
+					list.FirstRequestAt = reqSwap.CreatedAt
+				}
+				if reqSwap.CreatedAt > list.LastRequestAt {
+					list.LastRequestAt = reqSwap.CreatedAt
+				}
+			}
 		}
-		swapMap[k] = &RequestSwapList{RequestedSwaps: swapList}
+		swapMap[k] = list
 	}
 	return &ListRequestedSwapsResponse{RequestedSwaps: swapMap}, nil
 }
 
+func (p *PeerswapServer) GetPeerHistory(ctx context.Context, request *GetPeerHistoryRequest) (*GetPeerHistoryResponse, error) {
+	if request.PeerPubkey == "" {
+		return nil, errors.New("Missing required peer_pubkey parameter")
+	}
+	history, err := p.pollService.GetPeerHistory(request.PeerPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GetPeerHistoryResponse{
+		NodeId:    request.PeerPubkey,
+		FirstSeen: history.FirstSeen.Unix(),
+		LastSeen:  history.LastSeen.Unix(),
+	}
+	for _, u := range history.Uptimes(time.Now()) {
+		res.Uptime = append(res.Uptime, &PeerUptime{WindowHours: u.WindowHours, Percentage: u.Percentage})
+	}
+	for _, v := range history.Versions {
+		res.Versions = append(res.Versions, &PeerVersion{Version: v.Version, FirstSeen: v.FirstSeen.Unix(), LastSeen: v.LastSeen.Unix()})
+	}
+	for _, c := range history.AssetChanges {
+		res.AssetChanges = append(res.AssetChanges, &PeerAssetChange{Time: c.Time.Unix(), Assets: c.Assets})
+	}
+	return res, nil
+}
+
 func (p *PeerswapServer) LiquidGetAddress(ctx context.Context, request *GetAddressRequest) (*GetAddressResponse, error) {
 	if !p.swaps.LiquidEnabled {
 		return nil, errors.New("liquid swaps are not enabled")
@@ -517,6 +782,71 @@ func (p *PeerswapServer) LiquidSendToAddress(ctx context.Context, request *SendT
 	return &SendToAddressResponse{TxId: res}, nil
 }
 
+func (p *PeerswapServer) BtcGetAddress(ctx context.Context, request *GetAddressRequest) (*GetAddressResponse, error) {
+	res, err := p.btcWallet.NewAddress()
+	if err != nil {
+		return nil, err
+	}
+	return &GetAddressResponse{Address: res}, nil
+}
+
+func (p *PeerswapServer) BtcGetBalance(ctx context.Context, request *GetBalanceRequest) (*GetBalanceResponse, error) {
+	res, err := p.btcWallet.GetOnchainBalance()
+	if err != nil {
+		return nil, err
+	}
+	return &GetBalanceResponse{SatAmount: res}, nil
+}
+
+func (p *PeerswapServer) BtcSendToAddress(ctx context.Context, request *SendToAddressRequest) (*SendToAddressResponse, error) {
+	if request.Address == "" {
+		return nil, errors.New("address not set")
+	}
+	if request.SatAmount < 5000 {
+		return nil, errors.New("amount is dust")
+	}
+
+	res, err := p.btcWallet.SendToAddress(request.Address, request.SatAmount)
+	if err != nil {
+		return nil, err
+	}
+	return &SendToAddressResponse{TxId: res}, nil
+}
+
+// GetBalances returns the on-chain balances of the btc and lbtc wallets and
+// the amounts that are available for new swaps after subtracting the policy
+// reserve and the funds committed to active swaps.
+func (p *PeerswapServer) GetBalances(ctx context.Context, request *GetBalancesRequest) (*GetBalancesResponse, error) {
+	var balances []*WalletBalance
+
+	btcBalance, err := p.btcWallet.GetOnchainBalance()
+	if err != nil {
+		return nil, err
+	}
+	balances = append(balances, p.walletBalance("btc", btcBalance))
+
+	if p.swaps.LiquidEnabled && p.Gelements != nil {
+		liquidBalance, err := p.liquidWallet.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, p.walletBalance("lbtc", liquidBalance))
+	}
+
+	return &GetBalancesResponse{Balances: balances}, nil
+}
+
+func (p *PeerswapServer) walletBalance(asset string, balance uint64) *WalletBalance {
+	reserve, committed, available := p.swaps.OnchainBalanceSplit(asset, balance)
+	return &WalletBalance{
+		Asset:        asset,
+		OnchainSat:   balance,
+		ReserveSat:   reserve,
+		CommittedSat: committed,
+		AvailableSat: available,
+	}
+}
+
 func (p *PeerswapServer) ListActiveSwaps(ctx context.Context, request *ListSwapsRequest) (*ListSwapsResponse, error) {
 	swaps, err := p.swaps.ListActiveSwaps()
 	if err != nil {
@@ -546,6 +876,61 @@ func (p *PeerswapServer) AllowSwapRequests(ctx context.Context, request *AllowSw
 	return GetPolicyMessage(pol), nil
 }
 
+// Drain disables new swaps in both directions and reports the active swaps
+// that are left. If requested, the daemon stops once the last active swap
+// completed.
+func (p *PeerswapServer) Drain(ctx context.Context, request *DrainRequest) (*DrainResponse, error) {
+	err := p.policy.DisableSwaps()
+	if err != nil {
+		return nil, err
+	}
+
+	swaps, err := p.swaps.ListDrainingSwaps()
+	if err != nil {
+		return nil, err
+	}
+
+	p.drainMu.Lock()
+	defer p.drainMu.Unlock()
+	if request.Shutdown && !p.drainShutdown {
+		p.drainShutdown = true
+		go func() {
+			err := p.swaps.WaitForDrain(context.Background())
+			if err != nil {
+				log.Infof("[Drain] Could not wait for active swaps: %v", err)
+				p.drainMu.Lock()
+				p.drainShutdown = false
+				p.drainMu.Unlock()
+				return
+			}
+			log.Infof("[Drain] All active swaps completed, shutting down")
+			p.sigchan <- os.Interrupt
+		}()
+	}
+
+	return &DrainResponse{Swaps: DrainingSwapsFromService(swaps), Shutdown: p.drainShutdown}, nil
+}
+
+// DrainingSwapsFromService returns the draining swaps sorted by creation
+// time.
+func DrainingSwapsFromService(swaps []*swap.DrainingSwap) []*DrainingSwap {
+	sort.Slice(swaps, func(i, j int) bool {
+		if swaps[i].Swap.Data != nil && swaps[j].Swap.Data != nil {
+			return swaps[i].Swap.Data.CreatedAt < swaps[j].Swap.Data.CreatedAt
+		}
+		return false
+	})
+	var resSwaps []*DrainingSwap
+	for _, v := range swaps {
+		resSwaps = append(resSwaps, &DrainingSwap{
+			Swap:             PrettyprintFromServiceSwap(v.Swap),
+			CurrentHeight:    v.CurrentHeight,
+			CompletionHeight: v.CompletionHeight,
+		})
+	}
+	return resSwaps
+}
+
 func PrettyprintFromServiceSwap(swap *swap.SwapStateMachine) *PrettyPrintSwap {
 	scid, err := newScidFromString(swap.Data.GetScid())
 	if err != nil {
@@ -572,6 +957,9 @@ func PrettyprintFromServiceSwap(swap *swap.SwapStateMachine) *PrettyPrintSwap {
 		ClaimTxId:       swap.Data.ClaimTxId,
 		CancelMessage:   swap.Data.GetCancelMessage(),
 		LndChanId:       lnd_chan_id,
+		DoubleSpendTxId: swap.Data.DoubleSpendTxId,
+		CancelCode:      CancelCode(swap.Data.GetCancelCode()),
+		OpeningPsbt:     swap.Data.GetOpeningPsbt(),
 	}
 }
 
@@ -0,0 +1,18 @@
+package peerswaprpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elementsproject/peerswap/swap"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_CancelCodes checks that the rpc cancel codes have the values of the
+// codes of the cancel message.
+func Test_CancelCodes(t *testing.T) {
+	assert.Len(t, CancelCode_name, int(swap.CancelCode_TooManySwaps)+1)
+	for value, name := range CancelCode_name {
+		assert.Equal(t, strings.ToLower(name), swap.CancelCode(value).String())
+	}
+}
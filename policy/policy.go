@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -25,6 +26,27 @@ const (
 	// to perform a swap. We need this lower boundary as it is uneconomical to
 	// swap small amounts.
 	defaultMinSwapAmountMsat uint64 = 100000000
+
+	// defaultMaxFeeInvoiceMultiple is the default multiple of our own
+	// estimate of the opening tx fee that a swap-out fee invoice may charge.
+	defaultMaxFeeInvoiceMultiple float64 = 3
+
+	// defaultMaxFeeInvoiceSat is the default absolute cap on swap-out fee
+	// invoices, 0 means no cap.
+	defaultMaxFeeInvoiceSat uint64 = 0
+
+	// defaultMaxPendingIncomingSwaps is the default number of swaps a peer
+	// can have requested from us that have no opening tx yet.
+	defaultMaxPendingIncomingSwaps uint64 = 10
+
+	// defaultExternalFundingTimeoutSec is the default time in seconds we
+	// wait for an externally funded opening tx.
+	defaultExternalFundingTimeoutSec uint64 = 540
+
+	// maxExternalFundingTimeoutSec is the upper bound of the external
+	// funding timeout. The peer cancels the swap 10 minutes after it sent
+	// its agreement, the opening tx has to be broadcasted before.
+	maxExternalFundingTimeoutSec uint64 = 570
 )
 
 // Global Mutex
@@ -87,6 +109,20 @@ type Policy struct {
 	// when we want to upgrade the node and do not want to allow for any new
 	// swap request from the peer or the node operator.
 	AllowNewSwaps bool `json:"allow_new_swaps" long:"allow_new_swaps" description:"If set to false, disables all swap requests, defaults to true."`
+
+	// MaxFeeInvoiceMultiple and MaxFeeInvoiceSat limit the fee invoice that
+	// we pay for the opening tx of a swap-out. The fee is checked against our
+	// own estimate of the opening tx fee.
+	MaxFeeInvoiceMultiple float64 `json:"max_fee_invoice_multiple" long:"max_fee_invoice_multiple" description:"Swap-out fee invoices above this multiple of our own estimate of the opening tx fee are rejected, defaults to 3."`
+	MaxFeeInvoiceSat      uint64  `json:"max_fee_invoice_sat" long:"max_fee_invoice_sat" description:"Swap-out fee invoices above this amount in sat are rejected, 0 disables the cap."`
+
+	// MaxPendingIncomingSwaps limits the swaps a peer can request from us at
+	// the same time. Only swaps without an opening tx are counted.
+	MaxPendingIncomingSwaps uint64 `json:"max_pending_incoming_swaps" long:"max_pending_incoming_swaps" description:"The number of swaps a peer can request at the same time before their opening tx is broadcasted, defaults to 10."`
+
+	// ExternalFundingTimeoutSec is the time the operator has to submit the
+	// opening tx of an externally funded swap-in.
+	ExternalFundingTimeoutSec uint64 `json:"external_funding_timeout_sec" long:"external_funding_timeout_sec" description:"The seconds to submit the opening tx of an externally funded swap-in before the swap is canceled, at most 570, defaults to 540."`
 }
 
 func (p *Policy) String() string {
@@ -96,13 +132,21 @@ func (p *Policy) String() string {
 			"reserve_onchain_msat: %d\n"+
 			"allowlisted_peers: %s\n"+
 			"accept_all_peers: %t\n"+
-			"suspicious_peers: %s\n",
+			"suspicious_peers: %s\n"+
+			"max_fee_invoice_multiple: %g\n"+
+			"max_fee_invoice_sat: %d\n"+
+			"max_pending_incoming_swaps: %d\n"+
+			"external_funding_timeout_sec: %d\n",
 		p.AllowNewSwaps,
 		p.MinSwapAmountMsat,
 		p.ReserveOnchainMsat,
 		p.PeerAllowlist,
 		p.AcceptAllPeers,
 		p.SuspiciousPeerList,
+		p.MaxFeeInvoiceMultiple,
+		p.MaxFeeInvoiceSat,
+		p.MaxPendingIncomingSwaps,
+		p.ExternalFundingTimeoutSec,
 	)
 	return str
 }
@@ -118,6 +162,11 @@ func (p *Policy) Get() Policy {
 		AcceptAllPeers:     p.AcceptAllPeers,
 		MinSwapAmountMsat:  p.MinSwapAmountMsat,
 		AllowNewSwaps:      p.AllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     p.MaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          p.MaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   p.MaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: p.ExternalFundingTimeoutSec,
 	}
 }
 
@@ -138,6 +187,38 @@ func (p *Policy) GetMinSwapAmountMsat() uint64 {
 	return p.MinSwapAmountMsat
 }
 
+// GetMaxFeeInvoiceMultiple returns the multiple of the estimated opening tx
+// fee that a swap-out fee invoice may charge.
+func (p *Policy) GetMaxFeeInvoiceMultiple() float64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return p.MaxFeeInvoiceMultiple
+}
+
+// GetMaxFeeInvoiceSat returns the absolute cap in sat on swap-out fee
+// invoices, 0 means no cap.
+func (p *Policy) GetMaxFeeInvoiceSat() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return p.MaxFeeInvoiceSat
+}
+
+// GetMaxPendingIncomingSwaps returns the number of swaps without an opening tx
+// a peer can have requested from us at the same time.
+func (p *Policy) GetMaxPendingIncomingSwaps() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return p.MaxPendingIncomingSwaps
+}
+
+// GetExternalFundingTimeoutSec returns the seconds the operator has to submit
+// an externally funded opening tx.
+func (p *Policy) GetExternalFundingTimeoutSec() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return p.ExternalFundingTimeoutSec
+}
+
 // NewSwapsAllowed returns the boolean value of AllowNewSwaps.
 func (p *Policy) NewSwapsAllowed() bool {
 	return p.AllowNewSwaps
@@ -425,6 +506,11 @@ func DefaultPolicy() *Policy {
 		AcceptAllPeers:     defaultAcceptAllPeers,
 		MinSwapAmountMsat:  defaultMinSwapAmountMsat,
 		AllowNewSwaps:      defaultAllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     defaultMaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          defaultMaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   defaultMaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: defaultExternalFundingTimeoutSec,
 	}
 }
 
@@ -470,6 +556,23 @@ func create(r io.Reader) (*Policy, error) {
 		return nil, ErrCreatePolicy(err.Error())
 	}
 
+	// A cap of 0 would reject every swap request.
+	if policy.MaxPendingIncomingSwaps == 0 {
+		policy.MaxPendingIncomingSwaps = defaultMaxPendingIncomingSwaps
+	}
+
+	// A multiple of 0 would reject every fee invoice, NaN would accept any.
+	if !(policy.MaxFeeInvoiceMultiple > 0) || math.IsInf(policy.MaxFeeInvoiceMultiple, 0) {
+		policy.MaxFeeInvoiceMultiple = defaultMaxFeeInvoiceMultiple
+	}
+
+	if policy.ExternalFundingTimeoutSec == 0 {
+		policy.ExternalFundingTimeoutSec = defaultExternalFundingTimeoutSec
+	}
+	if policy.ExternalFundingTimeoutSec > maxExternalFundingTimeoutSec {
+		return nil, ErrCreatePolicy(fmt.Sprintf("external_funding_timeout_sec must be at most %d", maxExternalFundingTimeoutSec))
+	}
+
 	return policy, nil
 }
 
@@ -31,6 +31,11 @@ func Test_Create(t *testing.T) {
 		AcceptAllPeers:     defaultAcceptAllPeers,
 		MinSwapAmountMsat:  defaultMinSwapAmountMsat,
 		AllowNewSwaps:      defaultAllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     defaultMaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          defaultMaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   defaultMaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: defaultExternalFundingTimeoutSec,
 	}, policy)
 
 	peer1 := "123"
@@ -63,6 +68,11 @@ func Test_Create(t *testing.T) {
 		AcceptAllPeers:     accept,
 		MinSwapAmountMsat:  defaultMinSwapAmountMsat,
 		AllowNewSwaps:      defaultAllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     defaultMaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          defaultMaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   defaultMaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: defaultExternalFundingTimeoutSec,
 	}, policy2)
 }
 
@@ -86,6 +96,11 @@ func Test_Reload(t *testing.T) {
 		AcceptAllPeers:     accept,
 		MinSwapAmountMsat:  defaultMinSwapAmountMsat,
 		AllowNewSwaps:      defaultAllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     defaultMaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          defaultMaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   defaultMaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: defaultExternalFundingTimeoutSec,
 	}, policy)
 
 	newPeer := "new_peer"
@@ -100,6 +115,11 @@ func Test_Reload(t *testing.T) {
 		AcceptAllPeers:     defaultAcceptAllPeers,
 		MinSwapAmountMsat:  defaultMinSwapAmountMsat,
 		AllowNewSwaps:      defaultAllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     defaultMaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          defaultMaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   defaultMaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: defaultExternalFundingTimeoutSec,
 	}, policy)
 }
 
@@ -123,6 +143,11 @@ func Test_Reload_NoOverrideOnError(t *testing.T) {
 		AcceptAllPeers:     accept,
 		MinSwapAmountMsat:  defaultMinSwapAmountMsat,
 		AllowNewSwaps:      defaultAllowNewSwaps,
+
+		MaxFeeInvoiceMultiple:     defaultMaxFeeInvoiceMultiple,
+		MaxFeeInvoiceSat:          defaultMaxFeeInvoiceSat,
+		MaxPendingIncomingSwaps:   defaultMaxPendingIncomingSwaps,
+		ExternalFundingTimeoutSec: defaultExternalFundingTimeoutSec,
 	}, policy)
 
 	// copy policy
@@ -271,6 +296,43 @@ func Test_IsPeerAllowed_AcceptAll(t *testing.T) {
 
 }
 
+func Test_MaxFeeInvoice(t *testing.T) {
+	conf := "max_fee_invoice_multiple=1.5\nmax_fee_invoice_sat=5000"
+
+	policy, err := create(strings.NewReader(conf))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, policy.GetMaxFeeInvoiceMultiple())
+	assert.Equal(t, uint64(5000), policy.GetMaxFeeInvoiceSat())
+
+	// Invalid multiples fall back to the default.
+	for _, multiple := range []string{"0", "-1", "NaN", "+Inf"} {
+		policy, err = create(strings.NewReader("max_fee_invoice_multiple=" + multiple))
+		assert.NoError(t, err)
+		assert.Equal(t, defaultMaxFeeInvoiceMultiple, policy.GetMaxFeeInvoiceMultiple(), multiple)
+	}
+}
+
+func Test_MaxPendingIncomingSwaps(t *testing.T) {
+	policy, err := create(strings.NewReader("max_pending_incoming_swaps=20"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), policy.GetMaxPendingIncomingSwaps())
+
+	// A cap of 0 would reject every swap request, the default is used.
+	policy, err = create(strings.NewReader("max_pending_incoming_swaps=0"))
+	assert.NoError(t, err)
+	assert.Equal(t, defaultMaxPendingIncomingSwaps, policy.GetMaxPendingIncomingSwaps())
+}
+
+func Test_ExternalFundingTimeout(t *testing.T) {
+	policy, err := create(strings.NewReader("external_funding_timeout_sec=300"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), policy.GetExternalFundingTimeoutSec())
+
+	// The peer cancels the swap before a longer timeout passes.
+	_, err = create(strings.NewReader("external_funding_timeout_sec=600"))
+	assert.Error(t, err)
+}
+
 func Test_CreateFile(t *testing.T) {
 	confPath := filepath.Join(t.TempDir(), "peerswap.conf")
 
@@ -0,0 +1,148 @@
+package poll
+
+import (
+	"time"
+)
+
+const (
+	// historyWindow is the time for which the online hours of a peer are
+	// kept to calculate its uptime.
+	historyWindow = 30 * 24 * time.Hour
+	// maxAssetChanges is the number of asset changes that are kept per peer.
+	maxAssetChanges = 50
+	// maxVersions is the number of protocol versions that are kept per
+	// peer.
+	maxVersions = 20
+)
+
+// UptimeWindows are the windows the uptime of a peer is reported for.
+var UptimeWindows = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, historyWindow}
+
+// PeerHistory is the history of the polls we received from a peer.
+type PeerHistory struct {
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// OnlineHours are the unix hours in which we received a poll from the
+	// peer during the last historyWindow.
+	OnlineHours []int64 `json:"online_hours,omitempty"`
+	// Versions are the protocol versions the peer announced.
+	Versions []VersionSeen `json:"versions,omitempty"`
+	// AssetChanges are the last changes of the assets the peer supports,
+	// the first entry are the assets of the first poll.
+	AssetChanges []AssetChange `json:"asset_changes,omitempty"`
+}
+
+// VersionSeen tells when a peer announced a protocol version.
+type VersionSeen struct {
+	Version   uint64    `json:"version"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// AssetChange tells when a peer changed the assets it supports.
+type AssetChange struct {
+	Time   time.Time `json:"time"`
+	Assets []string  `json:"assets"`
+}
+
+// Add records a poll received at now.
+func (h *PeerHistory) Add(info PollInfo, now time.Time) {
+	if h.FirstSeen.IsZero() {
+		h.FirstSeen = now
+	}
+	h.LastSeen = now
+
+	// Record the online hour and drop the ones outside of the window.
+	hour := now.Unix() / 3600
+	oldest := hour - int64(historyWindow/time.Hour) + 1
+	var hours []int64
+	for _, online := range h.OnlineHours {
+		if online >= oldest && online != hour {
+			hours = append(hours, online)
+		}
+	}
+	h.OnlineHours = append(hours, hour)
+
+	found := false
+	for i := range h.Versions {
+		if h.Versions[i].Version == info.ProtocolVersion {
+			h.Versions[i].LastSeen = now
+			found = true
+		}
+	}
+	if !found {
+		h.Versions = append(h.Versions, VersionSeen{
+			Version:   info.ProtocolVersion,
+			FirstSeen: now,
+			LastSeen:  now,
+		})
+		if len(h.Versions) > maxVersions {
+			// Drop the version that was announced least recently.
+			oldest := 0
+			for i := range h.Versions {
+				if h.Versions[i].LastSeen.Before(h.Versions[oldest].LastSeen) {
+					oldest = i
+				}
+			}
+			h.Versions = append(h.Versions[:oldest], h.Versions[oldest+1:]...)
+		}
+	}
+
+	if len(h.AssetChanges) == 0 || !equalAssets(h.AssetChanges[len(h.AssetChanges)-1].Assets, info.Assets) {
+		h.AssetChanges = append(h.AssetChanges, AssetChange{Time: now, Assets: info.Assets})
+		if len(h.AssetChanges) > maxAssetChanges {
+			h.AssetChanges = h.AssetChanges[len(h.AssetChanges)-maxAssetChanges:]
+		}
+	}
+}
+
+// Uptime returns the share of the hours in the window up to now in which the
+// peer was online, the current hour included. Hours before the peer was first seen are not counted.
+func (h *PeerHistory) Uptime(now time.Time, window time.Duration) float64 {
+	if h.FirstSeen.IsZero() {
+		return 0
+	}
+	last := now.Unix() / 3600
+	first := last - int64(window/time.Hour) + 1
+	if firstSeen := h.FirstSeen.Unix() / 3600; firstSeen > first {
+		first = firstSeen
+	}
+
+	var online int64
+	for _, hour := range h.OnlineHours {
+		if hour >= first && hour <= last {
+			online++
+		}
+	}
+	return float64(online) / float64(last-first+1)
+}
+
+// WindowUptime is the uptime of a peer in percent over a window.
+type WindowUptime struct {
+	WindowHours uint64  `json:"window_hours"`
+	Percentage  float64 `json:"percentage"`
+}
+
+// Uptimes returns the uptime of the peer for each of the UptimeWindows.
+func (h *PeerHistory) Uptimes(now time.Time) []WindowUptime {
+	var uptimes []WindowUptime
+	for _, window := range UptimeWindows {
+		uptimes = append(uptimes, WindowUptime{
+			WindowHours: uint64(window / time.Hour),
+			Percentage:  h.Uptime(now, window) * 100,
+		})
+	}
+	return uptimes
+}
+
+func equalAssets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,10 +2,41 @@ package poll
 
 import "github.com/elementsproject/peerswap/messages"
 
+// CAPABILITIES_VERSION is the version of the capability block. It is
+// increased on changes that old peers would misinterpret.
+const CAPABILITIES_VERSION = 1
+
+// Capabilities describe the swaps a peer accepts as responder, so that we
+// do not send requests that are certainly rejected.
+type Capabilities struct {
+	Version       uint64 `json:"version" tlv:"0"`
+	AllowNewSwaps bool   `json:"allow_new_swaps" tlv:"2"`
+	// OutputTypes are the supported opening tx outputs, e.g. p2wsh.
+	OutputTypes []string `json:"output_types,omitempty" tlv:"4"`
+	// Encodings are the supported message encodings, json or tlv.
+	Encodings []string            `json:"encodings,omitempty" tlv:"6"`
+	Assets    []AssetCapabilities `json:"assets,omitempty" tlv:"8"`
+}
+
+// AssetCapabilities describe the swaps a peer accepts on an asset.
+type AssetCapabilities struct {
+	Asset        string `json:"asset" tlv:"0"`
+	MinAmountSat uint64 `json:"min_amount_sat" tlv:"2"`
+	// MaxSwapOutAmountSat is the largest swap out the peer can fund. We
+	// round it down to a multiple of 100000 sat.
+	MaxSwapOutAmountSat uint64 `json:"max_swap_out_amount_sat,omitempty" tlv:"4"`
+	// SwapTypes are the accepted swap directions, swap_in and swap_out.
+	SwapTypes []string `json:"swap_types,omitempty" tlv:"6"`
+}
+
 type PollMessage struct {
-	Version     uint64   `json:"version"`
-	Assets      []string `json:"assets"`
-	PeerAllowed bool     `json:"peer_allowed"`
+	Version     uint64   `json:"version" tlv:"0"`
+	Assets      []string `json:"assets" tlv:"2"`
+	PeerAllowed bool     `json:"peer_allowed" tlv:"4"`
+	// Features are the optional protocol features the peer supports.
+	Features []string `json:"features,omitempty" tlv:"6"`
+	// Capabilities describe the swaps the peer currently accepts.
+	Capabilities *Capabilities `json:"capabilities,omitempty" tlv:"8"`
 }
 
 func (PollMessage) MessageType() messages.MessageType {
@@ -13,9 +44,13 @@ func (PollMessage) MessageType() messages.MessageType {
 }
 
 type RequestPollMessage struct {
-	Version     uint64   `json:"version"`
-	Assets      []string `json:"assets"`
-	PeerAllowed bool     `json:"peer_allowed"`
+	Version     uint64   `json:"version" tlv:"0"`
+	Assets      []string `json:"assets" tlv:"2"`
+	PeerAllowed bool     `json:"peer_allowed" tlv:"4"`
+	// Features are the optional protocol features the peer supports.
+	Features []string `json:"features,omitempty" tlv:"6"`
+	// Capabilities describe the swaps the peer currently accepts.
+	Capabilities *Capabilities `json:"capabilities,omitempty" tlv:"8"`
 }
 
 func (RequestPollMessage) MessageType() messages.MessageType {
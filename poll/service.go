@@ -2,12 +2,12 @@ package poll
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/elementsproject/peerswap/log"
+	"github.com/elementsproject/peerswap/metrics"
 	"github.com/elementsproject/peerswap/swap"
 
 	"github.com/elementsproject/peerswap/messages"
@@ -19,6 +19,14 @@ func (p PollNotFoundErr) Error() string {
 	return fmt.Sprintf("poll for node %s not found", string(p))
 }
 
+// ErrSwapNotAccepted is returned if the capabilities of a peer show that it
+// rejects a swap.
+type ErrSwapNotAccepted string
+
+func (e ErrSwapNotAccepted) Error() string {
+	return fmt.Sprintf("peer does not accept the swap: %s", string(e))
+}
+
 type MessageSender interface {
 	SendMessage(peerId string, message []byte, messageType int) error
 }
@@ -38,11 +46,23 @@ type PeerGetter interface {
 
 type Policy interface {
 	IsPeerAllowed(peerId string) bool
+	NewSwapsAllowed() bool
+}
+
+// SwapLimits tells the limits of the swaps we accept on the enabled chains.
+type SwapLimits interface {
+	GetAssetLimits() []swap.AssetLimit
+}
+
+// RateLimiter limits the incoming messages of a peer per message type.
+type RateLimiter interface {
+	AllowMessage(peerId string, msgType messages.MessageType) error
 }
 
 type Store interface {
 	Update(peerId string, info PollInfo) error
 	GetAll() (map[string]PollInfo, error)
+	GetHistory(peerId string) (*PeerHistory, error)
 	RemoveUnseen(olderThan time.Duration) error
 }
 
@@ -51,7 +71,13 @@ type PollInfo struct {
 	Assets          []string `json:"assets"`
 	PeerAllowed     bool
 	LastSeen        time.Time
+	Features        []string      `json:"features,omitempty"`
+	Capabilities    *Capabilities `json:"capabilities,omitempty"`
 }
+
+// supportedFeatures are announced to our peers in the poll.
+var supportedFeatures = []string{messages.FEATURE_TLV, messages.FEATURE_ACK}
+
 type Service struct {
 	sync.RWMutex
 	clock *time.Ticker
@@ -63,6 +89,8 @@ type Service struct {
 	policy         Policy
 	peers          PeerGetter
 	store          Store
+	limits         SwapLimits
+	rateLimiter    RateLimiter
 	tmpStore       map[string]string
 	removeDuration time.Duration
 }
@@ -87,6 +115,22 @@ func NewService(tickDuration time.Duration, removeDuration time.Duration, store
 	return s
 }
 
+// SetSwapLimits sets the source of the swap limits we announce in the
+// capabilities. It must be set before the service is started.
+func (s *Service) SetSwapLimits(limits SwapLimits) {
+	s.Lock()
+	defer s.Unlock()
+	s.limits = limits
+}
+
+// SetRateLimiter sets the limiter of the incoming polls and poll requests. It
+// must be set before the service is started.
+func (s *Service) SetRateLimiter(limiter RateLimiter) {
+	s.Lock()
+	defer s.Unlock()
+	s.rateLimiter = limiter
+}
+
 // Start the poll message loop and send the poll
 // messages on every tick.
 func (s *Service) Start() {
@@ -99,6 +143,7 @@ func (s *Service) Start() {
 			case <-s.clock.C:
 				// remove unseen
 				s.store.RemoveUnseen(s.removeDuration)
+				s.updateMetrics()
 				// poll
 				s.PollAllPeers()
 			case <-s.ctx.Done():
@@ -115,13 +160,22 @@ func (s *Service) Stop() {
 
 // Poll sends the POLL message to a single peer.
 func (s *Service) Poll(peer string) {
+	s.poll(peer, s.assetCapabilities())
+}
+
+// poll sends the POLL message with the asset capabilities `assets` to a
+// single peer.
+func (s *Service) poll(peer string, assets []AssetCapabilities) {
+	peerAllowed := s.policy.IsPeerAllowed(peer)
 	poll := PollMessage{
-		Version:     swap.PEERSWAP_PROTOCOL_VERSION,
-		Assets:      s.assets,
-		PeerAllowed: s.policy.IsPeerAllowed(peer),
+		Version:      swap.PEERSWAP_PROTOCOL_VERSION,
+		Assets:       s.assets,
+		PeerAllowed:  peerAllowed,
+		Features:     supportedFeatures,
+		Capabilities: s.capabilities(peerAllowed, assets),
 	}
 
-	msg, err := json.Marshal(poll)
+	msg, err := messages.Marshal(poll, s.HasFeature(peer, messages.FEATURE_TLV))
 	if err != nil {
 		log.Debugf("poll_service: could not marshal poll msg: %v", err)
 		return
@@ -133,21 +187,31 @@ func (s *Service) Poll(peer string) {
 }
 
 func (s *Service) PollAllPeers() {
+	assets := s.assetCapabilities()
 	for _, peer := range s.peers.GetPeers() {
-		go s.Poll(peer)
+		go s.poll(peer, assets)
 	}
 }
 
 // RequestPoll sends the REUQEST_POLL message to a
 // single peer.
 func (s *Service) RequestPoll(peer string) {
+	s.requestPoll(peer, s.assetCapabilities())
+}
+
+// requestPoll sends the REQUEST_POLL message with the asset capabilities
+// `assets` to a single peer.
+func (s *Service) requestPoll(peer string, assets []AssetCapabilities) {
+	peerAllowed := s.policy.IsPeerAllowed(peer)
 	request := RequestPollMessage{
-		Version:     swap.PEERSWAP_PROTOCOL_VERSION,
-		Assets:      s.assets,
-		PeerAllowed: s.policy.IsPeerAllowed(peer),
+		Version:      swap.PEERSWAP_PROTOCOL_VERSION,
+		Assets:       s.assets,
+		PeerAllowed:  peerAllowed,
+		Features:     supportedFeatures,
+		Capabilities: s.capabilities(peerAllowed, assets),
 	}
 
-	msg, err := json.Marshal(request)
+	msg, err := messages.Marshal(request, s.HasFeature(peer, messages.FEATURE_TLV))
 	if err != nil {
 		log.Debugf("poll_service: could not marshal request_poll msg: %v", err)
 		return
@@ -158,11 +222,54 @@ func (s *Service) RequestPoll(peer string) {
 	}
 }
 
+// capabilities returns the capabilities we announce to a peer. The asset
+// limits are only shared with peers that are allowed to request swaps.
+func (s *Service) capabilities(peerAllowed bool, assets []AssetCapabilities) *Capabilities {
+	c := &Capabilities{
+		Version:       CAPABILITIES_VERSION,
+		AllowNewSwaps: peerAllowed && s.policy.NewSwapsAllowed(),
+		OutputTypes:   []string{"p2wsh"},
+		Encodings:     []string{"json", messages.FEATURE_TLV},
+	}
+	if c.AllowNewSwaps {
+		c.Assets = assets
+	}
+	return c
+}
+
+// assetCapabilities returns the limits of the swaps we currently accept per
+// asset. The maximum swap out amount is rounded down already, so that our
+// peers do not learn our exact balance.
+func (s *Service) assetCapabilities() []AssetCapabilities {
+	s.RLock()
+	limits := s.limits
+	s.RUnlock()
+	if limits == nil {
+		return nil
+	}
+
+	var assets []AssetCapabilities
+	for _, limit := range limits.GetAssetLimits() {
+		var swapTypes []string
+		for _, swapType := range limit.SwapTypes {
+			swapTypes = append(swapTypes, swapType.JsonFieldValue())
+		}
+		assets = append(assets, AssetCapabilities{
+			Asset:               limit.Asset,
+			MinAmountSat:        limit.MinAmountSat,
+			MaxSwapOutAmountSat: limit.MaxSwapOutAmountSat,
+			SwapTypes:           swapTypes,
+		})
+	}
+	return assets
+}
+
 // RequestAllPeerPolls requests the poll message from
 // every peer.
 func (s *Service) RequestAllPeerPolls() {
+	assets := s.assetCapabilities()
 	for _, peer := range s.peers.GetPeers() {
-		go s.RequestPoll(peer)
+		go s.requestPoll(peer, assets)
 	}
 }
 
@@ -175,10 +282,22 @@ func (s *Service) MessageHandler(peerId string, msgType string, payload []byte)
 		return err
 	}
 
+	switch messageType {
+	case messages.MESSAGETYPE_POLL, messages.MESSAGETYPE_REQUEST_POLL:
+		s.RLock()
+		limiter := s.rateLimiter
+		s.RUnlock()
+		if limiter != nil {
+			if err := limiter.AllowMessage(peerId, messageType); err != nil {
+				return err
+			}
+		}
+	}
+
 	switch messageType {
 	case messages.MESSAGETYPE_POLL:
 		var msg PollMessage
-		err = json.Unmarshal(payload, &msg)
+		err = messages.Unmarshal(payload, &msg)
 		if err != nil {
 			return err
 		}
@@ -187,22 +306,25 @@ func (s *Service) MessageHandler(peerId string, msgType string, payload []byte)
 			Assets:          msg.Assets,
 			PeerAllowed:     msg.PeerAllowed,
 			LastSeen:        time.Now(),
+			Features:        msg.Features,
+			Capabilities:    msg.Capabilities,
 		})
+		s.updateMetrics()
 		if ti, ok := s.tmpStore[peerId]; ok {
 			if ti == string(payload) {
 				return nil
 			}
 		}
 		if msg.Version != swap.PEERSWAP_PROTOCOL_VERSION {
-			log.Debugf("Received poll from INCOMPATIBLE peer %s: %s", peerId, string(payload))
+			log.Debugf("Received poll from INCOMPATIBLE peer %s: %s", peerId, messages.PayloadString(payload))
 		} else {
-			log.Debugf("Received poll from peer %s: %s", peerId, string(payload))
+			log.Debugf("Received poll from peer %s: %s", peerId, messages.PayloadString(payload))
 		}
 		s.tmpStore[peerId] = string(payload)
 		return nil
 	case messages.MESSAGETYPE_REQUEST_POLL:
 		var msg RequestPollMessage
-		err = json.Unmarshal([]byte(payload), &msg)
+		err = messages.Unmarshal(payload, &msg)
 		if err != nil {
 			return err
 		}
@@ -211,7 +333,10 @@ func (s *Service) MessageHandler(peerId string, msgType string, payload []byte)
 			Assets:          msg.Assets,
 			PeerAllowed:     msg.PeerAllowed,
 			LastSeen:        time.Now(),
+			Features:        msg.Features,
+			Capabilities:    msg.Capabilities,
 		})
+		s.updateMetrics()
 		// Send a poll on request
 		s.Poll(peerId)
 		if ti, ok := s.tmpStore[peerId]; ok {
@@ -220,9 +345,9 @@ func (s *Service) MessageHandler(peerId string, msgType string, payload []byte)
 			}
 		}
 		if msg.Version != swap.PEERSWAP_PROTOCOL_VERSION {
-			log.Debugf("Received poll from INCOMPATIBLE peer %s: %s", peerId, string(payload))
+			log.Debugf("Received poll from INCOMPATIBLE peer %s: %s", peerId, messages.PayloadString(payload))
 		} else {
-			log.Debugf("Received poll from peer %s: %s", peerId, string(payload))
+			log.Debugf("Received poll from peer %s: %s", peerId, messages.PayloadString(payload))
 		}
 		s.tmpStore[peerId] = string(payload)
 		return nil
@@ -251,6 +376,92 @@ func (s *Service) GetCompatiblePolls() (map[string]PollInfo, error) {
 	return compPeers, nil
 }
 
+// updateMetrics counts the peers in the poll store by their protocol
+// compatibility.
+func (s *Service) updateMetrics() {
+	polls, err := s.store.GetAll()
+	if err != nil {
+		log.Debugf("poll_service: could not get polls: %v", err)
+		return
+	}
+	var compatible, incompatible int
+	for _, p := range polls {
+		if p.ProtocolVersion == swap.PEERSWAP_PROTOCOL_VERSION {
+			compatible++
+		} else {
+			incompatible++
+		}
+	}
+	metrics.SetPollPeers(compatible, incompatible)
+}
+
+// HasFeature returns true if the peer announced the feature in its last poll.
+func (s *Service) HasFeature(peerId, feature string) bool {
+	poll, err := s.GetPollFrom(peerId)
+	if err != nil {
+		return false
+	}
+	for _, f := range poll.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCapabilities returns an ErrSwapNotAccepted if the capabilities the peer
+// announced show that it rejects the swap. Peers that did not announce their
+// capabilities are assumed to accept it.
+func (s *Service) CheckCapabilities(peerId, asset string, swapType swap.SwapType, amountSat uint64) error {
+	poll, err := s.GetPollFrom(peerId)
+	if err != nil {
+		return err
+	}
+	c := poll.Capabilities
+	if c == nil || c.Version != CAPABILITIES_VERSION {
+		return nil
+	}
+	if !c.AllowNewSwaps {
+		return ErrSwapNotAccepted("new swaps are disabled")
+	}
+
+	for _, a := range c.Assets {
+		if a.Asset != asset {
+			continue
+		}
+		if amountSat < a.MinAmountSat {
+			return ErrSwapNotAccepted(fmt.Sprintf("amount is below the minimum of %d sat", a.MinAmountSat))
+		}
+		accepted := false
+		for _, t := range a.SwapTypes {
+			if t == swapType.JsonFieldValue() {
+				accepted = true
+			}
+		}
+		if !accepted {
+			return ErrSwapNotAccepted(fmt.Sprintf("no %s on %s", swapType, asset))
+		}
+		if swapType == swap.SWAPTYPE_OUT && amountSat > a.MaxSwapOutAmountSat {
+			return ErrSwapNotAccepted(fmt.Sprintf("amount is above the maximum swap out of %d sat", a.MaxSwapOutAmountSat))
+		}
+		return nil
+	}
+	return ErrSwapNotAccepted(fmt.Sprintf("asset %s is not supported", asset))
+}
+
+// GetPeerHistory returns the poll history of a peer, or a PollNotFoundErr if
+// we never received a poll from the peer.
+func (s *Service) GetPeerHistory(peerId string) (*PeerHistory, error) {
+	history, err := s.store.GetHistory(peerId)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return nil, PollNotFoundErr(peerId)
+	}
+	return history, nil
+}
+
 // GetPollFrom returns the PollInfo for a single peer with peerId. Returns a
 // PollNotFoundErr if no PollInfo for the peer is present.
 func (s *Service) GetPollFrom(peerId string) (*PollInfo, error) {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/elementsproject/peerswap/messages"
+	"github.com/elementsproject/peerswap/swap"
 	"github.com/stretchr/testify/assert"
 	"go.etcd.io/bbolt"
 )
@@ -50,6 +51,32 @@ func (m *PolicyMock) IsPeerAllowed(peerId string) bool {
 	return m.allowList[m.called-1]
 }
 
+func (m *PolicyMock) NewSwapsAllowed() bool {
+	return true
+}
+
+type SwapLimitsMock struct {
+	limits []swap.AssetLimit
+}
+
+func (m *SwapLimitsMock) GetAssetLimits() []swap.AssetLimit {
+	return m.limits
+}
+
+// RateLimiterMock allows the messages of the peers that are not limited.
+type RateLimiterMock struct {
+	limited map[string]bool
+	checked []messages.MessageType
+}
+
+func (m *RateLimiterMock) AllowMessage(peerId string, msgType messages.MessageType) error {
+	m.checked = append(m.checked, msgType)
+	if m.limited[peerId] {
+		return swap.RateLimitError(peerId)
+	}
+	return nil
+}
+
 func TestSendMessage(t *testing.T) {
 	dir := t.TempDir()
 	db, err := bbolt.Open(path.Join(dir, "poll-db"), os.ModePerm, nil)
@@ -149,6 +176,46 @@ func TestRecievePollAndPollRequest(t *testing.T) {
 	assert.ElementsMatch(t, messenger.peersReceived, []string{"request-peer"})
 }
 
+func TestRateLimitedPolls(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bbolt.Open(path.Join(dir, "poll-db"), os.ModePerm, nil)
+	if err != nil {
+		t.Fatalf("could not open db: %v", err)
+	}
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	messenger := &MessengerMock{}
+	policy := &PolicyMock{allowList: []bool{true}}
+	ps := NewService(500*time.Millisecond, 1*time.Second, store, messenger, policy, &PeerGetterMock{}, []string{"btc"})
+	limiter := &RateLimiterMock{limited: map[string]bool{"limited": true}}
+	ps.SetRateLimiter(limiter)
+
+	pmt := messages.MessageTypeToHexString(messages.MESSAGETYPE_POLL)
+	rpmt := messages.MessageTypeToHexString(messages.MESSAGETYPE_REQUEST_POLL)
+	payload, err := json.Marshal(PollMessage{Version: swap.PEERSWAP_PROTOCOL_VERSION})
+	if err != nil {
+		t.Fatalf("could not marshal poll msg: %v", err)
+	}
+
+	// Limited polls and poll requests are neither stored nor answered.
+	assert.ErrorIs(t, ps.MessageHandler("limited", pmt, payload), swap.RateLimitError("limited"))
+	assert.ErrorIs(t, ps.MessageHandler("limited", rpmt, payload), swap.RateLimitError("limited"))
+	polls, err := store.GetAll()
+	assert.NoError(t, err)
+	assert.Empty(t, polls)
+	assert.Empty(t, messenger.peersReceived)
+
+	// Other messages are not limited by the poll service.
+	assert.NoError(t, ps.MessageHandler("limited", messages.MessageTypeToHexString(messages.MESSAGETYPE_SWAPINREQUEST), payload))
+	assert.Equal(t, []messages.MessageType{messages.MESSAGETYPE_POLL, messages.MESSAGETYPE_REQUEST_POLL}, limiter.checked)
+
+	assert.NoError(t, ps.MessageHandler("peer", rpmt, payload))
+	assert.Equal(t, []string{"peer"}, messenger.peersReceived)
+}
+
 func TestRemoveUnseen(t *testing.T) {
 	dir := t.TempDir()
 	db, err := bbolt.Open(path.Join(dir, "poll-db"), os.ModePerm, nil)
@@ -192,3 +259,220 @@ func TestRemoveUnseen(t *testing.T) {
 
 	assert.Len(t, m, 1)
 }
+
+func TestTlvFeatureNegotiation(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bbolt.Open(path.Join(dir, "poll-db"), os.ModePerm, nil)
+	if err != nil {
+		t.Fatalf("could not open db: %v", err)
+	}
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	messenger := &MessengerMock{}
+	policy := &PolicyMock{allowList: []bool{true, true, true}}
+	peerGetter := &PeerGetterMock{}
+	ps := NewService(500*time.Millisecond, 1*time.Second, store, messenger, policy, peerGetter, []string{"btc"})
+	pmt := messages.MessageTypeToHexString(messages.MESSAGETYPE_POLL)
+
+	// An old peer without features gets json polls.
+	pmp, err := json.Marshal(PollMessage{Version: 3, Assets: []string{"btc"}})
+	if err != nil {
+		t.Fatalf("could not marshal poll msg: %v", err)
+	}
+	ps.MessageHandler("old-peer", pmt, pmp)
+	assert.False(t, ps.HasFeature("old-peer", messages.FEATURE_TLV))
+	ps.Poll("old-peer")
+	assert.Equal(t, byte('{'), messenger.msgReceived[0][0])
+
+	// A peer that announces tlv gets tlv polls.
+	pmp, err = messages.MarshalTLV(PollMessage{Version: 3, Assets: []string{"btc"}, Features: []string{messages.FEATURE_TLV}})
+	if err != nil {
+		t.Fatalf("could not marshal poll msg: %v", err)
+	}
+	ps.MessageHandler("tlv-peer", pmt, pmp)
+	assert.True(t, ps.HasFeature("tlv-peer", messages.FEATURE_TLV))
+	ps.Poll("tlv-peer")
+
+	var msg PollMessage
+	err = messages.UnmarshalTLV(messenger.msgReceived[1], &msg)
+	if err != nil {
+		t.Fatalf("could not unmarshal poll msg: %v", err)
+	}
+	assert.True(t, msg.PeerAllowed)
+	assert.Equal(t, []string{"btc"}, msg.Assets)
+	assert.Equal(t, []string{messages.FEATURE_TLV, messages.FEATURE_ACK}, msg.Features)
+}
+
+func TestCapabilities(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bbolt.Open(path.Join(dir, "poll-db"), os.ModePerm, nil)
+	if err != nil {
+		t.Fatalf("could not open db: %v", err)
+	}
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	messenger := &MessengerMock{}
+	policy := &PolicyMock{allowList: []bool{true, false, true, true}}
+	peerGetter := &PeerGetterMock{}
+	ps := NewService(500*time.Millisecond, 1*time.Second, store, messenger, policy, peerGetter, []string{"btc"})
+	ps.SetSwapLimits(&SwapLimitsMock{limits: []swap.AssetLimit{{
+		Asset:               "btc",
+		MinAmountSat:        100000,
+		MaxSwapOutAmountSat: 500000,
+		SwapTypes:           []swap.SwapType{swap.SWAPTYPE_IN, swap.SWAPTYPE_OUT},
+	}}})
+
+	// Allowed peers get our limits.
+	ps.Poll("peer1")
+	var msg PollMessage
+	err = json.Unmarshal(messenger.msgReceived[0], &msg)
+	if err != nil {
+		t.Fatalf("could not unmarshal poll msg: %v", err)
+	}
+	assert.Equal(t, &Capabilities{
+		Version:       CAPABILITIES_VERSION,
+		AllowNewSwaps: true,
+		OutputTypes:   []string{"p2wsh"},
+		Encodings:     []string{"json", messages.FEATURE_TLV},
+		Assets: []AssetCapabilities{{
+			Asset:               "btc",
+			MinAmountSat:        100000,
+			MaxSwapOutAmountSat: 500000,
+			SwapTypes:           []string{"swap_in", "swap_out"},
+		}},
+	}, msg.Capabilities)
+
+	// Other peers only learn that they can not swap.
+	ps.Poll("peer2")
+	msg = PollMessage{}
+	err = json.Unmarshal(messenger.msgReceived[1], &msg)
+	if err != nil {
+		t.Fatalf("could not unmarshal poll msg: %v", err)
+	}
+	assert.False(t, msg.Capabilities.AllowNewSwaps)
+	assert.Empty(t, msg.Capabilities.Assets)
+
+	// Received capabilities are stored and used to check swaps.
+	pmt := messages.MessageTypeToHexString(messages.MESSAGETYPE_POLL)
+	pmp, err := json.Marshal(PollMessage{
+		Version: swap.PEERSWAP_PROTOCOL_VERSION,
+		Assets:  []string{"btc", "lbtc"},
+		Capabilities: &Capabilities{
+			Version:       CAPABILITIES_VERSION,
+			AllowNewSwaps: true,
+			Assets: []AssetCapabilities{
+				{Asset: "btc", MinAmountSat: 100000, MaxSwapOutAmountSat: 500000, SwapTypes: []string{"swap_in", "swap_out"}},
+				{Asset: "lbtc", MinAmountSat: 100000, SwapTypes: []string{"swap_in"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("could not marshal poll msg: %v", err)
+	}
+	ps.MessageHandler("peer3", pmt, pmp)
+
+	info, err := ps.GetPollFrom("peer3")
+	if err != nil {
+		t.Fatalf("could not get poll: %v", err)
+	}
+	assert.Len(t, info.Capabilities.Assets, 2)
+
+	assert.NoError(t, ps.CheckCapabilities("peer3", "btc", swap.SWAPTYPE_OUT, 200000))
+	assert.NoError(t, ps.CheckCapabilities("peer3", "lbtc", swap.SWAPTYPE_IN, 200000))
+	assert.ErrorAs(t, ps.CheckCapabilities("peer3", "btc", swap.SWAPTYPE_IN, 1000), new(ErrSwapNotAccepted))
+	assert.ErrorAs(t, ps.CheckCapabilities("peer3", "btc", swap.SWAPTYPE_OUT, 600000), new(ErrSwapNotAccepted))
+	assert.ErrorAs(t, ps.CheckCapabilities("peer3", "lbtc", swap.SWAPTYPE_OUT, 200000), new(ErrSwapNotAccepted))
+	assert.ErrorAs(t, ps.CheckCapabilities("peer3", "usdt", swap.SWAPTYPE_IN, 200000), new(ErrSwapNotAccepted))
+}
+
+func TestPeerHistory(t *testing.T) {
+	dir := t.TempDir()
+	db, err := bbolt.Open(path.Join(dir, "poll-db"), os.ModePerm, nil)
+	if err != nil {
+		t.Fatalf("could not open db: %v", err)
+	}
+	store, err := NewStore(db)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	start := time.Date(2022, 1, 1, 0, 30, 0, 0, time.UTC)
+	for i := 0; i < 48; i++ {
+		// The peer is offline during the second day.
+		if i >= 24 && i < 36 {
+			continue
+		}
+		info := PollInfo{ProtocolVersion: 3, Assets: []string{"btc"}, LastSeen: start.Add(time.Duration(i) * time.Hour)}
+		if i >= 36 {
+			info.ProtocolVersion = 4
+			info.Assets = []string{"btc", "lbtc"}
+		}
+		err = store.Update("peer", info)
+		if err != nil {
+			t.Fatalf("could not update store: %v", err)
+		}
+		// A second poll in the same hour does not count twice.
+		err = store.Update("peer", info)
+		if err != nil {
+			t.Fatalf("could not update store: %v", err)
+		}
+	}
+
+	// The history survives removing the unseen peer.
+	err = store.RemoveUnseen(time.Hour)
+	if err != nil {
+		t.Fatalf("could not remove unseen: %v", err)
+	}
+	ps := NewService(500*time.Millisecond, 1*time.Second, store, &MessengerMock{}, &PolicyMock{}, &PeerGetterMock{}, []string{"btc"})
+	history, err := ps.GetPeerHistory("peer")
+	if err != nil {
+		t.Fatalf("could not get history: %v", err)
+	}
+
+	now := start.Add(47 * time.Hour)
+	assert.Equal(t, start, history.FirstSeen.UTC())
+	assert.Equal(t, now, history.LastSeen.UTC())
+	assert.Len(t, history.OnlineHours, 36)
+	assert.Equal(t, 1.0, history.Uptime(now, 12*time.Hour))
+	assert.Equal(t, 36.0/48.0, history.Uptime(now, 30*24*time.Hour))
+
+	assert.Equal(t, []VersionSeen{
+		{Version: 3, FirstSeen: start, LastSeen: start.Add(23 * time.Hour)},
+		{Version: 4, FirstSeen: start.Add(36 * time.Hour), LastSeen: now},
+	}, normalizeVersions(history.Versions))
+	assert.Len(t, history.AssetChanges, 2)
+	assert.Equal(t, []string{"btc", "lbtc"}, history.AssetChanges[1].Assets)
+
+	_, err = ps.GetPeerHistory("unknown")
+	assert.ErrorAs(t, err, new(PollNotFoundErr))
+}
+
+func normalizeVersions(versions []VersionSeen) []VersionSeen {
+	for i := range versions {
+		versions[i].FirstSeen = versions[i].FirstSeen.UTC()
+		versions[i].LastSeen = versions[i].LastSeen.UTC()
+	}
+	return versions
+}
+
+func TestPeerHistoryLimits(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 30, 0, 0, time.UTC)
+	history := &PeerHistory{}
+	for i := 1; i <= maxVersions+5; i++ {
+		now := start.Add(time.Duration(i) * time.Minute)
+		history.Add(PollInfo{ProtocolVersion: uint64(i)}, now)
+		// A version that keeps being announced is not dropped.
+		history.Add(PollInfo{ProtocolVersion: 1}, now.Add(time.Second))
+	}
+
+	assert.Len(t, history.Versions, maxVersions)
+	assert.Equal(t, uint64(1), history.Versions[0].Version)
+	assert.Equal(t, uint64(7), history.Versions[1].Version)
+	assert.Equal(t, uint64(maxVersions+5), history.Versions[len(history.Versions)-1].Version)
+}
@@ -8,6 +8,7 @@ import (
 )
 
 var POLL_BUCKET = []byte("poll-list")
+var POLL_HISTORY_BUCKET = []byte("poll-history")
 
 type pollStore struct {
 	db *bbolt.DB
@@ -23,6 +24,10 @@ func NewStore(db *bbolt.DB) (*pollStore, error) {
 	if err != nil {
 		return nil, err
 	}
+	_, err = tx.CreateBucketIfNotExists(POLL_HISTORY_BUCKET)
+	if err != nil {
+		return nil, err
+	}
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -30,6 +35,8 @@ func NewStore(db *bbolt.DB) (*pollStore, error) {
 	return &pollStore{db: db}, nil
 }
 
+// Update stores the latest poll of the peer and adds it to the history of
+// the peer.
 func (s *pollStore) Update(peerId string, info PollInfo) error {
 	return s.db.Update(func(tx *bbolt.Tx) error {
 		infoBytes, err := json.Marshal(info)
@@ -38,10 +45,46 @@ func (s *pollStore) Update(peerId string, info PollInfo) error {
 		}
 
 		b := tx.Bucket(POLL_BUCKET)
-		return b.Put([]byte(peerId), infoBytes)
+		err = b.Put([]byte(peerId), infoBytes)
+		if err != nil {
+			return err
+		}
+
+		hb := tx.Bucket(POLL_HISTORY_BUCKET)
+		var history PeerHistory
+		if v := hb.Get([]byte(peerId)); v != nil {
+			err = json.Unmarshal(v, &history)
+			if err != nil {
+				return err
+			}
+		}
+		history.Add(info, info.LastSeen)
+		historyBytes, err := json.Marshal(history)
+		if err != nil {
+			return err
+		}
+		return hb.Put([]byte(peerId), historyBytes)
 	})
 }
 
+// GetHistory returns the poll history of the peer. The history is kept when
+// the peer is removed as unseen.
+func (s *pollStore) GetHistory(peerId string) (*PeerHistory, error) {
+	var history *PeerHistory
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(POLL_HISTORY_BUCKET).Get([]byte(peerId))
+		if v == nil {
+			return nil
+		}
+		history = &PeerHistory{}
+		return json.Unmarshal(v, history)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
 func (s *pollStore) GetAll() (map[string]PollInfo, error) {
 	pollinfos := map[string]PollInfo{}
 	err := s.db.View(func(tx *bbolt.Tx) error {
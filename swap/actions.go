@@ -9,12 +9,10 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/elementsproject/peerswap/log"
-
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/elementsproject/peerswap/isdev"
 	"github.com/elementsproject/peerswap/lightning"
-	"github.com/elementsproject/peerswap/messages"
+	"github.com/elementsproject/peerswap/metrics"
 )
 
 const (
@@ -30,58 +28,38 @@ func (a CheckRequestWrapperAction) Execute(services *SwapServices, swap *SwapDat
 	if !services.policy.NewSwapsAllowed() {
 		swap.LastErr = errors.New("swaps are disabled")
 		swap.CancelMessage = "swaps are disabled"
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_SwapsDisabled
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
 	if swap.GetChain() == l_btc_chain && !services.liquidEnabled {
 		swap.LastErr = errors.New("lbtc swaps are not supported")
 		swap.CancelMessage = "lbtc swaps are not supported"
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_ChainDisabled
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
 	if swap.GetChain() == btc_chain && !services.bitcoinEnabled {
 		swap.LastErr = errors.New("btc swaps are not supported")
 		swap.CancelMessage = "btc swaps are not supported"
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_ChainDisabled
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
 	if swap.GetProtocolVersion() != PEERSWAP_PROTOCOL_VERSION {
 		swap.CancelMessage = "incompatible peerswap version"
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_IncompatibleVersion
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
 	if swap.GetAmount()*1000 < services.policy.GetMinSwapAmountMsat() {
 		swap.CancelMessage = ErrMinimumSwapSize(services.policy.GetMinSwapAmountMsat()).Error()
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_AmountTooSmall
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
@@ -92,45 +70,29 @@ func (a CheckRequestWrapperAction) Execute(services *SwapServices, swap *SwapDat
 
 	if swap.GetAsset() != "" && swap.GetAsset() != wallet.GetAsset() {
 		swap.CancelMessage = fmt.Sprintf("invalid liquid asset %s", swap.GetAsset())
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_InvalidAsset
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
 	if swap.GetNetwork() != "" && swap.GetNetwork() != wallet.GetNetwork() {
 		swap.CancelMessage = fmt.Sprintf("invalid bitcoin network %s", swap.GetNetwork())
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_InvalidNetwork
+		addRequestedSwap(services, swap)
 		return swap.HandleError(errors.New(swap.CancelMessage))
 	}
 
 	if !services.policy.IsPeerAllowed(swap.PeerNodeId) {
 		swap.CancelMessage = fmt.Sprintf("peer %s not allowed to request swaps", swap.PeerNodeId)
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_PeerNotAllowed
+		addRequestedSwap(services, swap)
 		return swap.HandleError(PeerNotAllowedError(swap.PeerNodeId))
 	}
 
 	if services.policy.IsPeerSuspicious(swap.PeerNodeId) {
 		swap.CancelMessage = fmt.Sprintf("peer %s not allowed to request swaps", swap.PeerNodeId)
-		services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
-			Asset:           swap.GetChain(),
-			AmountSat:       swap.GetAmount(),
-			Type:            swap.GetType(),
-			RejectionReason: swap.CancelMessage,
-		})
+		swap.CancelCode = CancelCode_PeerSuspicious
+		addRequestedSwap(services, swap)
 		return swap.HandleError(PeerIsSuspiciousError(swap.PeerNodeId))
 	}
 
@@ -138,6 +100,20 @@ func (a CheckRequestWrapperAction) Execute(services *SwapServices, swap *SwapDat
 	return a.next.Execute(services, swap)
 }
 
+// addRequestedSwap stores a rejected swap request and counts the rejection
+// under the label of its cancel code.
+func addRequestedSwap(services *SwapServices, swap *SwapData) {
+	services.requestedSwapsStore.Add(swap.PeerNodeId, RequestedSwap{
+		Asset:           swap.GetChain(),
+		AmountSat:       swap.GetAmount(),
+		Type:            swap.GetType(),
+		RejectionReason: swap.CancelMessage,
+		RejectionCode:   swap.CancelCode,
+		CreatedAt:       time.Now().Unix(),
+	})
+	metrics.SwapRequestRejected(swap.GetType().String(), swap.GetChain(), swap.CancelCode.String())
+}
+
 // todo check for policy / balance
 // SwapInReceiverInitAction creates the swap-in process
 type SwapInReceiverInitAction struct{}
@@ -152,16 +128,14 @@ func (s *SwapInReceiverInitAction) Execute(services *SwapServices, swap *SwapDat
 	}
 	swap.SwapInAgreement = agreementMessage
 
-	nextMessage, nextMessageType, err := MarshalPeerswapMessage(agreementMessage)
+	nextMessage, nextMessageType, err := services.marshalMessage(swap.PeerNodeId, agreementMessage)
 	if err != nil {
 		return swap.HandleError(err)
 	}
 	swap.NextMessage = nextMessage
 	swap.NextMessageType = nextMessageType
 
-	toCtx, cancel := context.WithCancel(context.Background())
-	swap.toCancel = cancel
-	services.toService.addNewTimeOut(toCtx, 10*time.Minute, swap.GetId().String())
+	swap.startTimeout(services, 10*time.Minute)
 
 	return Event_ActionSucceeded
 }
@@ -182,15 +156,35 @@ type ClaimSwapTransactionWithPreimageAction struct{}
 
 // todo this is very critical
 func (s *ClaimSwapTransactionWithPreimageAction) Execute(services *SwapServices, swap *SwapData) EventType {
-	_, wallet, _, err := services.getOnChainServices(swap.GetChain())
+	_, wallet, validator, err := services.getOnChainServices(swap.GetChain())
 	if err != nil {
 		return swap.HandleError(err)
 	}
 
+	// Queue the claim for a batched sweep unless it is time critical. The
+	// batcher continues the swap with Event_OnRetry once it was swept.
+	batcher := services.claimBatcher(swap.GetChain())
+	if swap.ClaimTxId == "" && batcher != nil && swap.ClaimAddress == "" && swap.StartingBlockHeight > 0 && !swap.ClaimSweepFailed {
+		if swap.ClaimQueuedAt == 0 {
+			swap.ClaimQueuedAt = time.Now().Unix()
+		}
+		queued, err := batcher.add(&PreimageClaim{
+			SwapId:        swap.GetId().String(),
+			OpeningParams: swap.GetOpeningParams(),
+			ClaimParams:   swap.GetClaimParams(),
+		}, time.Unix(swap.ClaimQueuedAt, 0), swap.StartingBlockHeight+validator.GetCSVHeight())
+		if err != nil {
+			swap.Logger().Warnf("Error queueing claim %v", err)
+		}
+		if queued {
+			return NoOp
+		}
+	}
+
 	if swap.ClaimTxId == "" {
 		txId, _, err := wallet.CreatePreimageSpendingTransaction(swap.GetOpeningParams(), swap.GetClaimParams())
 		if err != nil {
-			log.Infof("Error claiming tx with preimage %v", err)
+			swap.Logger().Warnf("Error claiming tx with preimage %v", err)
 			return Event_OnRetry
 		}
 		swap.ClaimTxId = txId
@@ -199,6 +193,48 @@ func (s *ClaimSwapTransactionWithPreimageAction) Execute(services *SwapServices,
 	return Event_ActionSucceeded
 }
 
+// AwaitOpeningBatchAction waits for the batched opening tx. A swap whose
+// batch is gone, e.g. after a restart, is opened on its own unless the batch
+// tx was already persisted.
+type AwaitOpeningBatchAction struct{}
+
+func (a *AwaitOpeningBatchAction) Execute(services *SwapServices, swap *SwapData) EventType {
+	if swap.BatchOpeningTxId != "" {
+		return Event_SwapInSender_OnOpeningBatchDone
+	}
+	if services.openingBatches.isQueued(swap.OpeningBatchId, swap.GetId().String()) {
+		return NoOp
+	}
+	swap.cancelTimeout()
+	swap.OpeningBatchId = ""
+	return Event_SwapInSender_OnOpeningBatchDone
+}
+
+// LeaveOpeningBatchAction takes a canceled or timed out swap out of its
+// batch. If the batch tx was already persisted the opening output of the swap
+// may be on chain, it is reclaimed once the csv limit passed.
+type LeaveOpeningBatchAction struct{}
+
+func (a *LeaveOpeningBatchAction) Execute(services *SwapServices, swap *SwapData) EventType {
+	if swap.BatchOpeningTxId == "" {
+		services.openingBatches.remove(swap.OpeningBatchId, swap.GetId().String())
+		if swap.Cancel != nil {
+			return Event_OnCancelReceived
+		}
+		return Event_ActionFailed
+	}
+
+	swap.cancelTimeout()
+	swap.OpeningTxHex = swap.BatchOpeningTxHex
+	swap.OpeningTxBroadcasted = &OpeningTxBroadcastedMessage{
+		SwapId:      swap.GetId(),
+		TxId:        swap.BatchOpeningTxId,
+		ScriptOut:   swap.BatchOpeningVout,
+		BlindingKey: swap.BlindingKeyHex,
+	}
+	return Event_ActionSucceeded
+}
+
 type CreateAndBroadcastOpeningTransaction struct{}
 
 func (c *CreateAndBroadcastOpeningTransaction) Execute(services *SwapServices, swap *SwapData) EventType {
@@ -211,16 +247,16 @@ func (c *CreateAndBroadcastOpeningTransaction) Execute(services *SwapServices, s
 		return Event_ActionSucceeded
 	}
 
-	// Generate Preimage
-	preimage, err := lightning.GetPreimage()
-	if err != nil {
-		return swap.HandleError(err)
+	// Generate Preimage. An externally funded swap keeps its preimage, the
+	// opening output of the PSBT commits to its hash.
+	if swap.ClaimPreimage == "" {
+		preimage, err := lightning.GetPreimage()
+		if err != nil {
+			return swap.HandleError(err)
+		}
+		swap.ClaimPreimage = hex.EncodeToString(preimage[:])
 	}
-	swap.ClaimPreimage = hex.EncodeToString(preimage[:])
-
-	// Construct memo
-	memo := fmt.Sprintf("peerswap %s %s %s %s", swap.GetChain(), INVOICE_CLAIM, swap.GetScidInBoltFormat(), swap.GetId())
-	payreq, err := services.lightning.GetPayreq((swap.GetAmount())*1000, preimage.String(), swap.GetId().String(), memo, INVOICE_CLAIM, swap.GetInvoiceExpiry(), swap.GetInvoiceCltv())
+	preimage, err := lightning.MakePreimageFromStr(swap.ClaimPreimage)
 	if err != nil {
 		return swap.HandleError(err)
 	}
@@ -232,28 +268,98 @@ func (c *CreateAndBroadcastOpeningTransaction) Execute(services *SwapServices, s
 		blindingKeyHex = hex.EncodeToString(blindingKey.Serialize())
 	}
 
-	// Create the opening transaction
-	txHex, _, vout, err := wallet.CreateOpeningTransaction(&OpeningParams{
+	openingParams := &OpeningParams{
 		TakerPubkey:      swap.GetTakerPubkey(),
 		MakerPubkey:      swap.GetMakerPubkey(),
 		ClaimPaymentHash: preimage.Hash().String(),
 		Amount:           swap.GetAmount(),
 		BlindingKey:      blindingKey,
-	})
-	if err != nil {
-		return swap.HandleError(err)
 	}
 
-	txId, txHex, err := wallet.BroadcastOpeningTx(txHex)
-	if err != nil {
-		// todo: idempotent states
-		return swap.HandleError(err)
+	// Hand out a PSBT to the operator and wait for the signed tx.
+	if swap.ExternalFunding && swap.ExternalOpeningTxHex == "" {
+		psbt, err := wallet.CreateOpeningPsbt(openingParams)
+		if err != nil {
+			return swap.HandleError(err)
+		}
+		swap.OpeningPsbt = psbt
+		// The policy keeps the timeout below the 10 minutes the peer waits
+		// after it sent the agreement, so that we do not broadcast a tx
+		// for a swap the peer already canceled.
+		timeout := time.Duration(services.policy.GetExternalFundingTimeoutSec()) * time.Second
+		swap.cancelTimeout()
+		swap.startTimeout(services, timeout)
+		return Event_SwapInSender_OnAwaitExternalFunding
+	}
+
+	// The batch tx is persisted before it is broadcast. Publish it again in
+	// case we went down in between. If the node rejects a tx that it never
+	// accepted, the swap is opened on its own.
+	if swap.BatchOpeningTxId != "" {
+		if _, err := wallet.BroadcastTx(swap.BatchOpeningTxHex); err != nil {
+			if swap.BatchOpeningTxPublished || !txRejected(err) {
+				swap.Logger().Debugf("Rebroadcasting batch opening tx %s: %v", swap.BatchOpeningTxId, err)
+			} else {
+				swap.Logger().Infof("Batch opening tx %s was rejected, opening the swap on its own: %v", swap.BatchOpeningTxId, err)
+				releaseBatchOpeningTx(wallet, swap.BatchOpeningTxId, swap.BatchOpeningTxHex)
+				swap.resetBatchOpeningTx()
+				swap.OpeningBatchId = ""
+			}
+		}
 	}
-	startingHeight, err := txWatcher.GetBlockHeight()
+
+	// Add the opening output to the batch and wait for the batched tx. A swap
+	// that is no longer part of its batch is opened on its own.
+	if swap.OpeningBatchId != "" && swap.BatchOpeningTxId == "" {
+		if services.openingBatches.add(swap.OpeningBatchId, swap.GetId().String(), openingParams) {
+			swap.cancelTimeout()
+			swap.startTimeout(services, openingBatchTimeout)
+			return Event_SwapInSender_OnAwaitOpeningBatch
+		}
+		swap.OpeningBatchId = ""
+	}
+
+	// Construct memo
+	memo := fmt.Sprintf("peerswap %s %s %s %s", swap.GetChain(), INVOICE_CLAIM, swap.GetScidInBoltFormat(), swap.GetId())
+	payreq, err := services.lightning.GetPayreq((swap.GetAmount())*1000, preimage.String(), swap.GetId().String(), memo, INVOICE_CLAIM, swap.GetInvoiceExpiry(), swap.GetInvoiceCltv())
 	if err != nil {
 		return swap.HandleError(err)
 	}
-	swap.StartingBlockHeight = startingHeight
+
+	var txId, txHex string
+	var vout uint32
+	if swap.BatchOpeningTxId != "" {
+		swap.cancelTimeout()
+		txId, txHex, vout = swap.BatchOpeningTxId, swap.BatchOpeningTxHex, swap.BatchOpeningVout
+	} else if swap.ExternalFunding {
+		swap.cancelTimeout()
+		txHex, vout = swap.ExternalOpeningTxHex, swap.ExternalOpeningVout
+		txId, err = wallet.BroadcastTx(txHex)
+		if err != nil {
+			return swap.HandleError(err)
+		}
+	} else {
+		// Create the opening transaction
+		var unpreparedTxHex string
+		unpreparedTxHex, _, vout, err = wallet.CreateOpeningTransaction(openingParams)
+		if err != nil {
+			return swap.HandleError(err)
+		}
+
+		txId, txHex, err = wallet.BroadcastOpeningTx(unpreparedTxHex)
+		if err != nil {
+			// todo: idempotent states
+			return swap.HandleError(err)
+		}
+	}
+	// The batched opening tx keeps the height it was persisted at.
+	if swap.BatchOpeningTxId == "" || swap.StartingBlockHeight == 0 {
+		startingHeight, err := txWatcher.GetBlockHeight()
+		if err != nil {
+			return swap.HandleError(err)
+		}
+		swap.StartingBlockHeight = startingHeight
+	}
 
 	swap.OpeningTxHex = txHex
 
@@ -267,7 +373,7 @@ func (c *CreateAndBroadcastOpeningTransaction) Execute(services *SwapServices, s
 
 	swap.OpeningTxBroadcasted = message
 
-	nextMessage, nextMessageType, err := MarshalPeerswapMessage(message)
+	nextMessage, nextMessageType, err := services.marshalMessage(swap.PeerNodeId, message)
 	if err != nil {
 		return swap.HandleError(err)
 	}
@@ -284,7 +390,7 @@ type StopSendMessageWithRetryWrapperAction struct {
 
 func (a StopSendMessageWithRetryWrapperAction) Execute(services *SwapServices, swap *SwapData) EventType {
 	// Stop sending repeated messages
-	services.messengerManager.RemoveSender(swap.GetId().String())
+	services.outbox.Remove(swap.GetId().String())
 
 	// Call next Action
 	return a.next.Execute(services, swap)
@@ -293,7 +399,7 @@ func (a StopSendMessageWithRetryWrapperAction) Execute(services *SwapServices, s
 // AwaitPaymentOrCsvAction checks if the invoice has been paid
 type AwaitPaymentOrCsvAction struct{}
 
-//todo this will never throw an error
+// todo this will never throw an error
 func (w *AwaitPaymentOrCsvAction) Execute(services *SwapServices, swap *SwapData) EventType {
 	onchain, wallet, _, err := services.getOnChainServices(swap.GetChain())
 	if err != nil {
@@ -325,7 +431,7 @@ func (w *AwaitFeeInvoicePayment) Execute(services *SwapServices, swap *SwapData)
 // AwaitCsvAction adds the opening tx to the txwatcher
 type AwaitCsvAction struct{}
 
-//todo this will never throw an error
+// todo this will never throw an error
 func (w *AwaitCsvAction) Execute(services *SwapServices, swap *SwapData) EventType {
 	onchain, wallet, _, err := services.getOnChainServices(swap.GetChain())
 	if err != nil {
@@ -357,6 +463,11 @@ func (a *SetBlindingKeyActionWrapper) Execute(services *SwapServices, swap *Swap
 	return a.next.Execute(services, swap)
 }
 
+// swapOutSafetyNet is kept in the wallet on top of the swap amount and fees
+// when we fund a swap out.
+// TODO: this should be looked at in the future
+const swapOutSafetyNet = uint64(20000)
+
 // CreateSwapOutFromRequestAction creates the swap-out process and prepares the opening transaction
 type CreateSwapOutFromRequestAction struct{}
 
@@ -379,10 +490,8 @@ func (c *CreateSwapOutFromRequestAction) Execute(services *SwapServices, swap *S
 		return swap.HandleError(err)
 	}
 
-	// TODO: this should be looked at in the future
-	safetynet := uint64(20000)
-
-	if walletBalance < swap.GetAmount()+openingFee+safetynet {
+	if walletBalance < swap.GetAmount()+openingFee+swapOutSafetyNet {
+		swap.CancelCode = CancelCode_InsufficientBalance
 		return swap.HandleError(errors.New("insufficient walletbalance"))
 	}
 
@@ -407,16 +516,14 @@ func (c *CreateSwapOutFromRequestAction) Execute(services *SwapServices, swap *S
 	}
 	swap.SwapOutAgreement = message
 
-	nextMessage, nextMessageType, err := MarshalPeerswapMessage(message)
+	nextMessage, nextMessageType, err := services.marshalMessage(swap.PeerNodeId, message)
 	if err != nil {
 		return swap.HandleError(err)
 	}
 	swap.NextMessage = nextMessage
 	swap.NextMessageType = nextMessageType
 
-	toCtx, cancel := context.WithCancel(context.Background())
-	swap.toCancel = cancel
-	services.toService.addNewTimeOut(toCtx, 10*time.Minute, swap.GetId().String())
+	swap.startTimeout(services, 10*time.Minute)
 
 	return Event_ActionSucceeded
 }
@@ -474,13 +581,14 @@ type SendCancelAction struct{}
 
 func (s *SendCancelAction) Execute(services *SwapServices, swap *SwapData) EventType {
 	if swap.LastErr != nil {
-		log.Debugf("[FSM] Canceling because of %s", swap.LastErr.Error())
+		swap.Logger().Debugf("[FSM] Canceling because of %s", swap.LastErr.Error())
 	}
 	messenger := services.messenger
 
-	msgBytes, msgType, err := MarshalPeerswapMessage(&CancelMessage{
+	msgBytes, msgType, err := services.marshalMessage(swap.PeerNodeId, &CancelMessage{
 		SwapId:  swap.GetId(),
 		Message: swap.CancelMessage,
+		Code:    swap.CancelCode,
 	})
 	if err != nil {
 		return swap.HandleError(err)
@@ -498,7 +606,7 @@ type TakerSendPrivkeyAction struct{}
 
 func (s *TakerSendPrivkeyAction) Execute(services *SwapServices, swap *SwapData) EventType {
 	privkeystring := hex.EncodeToString(swap.PrivkeyBytes)
-	nextMessage, nextMessageType, err := MarshalPeerswapMessage(&CoopCloseMessage{
+	nextMessage, nextMessageType, err := services.marshalMessage(swap.PeerNodeId, &CoopCloseMessage{
 		SwapId:  swap.GetId(),
 		Message: swap.CancelMessage,
 		Privkey: privkeystring,
@@ -515,9 +623,9 @@ func (s *TakerSendPrivkeyAction) Execute(services *SwapServices, swap *SwapData)
 // SwapInSenderCreateSwapAction creates the swap data
 type CreateSwapRequestAction struct{}
 
-//todo validate data
+// todo validate data
 func (a *CreateSwapRequestAction) Execute(services *SwapServices, swap *SwapData) EventType {
-	nextMessage, nextMessageType, err := MarshalPeerswapMessage(swap.GetRequest())
+	nextMessage, nextMessageType, err := services.marshalMessage(swap.PeerNodeId, swap.GetRequest())
 	if err != nil {
 		return swap.HandleError(err)
 	}
@@ -525,9 +633,7 @@ func (a *CreateSwapRequestAction) Execute(services *SwapServices, swap *SwapData
 	swap.NextMessage = nextMessage
 	swap.NextMessageType = nextMessageType
 
-	toCtx, cancel := context.WithCancel(context.Background())
-	swap.toCancel = cancel
-	services.toService.addNewTimeOut(toCtx, 10*time.Minute, swap.GetId().String())
+	swap.startTimeout(services, 10*time.Minute)
 
 	return Event_ActionSucceeded
 }
@@ -553,13 +659,17 @@ func (s *SendMessageWithRetryAction) Execute(services *SwapServices, swap *SwapD
 		return swap.HandleError(errors.New("swap.NextMessage is nil"))
 	}
 
-	// Send message repeated as we really want the message to be received at some point!
-	rm := messages.NewRedundantMessenger(services.messenger, 10*time.Second)
-	err := services.messengerManager.AddSender(swap.GetId().String(), rm)
+	err := services.messenger.SendMessage(swap.PeerNodeId, swap.NextMessage, swap.NextMessageType)
+	if err != nil {
+		return swap.HandleError(err)
+	}
+
+	// Resend the message until it is acknowledged as we really want the
+	// message to be received at some point!
+	err = services.outbox.Add(swap.GetId().String(), swap.PeerNodeId, swap.NextMessage, swap.NextMessageType)
 	if err != nil {
 		return swap.HandleError(err)
 	}
-	rm.SendMessage(swap.PeerNodeId, swap.NextMessage, swap.NextMessageType)
 
 	return Event_ActionSucceeded
 }
@@ -582,17 +692,23 @@ func (r *PayFeeInvoiceAction) Execute(services *SwapServices, swap *SwapData) Ev
 
 	swap.OpeningTxFee = msatAmt / 1000
 
+	// Estimate the opening tx fee of the peer with our own estimator and the
+	// expected size of the opening tx.
 	expectedFee, err := wallet.GetFlatSwapOutFee()
 	if err != nil {
 		swap.LastErr = err
 		return swap.HandleError(err)
 	}
-
-	maxExpected := uint64((float64(expectedFee) * 3))
+	swap.ExpectedOpeningTxFee = expectedFee
 
 	// if the fee invoice is larger than what we would expect, don't pay
-	if swap.OpeningTxFee > maxExpected {
-		return swap.HandleError(errors.New(fmt.Sprintf("Fee is too damn high. Max expected: %v Received %v", maxExpected, swap.OpeningTxFee)))
+	maxFee, limit := maxFeeInvoice(services.policy, expectedFee)
+	if swap.OpeningTxFee > maxFee {
+		swap.CancelCode = CancelCode_FeeTooHigh
+		// Keep the overcharge for the reputation of the peer.
+		swap.FeeOvercharge = swap.OpeningTxFee - maxFee
+		swap.Logger().Infof("[FSM] rejecting fee invoice of %d sat, it exceeds %s by %d sat", swap.OpeningTxFee, limit, swap.FeeOvercharge)
+		return swap.HandleError(fmt.Errorf("fee invoice of %d sat exceeds %s", swap.OpeningTxFee, limit))
 	}
 
 	preimage, err := ll.PayInvoiceViaChannel(swap.SwapOutAgreement.Payreq, swap.GetScid())
@@ -603,11 +719,25 @@ func (r *PayFeeInvoiceAction) Execute(services *SwapServices, swap *SwapData) Ev
 	return Event_ActionSucceeded
 }
 
+// maxFeeInvoice returns the largest fee invoice in sat that we pay for an
+// opening tx whose fee we estimate at expectedFee, and the limit that set it.
+func maxFeeInvoice(policy Policy, expectedFee uint64) (uint64, string) {
+	multiple := policy.GetMaxFeeInvoiceMultiple()
+	maxFee := uint64(float64(expectedFee) * multiple)
+	limit := fmt.Sprintf("%gx our opening tx fee estimate of %d sat", multiple, expectedFee)
+
+	if maxSat := policy.GetMaxFeeInvoiceSat(); maxSat > 0 && maxSat < maxFee {
+		maxFee = maxSat
+		limit = fmt.Sprintf("the fee invoice cap of %d sat", maxSat)
+	}
+	return maxFee, limit
+}
+
 // AwaitTxConfirmationAction  checks the claim invoice and adds the transaction
 // to the txwatcher.
 type AwaitTxConfirmationAction struct{}
 
-//todo this will not ever throw an error
+// todo this will not ever throw an error
 func (t *AwaitTxConfirmationAction) Execute(services *SwapServices, swap *SwapData) EventType {
 	txWatcher, wallet, validator, err := services.getOnChainServices(swap.GetChain())
 	if err != nil {
@@ -638,10 +768,12 @@ func (t *AwaitTxConfirmationAction) Execute(services *SwapServices, swap *SwapDa
 	// accepting it.
 	if (swap.GetChain() == btc_chain && expiry > BitcoinCsv/2) ||
 		(swap.GetChain() == l_btc_chain && expiry > LiquidCsv/2) {
+		swap.CancelCode = CancelCode_InvalidInvoice
 		return swap.HandleError(fmt.Errorf("unsafe invoice cltv: %d", expiry))
 	}
 
 	if msatAmount != swap.GetAmount()*1000 {
+		swap.CancelCode = CancelCode_InvalidInvoice
 		return swap.HandleError(fmt.Errorf("invoice amount does not equal swap amount, invoice: %v, swap %v", swap.OpeningTxBroadcasted.Payreq, swap.GetAmount()))
 	}
 
@@ -652,7 +784,7 @@ func (t *AwaitTxConfirmationAction) Execute(services *SwapServices, swap *SwapDa
 		return swap.HandleError(err)
 	}
 	txWatcher.AddWaitForConfirmationTx(swap.GetId().String(), swap.OpeningTxBroadcasted.TxId, swap.OpeningTxBroadcasted.ScriptOut, swap.StartingBlockHeight, wantScript)
-	log.Debugf("Await confirmation for tx with id: %s on swap %s", swap.OpeningTxBroadcasted.TxId, swap.GetId().String())
+	swap.Logger().Debugf("Await confirmation for tx with id: %s", swap.OpeningTxBroadcasted.TxId)
 	return NoOp
 }
 
@@ -672,6 +804,7 @@ func (p *ValidateTxAndPayClaimInvoiceAction) Execute(services *SwapServices, swa
 		return swap.HandleError(err)
 	}
 	if !ok {
+		swap.CancelCode = CancelCode_InvalidTx
 		return swap.HandleError(errors.New("tx is not valid"))
 	}
 
@@ -683,7 +816,7 @@ func (p *ValidateTxAndPayClaimInvoiceAction) Execute(services *SwapServices, swa
 		if prtStr := os.Getenv("PAYMENT_RETRY_TIME"); prtStr != "" {
 			prtInt, err := strconv.Atoi(prtStr)
 			if err != nil {
-				log.Debugf("could not read from PAYMENT_RETRY_TIME")
+				swap.Logger().Debugf("could not read from PAYMENT_RETRY_TIME")
 			} else {
 				retryTime = time.Duration(prtInt) * time.Second
 			}
@@ -697,15 +830,30 @@ func (p *ValidateTxAndPayClaimInvoiceAction) Execute(services *SwapServices, swa
 	ctx, cancel := context.WithTimeout(context.Background(), retryTime)
 	defer cancel()
 
+	// Stop paying if the opening tx is reorged out of the chain while we
+	// retry, the swap waits for the tx to confirm again.
+	reorged := services.reorgs.wait(swap.GetId().String())
+	defer services.reorgs.reset(swap.GetId().String())
+	// Stop paying if the maker spent the opening output in the meantime.
+	spent := services.spends.wait(swap.GetId().String())
+	defer services.spends.reset(swap.GetId().String())
+
 	var preimage string
 	for {
 		select {
+		case <-reorged:
+			swap.Logger().Infof("opening tx %s was reorged out of the chain, await confirmation before paying the claim invoice", swap.GetOpeningTxId())
+			return Event_OnTxReorged
+		case <-spent:
+			swap.HandleError(fmt.Errorf("opening tx %s was spent by the maker", swap.GetOpeningTxId()))
+			swap.Logger().Warnf("%s, stop paying the claim invoice", swap.LastErrString)
+			return Event_OnOpeningTxSpent
 		case <-ctx.Done():
 			return swap.HandleError(fmt.Errorf("could not pay invoice, last err %w", err))
 		case <-ticker.C:
 			preimage, err = lc.RebalancePayment(swap.OpeningTxBroadcasted.Payreq, swap.GetScid())
 			if err != nil {
-				log.Infof("error trying to pay invoice: %v, retry...", err)
+				swap.Logger().Warnf("error trying to pay invoice: %v, retry...", err)
 				// Another round!
 				continue
 			}
@@ -739,6 +887,7 @@ func (s *SetStartingBlockHeightAction) Execute(services *SwapServices, swap *Swa
 	} else if now >= swap.StartingBlockHeight+(validator.GetCSVHeight()/2) {
 		swap.LastErr = fmt.Errorf("too close to csv")
 		swap.CancelMessage = swap.LastErr.Error()
+		swap.CancelCode = CancelCode_TooCloseToCsv
 		return Event_ActionFailed
 	}
 
@@ -754,8 +903,8 @@ func (n *NoOpAction) Execute(services *SwapServices, swap *SwapData) EventType {
 type NoOpDoneAction struct{}
 
 func (a *NoOpDoneAction) Execute(services *SwapServices, swap *SwapData) EventType {
-	// Remove possible message sender
-	services.messengerManager.RemoveSender(swap.GetId().String())
+	// Remove possible messages from the outbox
+	services.outbox.Remove(swap.GetId().String())
 
 	return Event_Done
 }
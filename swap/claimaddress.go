@@ -0,0 +1,106 @@
+package swap
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+)
+
+var claimAddressesBucket = []byte("claim-addresses")
+
+// ClaimAddressDeriver derives the address at an index of a ranged output
+// descriptor.
+type ClaimAddressDeriver interface {
+	Address(index uint32) (string, error)
+}
+
+// ClaimAddresses hands out fresh addresses of a descriptor that swap-outs
+// can be claimed to. The next index is persisted so that no address is
+// handed out twice.
+type ClaimAddresses struct {
+	db      *bbolt.DB
+	deriver ClaimAddressDeriver
+	// key identifies the descriptor, it is the address at index 0. The
+	// index starts over if the descriptor is changed.
+	key []byte
+}
+
+func NewClaimAddresses(db *bbolt.DB, deriver ClaimAddressDeriver) (*ClaimAddresses, error) {
+	first, err := deriver.Address(0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(claimAddressesBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClaimAddresses{db: db, deriver: deriver, key: []byte(first)}, nil
+}
+
+// NewAddress returns the address at the next unused index.
+func (c *ClaimAddresses) NewAddress() (string, error) {
+	var addr string
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(claimAddressesBucket)
+
+		var index uint32
+		if v := b.Get(c.key); v != nil {
+			index = binary.BigEndian.Uint32(v)
+		}
+
+		var err error
+		addr, err = c.deriver.Address(index)
+		if err != nil {
+			return err
+		}
+
+		next := make([]byte, 4)
+		binary.BigEndian.PutUint32(next, index+1)
+		return b.Put(c.key, next)
+	})
+	if err != nil {
+		return "", err
+	}
+	return addr, nil
+}
+
+// SetClaimAddresses sets the descriptor addresses that swap-outs on chain
+// are claimed to on request.
+func (s *SwapService) SetClaimAddresses(chain string, addresses *ClaimAddresses) {
+	s.Lock()
+	defer s.Unlock()
+	s.claimAddresses[chain] = addresses
+}
+
+// NewClaimAddress returns a fresh address of the claim descriptor of chain.
+func (s *SwapService) NewClaimAddress(chain string) (string, error) {
+	s.RLock()
+	addresses, ok := s.claimAddresses[chain]
+	s.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("no claim descriptor configured for %s", chain)
+	}
+	return addresses.NewAddress()
+}
+
+// validateClaimAddress returns an error if address can not be used to claim
+// a swap on chain.
+func (s *SwapService) validateClaimAddress(chain string, address string) error {
+	if (chain == btc_chain && !s.BitcoinEnabled) || (chain == l_btc_chain && !s.LiquidEnabled) {
+		return fmt.Errorf("%s swaps are not enabled", chain)
+	}
+	_, wallet, _, err := s.swapServices.getOnChainServices(chain)
+	if err != nil {
+		return err
+	}
+	if err := wallet.ValidateAddress(address); err != nil {
+		return fmt.Errorf("invalid claim address %s: %w", address, err)
+	}
+	return nil
+}
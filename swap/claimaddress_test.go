@@ -0,0 +1,96 @@
+package swap
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/elementsproject/peerswap/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+type claimAddressDeriverStub struct {
+	prefix string
+}
+
+func (c *claimAddressDeriverStub) Address(index uint32) (string, error) {
+	return fmt.Sprintf("%s-%d", c.prefix, index), nil
+}
+
+func TestClaimAddresses(t *testing.T) {
+	db, err := bbolt.Open(path.Join(t.TempDir(), "swaps"), 0700, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	addresses, err := NewClaimAddresses(db, &claimAddressDeriverStub{prefix: "a"})
+	require.NoError(t, err)
+	for _, want := range []string{"a-0", "a-1"} {
+		addr, err := addresses.NewAddress()
+		require.NoError(t, err)
+		assert.Equal(t, want, addr)
+	}
+
+	// The index is persisted.
+	addresses, err = NewClaimAddresses(db, &claimAddressDeriverStub{prefix: "a"})
+	require.NoError(t, err)
+	addr, err := addresses.NewAddress()
+	require.NoError(t, err)
+	assert.Equal(t, "a-2", addr)
+
+	// Another descriptor starts at 0.
+	addresses, err = NewClaimAddresses(db, &claimAddressDeriverStub{prefix: "b"})
+	require.NoError(t, err)
+	addr, err = addresses.NewAddress()
+	require.NoError(t, err)
+	assert.Equal(t, "b-0", addr)
+
+	// The swap service hands out the addresses per chain.
+	service := getTestSetup("alice")
+	_, err = service.NewClaimAddress(btc_chain)
+	assert.Error(t, err)
+	service.SetClaimAddresses(btc_chain, addresses)
+	addr, err = service.NewClaimAddress(btc_chain)
+	require.NoError(t, err)
+	assert.Equal(t, "b-1", addr)
+}
+
+func Test_SwapOutClaimAddress(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService := getTestSetup(initiator)
+	bobSwapService := getTestSetup(peer)
+	aliceSwapService.swapServices.messenger.(*ConnectedMessenger).other = bobSwapService.swapServices.messenger.(*ConnectedMessenger)
+	bobSwapService.swapServices.messenger.(*ConnectedMessenger).other = aliceSwapService.swapServices.messenger.(*ConnectedMessenger)
+
+	aliceSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = make(chan messages.MessageType)
+	bobSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = make(chan messages.MessageType)
+
+	aliceMsgChan := aliceSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan
+	bobMsgChan := bobSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan
+
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+
+	// Invalid addresses are rejected before the swap starts.
+	_, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "invalid")
+	assert.Error(t, err)
+	assert.Empty(t, aliceSwapService.activeSwaps)
+
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "claim-address")
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, <-bobMsgChan)
+	bobSwap := bobSwapService.activeSwaps[aliceSwap.SwapId.String()]
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTAGREEMENT, <-aliceMsgChan)
+	bobSwapService.swapServices.lightning.(*dummyLightningClient).TriggerPayment(bobSwap.SwapId.String(), INVOICE_FEE)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-aliceMsgChan)
+
+	err = aliceSwapService.swapServices.bitcoinTxWatcher.(*dummyChain).txConfirmedFunc(aliceSwap.SwapId.String(), aliceSwap.Data.OpeningTxHex)
+	require.NoError(t, err)
+	assert.Equal(t, State_ClaimedPreimage, aliceSwap.Current)
+	assert.Equal(t, "claim-address", aliceSwap.Data.ClaimAddress)
+	assert.Equal(t, "claim-address", aliceSwapService.swapServices.bitcoinWallet.(*dummyChain).claimAddress)
+}
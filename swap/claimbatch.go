@@ -0,0 +1,270 @@
+package swap
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elementsproject/peerswap/log"
+)
+
+const (
+	DefaultClaimBatchMaxDelay  = 6 * time.Hour
+	DefaultClaimBatchCsvMargin = 144
+
+	// claimBatchInterval is the interval in which the batcher checks if a
+	// queued claim is due.
+	claimBatchInterval = time.Minute
+)
+
+// ClaimSweeper spends the opening outputs of several swaps with their
+// preimages in a single sweep tx.
+type ClaimSweeper interface {
+	SweepPreimageClaims(claims []*PreimageClaim) (txId, txHex string, err error)
+}
+
+// PreimageClaim is the claim of the opening output of a swap with the
+// preimage.
+type PreimageClaim struct {
+	SwapId        string
+	OpeningParams *OpeningParams
+	ClaimParams   *ClaimParams
+}
+
+// ClaimBatchConfig bounds how long claims are queued for a batch.
+type ClaimBatchConfig struct {
+	// MaxDelay is the longest time a claim is queued. The batch is swept
+	// when the first of its claims reaches the delay.
+	MaxDelay time.Duration
+	// CsvSafetyMargin is the number of blocks before the csv limit of the
+	// opening tx at which the maker can reclaim the output. A claim that is
+	// this close to the limit is not queued, a queued batch is swept.
+	CsvSafetyMargin uint32
+}
+
+type queuedClaim struct {
+	claim    *PreimageClaim
+	deadline time.Time
+	csvLimit uint32
+}
+
+// ClaimBatcher queues the preimage claims of swaps that are not time
+// critical and spends them together in a single sweep tx. Only the bitcoin
+// wallets implement ClaimSweeper, liquid claims are not batched. The queue is not
+// persisted, queued swaps stay in their claim state and are queued again on
+// recovery.
+type ClaimBatcher struct {
+	sync.Mutex
+	config    ClaimBatchConfig
+	sweeper   ClaimSweeper
+	txWatcher TxWatcher
+	queue     map[string]*queuedClaim
+
+	// onSwept is called with the swaps of a sweep and the txid of the sweep
+	// tx, the txid is empty if the sweep failed.
+	onSwept func(swapIds []string, txId string)
+
+	// sweepMu serializes the sweeps.
+	sweepMu sync.Mutex
+	stop    chan struct{}
+}
+
+func NewClaimBatcher(sweeper ClaimSweeper, config ClaimBatchConfig) *ClaimBatcher {
+	return &ClaimBatcher{
+		config:  config,
+		sweeper: sweeper,
+		queue:   map[string]*queuedClaim{},
+	}
+}
+
+// Start checks for due claims in the background.
+func (b *ClaimBatcher) Start() {
+	b.Lock()
+	defer b.Unlock()
+	if b.stop != nil {
+		return
+	}
+	b.stop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(claimBatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				b.sweep(false)
+			}
+		}
+	}(b.stop)
+}
+
+func (b *ClaimBatcher) Stop() {
+	b.Lock()
+	defer b.Unlock()
+	if b.stop != nil {
+		close(b.stop)
+		b.stop = nil
+	}
+}
+
+// Flush sweeps all queued claims right away.
+func (b *ClaimBatcher) Flush() {
+	b.sweep(true)
+}
+
+// add queues a claim that was first queued at queuedAt. It returns false if
+// the claim is due and must be claimed directly.
+func (b *ClaimBatcher) add(claim *PreimageClaim, queuedAt time.Time, csvLimit uint32) (bool, error) {
+	height, err := b.txWatcher.GetBlockHeight()
+	if err != nil {
+		return false, err
+	}
+	q := &queuedClaim{
+		claim:    claim,
+		deadline: queuedAt.Add(b.config.MaxDelay),
+		csvLimit: csvLimit,
+	}
+	if b.isDue(q, time.Now(), height) {
+		return false, nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	b.queue[claim.SwapId] = q
+	return true, nil
+}
+
+func (b *ClaimBatcher) isDue(q *queuedClaim, now time.Time, height uint32) bool {
+	return !now.Before(q.deadline) || height+b.config.CsvSafetyMargin >= q.csvLimit
+}
+
+// sweep spends all queued claims if one of them is due or force is set.
+func (b *ClaimBatcher) sweep(force bool) {
+	b.sweepMu.Lock()
+	defer b.sweepMu.Unlock()
+
+	height, err := b.txWatcher.GetBlockHeight()
+	if err != nil && !force {
+		log.Debugf("[ClaimBatcher] could not get block height: %v", err)
+		return
+	}
+
+	b.Lock()
+	due := force
+	now := time.Now()
+	for _, q := range b.queue {
+		if b.isDue(q, now, height) {
+			due = true
+			break
+		}
+	}
+	if !due || len(b.queue) == 0 {
+		b.Unlock()
+		return
+	}
+	claims := make([]*PreimageClaim, 0, len(b.queue))
+	swapIds := make([]string, 0, len(b.queue))
+	for swapId, q := range b.queue {
+		claims = append(claims, q.claim)
+		swapIds = append(swapIds, swapId)
+	}
+	b.queue = map[string]*queuedClaim{}
+	b.Unlock()
+
+	txId, _, err := b.sweeper.SweepPreimageClaims(claims)
+	if err != nil {
+		log.Infof("[ClaimBatcher] sweeping %d claims failed, claiming them on their own: %v", len(claims), err)
+		txId = ""
+	} else {
+		log.Infof("[ClaimBatcher] swept %d claims in %s", len(claims), txId)
+	}
+	if b.onSwept != nil {
+		b.onSwept(swapIds, txId)
+	}
+}
+
+// claimSweptContext sets the claim txid of a swap that was swept in a batch.
+// If the sweep failed, txId is empty and the swap claims on its own.
+type claimSweptContext struct {
+	fsm  *SwapStateMachine
+	txId string
+}
+
+func (c *claimSweptContext) Validate(data *SwapData) error {
+	return nil
+}
+
+func (c *claimSweptContext) ApplyToSwapData(data *SwapData) error {
+	// Called by SendEvent, the fsm is locked.
+	if c.fsm.Current != State_SwapOutSender_ClaimSwap && c.fsm.Current != State_SwapInReceiver_ClaimSwap {
+		return fmt.Errorf("swap is not claiming, state: %s", c.fsm.Current)
+	}
+	if c.txId == "" {
+		data.ClaimSweepFailed = true
+		return nil
+	}
+	if data.ClaimTxId == "" {
+		data.ClaimTxId = c.txId
+	}
+	return nil
+}
+
+// SetClaimBatcher batches the preimage claims of swaps on chain.
+func (s *SwapService) SetClaimBatcher(chain string, batcher *ClaimBatcher) error {
+	txWatcher, _, _, err := s.swapServices.getOnChainServices(chain)
+	if err != nil {
+		return err
+	}
+	batcher.txWatcher = txWatcher
+	batcher.onSwept = s.onClaimsSwept
+
+	s.swapServices.claimBatchersMu.Lock()
+	defer s.swapServices.claimBatchersMu.Unlock()
+	s.swapServices.claimBatchers[chain] = batcher
+	return nil
+}
+
+// flushClaimBatches sweeps the queued claims of all chains.
+func (s *SwapService) flushClaimBatches() {
+	s.swapServices.claimBatchersMu.Lock()
+	batchers := make([]*ClaimBatcher, 0, len(s.swapServices.claimBatchers))
+	for _, batcher := range s.swapServices.claimBatchers {
+		batchers = append(batchers, batcher)
+	}
+	s.swapServices.claimBatchersMu.Unlock()
+
+	for _, batcher := range batchers {
+		batcher.Flush()
+	}
+}
+
+// onClaimsSwept continues the swaps of a sweep. The swaps of a failed sweep
+// are claimed on their own, as a single bad claim fails the whole sweep.
+func (s *SwapService) onClaimsSwept(swapIds []string, txId string) {
+	for _, swapId := range swapIds {
+		swap, err := s.GetActiveSwap(swapId)
+		if err != nil {
+			continue
+		}
+		done, err := swap.SendEvent(Event_OnRetry, &claimSweptContext{fsm: swap, txId: txId})
+		if err == ErrEventRejected {
+			continue
+		}
+		if err != nil {
+			swap.logger().Debugf("[SwapService] SendEvent(): %v", err)
+			continue
+		}
+		if done {
+			s.RemoveActiveSwap(swapId)
+		}
+	}
+}
+
+// claimBatcher returns the claim batcher of chain, nil if claims are not
+// batched.
+func (s *SwapServices) claimBatcher(chain string) *ClaimBatcher {
+	s.claimBatchersMu.Lock()
+	defer s.claimBatchersMu.Unlock()
+	return s.claimBatchers[chain]
+}
@@ -0,0 +1,173 @@
+package swap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elementsproject/peerswap/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sweeperStub struct {
+	claims [][]*PreimageClaim
+	err    error
+}
+
+func (s *sweeperStub) SweepPreimageClaims(claims []*PreimageClaim) (string, string, error) {
+	s.claims = append(s.claims, claims)
+	if s.err != nil {
+		return "", "", s.err
+	}
+	return "sweeptxid", "sweeptxhex", nil
+}
+
+func Test_ClaimBatching(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService := getTestSetup(initiator)
+	bobSwapService := getTestSetup(peer)
+	aliceSwapService.swapServices.messenger.(*ConnectedMessenger).other = bobSwapService.swapServices.messenger.(*ConnectedMessenger)
+	bobSwapService.swapServices.messenger.(*ConnectedMessenger).other = aliceSwapService.swapServices.messenger.(*ConnectedMessenger)
+
+	aliceSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = make(chan messages.MessageType)
+	bobSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = make(chan messages.MessageType)
+
+	aliceMsgChan := aliceSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan
+	bobMsgChan := bobSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan
+
+	sweeper := &sweeperStub{}
+	batcher := NewClaimBatcher(sweeper, ClaimBatchConfig{
+		MaxDelay:        DefaultClaimBatchMaxDelay,
+		CsvSafetyMargin: DefaultClaimBatchCsvMargin,
+	})
+	require.NoError(t, aliceSwapService.SetClaimBatcher(btc_chain, batcher))
+
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, <-bobMsgChan)
+	bobSwap := bobSwapService.activeSwaps[aliceSwap.SwapId.String()]
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTAGREEMENT, <-aliceMsgChan)
+
+	bobSwapService.swapServices.lightning.(*dummyLightningClient).TriggerPayment(bobSwap.SwapId.String(), INVOICE_FEE)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-aliceMsgChan)
+
+	// The confirmed opening tx is far from the csv limit, the claim is
+	// queued.
+	err = aliceSwapService.swapServices.bitcoinTxWatcher.(*dummyChain).txConfirmedFunc(aliceSwap.SwapId.String(), aliceSwap.Data.OpeningTxHex)
+	require.NoError(t, err)
+	assert.Equal(t, State_SwapOutSender_ClaimSwap, aliceSwap.Current)
+	assert.NotZero(t, aliceSwap.Data.ClaimQueuedAt)
+	assert.Empty(t, aliceSwap.Data.ClaimTxId)
+	assert.Len(t, batcher.queue, 1)
+
+	// The batch is not due yet.
+	batcher.sweep(false)
+	assert.Empty(t, sweeper.claims)
+
+	batcher.Flush()
+	require.Len(t, sweeper.claims, 1)
+	require.Len(t, sweeper.claims[0], 1)
+	assert.Equal(t, aliceSwap.SwapId.String(), sweeper.claims[0][0].SwapId)
+	assert.Equal(t, State_ClaimedPreimage, aliceSwap.Current)
+	assert.Equal(t, "sweeptxid", aliceSwap.Data.ClaimTxId)
+	assert.Empty(t, batcher.queue)
+}
+
+func Test_ClaimBatcherDue(t *testing.T) {
+	chain := &dummyChain{}
+	claim := &PreimageClaim{SwapId: "swap"}
+
+	batcher := NewClaimBatcher(&sweeperStub{}, ClaimBatchConfig{
+		MaxDelay:        time.Hour,
+		CsvSafetyMargin: 10,
+	})
+	batcher.txWatcher = chain
+
+	// The block height of the dummy chain is 1.
+	queued, err := batcher.add(claim, time.Now(), 11)
+	require.NoError(t, err)
+	assert.False(t, queued, "claim close to the csv limit must not be queued")
+
+	queued, err = batcher.add(claim, time.Now().Add(-time.Hour), 1000)
+	require.NoError(t, err)
+	assert.False(t, queued, "claim past the max delay must not be queued")
+
+	queued, err = batcher.add(claim, time.Now(), 1000)
+	require.NoError(t, err)
+	assert.True(t, queued)
+}
+
+func Test_ClaimBatcherSweepFailed(t *testing.T) {
+	chain := &dummyChain{}
+	sweeper := &sweeperStub{err: errors.New("sweep failed")}
+	batcher := NewClaimBatcher(sweeper, ClaimBatchConfig{
+		MaxDelay:        time.Hour,
+		CsvSafetyMargin: 10,
+	})
+	batcher.txWatcher = chain
+
+	var sweptIds []string
+	sweptTxId := "unset"
+	batcher.onSwept = func(swapIds []string, txId string) {
+		sweptIds = swapIds
+		sweptTxId = txId
+	}
+
+	queued, err := batcher.add(&PreimageClaim{SwapId: "swap"}, time.Now(), 1000)
+	require.NoError(t, err)
+	require.True(t, queued)
+
+	// A failed sweep hands the claims back to their swaps.
+	batcher.Flush()
+	assert.Len(t, sweeper.claims, 1)
+	assert.Equal(t, []string{"swap"}, sweptIds)
+	assert.Empty(t, sweptTxId)
+	assert.Empty(t, batcher.queue)
+}
+
+func Test_ClaimBatchFailedClaimsOnItsOwn(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService, bobSwapService, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+
+	sweeper := &sweeperStub{err: errors.New("opening output already spent")}
+	batcher := NewClaimBatcher(sweeper, ClaimBatchConfig{
+		MaxDelay:        DefaultClaimBatchMaxDelay,
+		CsvSafetyMargin: DefaultClaimBatchCsvMargin,
+	})
+	require.NoError(t, aliceSwapService.SetClaimBatcher(btc_chain, batcher))
+
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, <-bobMsgChan)
+	bobSwap := bobSwapService.activeSwaps[aliceSwap.SwapId.String()]
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTAGREEMENT, <-aliceMsgChan)
+
+	bobSwapService.swapServices.lightning.(*dummyLightningClient).TriggerPayment(bobSwap.SwapId.String(), INVOICE_FEE)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-aliceMsgChan)
+
+	err = aliceSwapService.swapServices.bitcoinTxWatcher.(*dummyChain).txConfirmedFunc(aliceSwap.SwapId.String(), aliceSwap.Data.OpeningTxHex)
+	require.NoError(t, err)
+	require.Len(t, batcher.queue, 1)
+
+	// The claim is not queued again after the failed sweep, it is spent on
+	// its own.
+	batcher.Flush()
+	require.Len(t, sweeper.claims, 1)
+	assert.Equal(t, State_ClaimedPreimage, aliceSwap.Current)
+	assert.True(t, aliceSwap.Data.ClaimSweepFailed)
+	assert.NotEmpty(t, aliceSwap.Data.ClaimTxId)
+	assert.Empty(t, batcher.queue)
+}
@@ -0,0 +1,76 @@
+package swap
+
+import (
+	"fmt"
+)
+
+// openingTxSubmittedContext carries the externally signed opening tx of a
+// swap-in.
+type openingTxSubmittedContext struct {
+	fsm   *SwapStateMachine
+	txHex string
+	vout  uint32
+}
+
+func (o *openingTxSubmittedContext) Validate(data *SwapData) error {
+	return nil
+}
+
+func (o *openingTxSubmittedContext) ApplyToSwapData(data *SwapData) error {
+	// Called by SendEvent, the fsm is locked. The tx must not be applied to a
+	// swap that stopped waiting for it in the meantime.
+	if o.fsm.Current != State_SwapInSender_AwaitExternalFunding {
+		return fmt.Errorf("swap is not waiting for an opening tx, state: %s", o.fsm.Current)
+	}
+	data.ExternalOpeningTxHex = o.txHex
+	data.ExternalOpeningVout = o.vout
+	return nil
+}
+
+// GetOpeningPsbt returns the PSBT that the external wallet funds while the
+// swap waits for the signed opening tx.
+func (s *SwapData) GetOpeningPsbt() string {
+	if s.FSMState != State_SwapInSender_AwaitExternalFunding {
+		return ""
+	}
+	return s.OpeningPsbt
+}
+
+// SubmitOpeningTx broadcasts the externally funded opening tx of a swap-in.
+// signedTx is either the signed PSBT (PSET) of the swap or a raw tx. The tx
+// must pay the swap amount to the opening output, a tx that does not is
+// rejected and the swap keeps waiting.
+func (s *SwapService) SubmitOpeningTx(swapId string, signedTx string) (*SwapStateMachine, error) {
+	swap, err := s.GetActiveSwap(swapId)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Type != SWAPTYPE_IN || swap.Role != SWAPROLE_SENDER || !swap.Data.ExternalFunding {
+		return nil, fmt.Errorf("swap %s is not an externally funded swap in", swapId)
+	}
+	if swap.Current != State_SwapInSender_AwaitExternalFunding {
+		return nil, fmt.Errorf("swap %s is not waiting for an opening tx, state: %s", swapId, swap.Current)
+	}
+
+	_, wallet, _, err := s.swapServices.getOnChainServices(swap.Data.GetChain())
+	if err != nil {
+		return nil, err
+	}
+	txHex, vout, err := wallet.ExtractOpeningTx(swap.Data.GetOpeningParams(), signedTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid opening tx: %w", err)
+	}
+
+	done, err := swap.SendEvent(Event_SwapInSender_OnOpeningTxSubmitted, &openingTxSubmittedContext{
+		fsm:   swap,
+		txHex: txHex,
+		vout:  vout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		s.RemoveActiveSwap(swap.SwapId.String())
+	}
+	return swap, nil
+}
@@ -0,0 +1,94 @@
+package swap
+
+import (
+	"testing"
+
+	"github.com/elementsproject/peerswap/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SwapInExternalFunding(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService := getTestSetup(initiator)
+	bobSwapService := getTestSetup(peer)
+	aliceSwapService.swapServices.messenger.(*ConnectedMessenger).other = bobSwapService.swapServices.messenger.(*ConnectedMessenger)
+	bobSwapService.swapServices.messenger.(*ConnectedMessenger).other = aliceSwapService.swapServices.messenger.(*ConnectedMessenger)
+
+	aliceSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = make(chan messages.MessageType)
+	bobSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = make(chan messages.MessageType)
+
+	aliceMsgChan := aliceSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan
+	bobMsgChan := bobSwapService.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan
+
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+
+	aliceSwap, err := aliceSwapService.SwapIn(peer, btc_chain, channelId, initiator, amount, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPINREQUEST, <-bobMsgChan)
+	assert.Equal(t, messages.MESSAGETYPE_SWAPINAGREEMENT, <-aliceMsgChan)
+
+	// The swap waits for the operator to fund the opening tx.
+	assert.Equal(t, State_SwapInSender_AwaitExternalFunding, aliceSwap.Current)
+	assert.Equal(t, "psbt", aliceSwap.Data.OpeningPsbt)
+	assert.NotZero(t, aliceSwap.Data.TimeOutAt)
+
+	// A tx that does not pay to the opening output is rejected, the swap
+	// keeps waiting.
+	_, err = aliceSwapService.SubmitOpeningTx(aliceSwap.SwapId.String(), "invalid")
+	assert.Error(t, err)
+	assert.Equal(t, State_SwapInSender_AwaitExternalFunding, aliceSwap.Current)
+
+	_, err = aliceSwapService.SubmitOpeningTx(aliceSwap.SwapId.String(), "signedtx")
+	require.NoError(t, err)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+
+	assert.Equal(t, "signedtx", aliceSwapService.swapServices.bitcoinWallet.(*dummyChain).broadcastedTx)
+	assert.Equal(t, "signedtx", aliceSwap.Data.OpeningTxHex)
+	assert.Equal(t, uint32(1), aliceSwap.Data.OpeningTxBroadcasted.ScriptOut)
+	assert.Zero(t, aliceSwap.Data.TimeOutAt)
+
+	// The tx can only be submitted once.
+	_, err = aliceSwapService.SubmitOpeningTx(aliceSwap.SwapId.String(), "signedtx")
+	assert.Error(t, err)
+}
+
+func Test_SwapInExternalFundingTimeout(t *testing.T) {
+	swapAmount := uint64(100000)
+	initiator, peer, takerPubkeyHash, _, chanId := getTestParams()
+	msgChan := make(chan PeerMessage)
+
+	swapServices := getSwapServices(msgChan)
+	swapServices.toService = &timeOutDummy{}
+	swap := newSwapInSenderFSM(swapServices, initiator, peer)
+	swap.Data.ExternalFunding = true
+
+	_, err := swap.SendEvent(Event_SwapInSender_OnSwapInRequested, &SwapInRequestMessage{
+		Amount:          swapAmount,
+		ProtocolVersion: PEERSWAP_PROTOCOL_VERSION,
+		SwapId:          swap.SwapId,
+		Network:         "mainnet",
+		Scid:            chanId,
+		Pubkey:          initiator,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, messages.MESSAGETYPE_SWAPINREQUEST, (<-msgChan).MessageType())
+
+	_, err = swap.SendEvent(Event_SwapInSender_OnAgreementReceived, &SwapInAgreementMessage{
+		SwapId: swap.SwapId,
+		Pubkey: takerPubkeyHash,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, State_SwapInSender_AwaitExternalFunding, swap.Current)
+
+	go swap.SendEvent(Event_OnTimeout, &timeoutContext{fsm: swap})
+	assert.Equal(t, messages.MESSAGETYPE_CANCELED, (<-msgChan).MessageType())
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
+	assert.Equal(t, State_SwapCanceled, swap.Current)
+	assert.Equal(t, CancelCode_Timeout, swap.Data.CancelCode)
+}
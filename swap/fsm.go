@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/elementsproject/peerswap/log"
+	"github.com/elementsproject/peerswap/metrics"
 )
 
 // ErrEventRejected is the error returned when the state machine cannot process
@@ -143,7 +145,7 @@ func (s *SwapStateMachine) SendEvent(event EventType, eventCtx EventContext) (bo
 		err = eventCtx.Validate(s.Data)
 		if err != nil {
 			s.mutex.Unlock()
-			log.Infof("Message validation error: %v on msg %v", err, eventCtx)
+			s.logger().Warnf("Message validation error: %v on msg %v", err, eventCtx)
 			res, err := s.SendEvent(Event_OnInvalid_Message, nil)
 			s.mutex.Lock()
 			return res, err
@@ -164,7 +166,7 @@ func (s *SwapStateMachine) SendEvent(event EventType, eventCtx EventContext) (bo
 
 	for {
 		// Determine the next state for the event given the machine's current state.
-		log.Debugf("[FSM] event:id: %s, %s on %s", s.SwapId.String(), event, s.Current)
+		s.logger().Debugf("[FSM] event %s on %s", event, s.Current)
 		nextState, err := s.getNextState(event)
 		if err != nil {
 			return false, ErrEventRejected
@@ -184,6 +186,7 @@ func (s *SwapStateMachine) SendEvent(event EventType, eventCtx EventContext) (bo
 
 		// Print Swap information
 		s.logSwapInfo()
+		s.recordMetrics()
 
 		// Execute the next state's action and loop over again if the event returned
 		// is not a no-op.
@@ -206,7 +209,7 @@ func (s *SwapStateMachine) SendEvent(event EventType, eventCtx EventContext) (bo
 			}
 		case Event_ActionFailed:
 			if s.Data.LastErr != nil {
-				log.Infof("[FSM] Action failure %v", s.Data.LastErr)
+				s.logger().Warnf("[FSM] Action failure %v", s.Data.LastErr)
 			}
 		}
 
@@ -216,7 +219,7 @@ func (s *SwapStateMachine) SendEvent(event EventType, eventCtx EventContext) (bo
 
 // Recover tries to continue from the current state, by doing the associated Action
 func (s *SwapStateMachine) Recover() (bool, error) {
-	log.Infof("[Swap:%s]: Recovering from state %s", s.SwapId.String(), s.Current)
+	s.logger().Infof("Recovering from state %s", s.Current)
 	state, ok := s.States[s.Current]
 	if !ok {
 		return false, fmt.Errorf("unknown state: %s for swap %s", s.Current, s.SwapId.String())
@@ -230,6 +233,12 @@ func (s *SwapStateMachine) Recover() (bool, error) {
 		return s.SendEvent(Event_ActionFailed, nil)
 	}
 
+	// Re-arm a pending timeout with the time that is left. A deadline that
+	// passed while we were down fires immediately.
+	if _, ok := state.Events[Event_OnTimeout]; ok && s.Data.TimeOutAt > 0 {
+		s.Data.armTimeout(s.swapServices)
+	}
+
 	nextEvent := state.Action.Execute(s.swapServices, s.Data)
 	err := s.swapServices.swapStore.UpdateData(s)
 	if err != nil {
@@ -241,6 +250,33 @@ func (s *SwapStateMachine) Recover() (bool, error) {
 	return s.SendEvent(nextEvent, nil)
 }
 
+// PauseTimeout pauses the pending timeout of the swap while the peer is
+// offline. Returns the unix time at which the paused timeout fires at the
+// latest.
+func (s *SwapStateMachine) PauseTimeout() (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.Data.pauseTimeout(s.swapServices, time.Now()) {
+		return s.Data.TimeOutAt, nil
+	}
+	return s.Data.TimeOutAt, s.swapServices.swapStore.UpdateData(s)
+}
+
+// ResumeTimeout resumes a paused timeout when the peer reconnects. Returns
+// true if the swap waits for the peer, so that the last message should be
+// resent.
+func (s *SwapStateMachine) ResumeTimeout() (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.Data.TimeOutAt == 0 {
+		return false, nil
+	}
+	if !s.Data.resumeTimeout(s.swapServices, time.Now()) {
+		return true, nil
+	}
+	return true, s.swapServices.swapStore.UpdateData(s)
+}
+
 // IsFinished returns true if the swap is already finished
 func (s *SwapStateMachine) IsFinished() bool {
 	switch s.Current {
@@ -257,6 +293,13 @@ func (s *SwapStateMachine) IsFinished() bool {
 }
 
 // logSwapInfo give the user useful information depending on the swap statemachine
+// isMaker returns true if we fund the opening tx, that is as swap in sender
+// and as swap out receiver.
+func (s *SwapStateMachine) isMaker() bool {
+	return (s.Type == SWAPTYPE_IN && s.Role == SWAPROLE_SENDER) ||
+		(s.Type == SWAPTYPE_OUT && s.Role == SWAPROLE_RECEIVER)
+}
+
 func (s *SwapStateMachine) logSwapInfo() {
 
 	// Start Swap as Sender
@@ -309,6 +352,28 @@ func (s *SwapStateMachine) logSwapInfo() {
 
 }
 
+// recordMetrics counts started and finished swaps.
+func (s *SwapStateMachine) recordMetrics() {
+	swapType := s.Type.String()
+	role := s.Role.String()
+	asset := s.Data.GetChain()
+
+	switch s.Current {
+	case State_SwapInSender_CreateSwap, State_SwapOutSender_CreateSwap,
+		State_SwapInReceiver_CreateSwap, State_SwapOutReceiver_CreateSwap:
+		metrics.SwapStarted(swapType, asset, role)
+	case State_ClaimedPreimage, State_ClaimedCoop, State_ClaimedCsv:
+		metrics.SwapCompleted(swapType, asset, role, string(s.Current), s.duration())
+	case State_SwapCanceled:
+		metrics.SwapCanceled(swapType, asset, role, string(s.Current), s.duration())
+	}
+}
+
+// duration returns the time that passed since the swap was created.
+func (s *SwapStateMachine) duration() time.Duration {
+	return time.Since(time.Unix(s.Data.CreatedAt, 0))
+}
+
 func (s *SwapStateMachine) printFeeInvoiceInfo() {
 	if s.Data.SwapOutAgreement == nil {
 		return
@@ -325,6 +390,18 @@ func (s *SwapStateMachine) printFeeInvoiceInfo() {
 }
 
 func (s *SwapStateMachine) Infof(format string, v ...interface{}) {
-	idString := fmt.Sprintf("%s", s.SwapId.String())
-	log.Infof("[Swap:"+idString+"] "+format, v...)
+	s.logger().Infof(format, v...)
+}
+
+// logger returns a log entry that carries the context of the swap.
+func (s *SwapStateMachine) logger() *log.Entry {
+	fields := log.Fields{
+		log.SwapIdField: s.SwapId.String(),
+		log.StateField:  string(s.Current),
+	}
+	if s.Data != nil {
+		fields[log.PeerField] = s.Data.PeerNodeId
+		fields[log.ChainField] = s.Data.GetChain()
+	}
+	return log.WithFields(fields)
 }
@@ -26,25 +26,25 @@ func NewInvalidLengthError(paramName string, expected, actual int) error {
 type SwapInRequestMessage struct {
 	// ProtocolVersion is the version of the PeerSwap peer protocol the sending
 	// node uses.
-	ProtocolVersion uint8 `json:"protocol_version"`
+	ProtocolVersion uint8 `json:"protocol_version" tlv:"0"`
 	// SwapId is a randomly generated 32 byte string that must be kept the same
 	// through the whole process of a swap and serves as an identifier for a
 	// specific swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"2"`
 	// Network is the desired on-chain network to use. This can be:
 	// Bitcoin: mainnet, testnet, signet, regtest
 	// Liquid: The field is left blank as the asset id also defines the bitcoinNetwork.
-	Network string `json:"network"`
+	Network string `json:"network" tlv:"4"`
 	// Asset is the desired on-chain asset to use. This can be:
 	// Bitcoin: The field is left blank.
 	// Liquid: The asset id of the networks Bitcoin asset.
-	Asset string `json:"asset"`
+	Asset string `json:"asset" tlv:"6,hex"`
 	// Scid is the short channel id in human readable format, defined by BOLT#7
 	// with x as separator, e.g. 539268x845x1.
-	Scid string `json:"scid"`
+	Scid string `json:"scid" tlv:"8"`
 	// Amount is The amount in Sats that is asked for.
-	Amount uint64 `json:"amount"`
-	Pubkey string `json:"pubkey"`
+	Amount uint64 `json:"amount" tlv:"10"`
+	Pubkey string `json:"pubkey" tlv:"12,hex"`
 }
 
 func (s SwapInRequestMessage) MessageType() messages.MessageType {
@@ -158,17 +158,17 @@ func (s SwapInRequestMessage) ApplyToSwapData(swap *SwapData) error {
 type SwapInAgreementMessage struct {
 	// ProtocolVersion is the version of the PeerSwap peer protocol the sending
 	// node uses.
-	ProtocolVersion uint8 `json:"protocol_version"`
+	ProtocolVersion uint8 `json:"protocol_version" tlv:"0"`
 	// SwapId is a randomly generated 32 byte string that must be kept the same
 	// through the whole process of a swap and serves as an identifier for a
 	// specific swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"2"`
 	// Pubkey is a 33 byte compressed public key used for the spending paths in
 	// the opening_transaction.
-	Pubkey string `json:"pubkey"`
+	Pubkey string `json:"pubkey" tlv:"4,hex"`
 	// Premium is a compensation in Sats that the swap partner wants to be payed
 	// in order to participate in the swap.
-	Premium uint64 `json:"premium"`
+	Premium uint64 `json:"premium" tlv:"6"`
 }
 
 func (s SwapInAgreementMessage) Validate(swap *SwapData) error {
@@ -196,27 +196,27 @@ func (s SwapInAgreementMessage) ApplyToSwapData(swap *SwapData) error {
 type SwapOutRequestMessage struct {
 	// ProtocolVersion is the version of the PeerSwap peer protocol the sending
 	// node uses.
-	ProtocolVersion uint8 `json:"protocol_version"`
+	ProtocolVersion uint8 `json:"protocol_version" tlv:"0"`
 	// SwapId is a randomly generated 32 byte string that must be kept the same
 	// through the whole process of a swap and serves as an identifier for a
 	// specific swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"2"`
 	// Asset is the desired on-chain asset to use. This can be:
 	// Bitcoin: The field is left blank.
 	// Liquid: The asset id of the networks Bitcoin asset.
-	Asset string `json:"asset"`
+	Asset string `json:"asset" tlv:"4,hex"`
 	// Network is the desired on-chain network to use. This can be:
 	// Bitcoin: mainnet, testnet, signet, regtest
 	// Liquid: The field is left blank as the asset id also defines the bitcoinNetwork.
-	Network string `json:"network"`
+	Network string `json:"network" tlv:"6"`
 	// Scid is the short channel id in human readable format, defined by BOLT#7
 	// with x as separator, e.g. 539268x845x1.
-	Scid string `json:"scid"`
+	Scid string `json:"scid" tlv:"8"`
 	// Amount is The amount in Sats that is asked for.
-	Amount uint64 `json:"amount"`
+	Amount uint64 `json:"amount" tlv:"10"`
 	// Pubkey is a 33 byte compressed public key used for the spending paths in
 	// the opening_transaction.
-	Pubkey string `json:"pubkey"`
+	Pubkey string `json:"pubkey" tlv:"12,hex"`
 }
 
 func (s SwapOutRequestMessage) Validate(swap *SwapData) error {
@@ -252,17 +252,17 @@ func (s SwapOutRequestMessage) ApplyToSwapData(swap *SwapData) error {
 type SwapOutAgreementMessage struct {
 	// ProtocolVersion is the version of the PeerSwap peer protocol the sending
 	// node uses.
-	ProtocolVersion uint8 `json:"protocol_version"`
+	ProtocolVersion uint8 `json:"protocol_version" tlv:"0"`
 	// SwapId is a randomly generated 32 byte string that must be kept the same
 	// through the whole process of a swap and serves as an identifier for a
 	// specific swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"2"`
 	// Pubkey is a 33 byte compressed public key used for the spending paths in
 	// the opening_transaction.
-	Pubkey string `json:"pubkey"`
+	Pubkey string `json:"pubkey" tlv:"4,hex"`
 	// Payreq is a BOLT#11 invoice with an amount that covers the fee expenses
 	// for the on-chain transactions.
-	Payreq string
+	Payreq string `tlv:"6"`
 }
 
 func (s SwapOutAgreementMessage) Validate(swap *SwapData) error {
@@ -289,21 +289,21 @@ func (s SwapOutAgreementMessage) ApplyToSwapData(swap *SwapData) error {
 // tx.
 type OpeningTxBroadcastedMessage struct {
 	// SwapId is the unique identifier of the swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"0"`
 	// Payreq is the invoice as described in BOLT#11 that the responder is
 	// requested to pay.
-	Payreq string `json:"payreq"`
+	Payreq string `json:"payreq" tlv:"2"`
 	// TxId is the transaction id of the opening_transaction broadcasted by the
 	// initiator.
-	TxId string `json:"tx_id"`
+	TxId string `json:"tx_id" tlv:"4,hex"`
 	// ScriptOut is the transaction output that contains the opening_transaction
 	// output script for the swap.
-	ScriptOut uint32 `json:"script_out"`
+	ScriptOut uint32 `json:"script_out" tlv:"6"`
 	// BlindingKey:
 	// Bitcoin: Blank.
 	// Liquid BitcoinNetwork: Is the 32 byte blinding key to un-blind the outputs of
 	//the opening_transaction.
-	BlindingKey string `json:"blinding_key"`
+	BlindingKey string `json:"blinding_key" tlv:"8,hex"`
 }
 
 func (s OpeningTxBroadcastedMessage) Validate(swap *SwapData) error {
@@ -332,13 +332,68 @@ func (m OpeningTxBroadcastedMessage) ApplyToSwapData(swap *SwapData) error {
 	return nil
 }
 
+// CancelCode is a machine readable reason why a swap was canceled. The codes
+// are sent in the cancel message, so their values must never change.
+type CancelCode uint16
+
+const (
+	CancelCode_Unknown             CancelCode = 0
+	CancelCode_SwapsDisabled       CancelCode = 1
+	CancelCode_ChainDisabled       CancelCode = 2
+	CancelCode_IncompatibleVersion CancelCode = 3
+	CancelCode_AmountTooSmall      CancelCode = 4
+	CancelCode_InvalidAsset        CancelCode = 5
+	CancelCode_InvalidNetwork      CancelCode = 6
+	CancelCode_PeerNotAllowed      CancelCode = 7
+	CancelCode_PeerSuspicious      CancelCode = 8
+	CancelCode_InsufficientBalance CancelCode = 9
+	CancelCode_ActiveSwap          CancelCode = 10
+	CancelCode_Timeout             CancelCode = 11
+	CancelCode_InvalidTx           CancelCode = 12
+	CancelCode_InvalidInvoice      CancelCode = 13
+	CancelCode_FeeTooHigh          CancelCode = 14
+	CancelCode_TooCloseToCsv       CancelCode = 15
+	CancelCode_TooManySwaps        CancelCode = 16
+)
+
+var cancelCodeStrings = map[CancelCode]string{
+	CancelCode_Unknown:             "unknown",
+	CancelCode_SwapsDisabled:       "swaps_disabled",
+	CancelCode_ChainDisabled:       "chain_disabled",
+	CancelCode_IncompatibleVersion: "incompatible_version",
+	CancelCode_AmountTooSmall:      "amount_too_small",
+	CancelCode_InvalidAsset:        "invalid_asset",
+	CancelCode_InvalidNetwork:      "invalid_network",
+	CancelCode_PeerNotAllowed:      "peer_not_allowed",
+	CancelCode_PeerSuspicious:      "peer_suspicious",
+	CancelCode_InsufficientBalance: "insufficient_balance",
+	CancelCode_ActiveSwap:          "active_swap",
+	CancelCode_Timeout:             "timeout",
+	CancelCode_InvalidTx:           "invalid_tx",
+	CancelCode_InvalidInvoice:      "invalid_invoice",
+	CancelCode_FeeTooHigh:          "fee_too_high",
+	CancelCode_TooCloseToCsv:       "too_close_to_csv",
+	CancelCode_TooManySwaps:        "too_many_swaps",
+}
+
+// String returns the short label of the code. Codes that were added by a
+// newer peer are "unknown".
+func (c CancelCode) String() string {
+	if s, ok := cancelCodeStrings[c]; ok {
+		return s
+	}
+	return cancelCodeStrings[CancelCode_Unknown]
+}
+
 // CancelMessage is the message sent by a peer if he wants to / has to cancel
 // the swap
 type CancelMessage struct {
 	// SwapId is the unique identifier of the swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"0"`
 	// Message is a hint to why the swap was canceled.
-	Message string `json:"message"`
+	Message string `json:"message" tlv:"2"`
+	// Code is the machine readable reason why the swap was canceled.
+	Code CancelCode `json:"code,omitempty" tlv:"4"`
 }
 
 func (e CancelMessage) MessageType() messages.MessageType {
@@ -358,11 +413,11 @@ func (m CancelMessage) ApplyToSwapData(swap *SwapData) error {
 // cancel the swap, but allow the maker a quick close
 type CoopCloseMessage struct {
 	// SwapId is the unique identifier of the swap.
-	SwapId *SwapId `json:"swap_id"`
+	SwapId *SwapId `json:"swap_id" tlv:"0"`
 	// Message is a hint to why the swap was canceled.
-	Message string `json:"message"`
+	Message string `json:"message" tlv:"2"`
 	// privkey is the private key to the pubkey that is used to build the opening_transaction.
-	Privkey string `json:"privkey"`
+	Privkey string `json:"privkey" tlv:"4,hex"`
 }
 
 func (c CoopCloseMessage) MessageType() messages.MessageType {
@@ -385,6 +440,18 @@ func (m CoopCloseMessage) ApplyToSwapData(swap *SwapData) error {
 	return nil
 }
 
+// AckMessage is sent by the receiver of a swap message to acknowledge its
+// receipt, so that the sender stops resending it.
+type AckMessage struct {
+	// MessageId is the id of the received message, that is the hex encoded
+	// sha256 hash of the message type and payload.
+	MessageId string `json:"message_id" tlv:"0"`
+}
+
+func (a AckMessage) MessageType() messages.MessageType {
+	return messages.MESSAGETYPE_ACK
+}
+
 func MarshalPeerswapMessage(msg PeerMessage) ([]byte, int, error) {
 	msgBytes, err := json.Marshal(msg)
 	if err != nil {
@@ -392,3 +459,14 @@ func MarshalPeerswapMessage(msg PeerMessage) ([]byte, int, error) {
 	}
 	return msgBytes, int(msg.MessageType()), nil
 }
+
+// marshalMessage encodes a message for the peer. The message is encoded as
+// tlv stream if the peer announced tlv support, as json otherwise.
+func (s *SwapServices) marshalMessage(peerId string, msg PeerMessage) ([]byte, int, error) {
+	tlv := s.peerFeatures != nil && s.peerFeatures.HasFeature(peerId, messages.FEATURE_TLV)
+	msgBytes, err := messages.Marshal(msg, tlv)
+	if err != nil {
+		return nil, 0, err
+	}
+	return msgBytes, int(msg.MessageType()), nil
+}
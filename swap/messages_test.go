@@ -0,0 +1,65 @@
+package swap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/elementsproject/peerswap/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessages_TLVRoundTrip(t *testing.T) {
+	pubkey := "02" + strings.Repeat("ab", 32)
+	asset := "01" + strings.Repeat("cd", 32)
+	txId := strings.Repeat("ef", 32)
+
+	msgs := []PeerMessage{
+		&SwapInRequestMessage{ProtocolVersion: PEERSWAP_PROTOCOL_VERSION, SwapId: NewSwapId(), Asset: asset, Scid: "1x2x3", Amount: 100000, Pubkey: pubkey},
+		&SwapInAgreementMessage{ProtocolVersion: PEERSWAP_PROTOCOL_VERSION, SwapId: NewSwapId(), Pubkey: pubkey, Premium: 10},
+		&SwapOutRequestMessage{ProtocolVersion: PEERSWAP_PROTOCOL_VERSION, SwapId: NewSwapId(), Network: "regtest", Scid: "1x2x3", Amount: 100000, Pubkey: pubkey},
+		&SwapOutAgreementMessage{ProtocolVersion: PEERSWAP_PROTOCOL_VERSION, SwapId: NewSwapId(), Pubkey: pubkey, Payreq: "lnbcrt1"},
+		&OpeningTxBroadcastedMessage{SwapId: NewSwapId(), Payreq: "lnbcrt1", TxId: txId, ScriptOut: 1, BlindingKey: txId},
+		&CancelMessage{SwapId: NewSwapId(), Message: "canceled", Code: CancelCode_Timeout},
+		&CoopCloseMessage{SwapId: NewSwapId(), Message: "canceled", Privkey: txId},
+		&AckMessage{MessageId: "id"},
+	}
+
+	for _, msg := range msgs {
+		t.Run(reflect.TypeOf(msg).Elem().Name(), func(t *testing.T) {
+			b, err := messages.Marshal(msg, true)
+			require.NoError(t, err)
+
+			// Every field is encoded.
+			decoded := reflect.New(reflect.TypeOf(msg).Elem()).Interface()
+			require.NoError(t, messages.Unmarshal(b, decoded))
+			assert.Equal(t, msg, decoded)
+
+			// Json messages are still understood.
+			b, err = messages.Marshal(msg, false)
+			require.NoError(t, err)
+			decoded = reflect.New(reflect.TypeOf(msg).Elem()).Interface()
+			require.NoError(t, messages.Unmarshal(b, decoded))
+			assert.Equal(t, msg, decoded)
+		})
+	}
+}
+
+func TestSwapServices_MarshalMessage(t *testing.T) {
+	services := &SwapServices{peerFeatures: &peerFeaturesStub{}}
+	msg := &CancelMessage{SwapId: NewSwapId(), Message: "canceled"}
+
+	// Peers without tlv support get json.
+	b, msgType, err := services.marshalMessage("peer", msg)
+	require.NoError(t, err)
+	assert.Equal(t, int(messages.MESSAGETYPE_CANCELED), msgType)
+	assert.Equal(t, byte('{'), b[0])
+
+	services.peerFeatures = &peerFeaturesStub{features: []string{messages.FEATURE_TLV}}
+	b, _, err = services.marshalMessage("peer", msg)
+	require.NoError(t, err)
+	expected, err := messages.MarshalTLV(msg)
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+}
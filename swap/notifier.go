@@ -0,0 +1,61 @@
+package swap
+
+import "sync"
+
+// txNotifier wakes up the action that pays the claim invoice if the opening
+// tx is reorged out of the chain or its output is spent in the meantime. A
+// notification is kept until it is reset, so that it is not lost if it
+// arrives before the action waits for it.
+type txNotifier struct {
+	sync.Mutex
+	chans map[string]chan struct{}
+}
+
+func newTxNotifier() *txNotifier {
+	return &txNotifier{chans: map[string]chan struct{}{}}
+}
+
+// wait returns a channel that is closed once an event of the swap is
+// notified. The channel of a nil notifier is never closed.
+func (r *txNotifier) wait(swapId string) <-chan struct{} {
+	if r == nil {
+		return nil
+	}
+	r.Lock()
+	defer r.Unlock()
+	return r.get(swapId)
+}
+
+// notify closes the channel of the swap.
+func (r *txNotifier) notify(swapId string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	ch := r.get(swapId)
+	select {
+	case <-ch:
+	default:
+		close(ch)
+	}
+}
+
+// reset removes the notification of the swap.
+func (r *txNotifier) reset(swapId string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	delete(r.chans, swapId)
+}
+
+func (r *txNotifier) get(swapId string) chan struct{} {
+	ch, ok := r.chans[swapId]
+	if !ok {
+		ch = make(chan struct{})
+		r.chans[swapId] = ch
+	}
+	return ch
+}
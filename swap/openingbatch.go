@@ -0,0 +1,397 @@
+package swap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elementsproject/peerswap/log"
+)
+
+var (
+	// openingBatchWindow is the time a batch waits for the agreements of its
+	// swaps. Swaps that agree later are opened on their own.
+	openingBatchWindow = time.Minute
+
+	// openingBatchTimeout is the time a swap waits for the batched opening
+	// tx. It is shorter than the 10 minutes the peer waits for the opening
+	// tx after it sent the agreement.
+	openingBatchTimeout = 9 * time.Minute
+)
+
+// SwapInBatchRequest is a swap-in of a batch.
+type SwapInBatchRequest struct {
+	PeerId    string
+	ChannelId string
+	AmtSat    uint64
+}
+
+// SwapInBatchResult is the started swap or the error of a swap-in of a
+// batch.
+type SwapInBatchResult struct {
+	Swap *SwapStateMachine
+	Err  error
+}
+
+type openingBatchMember struct {
+	swapId string
+	params *OpeningParams
+}
+
+// openingBatch collects the opening outputs of swap-ins that are paid by a
+// single opening tx.
+type openingBatch struct {
+	chain string
+	// pending are the swaps that did not agree yet.
+	pending map[string]struct{}
+	// ready are the swaps whose opening outputs are added to the tx, in the
+	// order they agreed.
+	ready []*openingBatchMember
+	// sealed is set once all swaps of the batch were requested.
+	sealed bool
+	// built is set once the batch no longer takes opening outputs.
+	built bool
+	timer *time.Timer
+}
+
+// openingBatches keeps the batches of swap-ins that are not built yet. The
+// batches are not persisted, a swap whose batch is gone is opened on its own
+// unless the batch tx was already persisted on the swap.
+type openingBatches struct {
+	sync.Mutex
+	batches map[string]*openingBatch
+	// build creates and broadcasts the opening tx of a batch.
+	build func(batchId string, chain string, members []*openingBatchMember)
+}
+
+func newOpeningBatches() *openingBatches {
+	return &openingBatches{batches: map[string]*openingBatch{}}
+}
+
+// create adds a new batch on chain that takes swaps until it is sealed.
+func (o *openingBatches) create(batchId string, chain string) {
+	o.Lock()
+	defer o.Unlock()
+	o.batches[batchId] = &openingBatch{
+		chain:   chain,
+		pending: map[string]struct{}{},
+	}
+}
+
+// addPending adds a requested swap to the batch.
+func (o *openingBatches) addPending(batchId string, swapId string) {
+	o.Lock()
+	defer o.Unlock()
+	if batch, ok := o.batches[batchId]; ok && !batch.sealed {
+		batch.pending[swapId] = struct{}{}
+	}
+}
+
+// seal stops the batch from taking new swaps. The batch is built once all
+// swaps agreed or left it, but at the latest after openingBatchWindow.
+func (o *openingBatches) seal(batchId string) {
+	o.Lock()
+	defer o.Unlock()
+	batch, ok := o.batches[batchId]
+	if !ok {
+		return
+	}
+	batch.sealed = true
+	batch.timer = time.AfterFunc(openingBatchWindow, func() {
+		o.Lock()
+		defer o.Unlock()
+		o.maybeBuild(batchId, true)
+	})
+	o.maybeBuild(batchId, false)
+}
+
+// add adds the opening output of an agreed swap to the batch. It returns
+// false if the swap is not part of the batch (anymore).
+func (o *openingBatches) add(batchId string, swapId string, params *OpeningParams) bool {
+	if o == nil {
+		return false
+	}
+	o.Lock()
+	defer o.Unlock()
+	batch, ok := o.batches[batchId]
+	if !ok || batch.built {
+		return false
+	}
+	if _, ok := batch.pending[swapId]; !ok {
+		return false
+	}
+	delete(batch.pending, swapId)
+	batch.ready = append(batch.ready, &openingBatchMember{swapId: swapId, params: params})
+	o.maybeBuild(batchId, false)
+	return true
+}
+
+// isQueued returns true if the swap waits for the opening tx of the batch.
+func (o *openingBatches) isQueued(batchId string, swapId string) bool {
+	if o == nil {
+		return false
+	}
+	o.Lock()
+	defer o.Unlock()
+	batch, ok := o.batches[batchId]
+	if !ok {
+		return false
+	}
+	for _, member := range batch.ready {
+		if member.swapId == swapId {
+			return true
+		}
+	}
+	return false
+}
+
+// remove drops a swap that ended from the batch, so that the other swaps do
+// not wait for it.
+func (o *openingBatches) remove(batchId string, swapId string) {
+	if o == nil || batchId == "" {
+		return
+	}
+	o.Lock()
+	defer o.Unlock()
+	batch, ok := o.batches[batchId]
+	if !ok || batch.built {
+		return
+	}
+	delete(batch.pending, swapId)
+	for i, member := range batch.ready {
+		if member.swapId == swapId {
+			batch.ready = append(batch.ready[:i], batch.ready[i+1:]...)
+			break
+		}
+	}
+	o.maybeBuild(batchId, false)
+}
+
+// done deletes a batch whose swaps were notified.
+func (o *openingBatches) done(batchId string) {
+	o.Lock()
+	defer o.Unlock()
+	delete(o.batches, batchId)
+}
+
+// maybeBuild builds the batch in the background if it is sealed and no swap
+// is pending or force is set. Must be called with the lock held.
+func (o *openingBatches) maybeBuild(batchId string, force bool) {
+	batch, ok := o.batches[batchId]
+	if !ok || batch.built || !batch.sealed {
+		return
+	}
+	if len(batch.pending) > 0 && !force {
+		return
+	}
+	batch.built = true
+	if batch.timer != nil {
+		batch.timer.Stop()
+	}
+	if len(batch.ready) == 0 {
+		delete(o.batches, batchId)
+		return
+	}
+	members := make([]*openingBatchMember, len(batch.ready))
+	copy(members, batch.ready)
+	// The swap that triggers the build holds the lock of its fsm.
+	go o.build(batchId, batch.chain, members)
+}
+
+// openingBatchDoneContext hands the persisted batch tx to a swap. The swap
+// leaves the batch and is opened on its own if no batch tx was persisted.
+type openingBatchDoneContext struct {
+	fsm *SwapStateMachine
+}
+
+func (c *openingBatchDoneContext) Validate(data *SwapData) error {
+	return nil
+}
+
+func (c *openingBatchDoneContext) ApplyToSwapData(data *SwapData) error {
+	// Called by SendEvent, the fsm is locked.
+	if c.fsm.Current != State_SwapInSender_AwaitOpeningBatch {
+		return fmt.Errorf("swap is not waiting for an opening batch, state: %s", c.fsm.Current)
+	}
+	if data.BatchOpeningTxId == "" {
+		data.OpeningBatchId = ""
+	}
+	return nil
+}
+
+// SwapInBatch starts swap-ins on chain whose opening outputs are paid by a
+// single opening tx. A swap that is not agreed within a minute, or whose
+// peer cancels, leaves the batch without holding up the others. The result
+// of each request is returned in order.
+func (s *SwapService) SwapInBatch(chain string, initiator string, requests []SwapInBatchRequest) ([]SwapInBatchResult, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("no swaps in batch")
+	}
+	if chain != btc_chain && chain != l_btc_chain {
+		return nil, errors.New("invalid chain")
+	}
+
+	batchId := newSwapId()
+	s.swapServices.openingBatches.create(batchId, chain)
+
+	results := make([]SwapInBatchResult, len(requests))
+	for i, request := range requests {
+		results[i].Swap, results[i].Err = s.swapIn(request.PeerId, chain, request.ChannelId, initiator, request.AmtSat, false, batchId)
+	}
+	s.swapServices.openingBatches.seal(batchId)
+	return results, nil
+}
+
+// buildOpeningBatch creates and broadcasts the opening tx of a batch and
+// hands it to the swaps. If this fails the swaps are opened on their own.
+func (s *SwapService) buildOpeningBatch(batchId string, chain string, members []*openingBatchMember) {
+	defer s.swapServices.openingBatches.done(batchId)
+
+	// Hold the swaps while the batch tx is created, persisted and broadcast,
+	// so that no swap leaves the batch in between. Swaps that were canceled
+	// after the batch was sealed are dropped.
+	var swaps []*SwapStateMachine
+	var params []*OpeningParams
+	for _, member := range members {
+		swap, err := s.GetActiveSwap(member.swapId)
+		if err != nil {
+			continue
+		}
+		swap.mutex.Lock()
+		if swap.Current != State_SwapInSender_AwaitOpeningBatch {
+			swap.mutex.Unlock()
+			continue
+		}
+		swaps = append(swaps, swap)
+		params = append(params, member.params)
+	}
+	if len(swaps) == 0 {
+		return
+	}
+
+	txId, err := s.commitOpeningBatch(chain, swaps, params)
+	for _, swap := range swaps {
+		swap.mutex.Unlock()
+	}
+	if err != nil {
+		log.Infof("[OpeningBatch] opening tx of batch %s with %d swaps failed, opening them on their own: %v", batchId, len(swaps), err)
+	} else {
+		log.Infof("[OpeningBatch] broadcasted opening tx %s of batch %s with %d swaps", txId, batchId, len(swaps))
+	}
+
+	for _, swap := range swaps {
+		done, err := swap.SendEvent(Event_SwapInSender_OnOpeningBatchDone, &openingBatchDoneContext{fsm: swap})
+		if err == ErrEventRejected {
+			continue
+		}
+		if err != nil {
+			swap.logger().Debugf("[SwapService] SendEvent(): %v", err)
+			continue
+		}
+		if done {
+			s.RemoveActiveSwap(swap.SwapId.String())
+		}
+	}
+}
+
+// commitOpeningBatch creates the batch tx, persists it on the swaps and
+// broadcasts it. The tx is persisted first, so that a swap that recovers
+// after a crash publishes it again instead of funding a second opening tx.
+// If the broadcast fails because the tx is known already it is kept, if the
+// node rejected it the tx is dropped and the swaps open on their own. Must be
+// called with the swaps locked.
+func (s *SwapService) commitOpeningBatch(chain string, swaps []*SwapStateMachine, params []*OpeningParams) (string, error) {
+	txWatcher, wallet, _, err := s.swapServices.getOnChainServices(chain)
+	if err != nil {
+		return "", err
+	}
+	txId, txHex, vouts, err := wallet.CreateBatchOpeningTransaction(params)
+	if err != nil {
+		return "", err
+	}
+	height, err := txWatcher.GetBlockHeight()
+	if err != nil {
+		releaseBatchOpeningTx(wallet, txId, txHex)
+		return "", err
+	}
+
+	for i, swap := range swaps {
+		swap.Data.BatchOpeningTxId = txId
+		swap.Data.BatchOpeningTxHex = txHex
+		swap.Data.BatchOpeningVout = vouts[i]
+		swap.Data.StartingBlockHeight = height
+		err = s.swapServices.swapStore.UpdateData(swap)
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		_, err = wallet.BroadcastTx(txHex)
+		if err != nil && !txRejected(err) {
+			log.Infof("[OpeningBatch] broadcasting opening tx %s: %v", txId, err)
+			err = nil
+		}
+	}
+	if err != nil {
+		// The tx was not accepted, so it is safe to drop it.
+		for _, swap := range swaps {
+			swap.Data.resetBatchOpeningTx()
+			if err := s.swapServices.swapStore.UpdateData(swap); err != nil {
+				swap.logger().Warnf("[OpeningBatch] could not reset the batch opening tx: %v", err)
+			}
+		}
+		releaseBatchOpeningTx(wallet, txId, txHex)
+		return "", err
+	}
+
+	for _, swap := range swaps {
+		swap.Data.BatchOpeningTxPublished = true
+		if err := s.swapServices.swapStore.UpdateData(swap); err != nil {
+			swap.logger().Warnf("[OpeningBatch] could not persist the published batch opening tx: %v", err)
+		}
+	}
+	return txId, nil
+}
+
+// resetBatchOpeningTx drops the batch opening tx, the swap opens on its own.
+func (s *SwapData) resetBatchOpeningTx() {
+	s.BatchOpeningTxId = ""
+	s.BatchOpeningTxHex = ""
+	s.BatchOpeningVout = 0
+	s.BatchOpeningTxPublished = false
+	s.StartingBlockHeight = 0
+}
+
+// releaseBatchOpeningTx releases the wallet inputs of a dropped batch tx.
+func releaseBatchOpeningTx(wallet Wallet, txId, txHex string) {
+	if err := wallet.ReleaseBatchOpeningTransaction(txHex); err != nil {
+		log.Infof("[OpeningBatch] could not release the inputs of opening tx %s: %v", txId, err)
+	}
+}
+
+// knownTxErrors are the broadcast errors of bitcoind, elementsd, lnd and btcd
+// that tell that the tx is known already.
+var knownTxErrors = []string{
+	"already in block chain",
+	"already in utxo set",
+	"already-in-mempool",
+	"already in mempool",
+	"already known",
+	"already-known",
+	"already have transaction",
+	"transaction already exists",
+}
+
+// txRejected returns true if a broadcast failed because the node rejected
+// the tx, false if the tx is known already.
+func txRejected(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, known := range knownTxErrors {
+		if strings.Contains(msg, known) {
+			return false
+		}
+	}
+	return true
+}
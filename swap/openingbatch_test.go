@@ -0,0 +1,342 @@
+package swap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elementsproject/peerswap/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SwapInBatch(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, _ := getTestParams()
+
+	aliceSwapService, bobSwapService, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+
+	results, err := aliceSwapService.SwapInBatch(btc_chain, initiator, []SwapInBatchRequest{
+		{PeerId: peer, ChannelId: "1x1x1", AmtSat: amount},
+		{PeerId: peer, ChannelId: "2x2x2", AmtSat: amount},
+		// Only one swap per channel, the failed swap does not hold up the
+		// batch.
+		{PeerId: peer, ChannelId: "2x2x2", AmtSat: amount},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+	assert.Error(t, results[2].Err)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINREQUEST, <-bobMsgChan)
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINAGREEMENT, <-aliceMsgChan)
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	}
+
+	// Both swaps are opened by the same tx, each with its own vout.
+	first, second := results[0].Swap, results[1].Swap
+	first.mutex.Lock()
+	second.mutex.Lock()
+	defer first.mutex.Unlock()
+	defer second.mutex.Unlock()
+	assert.Equal(t, 1, aliceSwapService.swapServices.bitcoinWallet.(*dummyChain).batchOpenings)
+	assert.Equal(t, State_SwapInSender_AwaitClaimPayment, first.Current)
+	assert.Equal(t, State_SwapInSender_AwaitClaimPayment, second.Current)
+	assert.Equal(t, first.Data.OpeningTxBroadcasted.TxId, second.Data.OpeningTxBroadcasted.TxId)
+	assert.ElementsMatch(t, []uint32{0, 1}, []uint32{first.Data.OpeningTxBroadcasted.ScriptOut, second.Data.OpeningTxBroadcasted.ScriptOut})
+	assert.Equal(t, "batchtxhex", first.Data.OpeningTxHex)
+	assert.Zero(t, first.Data.TimeOutAt)
+}
+
+func Test_SwapInBatchFailed(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, _ := getTestParams()
+
+	aliceSwapService, bobSwapService, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+	aliceSwapService.swapServices.bitcoinWallet.(*dummyChain).batchOpeningErr = errors.New("insufficient funds")
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+
+	results, err := aliceSwapService.SwapInBatch(btc_chain, initiator, []SwapInBatchRequest{
+		{PeerId: peer, ChannelId: "1x1x1", AmtSat: amount},
+		{PeerId: peer, ChannelId: "2x2x2", AmtSat: amount},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINREQUEST, <-bobMsgChan)
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINAGREEMENT, <-aliceMsgChan)
+	}
+	// The swaps are opened on their own.
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	}
+	for _, result := range results {
+		result.Swap.mutex.Lock()
+		assert.Equal(t, State_SwapInSender_AwaitClaimPayment, result.Swap.Current)
+		assert.Empty(t, result.Swap.Data.OpeningBatchId)
+		assert.Equal(t, "txhex", result.Swap.Data.OpeningTxHex)
+		result.Swap.mutex.Unlock()
+	}
+}
+
+// startHeldBatch starts a batch of two swap-ins whose batch is not built but
+// handed to the test once both swaps agreed.
+func startHeldBatch(t *testing.T) (alice *SwapService, bobMsgChan chan messages.MessageType, batchId string, members []*openingBatchMember) {
+	amount := uint64(100000)
+	initiator, peer, _, _, _ := getTestParams()
+
+	alice, bob, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+	held := make(chan []*openingBatchMember, 1)
+	alice.swapServices.openingBatches.build = func(id string, chain string, m []*openingBatchMember) {
+		batchId = id
+		held <- m
+	}
+	require.NoError(t, alice.Start())
+	require.NoError(t, bob.Start())
+
+	_, err := alice.SwapInBatch(btc_chain, initiator, []SwapInBatchRequest{
+		{PeerId: peer, ChannelId: "1x1x1", AmtSat: amount},
+		{PeerId: peer, ChannelId: "2x2x2", AmtSat: amount},
+	})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINREQUEST, <-bobMsgChan)
+	}
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINAGREEMENT, <-aliceMsgChan)
+	}
+	members = <-held
+	require.Len(t, members, 2)
+	return alice, bobMsgChan, batchId, members
+}
+
+func Test_OpeningBatchRecover(t *testing.T) {
+	alice, bobMsgChan, batchId, members := startHeldBatch(t)
+	chain := alice.swapServices.bitcoinWallet.(*dummyChain)
+
+	// We go down after the batch tx was persisted.
+	var swaps []*SwapStateMachine
+	var params []*OpeningParams
+	for _, member := range members {
+		swap, err := alice.GetActiveSwap(member.swapId)
+		require.NoError(t, err)
+		swaps = append(swaps, swap)
+		params = append(params, member.params)
+		swap.mutex.Lock()
+	}
+	_, err := alice.commitOpeningBatch(btc_chain, swaps, params)
+	require.NoError(t, err)
+	chain.broadcastedTx = ""
+	for _, swap := range swaps {
+		swap.mutex.Unlock()
+	}
+	alice.swapServices.openingBatches.done(batchId)
+
+	// The swaps publish the persisted batch tx again instead of funding
+	// opening txs of their own.
+	for _, swap := range swaps {
+		_, err := swap.Recover()
+		require.NoError(t, err)
+		assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	}
+	assert.Equal(t, "batchtxhex", chain.broadcastedTx)
+	assert.Equal(t, 1, chain.batchOpenings)
+	for i, swap := range swaps {
+		swap.mutex.Lock()
+		assert.Equal(t, State_SwapInSender_AwaitClaimPayment, swap.Current)
+		assert.Equal(t, dummyBatchTxId, swap.Data.OpeningTxBroadcasted.TxId)
+		assert.Equal(t, uint32(i), swap.Data.OpeningTxBroadcasted.ScriptOut)
+		swap.mutex.Unlock()
+	}
+}
+
+func Test_OpeningBatchBroadcastFailed(t *testing.T) {
+	alice, bobMsgChan, batchId, members := startHeldBatch(t)
+	chain := alice.swapServices.bitcoinWallet.(*dummyChain)
+
+	// The tx is known already, the swaps keep the batch tx instead of
+	// funding opening txs of their own.
+	chain.broadcastTxErr = errors.New("txn-already-in-mempool")
+	alice.buildOpeningBatch(batchId, btc_chain, members)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	}
+	assert.Equal(t, 1, chain.batchOpenings)
+	for _, member := range members {
+		swap, err := alice.GetActiveSwap(member.swapId)
+		require.NoError(t, err)
+		swap.mutex.Lock()
+		assert.Equal(t, State_SwapInSender_AwaitClaimPayment, swap.Current)
+		assert.Equal(t, dummyBatchTxId, swap.Data.OpeningTxBroadcasted.TxId)
+		assert.Equal(t, "batchtxhex", swap.Data.OpeningTxHex)
+		swap.mutex.Unlock()
+	}
+}
+
+func Test_OpeningBatchRejected(t *testing.T) {
+	alice, bobMsgChan, batchId, members := startHeldBatch(t)
+	chain := alice.swapServices.bitcoinWallet.(*dummyChain)
+
+	// A rejected batch tx is dropped and its inputs are released, the swaps
+	// open on their own.
+	chain.broadcastTxErr = errors.New("bad-txns-inputs-missingorspent")
+	alice.buildOpeningBatch(batchId, btc_chain, members)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	}
+	assert.Equal(t, []string{"batchtxhex"}, chain.releasedBatchTxs)
+	for _, member := range members {
+		swap, err := alice.GetActiveSwap(member.swapId)
+		require.NoError(t, err)
+		swap.mutex.Lock()
+		assert.Equal(t, State_SwapInSender_AwaitClaimPayment, swap.Current)
+		assert.Empty(t, swap.Data.BatchOpeningTxId)
+		assert.NotEqual(t, dummyBatchTxId, swap.Data.OpeningTxBroadcasted.TxId)
+		assert.Equal(t, "txhex", swap.Data.OpeningTxHex)
+		swap.mutex.Unlock()
+	}
+}
+
+func Test_OpeningBatchRecoverRejected(t *testing.T) {
+	alice, bobMsgChan, batchId, members := startHeldBatch(t)
+	chain := alice.swapServices.bitcoinWallet.(*dummyChain)
+
+	// We go down after the batch tx was persisted but before it was
+	// published.
+	var swaps []*SwapStateMachine
+	var params []*OpeningParams
+	for _, member := range members {
+		swap, err := alice.GetActiveSwap(member.swapId)
+		require.NoError(t, err)
+		swaps = append(swaps, swap)
+		params = append(params, member.params)
+		swap.mutex.Lock()
+	}
+	_, err := alice.commitOpeningBatch(btc_chain, swaps, params)
+	require.NoError(t, err)
+	for _, swap := range swaps {
+		swap.Data.BatchOpeningTxPublished = false
+		swap.mutex.Unlock()
+	}
+	alice.swapServices.openingBatches.done(batchId)
+
+	// The node rejects the batch tx on recovery, the swaps open on their
+	// own.
+	chain.broadcastTxErr = errors.New("min relay fee not met")
+	for _, swap := range swaps {
+		_, err := swap.Recover()
+		require.NoError(t, err)
+		assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	}
+	assert.Len(t, chain.releasedBatchTxs, 2)
+	for _, swap := range swaps {
+		swap.mutex.Lock()
+		assert.Empty(t, swap.Data.BatchOpeningTxId)
+		assert.Empty(t, swap.Data.OpeningBatchId)
+		assert.Equal(t, "txhex", swap.Data.OpeningTxHex)
+		swap.mutex.Unlock()
+	}
+}
+
+func Test_OpeningBatchCanceled(t *testing.T) {
+	alice, _, batchId, members := startHeldBatch(t)
+	chain := alice.swapServices.bitcoinWallet.(*dummyChain)
+
+	// The peer of the first swap cancels before the batch is built, its
+	// output is dropped.
+	first, err := alice.GetActiveSwap(members[0].swapId)
+	require.NoError(t, err)
+	_, err = first.SendEvent(Event_OnCancelReceived, &CancelMessage{SwapId: first.SwapId, Message: "canceled"})
+	require.NoError(t, err)
+	assert.Equal(t, State_SwapCanceled, first.Current)
+
+	second, err := alice.GetActiveSwap(members[1].swapId)
+	require.NoError(t, err)
+	second.mutex.Lock()
+	_, err = alice.commitOpeningBatch(btc_chain, []*SwapStateMachine{second}, []*OpeningParams{members[1].params})
+	second.mutex.Unlock()
+	require.NoError(t, err)
+	assert.Equal(t, 1, chain.batchOpeningSize)
+
+	// The peer of the second swap cancels after the batch tx was persisted,
+	// the opening output is reclaimed after the csv limit.
+	_, err = second.SendEvent(Event_OnCancelReceived, &CancelMessage{SwapId: second.SwapId, Message: "canceled"})
+	require.NoError(t, err)
+	second.mutex.Lock()
+	defer second.mutex.Unlock()
+	assert.Equal(t, State_WaitCsv, second.Current)
+	assert.Equal(t, dummyBatchTxId, second.Data.OpeningTxBroadcasted.TxId)
+	assert.Equal(t, "batchtxhex", second.Data.OpeningTxHex)
+
+	alice.swapServices.openingBatches.done(batchId)
+}
+
+func Test_OpeningBatchDropsCanceled(t *testing.T) {
+	alice, bobMsgChan, batchId, members := startHeldBatch(t)
+	chain := alice.swapServices.bitcoinWallet.(*dummyChain)
+
+	first, err := alice.GetActiveSwap(members[0].swapId)
+	require.NoError(t, err)
+	_, err = first.SendEvent(Event_OnCancelReceived, &CancelMessage{SwapId: first.SwapId, Message: "canceled"})
+	require.NoError(t, err)
+
+	// The batch is built without the canceled swap.
+	alice.buildOpeningBatch(batchId, btc_chain, members)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-bobMsgChan)
+	assert.Equal(t, 1, chain.batchOpeningSize)
+	assert.Equal(t, "batchtxhex", chain.broadcastedTx)
+}
+
+func Test_OpeningBatches(t *testing.T) {
+	oldWindow := openingBatchWindow
+	openingBatchWindow = 100 * time.Millisecond
+	defer func() { openingBatchWindow = oldWindow }()
+
+	built := make(chan []*openingBatchMember, 1)
+	batches := newOpeningBatches()
+	batches.build = func(batchId string, chain string, members []*openingBatchMember) {
+		built <- members
+	}
+
+	// A swap that ended does not hold up the batch.
+	batches.create("batch", btc_chain)
+	batches.addPending("batch", "a")
+	batches.addPending("batch", "b")
+	batches.addPending("batch", "c")
+	batches.seal("batch")
+	assert.True(t, batches.add("batch", "a", &OpeningParams{}))
+	assert.True(t, batches.isQueued("batch", "a"))
+	batches.remove("batch", "b")
+	assert.True(t, batches.add("batch", "c", &OpeningParams{}))
+	members := <-built
+	require.Len(t, members, 2)
+	assert.Equal(t, "a", members[0].swapId)
+	assert.Equal(t, "c", members[1].swapId)
+
+	// Swaps that agree after the batch was built are opened on their own.
+	assert.False(t, batches.add("batch", "c", &OpeningParams{}))
+	batches.done("batch")
+	assert.False(t, batches.isQueued("batch", "a"))
+
+	// The batch is built without the swaps that did not agree in time.
+	batches.create("late", btc_chain)
+	batches.addPending("late", "a")
+	batches.addPending("late", "b")
+	batches.seal("late")
+	assert.True(t, batches.add("late", "a", &OpeningParams{}))
+	members = <-built
+	require.Len(t, members, 1)
+	assert.Equal(t, "a", members[0].swapId)
+	assert.False(t, batches.add("late", "b", &OpeningParams{}))
+}
@@ -0,0 +1,150 @@
+package swap
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elementsproject/peerswap/messages"
+)
+
+const (
+	// suspiciousViolations is the number of rate limit violations within
+	// violationWindow after which a peer is added to the suspicious peer
+	// list.
+	suspiciousViolations = 100
+	// violationWindow is the time after which the violations of a peer are
+	// forgotten, so that occasional violations of long-lived peers do not
+	// add up.
+	violationWindow = 24 * time.Hour
+	// maxRateLimitBuckets is the number of buckets after which full buckets
+	// are dropped, they are recreated full when needed.
+	maxRateLimitBuckets = 1000
+)
+
+// rateLimit allows Burst messages at once and refills at Rate messages per
+// second.
+type rateLimit struct {
+	Rate  float64
+	Burst float64
+}
+
+// defaultRateLimits are the limits of incoming messages per peer and message
+// type. Swap requests create a swap, so they are limited the most. Polls are
+// sent hourly and on reconnects, a request for a poll is answered with one.
+var defaultRateLimits = map[messages.MessageType]rateLimit{
+	messages.MESSAGETYPE_SWAPINREQUEST:        {Rate: 1.0 / 60, Burst: 5},
+	messages.MESSAGETYPE_SWAPOUTREQUEST:       {Rate: 1.0 / 60, Burst: 5},
+	messages.MESSAGETYPE_SWAPINAGREEMENT:      {Rate: 1, Burst: 20},
+	messages.MESSAGETYPE_SWAPOUTAGREEMENT:     {Rate: 1, Burst: 20},
+	messages.MESSAGETYPE_OPENINGTXBROADCASTED: {Rate: 1, Burst: 20},
+	messages.MESSAGETYPE_CANCELED:             {Rate: 1, Burst: 20},
+	messages.MESSAGETYPE_COOPCLOSE:            {Rate: 1, Burst: 20},
+	messages.MESSAGETYPE_ACK:                  {Rate: 10, Burst: 50},
+	messages.MESSAGETYPE_POLL:                 {Rate: 1.0 / 60, Burst: 10},
+	messages.MESSAGETYPE_REQUEST_POLL:         {Rate: 1.0 / 60, Burst: 10},
+}
+
+type bucketKey struct {
+	peerId  string
+	msgType messages.MessageType
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// violationCount counts the violations of a peer since the start of its
+// window.
+type violationCount struct {
+	count int
+	since time.Time
+}
+
+// rateLimiter holds a token bucket per peer and message type and counts the
+// messages that were dropped per peer within violationWindow.
+type rateLimiter struct {
+	sync.Mutex
+	limits     map[messages.MessageType]rateLimit
+	buckets    map[bucketKey]*tokenBucket
+	violations map[string]*violationCount
+}
+
+func newRateLimiter(limits map[messages.MessageType]rateLimit) *rateLimiter {
+	return &rateLimiter{
+		limits:     limits,
+		buckets:    map[bucketKey]*tokenBucket{},
+		violations: map[string]*violationCount{},
+	}
+}
+
+// allow takes a token from the bucket of the peer and message type. If the
+// bucket is empty it returns false and the number of violations of the peer.
+// Message types without a limit are always allowed.
+func (r *rateLimiter) allow(peerId string, msgType messages.MessageType, now time.Time) (bool, int) {
+	limit, ok := r.limits[msgType]
+	if !ok {
+		return true, 0
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	key := bucketKey{peerId: peerId, msgType: msgType}
+	bucket, ok := r.buckets[key]
+	if !ok {
+		r.prune(now)
+		bucket = &tokenBucket{tokens: limit.Burst, last: now}
+		r.buckets[key] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limit.Rate
+	if bucket.tokens > limit.Burst {
+		bucket.tokens = limit.Burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false, r.violate(peerId, now)
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// addViolation counts a violation of the limits that is not caught by the
+// buckets and returns the number of violations of the peer.
+func (r *rateLimiter) addViolation(peerId string, now time.Time) int {
+	r.Lock()
+	defer r.Unlock()
+	return r.violate(peerId, now)
+}
+
+// violate counts a violation of the peer and returns the number of its
+// violations within the window. The lock must be held.
+func (r *rateLimiter) violate(peerId string, now time.Time) int {
+	v, ok := r.violations[peerId]
+	if !ok || now.Sub(v.since) > violationWindow {
+		v = &violationCount{since: now}
+		r.violations[peerId] = v
+	}
+	v.count++
+	return v.count
+}
+
+// prune drops the buckets that are full again and the violations of expired
+// windows if there are too many buckets. The lock must be held.
+func (r *rateLimiter) prune(now time.Time) {
+	if len(r.buckets) < maxRateLimitBuckets {
+		return
+	}
+	for peerId, v := range r.violations {
+		if now.Sub(v.since) > violationWindow {
+			delete(r.violations, peerId)
+		}
+	}
+	for key, bucket := range r.buckets {
+		limit := r.limits[key.msgType]
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate >= limit.Burst {
+			delete(r.buckets, key)
+		}
+	}
+}
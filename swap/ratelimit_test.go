@@ -0,0 +1,59 @@
+package swap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elementsproject/peerswap/messages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(map[messages.MessageType]rateLimit{
+		messages.MESSAGETYPE_SWAPINREQUEST: {Rate: 1, Burst: 2},
+	})
+	now := time.Now()
+
+	// The burst is allowed at once.
+	for i := 0; i < 2; i++ {
+		ok, _ := limiter.allow("peer", messages.MESSAGETYPE_SWAPINREQUEST, now)
+		assert.True(t, ok)
+	}
+	ok, violations := limiter.allow("peer", messages.MESSAGETYPE_SWAPINREQUEST, now)
+	assert.False(t, ok)
+	assert.Equal(t, 1, violations)
+
+	// Other peers and message types have their own buckets.
+	ok, _ = limiter.allow("other", messages.MESSAGETYPE_SWAPINREQUEST, now)
+	assert.True(t, ok)
+	for i := 0; i < 10; i++ {
+		ok, _ = limiter.allow("peer", messages.MESSAGETYPE_CANCELED, now)
+		assert.True(t, ok)
+	}
+
+	// The bucket refills at the rate.
+	ok, _ = limiter.allow("peer", messages.MESSAGETYPE_SWAPINREQUEST, now.Add(time.Second))
+	assert.True(t, ok)
+	ok, violations = limiter.allow("peer", messages.MESSAGETYPE_SWAPINREQUEST, now.Add(time.Second))
+	assert.False(t, ok)
+	assert.Equal(t, 2, violations)
+	assert.Equal(t, 3, limiter.addViolation("peer", now.Add(time.Second)))
+
+	// The violations are forgotten after the window.
+	assert.Equal(t, 1, limiter.addViolation("peer", now.Add(violationWindow+time.Minute)))
+}
+
+func TestRateLimiter_Prune(t *testing.T) {
+	limiter := newRateLimiter(map[messages.MessageType]rateLimit{
+		messages.MESSAGETYPE_SWAPINREQUEST: {Rate: 1, Burst: 2},
+	})
+	now := time.Now()
+	for i := 0; i < maxRateLimitBuckets; i++ {
+		limiter.allow(getRandom33ByteHexString(), messages.MESSAGETYPE_SWAPINREQUEST, now)
+	}
+	assert.Len(t, limiter.buckets, maxRateLimitBuckets)
+
+	// Full buckets are dropped.
+	limiter.allow("peer", messages.MESSAGETYPE_SWAPINREQUEST, now.Add(time.Second))
+	assert.Len(t, limiter.buckets, 1)
+}
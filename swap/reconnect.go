@@ -0,0 +1,112 @@
+package swap
+
+import (
+	"time"
+
+	"github.com/elementsproject/peerswap/log"
+)
+
+// maxTimeoutPause is the longest time the timeouts of a swap are paused in
+// total while the peer is offline.
+const maxTimeoutPause = 30 * time.Minute
+
+// reconnectInterval is the interval in which we try to reconnect to an
+// offline peer that we have active swaps with.
+var reconnectInterval = time.Minute
+
+// OnPeerOffline pauses the timeouts of the swaps with the peer and tries to
+// reconnect to the peer as long as we have active swaps with it.
+func (s *SwapService) OnPeerOffline(peerId string) {
+	swaps := s.activeSwapsWithPeer(peerId)
+	if len(swaps) == 0 {
+		return
+	}
+	for _, swap := range swaps {
+		timeOutAt, err := swap.PauseTimeout()
+		if err != nil {
+			swap.logger().Infof("[SwapService] could not pause timeout: %v", err)
+			continue
+		}
+		swap.logger().Debugf("[SwapService] peer went offline, timeout paused until %s", time.Unix(timeOutAt, 0))
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.reconnecting[peerId]; ok {
+		return
+	}
+	stop := make(chan struct{})
+	s.reconnecting[peerId] = stop
+	go s.reconnect(peerId, stop)
+}
+
+// OnPeerOnline resumes the timeouts of the swaps with the peer and resends the
+// last message of the swaps that wait for the peer right away, as the backoff
+// of the outbox might only resend it after the timeout fired.
+func (s *SwapService) OnPeerOnline(peerId string) {
+	s.Lock()
+	if stop, ok := s.reconnecting[peerId]; ok {
+		close(stop)
+		delete(s.reconnecting, peerId)
+	}
+	s.Unlock()
+
+	for _, swap := range s.activeSwapsWithPeer(peerId) {
+		waiting, err := swap.ResumeTimeout()
+		if err != nil {
+			swap.logger().Infof("[SwapService] could not resume timeout: %v", err)
+			continue
+		}
+		if !waiting {
+			continue
+		}
+		err = s.ResendLastMessage(swap.SwapId.String())
+		if err != nil {
+			swap.logger().Infof("[SwapService] could not resend last message: %v", err)
+		}
+	}
+}
+
+// reconnect tries to connect to the peer until it is online again, the stop
+// channel is closed or no active swap with the peer is left.
+func (s *SwapService) reconnect(peerId string, stop chan struct{}) {
+	logger := log.WithFields(log.Fields{log.PeerField: peerId})
+	ticker := time.NewTicker(reconnectInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		if len(s.activeSwapsWithPeer(peerId)) == 0 {
+			s.Lock()
+			if s.reconnecting[peerId] == stop {
+				delete(s.reconnecting, peerId)
+			}
+			s.Unlock()
+			return
+		}
+
+		err := s.swapServices.lightning.ConnectPeer(peerId)
+		if err != nil {
+			logger.Debugf("[SwapService] could not reconnect: %v", err)
+			continue
+		}
+		logger.Infof("[SwapService] reconnected to peer")
+	}
+}
+
+// activeSwapsWithPeer returns the active swaps with the peer.
+func (s *SwapService) activeSwapsWithPeer(peerId string) []*SwapStateMachine {
+	s.RLock()
+	defer s.RUnlock()
+	var swaps []*SwapStateMachine
+	for _, swap := range s.activeSwaps {
+		if swap.Data.PeerNodeId == peerId {
+			swaps = append(swaps, swap)
+		}
+	}
+	return swaps
+}
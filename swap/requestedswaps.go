@@ -4,8 +4,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 )
 
+// RequestedSwapsFilter selects requested swaps. Zero values match all
+// requests.
+type RequestedSwapsFilter struct {
+	PeerId string
+	Since  time.Time
+	Until  time.Time
+}
+
+// match returns true if a request of peerId matches the filter. Requests
+// without a timestamp never match a time bound.
+func (f RequestedSwapsFilter) match(peerId string, reqswap RequestedSwap) bool {
+	if f.PeerId != "" && f.PeerId != peerId {
+		return false
+	}
+	if !f.Since.IsZero() && (reqswap.CreatedAt == 0 || reqswap.CreatedAt < f.Since.Unix()) {
+		return false
+	}
+	if !f.Until.IsZero() && (reqswap.CreatedAt == 0 || reqswap.CreatedAt > f.Until.Unix()) {
+		return false
+	}
+	return true
+}
+
 type JsonEnty struct {
 	NodeId   string                                  `json:"node_id"`
 	Requests map[string]map[string]*JsonAssetRequest `json:"requests"`
@@ -14,6 +38,13 @@ type JsonEnty struct {
 type JsonAssetRequest struct {
 	TotalAmountSat uint64 `json:"total_amount_sat"`
 	NRequests      uint64 `json:"n_requests"`
+	// RejectionCodes counts the requests by the code they were rejected
+	// with.
+	RejectionCodes map[string]uint64 `json:"rejection_codes,omitempty"`
+	// FirstRequestAt and LastRequestAt are the unix times of the oldest and
+	// the latest timestamped request.
+	FirstRequestAt int64 `json:"first_request_at,omitempty"`
+	LastRequestAt  int64 `json:"last_request_at,omitempty"`
 }
 
 type RequestedSwapsPrinter struct {
@@ -25,7 +56,7 @@ func NewRequestedSwapsPrinter(store RequestedSwapsStore) *RequestedSwapsPrinter
 }
 
 func (p *RequestedSwapsPrinter) Write(w io.Writer) {
-	reqswaps, err := p.Get()
+	reqswaps, err := p.Get(RequestedSwapsFilter{})
 	if err != nil {
 		w.Write([]byte(fmt.Sprintf("error reading requested swaps: %v", err)))
 	}
@@ -39,11 +70,26 @@ func (p *RequestedSwapsPrinter) Write(w io.Writer) {
 	w.Write(b)
 }
 
-func (p *RequestedSwapsPrinter) GetRaw() (map[string][]RequestedSwap, error) {
-	return p.store.GetAll()
+// GetRaw returns the requested swaps that match the filter by peer.
+func (p *RequestedSwapsPrinter) GetRaw(filter RequestedSwapsFilter) (map[string][]RequestedSwap, error) {
+	all, err := p.store.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	reqswaps := map[string][]RequestedSwap{}
+	for peerId, reqswapz := range all {
+		for _, reqswap := range reqswapz {
+			if filter.match(peerId, reqswap) {
+				reqswaps[peerId] = append(reqswaps[peerId], reqswap)
+			}
+		}
+	}
+	return reqswaps, nil
 }
-func (p *RequestedSwapsPrinter) Get() ([]JsonEnty, error) {
-	reqswaps, err := p.store.GetAll()
+
+func (p *RequestedSwapsPrinter) Get(filter RequestedSwapsFilter) ([]JsonEnty, error) {
+	reqswaps, err := p.GetRaw(filter)
 	if err != nil {
 		return nil, fmt.Errorf("error reading requested swaps: %w", err)
 	}
@@ -61,6 +107,22 @@ func (p *RequestedSwapsPrinter) Get() ([]JsonEnty, error) {
 				e.Requests[reqswap.Type.JsonFieldValue()][reqswap.Asset].TotalAmountSat = e.Requests[reqswap.Type.JsonFieldValue()][reqswap.Asset].TotalAmountSat + reqswap.AmountSat
 				e.Requests[reqswap.Type.JsonFieldValue()][reqswap.Asset].NRequests = e.Requests[reqswap.Type.JsonFieldValue()][reqswap.Asset].NRequests + 1
 			}
+			if reqswap.RejectionCode > 0 {
+				assetRequest := e.Requests[reqswap.Type.JsonFieldValue()][reqswap.Asset]
+				if assetRequest.RejectionCodes == nil {
+					assetRequest.RejectionCodes = map[string]uint64{}
+				}
+				assetRequest.RejectionCodes[reqswap.RejectionCode.String()]++
+			}
+			if reqswap.CreatedAt > 0 {
+				assetRequest := e.Requests[reqswap.Type.JsonFieldValue()][reqswap.Asset]
+				if assetRequest.FirstRequestAt == 0 || reqswap.CreatedAt < assetRequest.FirstRequestAt {
+					assetRequest.FirstRequestAt = reqswap.CreatedAt
+				}
+				if reqswap.CreatedAt > assetRequest.LastRequestAt {
+					assetRequest.LastRequestAt = reqswap.CreatedAt
+				}
+			}
 		}
 		reqbuf = append(reqbuf, e)
 	}
@@ -1,10 +1,14 @@
 package swap
 
 import (
+	"path"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
 )
 
 func TestPrint(t *testing.T) {
@@ -57,3 +61,155 @@ func TestPrint(t *testing.T) {
 	eq := reflect.DeepEqual(want, got)
 	assert.True(t, eq)
 }
+
+func TestPrint_RejectionCodes(t *testing.T) {
+	store := &requestedSwapsStoreMock{
+		data: map[string][]RequestedSwap{
+			"node1": {
+				{Asset: "btc", AmountSat: 10000, Type: SWAPTYPE_IN, RejectionCode: CancelCode_PeerNotAllowed},
+				{Asset: "btc", AmountSat: 20000, Type: SWAPTYPE_IN, RejectionCode: CancelCode_PeerNotAllowed},
+				{Asset: "btc", AmountSat: 100, Type: SWAPTYPE_IN, RejectionCode: CancelCode_AmountTooSmall},
+				{Asset: "lbtc", AmountSat: 10000, Type: SWAPTYPE_IN},
+			},
+		},
+	}
+	sp := NewRequestedSwapsPrinter(store)
+	got, err := sp.Get(RequestedSwapsFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	btc := got[0].Requests[SWAPTYPE_IN.JsonFieldValue()]["btc"]
+	assert.Equal(t, uint64(3), btc.NRequests)
+	assert.Equal(t, map[string]uint64{"peer_not_allowed": 2, "amount_too_small": 1}, btc.RejectionCodes)
+
+	// Requests from before the codes were added have none.
+	assert.Nil(t, got[0].Requests[SWAPTYPE_IN.JsonFieldValue()]["lbtc"].RejectionCodes)
+}
+
+func TestPrint_Filter(t *testing.T) {
+	store := &requestedSwapsStoreMock{
+		data: map[string][]RequestedSwap{
+			"node1": {
+				{Asset: "btc", AmountSat: 100, Type: SWAPTYPE_IN},
+				{Asset: "btc", AmountSat: 200, Type: SWAPTYPE_IN, CreatedAt: 1000},
+				{Asset: "btc", AmountSat: 300, Type: SWAPTYPE_IN, CreatedAt: 2000},
+				{Asset: "btc", AmountSat: 400, Type: SWAPTYPE_IN, CreatedAt: 3000},
+			},
+			"node2": {
+				{Asset: "lbtc", AmountSat: 500, Type: SWAPTYPE_OUT, CreatedAt: 2000},
+			},
+		},
+	}
+	sp := NewRequestedSwapsPrinter(store)
+
+	got, err := sp.GetRaw(RequestedSwapsFilter{PeerId: "node1"})
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Len(t, got["node1"], 4)
+
+	// Requests without a timestamp do not match a time bound.
+	got, err = sp.GetRaw(RequestedSwapsFilter{Since: time.Unix(2000, 0), Until: time.Unix(2500, 0)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]RequestedSwap{
+		"node1": {{Asset: "btc", AmountSat: 300, Type: SWAPTYPE_IN, CreatedAt: 2000}},
+		"node2": {{Asset: "lbtc", AmountSat: 500, Type: SWAPTYPE_OUT, CreatedAt: 2000}},
+	}, got)
+
+	entries, err := sp.Get(RequestedSwapsFilter{PeerId: "node1"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	btc := entries[0].Requests[SWAPTYPE_IN.JsonFieldValue()]["btc"]
+	assert.Equal(t, uint64(4), btc.NRequests)
+	assert.Equal(t, uint64(1000), btc.TotalAmountSat)
+	assert.Equal(t, int64(1000), btc.FirstRequestAt)
+	assert.Equal(t, int64(3000), btc.LastRequestAt)
+}
+
+func TestRequestedSwapsStore_Bounded(t *testing.T) {
+	db, err := bbolt.Open(path.Join(t.TempDir(), "swaps"), 0700, nil)
+	require.NoError(t, err)
+	defer db.Close()
+	store, err := NewRequestedSwapsStore(db, DefaultRequestedSwapsRetention())
+	require.NoError(t, err)
+
+	for i := 0; i < DefaultRequestedSwapsMaxPerPeer+10; i++ {
+		err = store.Add("node1", RequestedSwap{Asset: "btc", AmountSat: uint64(i), Type: SWAPTYPE_IN})
+		require.NoError(t, err)
+	}
+
+	// Only the last requests are kept.
+	reqswaps, err := store.Get("node1")
+	require.NoError(t, err)
+	assert.Len(t, reqswaps, DefaultRequestedSwapsMaxPerPeer)
+	assert.Equal(t, uint64(10), reqswaps[0].AmountSat)
+}
+
+func TestRequestedSwapsRetention_Ceiling(t *testing.T) {
+	reqswaps := make([]RequestedSwap, MaxRequestedSwapsPerPeer+10)
+	for i := range reqswaps {
+		reqswaps[i].AmountSat = uint64(i)
+	}
+
+	// Unbounded and too large limits are capped to the ceiling.
+	for _, maxPerPeer := range []int{0, MaxRequestedSwapsPerPeer + 1} {
+		kept := RequestedSwapsRetention{MaxPerPeer: maxPerPeer}.apply(reqswaps, time.Now())
+		assert.Len(t, kept, MaxRequestedSwapsPerPeer)
+		assert.Equal(t, uint64(10), kept[0].AmountSat)
+	}
+}
+
+func TestRequestedSwapsStore_Retention(t *testing.T) {
+	db, err := bbolt.Open(path.Join(t.TempDir(), "swaps"), 0700, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Store the requests without retention first.
+	store, err := NewRequestedSwapsStore(db, RequestedSwapsRetention{})
+	require.NoError(t, err)
+	now := time.Now()
+	old := now.Add(-48 * time.Hour).Unix()
+	require.NoError(t, store.Add("node1", RequestedSwap{Asset: "btc", AmountSat: 1, CreatedAt: old}))
+	require.NoError(t, store.Add("node1", RequestedSwap{Asset: "btc", AmountSat: 2}))
+	require.NoError(t, store.Add("node1", RequestedSwap{Asset: "btc", AmountSat: 3, CreatedAt: now.Unix()}))
+	require.NoError(t, store.Add("node2", RequestedSwap{Asset: "btc", AmountSat: 4, CreatedAt: old}))
+
+	store, err = NewRequestedSwapsStore(db, RequestedSwapsRetention{MaxAge: 24 * time.Hour})
+	require.NoError(t, err)
+
+	// Expired requests are hidden on reads.
+	reqswaps, err := store.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, reqswaps, 1)
+	assert.Len(t, reqswaps["node1"], 2)
+
+	// Prune drops them from the db, requests without a timestamp are kept.
+	require.NoError(t, store.Prune())
+	store.retention = RequestedSwapsRetention{}
+	reqswaps, err = store.GetAll()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]RequestedSwap{
+		"node1": {
+			{Asset: "btc", AmountSat: 2},
+			{Asset: "btc", AmountSat: 3, CreatedAt: now.Unix()},
+		},
+	}, reqswaps)
+
+	// Adding a request prunes the requests of the other peers as well, once
+	// per prune interval.
+	require.NoError(t, store.Add("node2", RequestedSwap{Asset: "btc", AmountSat: 4, CreatedAt: old}))
+	store.retention = RequestedSwapsRetention{MaxAge: 24 * time.Hour}
+	require.NoError(t, store.Add("node3", RequestedSwap{Asset: "btc", AmountSat: 5, CreatedAt: now.Unix()}))
+	store.retention = RequestedSwapsRetention{}
+	reqswaps, err = store.GetAll()
+	require.NoError(t, err)
+	assert.Contains(t, reqswaps, "node2")
+
+	store.lastPrune = now.Add(-requestedSwapsPruneInterval)
+	store.retention = RequestedSwapsRetention{MaxAge: 24 * time.Hour}
+	require.NoError(t, store.Add("node3", RequestedSwap{Asset: "btc", AmountSat: 6, CreatedAt: now.Unix()}))
+	store.retention = RequestedSwapsRetention{}
+	reqswaps, err = store.GetAll()
+	require.NoError(t, err)
+	assert.NotContains(t, reqswaps, "node2")
+	assert.Len(t, reqswaps["node3"], 2)
+}
@@ -1,12 +1,13 @@
 package swap
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/elementsproject/peerswap/log"
 
@@ -17,6 +18,14 @@ const (
 	PEERSWAP_PROTOCOL_VERSION = 3
 )
 
+// seenMessagesSize is the number of handled swap messages that are
+// remembered to recognize resent messages.
+const seenMessagesSize = 1000
+
+// drainPollInterval is the interval in which WaitForDrain checks for active
+// swaps.
+var drainPollInterval = 10 * time.Second
+
 var (
 	AllowedAssets       = []string{"btc", "lbtc"}
 	ErrSwapDoesNotExist = errors.New("swap does not exist")
@@ -58,15 +67,42 @@ type SwapService struct {
 	BitcoinEnabled bool
 	LiquidEnabled  bool
 	sync.RWMutex
+
+	// seenMessages are the swap messages that were already handled.
+	seenMessages *messages.SeenMessages
+
+	// rateLimiter limits the incoming messages per peer.
+	rateLimiter *rateLimiter
+
+	// reconnecting holds a stop channel per offline peer that we try to
+	// reconnect to.
+	reconnecting map[string]chan struct{}
+
+	// claimAddresses are the descriptor addresses per chain that swap-outs
+	// are claimed to on request.
+	claimAddresses map[string]*ClaimAddresses
 }
 
 func NewSwapService(services *SwapServices) *SwapService {
-	return &SwapService{
+	swapService := &SwapService{
 		swapServices:   services,
 		activeSwaps:    map[string]*SwapStateMachine{},
+		seenMessages:   messages.NewSeenMessages(seenMessagesSize),
+		reconnecting:   map[string]chan struct{}{},
+		claimAddresses: map[string]*ClaimAddresses{},
+		rateLimiter:    newRateLimiter(defaultRateLimits),
 		LiquidEnabled:  services.liquidEnabled,
 		BitcoinEnabled: services.bitcoinEnabled,
 	}
+	services.openingBatches.build = swapService.buildOpeningBatch
+	return swapService
+}
+
+// PersistSeenMessages keeps the handled swap messages in the store, so that
+// messages that the peer resends after a restart are handled only once. It
+// must be called before the service is started.
+func (s *SwapService) PersistSeenMessages(store messages.SeenStore) error {
+	return s.seenMessages.Load(store)
 }
 
 // Start adds callback to the messenger, txwatcher services and lightning client
@@ -77,10 +113,14 @@ func (s *SwapService) Start() error {
 	if s.LiquidEnabled {
 		s.swapServices.liquidTxWatcher.AddConfirmationCallback(s.OnTxConfirmed)
 		s.swapServices.liquidTxWatcher.AddCsvCallback(s.OnCsvPassed)
+		s.swapServices.liquidTxWatcher.AddReorgCallback(s.OnTxReorged)
+		s.swapServices.liquidTxWatcher.AddSpendCallback(s.OnTxSpent)
 	}
 	if s.BitcoinEnabled {
 		s.swapServices.bitcoinTxWatcher.AddConfirmationCallback(s.OnTxConfirmed)
 		s.swapServices.bitcoinTxWatcher.AddCsvCallback(s.OnCsvPassed)
+		s.swapServices.bitcoinTxWatcher.AddReorgCallback(s.OnTxReorged)
+		s.swapServices.bitcoinTxWatcher.AddSpendCallback(s.OnTxSpent)
 	}
 
 	s.swapServices.lightning.AddPaymentCallback(s.OnPayment)
@@ -151,13 +191,76 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 	}
 	msgBytes := []byte(payload)
 	switch msgType {
+	case messages.MESSAGETYPE_POLL, messages.MESSAGETYPE_REQUEST_POLL:
+		// Handled and limited by the poll service.
+		return nil
+	}
+	if err := s.AllowMessage(peerId, msgType); err != nil {
+		return err
+	}
+	switch msgType {
+	case messages.MESSAGETYPE_ACK:
+		var msg *AckMessage
+		err := messages.Unmarshal(msgBytes, &msg)
+		if err != nil {
+			return err
+		}
+		s.swapServices.outbox.Ack(peerId, msg.MessageId)
+		return nil
+	}
+
+	// Swap messages are acknowledged, resent messages are only acknowledged
+	// again. A message is only marked as seen if it was handled, so that the
+	// peer resends it otherwise.
+	messageId := messages.MessageId(msgType, msgBytes)
+	if !s.seenMessages.Contains(peerId + messageId) {
+		err = s.handleMessage(peerId, msgType, msgTypeString, msgBytes)
+		if err != nil {
+			return err
+		}
+		s.seenMessages.Add(peerId + messageId)
+	}
+	return s.sendAck(peerId, messageId)
+}
+
+// AllowMessage takes a message of the peer from its rate limit of the message
+// type. If the limit is exceeded the violation is reported and a
+// RateLimitError is returned, the message must be dropped then.
+func (s *SwapService) AllowMessage(peerId string, msgType messages.MessageType) error {
+	ok, violations := s.rateLimiter.allow(peerId, msgType, time.Now())
+	if !ok {
+		s.reportViolation(peerId, violations)
+		return RateLimitError(peerId)
+	}
+	return nil
+}
+
+// sendAck acknowledges the receipt of a message to the peer. Only peers
+// that announced the ack feature are sent acks.
+func (s *SwapService) sendAck(peerId, messageId string) error {
+	features := s.swapServices.peerFeatures
+	if features == nil || !features.HasFeature(peerId, messages.FEATURE_ACK) {
+		return nil
+	}
+	msg, msgType, err := s.swapServices.marshalMessage(peerId, &AckMessage{MessageId: messageId})
+	if err != nil {
+		return err
+	}
+	return s.swapServices.messenger.SendMessage(peerId, msg, msgType)
+}
+
+// handleMessage passes a swap message to the corresponding swap.
+func (s *SwapService) handleMessage(peerId string, msgType messages.MessageType, msgTypeString string, payload []byte) error {
+	msgBytes := []byte(payload)
+	logger := log.WithFields(log.Fields{log.PeerField: peerId})
+	switch msgType {
 	default:
 		// Do nothing here, as it will spam the cln log.
 		return nil
 	case messages.MESSAGETYPE_SWAPOUTREQUEST:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *SwapOutRequestMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -166,9 +269,9 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 			return err
 		}
 	case messages.MESSAGETYPE_SWAPOUTAGREEMENT:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *SwapOutAgreementMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -187,9 +290,9 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 			return err
 		}
 	case messages.MESSAGETYPE_OPENINGTXBROADCASTED:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *OpeningTxBroadcastedMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -208,9 +311,9 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 			return err
 		}
 	case messages.MESSAGETYPE_CANCELED:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *CancelMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -229,9 +332,9 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 			return err
 		}
 	case messages.MESSAGETYPE_SWAPINREQUEST:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *SwapInRequestMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -240,9 +343,9 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 			return err
 		}
 	case messages.MESSAGETYPE_SWAPINAGREEMENT:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *SwapInAgreementMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -261,9 +364,9 @@ func (s *SwapService) OnMessageReceived(peerId string, msgTypeString string, pay
 			return err
 		}
 	case messages.MESSAGETYPE_COOPCLOSE:
-		log.Debugf("[Messenger] From: %s got msgtype: %s payload: %s", peerId, msgTypeString, payload)
+		logger.Debugf("[Messenger] got msgtype: %s payload: %s", msgTypeString, messages.PayloadString(payload))
 		var msg *CoopCloseMessage
-		err := json.Unmarshal(msgBytes, &msg)
+		err := messages.Unmarshal(msgBytes, &msg)
 		if err != nil {
 			return err
 		}
@@ -293,6 +396,8 @@ func (s *SwapService) OnTxConfirmed(swapId string, txHex string) error {
 	}
 	// todo move to eventctx
 	swap.Data.OpeningTxHex = txHex
+	// A new confirmation supersedes an earlier reorg.
+	s.swapServices.reorgs.reset(swapId)
 	done, err := swap.SendEvent(Event_OnTxConfirmed, nil)
 	if err == ErrEventRejected {
 		return nil
@@ -305,6 +410,88 @@ func (s *SwapService) OnTxConfirmed(swapId string, txHex string) error {
 	return nil
 }
 
+// OnTxReorged is called if the confirmed opening tx of a swap was reorged out
+// of the chain. A swap that did not pay the claim invoice yet stops paying
+// and awaits the confirmation again.
+func (s *SwapService) OnTxReorged(swapId string) error {
+	swap, err := s.GetActiveSwap(swapId)
+	if err != nil {
+		return err
+	}
+	swap.Data.Logger().Infof("Opening tx was reorged out of the chain")
+	s.swapServices.reorgs.notify(swapId)
+	return nil
+}
+
+// OnTxSpent is called if the opening output of a swap was spent, either in a
+// block or in the mempool. If the taker claimed the output with the
+// preimage, the claim invoice was paid and the maker settles the swap, even
+// if we missed the payment notification. If the maker spent the output before
+// the taker claimed it, the taker stops paying the claim invoice. Our own
+// claims are ignored.
+func (s *SwapService) OnTxSpent(swapId string, txHex string) error {
+	swap, err := s.GetActiveSwap(swapId)
+	if err == ErrSwapDoesNotExist {
+		// The swap is already done.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if swap.Data.OpeningTxBroadcasted == nil {
+		return nil
+	}
+
+	_, _, validator, err := s.swapServices.getOnChainServices(swap.Data.GetChain())
+	if err != nil {
+		return err
+	}
+	spendingTxId, preimage, err := validator.GetPreimageFromSpendingTx(
+		swap.Data.OpeningTxBroadcasted.TxId,
+		swap.Data.OpeningTxBroadcasted.ScriptOut,
+		txHex,
+	)
+	if err != nil {
+		return err
+	}
+
+	// We validate the preimage here, as an invalid event context would
+	// move the swap to the csv path.
+	eventCtx := OpeningTxSpentContext{SpendingTxId: spendingTxId, Preimage: preimage}
+	err = eventCtx.Validate(swap.Data)
+	if err != nil {
+		swap.Data.Logger().Infof("Ignoring spend of the opening tx by %s: %v", spendingTxId, err)
+		return nil
+	}
+
+	var event EventType
+	switch {
+	case swap.isMaker() && preimage != "":
+		swap.Data.Logger().Infof("Opening tx was claimed with the preimage by %s", spendingTxId)
+		event = Event_OnClaimedPreimageOnchain
+	case !swap.isMaker() && preimage == "":
+		// Only the maker can spend the output without the preimage.
+		swap.Data.Logger().Warnf("Opening tx was spent by the maker in %s", spendingTxId)
+		s.swapServices.spends.notify(swapId)
+		event = Event_OnOpeningTxSpent
+	default:
+		return nil
+	}
+
+	// The event context is applied even if the current state does not
+	// handle the event, so that a spend that competes with our claim is
+	// recorded as a double-spend attempt in any case.
+	done, err := swap.SendEvent(event, eventCtx)
+	if err == ErrEventRejected {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if done {
+		s.RemoveActiveSwap(swap.SwapId.String())
+	}
+	return nil
+}
+
 // OnCsvPassed sends the csvpassed event to the corresponding swap
 func (s *SwapService) OnCsvPassed(swapId string) error {
 	swap, err := s.GetActiveSwap(swapId)
@@ -323,9 +510,77 @@ func (s *SwapService) OnCsvPassed(swapId string) error {
 	return nil
 }
 
+// AssetLimit describes the swaps we accept as responder on a chain.
+type AssetLimit struct {
+	Asset        string
+	MinAmountSat uint64
+	// MaxSwapOutAmountSat is the largest swap out we can fund from our
+	// wallet.
+	MaxSwapOutAmountSat uint64
+	SwapTypes           []SwapType
+}
+
+// maxSwapOutBucketSat is the granularity of the maximum swap out amount we
+// announce.
+const maxSwapOutBucketSat = 100000
+
+// GetAssetLimits returns the limits of the swaps we currently accept on the
+// enabled chains. The maximum swap out amount is rounded down to a multiple
+// of maxSwapOutBucketSat, so that our peers do not learn our exact balance.
+// Swap outs are only accepted if the rounded amount is at least the minimum
+// amount.
+func (s *SwapService) GetAssetLimits() []AssetLimit {
+	var limits []AssetLimit
+	minAmountSat := s.swapServices.policy.GetMinSwapAmountMsat() / 1000
+	for _, chain := range []string{btc_chain, l_btc_chain} {
+		if (chain == btc_chain && !s.swapServices.bitcoinEnabled) || (chain == l_btc_chain && !s.swapServices.liquidEnabled) {
+			continue
+		}
+		limit := AssetLimit{
+			Asset:        chain,
+			MinAmountSat: minAmountSat,
+			SwapTypes:    []SwapType{SWAPTYPE_IN},
+		}
+
+		_, wallet, _, err := s.swapServices.getOnChainServices(chain)
+		if err != nil {
+			continue
+		}
+		maxAmountSat, err := maxSwapOutAmount(wallet)
+		if err != nil {
+			log.Debugf("could not get max %s swap out amount: %v", chain, err)
+		}
+		maxAmountSat = maxAmountSat / maxSwapOutBucketSat * maxSwapOutBucketSat
+		if maxAmountSat > 0 && maxAmountSat >= minAmountSat {
+			limit.MaxSwapOutAmountSat = maxAmountSat
+			limit.SwapTypes = append(limit.SwapTypes, SWAPTYPE_OUT)
+		}
+		limits = append(limits, limit)
+	}
+	return limits
+}
+
+// maxSwapOutAmount returns the largest swap out the wallet can fund, see
+// CreateSwapOutFromRequestAction.
+func maxSwapOutAmount(wallet Wallet) (uint64, error) {
+	balance, err := wallet.GetOnchainBalance()
+	if err != nil {
+		return 0, err
+	}
+	fee, err := wallet.GetFlatSwapOutFee()
+	if err != nil {
+		return 0, err
+	}
+	if balance <= fee+swapOutSafetyNet {
+		return 0, nil
+	}
+	return balance - fee - swapOutSafetyNet, nil
+}
+
 // todo move wallet and chain / channel validation logic here
-// SwapOut starts a new swap out process
-func (s *SwapService) SwapOut(peer string, chain string, channelId string, initiator string, amtSat uint64) (*SwapStateMachine, error) {
+// SwapOut starts a new swap out process. The swap is claimed to claimAddress,
+// or to a new wallet address if it is empty.
+func (s *SwapService) SwapOut(peer string, chain string, channelId string, initiator string, amtSat uint64, claimAddress string) (*SwapStateMachine, error) {
 	if !s.swapServices.policy.NewSwapsAllowed() {
 		return nil, fmt.Errorf("swaps are disabled")
 	}
@@ -343,7 +598,14 @@ func (s *SwapService) SwapOut(peer string, chain string, channelId string, initi
 		return nil, err
 	}
 
+	if claimAddress != "" {
+		if err := s.validateClaimAddress(chain, claimAddress); err != nil {
+			return nil, err
+		}
+	}
+
 	swap := newSwapOutSenderFSM(s.swapServices, initiator, peer)
+	swap.Data.ClaimAddress = claimAddress
 	err = s.lockSwap(swap.SwapId.String(), channelId, swap)
 	if err != nil {
 		return nil, err
@@ -381,8 +643,16 @@ func (s *SwapService) SwapOut(peer string, chain string, channelId string, initi
 }
 
 // todo check prerequisites
-// SwapIn starts a new swap in process
-func (s *SwapService) SwapIn(peer string, chain string, channelId string, initiator string, amtSat uint64) (*SwapStateMachine, error) {
+// SwapIn starts a new swap in process. If externalFunding is set the opening
+// tx is not funded by the wallet, instead the swap waits for a signed opening
+// tx that is submitted with SubmitOpeningTx.
+func (s *SwapService) SwapIn(peer string, chain string, channelId string, initiator string, amtSat uint64, externalFunding bool) (*SwapStateMachine, error) {
+	return s.swapIn(peer, chain, channelId, initiator, amtSat, externalFunding, "")
+}
+
+// swapIn starts a new swap in process, as part of the opening batch batchId
+// if it is set.
+func (s *SwapService) swapIn(peer string, chain string, channelId string, initiator string, amtSat uint64, externalFunding bool, batchId string) (*SwapStateMachine, error) {
 	if !s.swapServices.policy.NewSwapsAllowed() {
 		return nil, fmt.Errorf("swaps are disabled")
 	}
@@ -405,10 +675,15 @@ func (s *SwapService) SwapIn(peer string, chain string, channelId string, initia
 		return nil, errors.New("invalid chain")
 	}
 	swap := newSwapInSenderFSM(s.swapServices, initiator, peer)
+	swap.Data.ExternalFunding = externalFunding
 	err := s.lockSwap(swap.SwapId.String(), channelId, swap)
 	if err != nil {
 		return nil, err
 	}
+	if batchId != "" {
+		swap.Data.OpeningBatchId = batchId
+		s.swapServices.openingBatches.addPending(batchId, swap.SwapId.String())
+	}
 
 	request := &SwapInRequestMessage{
 		ProtocolVersion: PEERSWAP_PROTOCOL_VERSION,
@@ -432,15 +707,22 @@ func (s *SwapService) SwapIn(peer string, chain string, channelId string, initia
 
 // OnSwapInRequestReceived creates a new swap-in process and sends the event to the swap statemachine
 func (s *SwapService) OnSwapInRequestReceived(swapId *SwapId, peerId string, message *SwapInRequestMessage) error {
+	// The peer resends the request of a swap we already handle after a
+	// reconnect.
+	if s.isActiveSwapWithPeer(swapId.String(), peerId) {
+		return nil
+	}
+
 	swap := newSwapInReceiverFSM(swapId, s.swapServices, peerId)
 
 	err := s.swapServices.lightning.CanSpend(message.Amount * 1000)
 	if err != nil {
 		msg := fmt.Sprintf("from the %s peer: %s", s.swapServices.lightning.Implementation(), err.Error())
 		// We want to tell our peer why we can not do this swap.
-		msgBytes, msgType, err := MarshalPeerswapMessage(&CancelMessage{
+		msgBytes, msgType, err := s.swapServices.marshalMessage(peerId, &CancelMessage{
 			SwapId:  swapId,
 			Message: msg,
+			Code:    CancelCode_InsufficientBalance,
 		})
 		s.swapServices.messenger.SendMessage(peerId, msgBytes, msgType)
 		return err
@@ -450,11 +732,7 @@ func (s *SwapService) OnSwapInRequestReceived(swapId *SwapId, peerId string, mes
 	if err != nil {
 		// If we already have an active swap on the same channel or can not lock
 		// in a new swap we want to tell it our peer.
-		msgBytes, msgType, err := MarshalPeerswapMessage(&CancelMessage{
-			SwapId:  swapId,
-			Message: err.Error(),
-		})
-		s.swapServices.messenger.SendMessage(peerId, msgBytes, msgType)
+		s.sendLockFailedCancel(peerId, swapId, err)
 		return err
 	}
 
@@ -467,17 +745,19 @@ func (s *SwapService) OnSwapInRequestReceived(swapId *SwapId, peerId string, mes
 
 // OnSwapInRequestReceived creates a new swap-out process and sends the event to the swap statemachine
 func (s *SwapService) OnSwapOutRequestReceived(swapId *SwapId, peerId string, message *SwapOutRequestMessage) error {
+	// The peer resends the request of a swap we already handle after a
+	// reconnect.
+	if s.isActiveSwapWithPeer(swapId.String(), peerId) {
+		return nil
+	}
+
 	swap := newSwapOutReceiverFSM(swapId, s.swapServices, peerId)
 
 	err := s.lockSwap(swap.SwapId.String(), message.Scid, swap)
 	if err != nil {
 		// If we already have an active swap on the same channel or can not lock
 		// in a new swap we want to tell it our peer.
-		msgBytes, msgType, err := MarshalPeerswapMessage(&CancelMessage{
-			SwapId:  swapId,
-			Message: err.Error(),
-		})
-		s.swapServices.messenger.SendMessage(peerId, msgBytes, msgType)
+		s.sendLockFailedCancel(peerId, swapId, err)
 		return err
 	}
 
@@ -607,9 +887,10 @@ func getPaymentLabel(description string) string {
 // OnPayment handles incoming payments and if it corresponds to a claim or
 // fee invoice passes the dater to the corresponding function
 func (s *SwapService) OnPayment(swapIdStr string, invoiceType InvoiceType) {
+	logger := log.WithFields(log.Fields{log.SwapIdField: swapIdStr})
 	swapId, err := ParseSwapIdFromString(swapIdStr)
 	if err != nil {
-		log.Infof("parse swapId error")
+		logger.Warnf("parse swapId error")
 		return
 	}
 
@@ -617,12 +898,12 @@ func (s *SwapService) OnPayment(swapIdStr string, invoiceType InvoiceType) {
 	switch invoiceType {
 	case INVOICE_FEE:
 		if err := s.OnFeeInvoiceNotification(swapId); err != nil {
-			log.Infof("[SwapService] Error OnFeeInvoiceNotification: %v", err)
+			logger.Warnf("[SwapService] Error OnFeeInvoiceNotification: %v", err)
 			return
 		}
 	case INVOICE_CLAIM:
 		if err := s.OnClaimInvoiceNotification(swapId); err != nil {
-			log.Infof("[SwapService] Error OnClaimInvoiceNotification: %v", err)
+			logger.Warnf("[SwapService] Error OnClaimInvoiceNotification: %v", err)
 			return
 		}
 	default:
@@ -683,6 +964,8 @@ func (s *SwapService) ResendLastMessage(swapId string) error {
 	if err != nil {
 		return err
 	}
+	swap.mutex.Lock()
+	defer swap.mutex.Unlock()
 	action := &SendMessageAction{}
 	event := action.Execute(s.swapServices, swap.Data)
 	if event == Event_ActionFailed {
@@ -708,6 +991,74 @@ func (s *SwapService) ListActiveSwaps() ([]*SwapStateMachine, error) {
 	return activeSwaps, nil
 }
 
+// DrainingSwap is an active swap together with the block heights that show
+// its progress while we drain.
+type DrainingSwap struct {
+	Swap *SwapStateMachine
+	// CurrentHeight is the current block height of the swap's chain.
+	CurrentHeight uint32
+	// CompletionHeight is the height at which the csv of the opening tx
+	// passes. The maker can claim the funds back from then on, so the swap
+	// completes by this height at the latest. It is 0 if the opening tx is
+	// not known yet.
+	CompletionHeight uint32
+}
+
+// ListDrainingSwaps returns the active swaps with their expected completion
+// heights.
+func (s *SwapService) ListDrainingSwaps() ([]*DrainingSwap, error) {
+	swaps, err := s.ListActiveSwaps()
+	if err != nil {
+		return nil, err
+	}
+
+	drainingSwaps := []*DrainingSwap{}
+	for _, swap := range swaps {
+		drainingSwap := &DrainingSwap{Swap: swap}
+		drainingSwaps = append(drainingSwaps, drainingSwap)
+
+		chain := swap.Data.GetChain()
+		if (chain == btc_chain && !s.swapServices.bitcoinEnabled) ||
+			(chain == l_btc_chain && !s.swapServices.liquidEnabled) {
+			continue
+		}
+		txWatcher, _, validator, err := s.swapServices.getOnChainServices(chain)
+		if err != nil {
+			return nil, err
+		}
+		drainingSwap.CurrentHeight, err = txWatcher.GetBlockHeight()
+		if err != nil {
+			return nil, err
+		}
+		if swap.Data.StartingBlockHeight > 0 {
+			drainingSwap.CompletionHeight = swap.Data.StartingBlockHeight + validator.GetCSVHeight()
+		}
+	}
+	return drainingSwaps, nil
+}
+
+// WaitForDrain blocks until no active swaps are left or the context is done.
+// Queued claims are swept right away instead of waiting for their batch.
+func (s *SwapService) WaitForDrain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		s.flushClaimBatches()
+		active, err := s.HasActiveSwaps()
+		if err != nil {
+			return err
+		}
+		if !active {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // GetActiveSwap returns the active swap, or an error if it does not exist
 func (s *SwapService) GetActiveSwap(swapId string) (*SwapStateMachine, error) {
 	s.RLock()
@@ -718,11 +1069,62 @@ func (s *SwapService) GetActiveSwap(swapId string) (*SwapStateMachine, error) {
 	return nil, ErrSwapDoesNotExist
 }
 
+// ActiveSwapsByState returns the number of active swaps per fsm state.
+func (s *SwapService) ActiveSwapsByState() map[string]int {
+	states := map[string]int{}
+	for _, swap := range s.activeSwapsList() {
+		swap.mutex.Lock()
+		states[string(swap.Current)]++
+		swap.mutex.Unlock()
+	}
+	return states
+}
+
+// OnchainBalanceSplit returns the part of the on-chain `balance` of `chain`
+// that is kept as reserve by the policy, the part that is committed to active
+// swaps that still have to broadcast our opening transaction and the
+// remaining available amount.
+func (s *SwapService) OnchainBalanceSplit(chain string, balance uint64) (reserve, committed, available uint64) {
+	reserve = s.swapServices.policy.GetReserveOnchainMsat() / 1000
+
+	for _, swap := range s.activeSwapsList() {
+		swap.mutex.Lock()
+		if swap.Data != nil && swap.Data.GetChain() == chain &&
+			swap.isMaker() && swap.Data.GetOpeningTxId() == "" {
+			committed += swap.Data.GetAmount()
+		}
+		swap.mutex.Unlock()
+	}
+
+	if reserve+committed < balance {
+		available = balance - reserve - committed
+	}
+	return reserve, committed, available
+}
+
+// activeSwapsList returns the active swaps. The swaps are locked on their
+// own after the service lock is released, as the swaps take the service lock
+// while they hold their own lock.
+func (s *SwapService) activeSwapsList() []*SwapStateMachine {
+	s.RLock()
+	defer s.RUnlock()
+	swaps := make([]*SwapStateMachine, 0, len(s.activeSwaps))
+	for _, swap := range s.activeSwaps {
+		swaps = append(swaps, swap)
+	}
+	return swaps
+}
+
 // RemoveActiveSwap removes a swap from the active swap map
 func (s *SwapService) RemoveActiveSwap(swapId string) {
 	s.Lock()
 	defer s.Unlock()
+	if swap, ok := s.activeSwaps[swapId]; ok {
+		s.swapServices.openingBatches.remove(swap.Data.OpeningBatchId, swapId)
+	}
 	delete(s.activeSwaps, swapId)
+	s.swapServices.reorgs.reset(swapId)
+	s.swapServices.spends.reset(swapId)
 }
 
 // lockSwap locks in a swap. This function ensures that we only have one active
@@ -732,11 +1134,22 @@ func (s *SwapService) lockSwap(swapId, channelId string, fsm *SwapStateMachine)
 	s.Lock()
 	defer s.Unlock()
 
-	// Check if we already have an active swap on the same channel
+	// Check if we already have an active swap on the same channel. Swaps
+	// of the peer whose opening tx was not broadcasted yet are pending, a
+	// swap that waits for the csv limit is no longer counted.
+	var pending uint64
 	for id, swap := range s.activeSwaps {
 		if swap.Data.GetScid() == channelId {
 			return ActiveSwapError{channelId: channelId, swapId: id}
 		}
+		if swap.Role == SWAPROLE_RECEIVER && swap.Data.PeerNodeId == fsm.Data.PeerNodeId && swap.Data.OpeningTxBroadcasted == nil {
+			pending++
+		}
+	}
+
+	// Limit the swaps a peer can request from us at the same time.
+	if fsm.Role == SWAPROLE_RECEIVER && pending >= s.swapServices.policy.GetMaxPendingIncomingSwaps() {
+		return TooManySwapsError(fsm.Data.PeerNodeId)
 	}
 
 	// Add active swap
@@ -753,12 +1166,69 @@ func (e ActiveSwapError) Error() string {
 	return fmt.Sprintf("already has an active swap on channel %s: %s", e.channelId, e.swapId)
 }
 
+type TooManySwapsError string
+
+func (e TooManySwapsError) Error() string {
+	return fmt.Sprintf("peer %s has too many pending swaps", string(e))
+}
+
+type RateLimitError string
+
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("peer %s exceeded the message rate limit", string(e))
+}
+
+// sendLockFailedCancel tells the peer why we could not lock in its swap.
+func (s *SwapService) sendLockFailedCancel(peerId string, swapId *SwapId, lockErr error) {
+	code := CancelCode_ActiveSwap
+	var tooManySwaps TooManySwapsError
+	if errors.As(lockErr, &tooManySwaps) {
+		code = CancelCode_TooManySwaps
+		s.reportViolation(peerId, s.rateLimiter.addViolation(peerId, time.Now()))
+	}
+
+	msgBytes, msgType, err := s.swapServices.marshalMessage(peerId, &CancelMessage{
+		SwapId:  swapId,
+		Message: lockErr.Error(),
+		Code:    code,
+	})
+	if err != nil {
+		log.Debugf("could not marshal cancel message: %v", err)
+		return
+	}
+	s.swapServices.messenger.SendMessage(peerId, msgBytes, msgType)
+}
+
+// reportViolation adds the peer to the suspicious peer list when it reached
+// suspiciousViolations violations of the message limits.
+func (s *SwapService) reportViolation(peerId string, violations int) {
+	if violations != suspiciousViolations {
+		return
+	}
+	logger := log.WithFields(log.Fields{log.PeerField: peerId})
+	logger.Infof("[SwapService] peer violated the message limits %d times, adding it to the suspicious peer list", violations)
+	err := s.swapServices.policy.AddToSuspiciousPeerList(peerId)
+	if err != nil {
+		logger.Infof("[SwapService] could not add peer to the suspicious peer list: %v", err)
+	}
+}
+
 type WrongAssetError string
 
 func (e WrongAssetError) Error() string {
 	return fmt.Sprintf("unallowed asset: %s", string(e))
 }
 
+// isActiveSwapWithPeer returns true if the swap is active and done with the
+// peer.
+func (s *SwapService) isActiveSwapWithPeer(swapId, peerId string) bool {
+	swap, err := s.GetActiveSwap(swapId)
+	if err != nil {
+		return false
+	}
+	return swap.Data.PeerNodeId == peerId
+}
+
 // isMessageSenderExpectedPeer returns true if the senderId matches the
 // PeerNodeId of the swap, false if not.
 func (s *SwapService) isMessageSenderExpectedPeer(senderId string, swapId *SwapId) (bool, error) {
@@ -776,21 +1246,23 @@ func (s *SwapService) createTimeoutCallback(swapId string) func() {
 			return
 		}
 		if err != nil {
-			log.Debugf("[SwapService] timeout callback: %v", err)
+			log.WithFields(log.Fields{log.SwapIdField: swapId}).Debugf("[SwapService] timeout callback: %v", err)
 			return
 		}
 
-		// Reset cancel func
+		// Reset cancel func and deadline
 		if swap != nil && swap.Data != nil {
 			swap.Data.toCancel = nil
+			swap.Data.TimeOutAt = 0
+			swap.Data.PeerOfflineAt = 0
 		}
 
-		done, err := swap.SendEvent(Event_OnTimeout, nil)
+		done, err := swap.SendEvent(Event_OnTimeout, &timeoutContext{fsm: swap})
 		if err == ErrEventRejected {
 			return
 		}
 		if err != nil {
-			log.Debugf("[SwapService] SendEvent(): %v", err)
+			swap.logger().Debugf("[SwapService] SendEvent(): %v", err)
 			return
 		}
 
@@ -3,8 +3,10 @@ package swap
 import (
 	"context"
 	"encoding/hex"
+	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -40,7 +42,7 @@ func Test_GoodCase(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount)
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "")
 	if err != nil {
 		t.Fatalf(" error swapping oput %v: ", err)
 	}
@@ -97,7 +99,7 @@ func Test_FeePaymentFailed(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	aliceSwap, err := aliceSwapService.SwapOut(peer, "btc", channelId, initiator, amount)
+	aliceSwap, err := aliceSwapService.SwapOut(peer, "btc", channelId, initiator, amount, "")
 	if err != nil {
 		t.Fatalf(" error swapping oput %v: ", err)
 	}
@@ -138,7 +140,7 @@ func Test_ClaimPaymentFailedCoopClose(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	aliceSwap, err := aliceSwapService.SwapOut(peer, "btc", channelId, initiator, amount)
+	aliceSwap, err := aliceSwapService.SwapOut(peer, "btc", channelId, initiator, amount, "")
 	if err != nil {
 		t.Fatalf(" error swapping oput %v: ", err)
 	}
@@ -176,6 +178,121 @@ func Test_ClaimPaymentFailedCoopClose(t *testing.T) {
 	assert.Equal(t, State_ClaimedCoop, bobSwap.Current)
 }
 
+func Test_OpeningTxReorged(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService, bobSwapService, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, <-bobMsgChan)
+	bobSwap := bobSwapService.activeSwaps[aliceSwap.SwapId.String()]
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTAGREEMENT, <-aliceMsgChan)
+	bobSwapService.swapServices.lightning.(*dummyLightningClient).TriggerPayment(bobSwap.SwapId.String(), INVOICE_FEE)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-aliceMsgChan)
+
+	// The claim payment does not go through before the opening tx is
+	// reorged out of the chain.
+	aliceSwapService.swapServices.lightning.(*dummyLightningClient).failpayment = true
+	txWatcher := aliceSwapService.swapServices.liquidTxWatcher.(*dummyChain)
+	confirmed := make(chan error)
+	go func() {
+		confirmed <- txWatcher.txConfirmedFunc(aliceSwap.SwapId.String(), aliceSwap.Data.OpeningTxHex)
+	}()
+	require.Eventually(t, func() bool {
+		return aliceSwap.Data.GetCurrentState() == State_SwapOutSender_ValidateTxAndPayClaimInvoice
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, txWatcher.txReorgedFunc(aliceSwap.SwapId.String()))
+	require.NoError(t, <-confirmed)
+	assert.Equal(t, State_SwapOutSender_AwaitTxConfirmation, aliceSwap.Current)
+
+	// The swap continues once the tx is confirmed again.
+	aliceSwapService.swapServices.lightning.(*dummyLightningClient).failpayment = false
+	require.NoError(t, txWatcher.txConfirmedFunc(aliceSwap.SwapId.String(), aliceSwap.Data.OpeningTxHex))
+	assert.Equal(t, State_ClaimedPreimage, aliceSwap.Current)
+}
+
+func Test_OpeningTxClaimedWithPreimage(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService, bobSwapService, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, <-bobMsgChan)
+	bobSwap := bobSwapService.activeSwaps[aliceSwap.SwapId.String()]
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTAGREEMENT, <-aliceMsgChan)
+	bobSwapService.swapServices.lightning.(*dummyLightningClient).TriggerPayment(bobSwap.SwapId.String(), INVOICE_FEE)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-aliceMsgChan)
+	require.Eventually(t, func() bool {
+		return bobSwap.Data.GetCurrentState() == State_SwapOutReceiver_AwaitClaimInvoicePayment
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Spends that do not reveal the preimage of the claim invoice are
+	// ignored.
+	txWatcher := bobSwapService.swapServices.liquidTxWatcher.(*dummyChain)
+	txWatcher.spendingPreimage = ""
+	require.NoError(t, txWatcher.txSpentFunc(bobSwap.SwapId.String(), "txhex"))
+	assert.Equal(t, State_SwapOutReceiver_AwaitClaimInvoicePayment, bobSwap.Data.GetCurrentState())
+	txWatcher.spendingPreimage = getRandom32ByteHexString()
+	require.NoError(t, txWatcher.txSpentFunc(bobSwap.SwapId.String(), "txhex"))
+	assert.Equal(t, State_SwapOutReceiver_AwaitClaimInvoicePayment, bobSwap.Data.GetCurrentState())
+
+	// The swap is settled as soon as the taker claims the opening tx with
+	// the preimage, without the claim invoice payment notification.
+	txWatcher.spendingPreimage = bobSwap.Data.ClaimPreimage
+	require.NoError(t, txWatcher.txSpentFunc(bobSwap.SwapId.String(), "txhex"))
+	assert.Equal(t, State_ClaimedPreimage, bobSwap.Data.GetCurrentState())
+	assert.NotEmpty(t, bobSwap.Data.ClaimTxId)
+}
+
+func Test_OpeningTxSpentByMaker(t *testing.T) {
+	amount := uint64(100000)
+	initiator, peer, _, _, channelId := getTestParams()
+
+	aliceSwapService, bobSwapService, aliceMsgChan, bobMsgChan := getConnectedTestSetup(initiator, peer)
+	require.NoError(t, aliceSwapService.Start())
+	require.NoError(t, bobSwapService.Start())
+	aliceSwap, err := aliceSwapService.SwapOut(peer, btc_chain, channelId, initiator, amount, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, <-bobMsgChan)
+	bobSwap := bobSwapService.activeSwaps[aliceSwap.SwapId.String()]
+	assert.Equal(t, messages.MESSAGETYPE_SWAPOUTAGREEMENT, <-aliceMsgChan)
+	bobSwapService.swapServices.lightning.(*dummyLightningClient).TriggerPayment(bobSwap.SwapId.String(), INVOICE_FEE)
+	assert.Equal(t, messages.MESSAGETYPE_OPENINGTXBROADCASTED, <-aliceMsgChan)
+
+	// The claim payment does not go through before the maker spends the
+	// opening output.
+	aliceSwapService.swapServices.lightning.(*dummyLightningClient).failpayment = true
+	txWatcher := aliceSwapService.swapServices.liquidTxWatcher.(*dummyChain)
+	confirmed := make(chan error)
+	go func() {
+		confirmed <- txWatcher.txConfirmedFunc(aliceSwap.SwapId.String(), aliceSwap.Data.OpeningTxHex)
+	}()
+	require.Eventually(t, func() bool {
+		return aliceSwap.Data.GetCurrentState() == State_SwapOutSender_ValidateTxAndPayClaimInvoice
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The taker stops paying and records the double-spend attempt.
+	spent := make(chan error)
+	go func() {
+		spent <- txWatcher.txSpentFunc(aliceSwap.SwapId.String(), "txhex")
+	}()
+	assert.Equal(t, messages.MESSAGETYPE_COOPCLOSE, <-bobMsgChan)
+	require.NoError(t, <-confirmed)
+	require.NoError(t, <-spent)
+	assert.True(t, aliceSwap.IsFinished())
+	assert.NotEmpty(t, aliceSwap.Data.DoubleSpendTxId)
+}
+
 func Test_OnlyOneActiveSwapPerChannel(t *testing.T) {
 	service := getTestSetup("alice")
 	swapId := NewSwapId()
@@ -223,12 +340,12 @@ func Test_OnlyOneActiveSwapPerChannel(t *testing.T) {
 		failures: 0,
 	})
 
-	_, err := service.SwapOut("peer", "lbtc", "channelID", "alice", uint64(100000))
+	_, err := service.SwapOut("peer", "lbtc", "channelID", "alice", uint64(100000), "")
 	assert.Error(t, err, "expected error")
 	assert.ErrorIs(t, err, ActiveSwapError{channelId: "channelID", swapId: swapId.String()})
 	t.Logf("Got Error: %s", err.Error())
 
-	_, err = service.SwapIn("peer", "lbtc", "channelID", "alice", uint64(100000))
+	_, err = service.SwapIn("peer", "lbtc", "channelID", "alice", uint64(100000), false)
 	assert.Error(t, err, "expected error")
 	assert.ErrorIs(t, err, ActiveSwapError{channelId: "channelID", swapId: swapId.String()})
 	t.Logf("Got Error: %s", err.Error())
@@ -257,7 +374,7 @@ func TestMessageFromUnexpectedPeer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	aliceSwap, err := aliceSwapService.SwapOut(peer, "btc", channelId, initiator, amount)
+	aliceSwap, err := aliceSwapService.SwapOut(peer, "btc", channelId, initiator, amount, "")
 	if err != nil {
 		t.Fatalf(" error swapping oput %v: ", err)
 	}
@@ -334,7 +451,7 @@ func TestTimeout(t *testing.T) {
 	sws.lockSwap(fsm.SwapId.String(), fsm.Data.GetScid(), fsm)
 
 	fsm.Current = State_SwapInSender_AwaitAgreement
-	sws.swapServices.toService.addNewTimeOut(context.Background(), 10*time.Millisecond, fsm.SwapId.String())
+	sws.swapServices.toService.addNewTimeOut(context.Background(), time.Now().Add(10*time.Millisecond), fsm.SwapId.String())
 
 	tm := time.NewTimer(1 * time.Second)
 
@@ -355,6 +472,32 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+// TestTimeoutExpiredOnRecover checks that a timeout that expired while we
+// were down fires immediately when the swap is recovered.
+func TestTimeoutExpiredOnRecover(t *testing.T) {
+	t.Parallel()
+	sws := getTestSetup("alice")
+	sws.swapServices.messenger = &noopMessenger{}
+	sws.Start()
+
+	fsm := newSwapInSenderFSM(sws.swapServices, "alice", "bob")
+	fsm.Current = State_SwapInSender_AwaitAgreement
+	fsm.Data.SwapInRequest = &SwapInRequestMessage{SwapId: fsm.SwapId}
+	fsm.Data.TimeOutAt = time.Now().Add(-time.Minute).Unix()
+	sws.lockSwap(fsm.SwapId.String(), fsm.Data.GetScid(), fsm)
+
+	_, err := fsm.Recover()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		fsm.mutex.Lock()
+		defer fsm.mutex.Unlock()
+		return fsm.Current == State_SwapCanceled
+	}, time.Second, 10*time.Millisecond)
+	assert.Zero(t, fsm.Data.TimeOutAt)
+	assert.Equal(t, CancelCode_Timeout, fsm.Data.GetCancelCode())
+}
+
 // Test_SwapIn_PeerIsSuspicious checks that no swap is requested if the peer is
 // suspicious.
 func Test_SwapIn_PeerIsSuspicious(t *testing.T) {
@@ -368,7 +511,7 @@ func Test_SwapIn_PeerIsSuspicious(t *testing.T) {
 		newSwapsAllowedReturn:  policy.DefaultPolicy().AllowNewSwaps,
 	}
 
-	_, err := swapService.SwapOut(peer, "regtest", "", node, 100000)
+	_, err := swapService.SwapOut(peer, "regtest", "", node, 100000, "")
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, PeerIsSuspiciousError(peer))
 }
@@ -387,18 +530,66 @@ func Test_SwapOut_PeerIsSuspicious(t *testing.T) {
 		newSwapsAllowedReturn:      policy.DefaultPolicy().AllowNewSwaps,
 	}
 
-	_, err := swapService.SwapOut(peer, "regtest", "", node, 100000)
+	_, err := swapService.SwapOut(peer, "regtest", "", node, 100000, "")
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, PeerIsSuspiciousError(peer))
 }
 
+func Test_OnchainBalanceSplit(t *testing.T) {
+	service := getTestSetup("alice")
+	service.activeSwaps = map[string]*SwapStateMachine{
+		// Swap in sender funds the opening tx.
+		"in-sender": {
+			Type: SWAPTYPE_IN,
+			Role: SWAPROLE_SENDER,
+			Data: &SwapData{SwapInRequest: &SwapInRequestMessage{Network: "regtest", Amount: 100000}},
+		},
+		// Swap out receiver funds the opening tx.
+		"out-receiver": {
+			Type: SWAPTYPE_OUT,
+			Role: SWAPROLE_RECEIVER,
+			Data: &SwapData{SwapOutRequest: &SwapOutRequestMessage{Network: "regtest", Amount: 200000}},
+		},
+		// Opening tx already broadcasted.
+		"in-sender-opened": {
+			Type: SWAPTYPE_IN,
+			Role: SWAPROLE_SENDER,
+			Data: &SwapData{
+				SwapInRequest:        &SwapInRequestMessage{Network: "regtest", Amount: 400000},
+				OpeningTxBroadcasted: &OpeningTxBroadcastedMessage{TxId: "txid"},
+			},
+		},
+		// Swap out sender does not fund the opening tx.
+		"out-sender": {
+			Type: SWAPTYPE_OUT,
+			Role: SWAPROLE_SENDER,
+			Data: &SwapData{SwapOutRequest: &SwapOutRequestMessage{Network: "regtest", Amount: 800000}},
+		},
+		// Different chain.
+		"in-sender-liquid": {
+			Type: SWAPTYPE_IN,
+			Role: SWAPROLE_SENDER,
+			Data: &SwapData{SwapInRequest: &SwapInRequestMessage{Asset: "asset", Amount: 1600000}},
+		},
+	}
+	service.swapServices.policy = &dummyPolicy{reserveOnchainMsatReturn: 50000000}
+
+	reserve, committed, available := service.OnchainBalanceSplit(btc_chain, 1000000)
+	assert.EqualValues(t, 50000, reserve)
+	assert.EqualValues(t, 300000, committed)
+	assert.EqualValues(t, 650000, available)
+
+	_, _, available = service.OnchainBalanceSplit(btc_chain, 300000)
+	assert.EqualValues(t, 0, available)
+}
+
 func getTestSetup(name string) *SwapService {
 	store := &dummyStore{dataMap: map[string]*SwapStateMachine{}}
 	reqSwapsStore := &requestedSwapsStoreMock{data: map[string][]RequestedSwap{}}
 	messenger := &ConnectedMessenger{
 		thisPeerId: name,
 	}
-	mmgr := &MessengerManagerStub{}
+	outbox := &OutboxStub{}
 	lc := &dummyLightningClient{preimage: ""}
 	policy := &dummyPolicy{
 		getMinSwapAmountMsatReturn: policy.DefaultPolicy().MinSwapAmountMsat,
@@ -406,11 +597,26 @@ func getTestSetup(name string) *SwapService {
 	}
 	chain := &dummyChain{returnGetCSVHeight: 1008}
 	chain.SetBalance(10000000)
-	swapServices := NewSwapServices(store, reqSwapsStore, lc, messenger, mmgr, policy, true, chain, chain, chain, true, chain, chain, chain)
+	// Peers exchange tlv encoded messages and acknowledge them.
+	features := &peerFeaturesStub{features: []string{messages.FEATURE_TLV, messages.FEATURE_ACK}}
+	swapServices := NewSwapServices(store, reqSwapsStore, lc, messenger, outbox, features, policy, true, chain, chain, chain, true, chain, chain, chain)
 	swapService := NewSwapService(swapServices)
 	return swapService
 }
 
+func getConnectedTestSetup(initiator, peer string) (alice, bob *SwapService, aliceMsgChan, bobMsgChan chan messages.MessageType) {
+	alice = getTestSetup(initiator)
+	bob = getTestSetup(peer)
+	alice.swapServices.messenger.(*ConnectedMessenger).other = bob.swapServices.messenger.(*ConnectedMessenger)
+	bob.swapServices.messenger.(*ConnectedMessenger).other = alice.swapServices.messenger.(*ConnectedMessenger)
+
+	aliceMsgChan = make(chan messages.MessageType)
+	bobMsgChan = make(chan messages.MessageType)
+	alice.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = aliceMsgChan
+	bob.swapServices.messenger.(*ConnectedMessenger).msgReceivedChan = bobMsgChan
+	return alice, bob, aliceMsgChan, bobMsgChan
+}
+
 type ConnectedMessenger struct {
 	sync.Mutex
 	thisPeerId      string
@@ -431,7 +637,8 @@ func (c *ConnectedMessenger) SendMessage(peerId string, msg []byte, msgType int)
 			c.other.lastErr = err
 			c.other.Unlock()
 		}
-		if c.other.msgReceivedChan != nil {
+		// Acks are not reported to keep the message flow of the tests.
+		if c.other.msgReceivedChan != nil && messages.MessageType(msgType) != messages.MESSAGETYPE_ACK {
 			c.other.msgReceivedChan <- messages.MessageType(msgType)
 		}
 	}()
@@ -443,28 +650,51 @@ func (c *ConnectedMessenger) AddMessageHandler(f func(peerId string, msgType str
 	c.OnMessage = f
 }
 
-type MessengerManagerStub struct {
+type OutboxStub struct {
 	sync.Mutex
-	called  int
 	added   int
+	acked   []string
 	removed int
 }
 
-func (s *MessengerManagerStub) AddSender(id string, messenger messages.StoppableMessenger) error {
+func (s *OutboxStub) Add(swapId, peerId string, message []byte, messageType int) error {
 	s.Lock()
 	defer s.Unlock()
-	s.called++
 	s.added++
 	return nil
 }
 
-func (s *MessengerManagerStub) RemoveSender(id string) {
+func (s *OutboxStub) Ack(peerId, messageId string) {
+	s.Lock()
+	defer s.Unlock()
+	s.acked = append(s.acked, messageId)
+}
+
+func (s *OutboxStub) Remove(swapId string) {
 	s.Lock()
 	defer s.Unlock()
-	s.called++
 	s.removed++
 }
 
+func (s *OutboxStub) Acked() []string {
+	s.Lock()
+	defer s.Unlock()
+	return s.acked
+}
+
+type peerFeaturesStub struct {
+	features []string
+}
+
+func (s *peerFeaturesStub) HasFeature(peerId, feature string) bool {
+	for _, f := range s.features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
 type noopMessenger struct {
 }
 
@@ -488,3 +718,245 @@ func getRandom32ByteHexString() string {
 	privkey, _ := btcec.NewPrivateKey()
 	return hex.EncodeToString(privkey.Serialize())
 }
+
+func Test_Drain(t *testing.T) {
+	service := getTestSetup("alice")
+	store := service.swapServices.swapStore
+
+	opened := &SwapStateMachine{
+		SwapId:  NewSwapId(),
+		Type:    SWAPTYPE_IN,
+		Role:    SWAPROLE_SENDER,
+		Current: State_SwapInSender_AwaitClaimPayment,
+		Data: &SwapData{
+			SwapInRequest:       &SwapInRequestMessage{Network: "regtest"},
+			StartingBlockHeight: 100,
+		},
+	}
+	requested := &SwapStateMachine{
+		SwapId:  NewSwapId(),
+		Type:    SWAPTYPE_OUT,
+		Role:    SWAPROLE_SENDER,
+		Current: State_SwapOutSender_AwaitAgreement,
+		Data:    &SwapData{SwapOutRequest: &SwapOutRequestMessage{Network: "regtest"}},
+	}
+	finished := &SwapStateMachine{
+		SwapId:  NewSwapId(),
+		Current: State_ClaimedPreimage,
+		Data:    &SwapData{SwapOutRequest: &SwapOutRequestMessage{Network: "regtest"}},
+	}
+	for _, swap := range []*SwapStateMachine{opened, requested, finished} {
+		require.NoError(t, store.UpdateData(swap))
+	}
+
+	swaps, err := service.ListDrainingSwaps()
+	require.NoError(t, err)
+	require.Len(t, swaps, 2)
+	heights := map[string]uint32{}
+	for _, swap := range swaps {
+		assert.EqualValues(t, 1, swap.CurrentHeight)
+		heights[swap.Swap.SwapId.String()] = swap.CompletionHeight
+	}
+	assert.EqualValues(t, 1108, heights[opened.SwapId.String()])
+	assert.EqualValues(t, 0, heights[requested.SwapId.String()])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, service.WaitForDrain(ctx), context.DeadlineExceeded)
+
+	opened.Current = State_ClaimedCsv
+	requested.Current = State_SwapCanceled
+	assert.NoError(t, service.WaitForDrain(context.Background()))
+}
+
+func Test_MessageAckAndDedupe(t *testing.T) {
+	service := getTestSetup("alice")
+	msgChan := make(chan PeerMessage)
+	service.swapServices.messenger = &dummyMessenger{msgChan: msgChan}
+	service.Start()
+
+	fsm := newSwapInSenderFSM(service.swapServices, "alice", "bob")
+	fsm.Current = State_SwapInSender_AwaitAgreement
+	fsm.Data.SwapInRequest = &SwapInRequestMessage{SwapId: fsm.SwapId}
+	service.lockSwap(fsm.SwapId.String(), fsm.Data.GetScid(), fsm)
+
+	msg, msgType, err := MarshalPeerswapMessage(&CancelMessage{SwapId: fsm.SwapId, Message: "canceled"})
+	require.NoError(t, err)
+	msgTypeString := messages.MessageTypeToHexString(messages.MessageType(msgType))
+
+	// The message is handled and acknowledged.
+	err = service.OnMessageReceived("bob", msgTypeString, msg)
+	require.NoError(t, err)
+	assert.Equal(t, State_SwapCanceled, fsm.Current)
+	assert.Equal(t, messages.MESSAGETYPE_ACK, (<-msgChan).MessageType())
+
+	// The resent message is only acknowledged again, the swap is not active
+	// anymore.
+	err = service.OnMessageReceived("bob", msgTypeString, msg)
+	require.NoError(t, err)
+	assert.Equal(t, messages.MESSAGETYPE_ACK, (<-msgChan).MessageType())
+
+	// Peers that did not announce the ack feature are not sent acks.
+	service.swapServices.peerFeatures = &peerFeaturesStub{features: []string{messages.FEATURE_TLV}}
+	done := make(chan error)
+	go func() {
+		done <- service.OnMessageReceived("bob", msgTypeString, msg)
+	}()
+	select {
+	case m := <-msgChan:
+		t.Fatalf("unexpected message %v", m.MessageType())
+	case err := <-done:
+		require.NoError(t, err)
+	}
+
+	// An ack is passed to the outbox.
+	ack, ackType, err := MarshalPeerswapMessage(&AckMessage{MessageId: "id"})
+	require.NoError(t, err)
+	err = service.OnMessageReceived("bob", messages.MessageTypeToHexString(messages.MessageType(ackType)), ack)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, service.swapServices.outbox.(*OutboxStub).Acked())
+}
+
+func Test_GetAssetLimits(t *testing.T) {
+	swapService := getTestSetup("alice")
+	minAmountSat := policy.DefaultPolicy().MinSwapAmountMsat / 1000
+
+	// Swap outs are limited by the wallet balance, rounded down to
+	// maxSwapOutBucketSat.
+	limits := swapService.GetAssetLimits()
+	assert.Equal(t, []AssetLimit{
+		{Asset: btc_chain, MinAmountSat: minAmountSat, MaxSwapOutAmountSat: 9900000, SwapTypes: []SwapType{SWAPTYPE_IN, SWAPTYPE_OUT}},
+		{Asset: l_btc_chain, MinAmountSat: minAmountSat, MaxSwapOutAmountSat: 9900000, SwapTypes: []SwapType{SWAPTYPE_IN, SWAPTYPE_OUT}},
+	}, limits)
+
+	// Without the balance for the smallest swap out only swap ins are
+	// accepted.
+	swapService.swapServices.bitcoinWallet.(*dummyChain).SetBalance(minAmountSat)
+	limits = swapService.GetAssetLimits()
+	assert.Equal(t, AssetLimit{Asset: btc_chain, MinAmountSat: minAmountSat, SwapTypes: []SwapType{SWAPTYPE_IN}}, limits[0])
+
+	// The same holds if the balance only covers the minimum amount before
+	// it is rounded down.
+	minAmountSat = maxSwapOutBucketSat + 50000
+	swapService.swapServices.policy.(*dummyPolicy).getMinSwapAmountMsatReturn = minAmountSat * 1000
+	swapService.swapServices.bitcoinWallet.(*dummyChain).SetBalance(minAmountSat + 10000 + 100 + swapOutSafetyNet)
+	limits = swapService.GetAssetLimits()
+	assert.Equal(t, AssetLimit{Asset: btc_chain, MinAmountSat: minAmountSat, SwapTypes: []SwapType{SWAPTYPE_IN}}, limits[0])
+}
+
+// Test_PeerOfflineAndOnline checks that the timeout of a swap is paused while
+// the peer is offline and that the last message is resent on reconnect.
+func Test_PeerOfflineAndOnline(t *testing.T) {
+	interval := reconnectInterval
+	reconnectInterval = 10 * time.Millisecond
+	defer func() { reconnectInterval = interval }()
+
+	sws := getTestSetup("alice")
+	msgChan := make(chan PeerMessage)
+	sws.swapServices.messenger = &dummyMessenger{msgChan: msgChan}
+	sws.Start()
+
+	fsm := newSwapInSenderFSM(sws.swapServices, "alice", "bob")
+	fsm.Current = State_SwapInSender_AwaitAgreement
+	fsm.Data.SwapInRequest = &SwapInRequestMessage{SwapId: fsm.SwapId}
+	fsm.Data.NextMessage = []byte("request")
+	fsm.Data.NextMessageType = int(messages.MESSAGETYPE_SWAPINREQUEST)
+	fsm.Data.startTimeout(sws.swapServices, 10*time.Minute)
+	deadline := fsm.Data.TimeOutAt
+	sws.lockSwap(fsm.SwapId.String(), fsm.Data.GetScid(), fsm)
+
+	// The deadline is moved by the max pause while the peer is offline.
+	sws.OnPeerOffline("bob")
+	maxPause := int64(maxTimeoutPause / time.Second)
+	assert.Equal(t, deadline+maxPause, fsm.Data.TimeOutAt)
+	assert.NotZero(t, fsm.Data.PeerOfflineAt)
+
+	lc := sws.swapServices.lightning.(*dummyLightningClient)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&lc.connectPeerCalled) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	// The peer comes back after 5 minutes, only the time it was offline is
+	// added to the deadline.
+	fsm.Data.PeerOfflineAt -= 300
+	sws.OnPeerOnline("bob")
+	assert.Equal(t, deadline+300, fsm.Data.TimeOutAt)
+	assert.Equal(t, int64(300), fsm.Data.TimeoutPausedSec)
+	assert.Zero(t, fsm.Data.PeerOfflineAt)
+
+	select {
+	case msg := <-msgChan:
+		assert.Equal(t, messages.MESSAGETYPE_SWAPINREQUEST, msg.MessageType())
+	case <-time.After(time.Second):
+		t.Fatal("last message was not resent")
+	}
+
+	// The pause is capped.
+	sws.OnPeerOffline("bob")
+	fsm.Data.PeerOfflineAt -= 2 * maxPause
+	sws.OnPeerOnline("bob")
+	assert.Equal(t, deadline+maxPause, fsm.Data.TimeOutAt)
+	<-msgChan
+}
+
+// Test_RateLimit checks that messages above the rate limit are dropped and
+// that a peer that keeps flooding us is reported as suspicious.
+func Test_RateLimit(t *testing.T) {
+	sws := getTestSetup("alice")
+	sws.swapServices.messenger = &noopMessenger{}
+	pol := sws.swapServices.policy.(*dummyPolicy)
+	msgType := messages.MessageTypeToHexString(messages.MESSAGETYPE_SWAPINREQUEST)
+	msg, err := messages.Marshal(&SwapInRequestMessage{SwapId: NewSwapId()}, false)
+	require.NoError(t, err)
+
+	burst := int(defaultRateLimits[messages.MESSAGETYPE_SWAPINREQUEST].Burst)
+	for i := 0; i < burst; i++ {
+		err = sws.OnMessageReceived("bob", msgType, msg)
+		assert.NotErrorIs(t, err, RateLimitError("bob"))
+	}
+	for i := 0; i < suspiciousViolations; i++ {
+		err = sws.OnMessageReceived("bob", msgType, msg)
+		assert.ErrorIs(t, err, RateLimitError("bob"))
+	}
+	assert.Equal(t, []string{"bob"}, pol.addedSuspiciousPeers)
+
+	// Polls are left to the poll service, which limits them with
+	// AllowMessage.
+	pollType := messages.MessageTypeToHexString(messages.MESSAGETYPE_POLL)
+	burst = int(defaultRateLimits[messages.MESSAGETYPE_POLL].Burst)
+	for i := 0; i < 2*burst; i++ {
+		assert.NoError(t, sws.OnMessageReceived("carol", pollType, msg))
+	}
+	for i := 0; i < burst; i++ {
+		assert.NoError(t, sws.AllowMessage("carol", messages.MESSAGETYPE_POLL))
+	}
+	assert.ErrorIs(t, sws.AllowMessage("carol", messages.MESSAGETYPE_POLL), RateLimitError("carol"))
+}
+
+// Test_MaxPendingIncomingSwaps checks that a peer can only request a limited
+// number of swaps at the same time.
+func Test_MaxPendingIncomingSwaps(t *testing.T) {
+	sws := getTestSetup("alice")
+	maxPending := int(sws.swapServices.policy.GetMaxPendingIncomingSwaps())
+	var fsms []*SwapStateMachine
+	for i := 0; i < maxPending; i++ {
+		fsm := newSwapInReceiverFSM(NewSwapId(), sws.swapServices, "bob")
+		err := sws.lockSwap(fsm.SwapId.String(), fmt.Sprintf("1x%dx0", i), fsm)
+		require.NoError(t, err)
+		fsms = append(fsms, fsm)
+	}
+
+	fsm := newSwapInReceiverFSM(NewSwapId(), sws.swapServices, "bob")
+	err := sws.lockSwap(fsm.SwapId.String(), "2x0x0", fsm)
+	assert.ErrorIs(t, err, TooManySwapsError("bob"))
+
+	// A swap whose opening tx was broadcasted is no longer pending.
+	fsms[0].Data.OpeningTxBroadcasted = &OpeningTxBroadcastedMessage{}
+	assert.NoError(t, sws.lockSwap(fsm.SwapId.String(), "2x0x0", fsm))
+
+	// Other peers and our own swaps are not affected.
+	fsm = newSwapInReceiverFSM(NewSwapId(), sws.swapServices, "carol")
+	assert.NoError(t, sws.lockSwap(fsm.SwapId.String(), "3x0x0", fsm))
+	fsm = newSwapOutSenderFSM(sws.swapServices, "alice", "bob")
+	assert.NoError(t, sws.lockSwap(fsm.SwapId.String(), "4x0x0", fsm))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/elementsproject/peerswap/messages"
@@ -22,10 +23,19 @@ type Messenger interface {
 	AddMessageHandler(func(peerId string, msgType string, payload []byte) error)
 }
 
-type MessengerManager interface {
-	AddSender(id string, messenger messages.StoppableMessenger) error
-	RemoveSender(id string)
+// Outbox resends messages until the peer acknowledges them.
+type Outbox interface {
+	Add(swapId, peerId string, message []byte, messageType int) error
+	Ack(peerId, messageId string)
+	// Remove stops resending the messages of a swap.
+	Remove(swapId string)
 }
+
+// PeerFeatures tells which features a peer announced in its poll.
+type PeerFeatures interface {
+	HasFeature(peerId, feature string) bool
+}
+
 type PeerMessage interface {
 	MessageType() messages.MessageType
 }
@@ -33,8 +43,13 @@ type PeerMessage interface {
 type Policy interface {
 	IsPeerAllowed(peer string) bool
 	IsPeerSuspicious(peer string) bool
+	AddToSuspiciousPeerList(peer string) error
 	GetReserveOnchainMsat() uint64
 	GetMinSwapAmountMsat() uint64
+	GetMaxFeeInvoiceMultiple() float64
+	GetMaxFeeInvoiceSat() uint64
+	GetMaxPendingIncomingSwaps() uint64
+	GetExternalFundingTimeoutSec() uint64
 	NewSwapsAllowed() bool
 }
 
@@ -48,6 +63,8 @@ type LightningClient interface {
 	RebalancePayment(payreq string, channel string) (preimage string, err error)
 	CanSpend(amountMsat uint64) error
 	Implementation() string
+	// ConnectPeer connects to the peer on one of its known addresses.
+	ConnectPeer(peerId string) error
 }
 
 type TxWatcher interface {
@@ -55,23 +72,54 @@ type TxWatcher interface {
 	AddWaitForCsvTx(swapId, txId string, vout uint32, startingHeight uint32, scriptpubkey []byte)
 	AddConfirmationCallback(func(swapId string, txHex string) error)
 	AddCsvCallback(func(swapId string) error)
+	// AddReorgCallback adds a callback that is called if a confirmed
+	// opening tx is reorged out of the chain.
+	AddReorgCallback(func(swapId string) error)
+	// AddSpendCallback adds a callback that is called with the spending tx
+	// if the opening output of a swap that waits for the csv limit is spent.
+	AddSpendCallback(func(swapId string, txHex string) error)
 	GetBlockHeight() (uint32, error)
 }
 
 type Validator interface {
 	TxIdFromHex(txHex string) (string, error)
 	ValidateTx(swapParams *OpeningParams, txHex string) (bool, error)
+	// GetPreimageFromSpendingTx returns the txid of the tx that spends the
+	// opening output and the preimage revealed by it, "" if the output was
+	// not spent with the preimage.
+	GetPreimageFromSpendingTx(openingTxId string, vout uint32, txHex string) (claimTxId, preimage string, err error)
 	GetCSVHeight() uint32
 }
 
 type Wallet interface {
 	CreateOpeningTransaction(swapParams *OpeningParams) (unpreparedTxHex string, fee uint64, vout uint32, err error)
 	BroadcastOpeningTx(unpreparedTxHex string) (txId, txHex string, error error)
+	// CreateBatchOpeningTransaction returns a funded and signed opening tx
+	// that pays several swaps, vouts[i] is the opening output of
+	// swapParams[i]. The tx is not broadcast, this is done with BroadcastTx
+	// once the swaps persisted it.
+	CreateBatchOpeningTransaction(swapParams []*OpeningParams) (txId, txHex string, vouts []uint32, err error)
+	// ReleaseBatchOpeningTransaction releases the wallet inputs that were
+	// reserved for a batch opening tx that the node rejected.
+	ReleaseBatchOpeningTransaction(txHex string) error
+	// CreateOpeningPsbt returns an unsigned base64 PSBT (PSET on liquid)
+	// without inputs that pays the swap amount to the opening output. It is
+	// funded and signed by an external wallet.
+	CreateOpeningPsbt(swapParams *OpeningParams) (string, error)
+	// ExtractOpeningTx returns the final opening tx of a signed PSBT (PSET)
+	// or a raw tx hex and the vout of the opening output. It returns an error
+	// if the tx does not pay the swap amount to the opening output.
+	ExtractOpeningTx(swapParams *OpeningParams, signedTx string) (txHex string, vout uint32, err error)
+	// BroadcastTx broadcasts a final tx and returns its txid.
+	BroadcastTx(txHex string) (string, error)
 	CreatePreimageSpendingTransaction(swapParams *OpeningParams, claimParams *ClaimParams) (string, string, error)
 	CreateCsvSpendingTransaction(swapParams *OpeningParams, claimParams *ClaimParams) (txId, txHex string, error error)
 	CreateCoopSpendingTransaction(swapParams *OpeningParams, claimParams *ClaimParams, takerSigner Signer) (txId, txHex string, error error)
 	GetOutputScript(params *OpeningParams) ([]byte, error)
 	NewAddress() (string, error)
+	// ValidateAddress returns an error if address can not be used as claim
+	// address on the network of the wallet.
+	ValidateAddress(address string) error
 	GetRefundFee() (uint64, error)
 	GetFlatSwapOutFee() (uint64, error)
 	GetAsset() string
@@ -100,6 +148,9 @@ type ClaimParams struct {
 	Preimage     string
 	Signer       Signer
 	OpeningTxHex string
+	// ClaimAddress is the address the funds are sent to. A new wallet
+	// address is used if it is empty.
+	ClaimAddress string
 
 	// blinded tx stuff
 	BlindingSeed              []byte
@@ -116,7 +167,7 @@ type Signer interface {
 }
 
 type TimeOutService interface {
-	addNewTimeOut(ctx context.Context, d time.Duration, id string)
+	addNewTimeOut(ctx context.Context, deadline time.Time, id string)
 }
 
 type SwapServices struct {
@@ -124,7 +175,8 @@ type SwapServices struct {
 	requestedSwapsStore RequestedSwapsStore
 	lightning           LightningClient
 	messenger           Messenger
-	messengerManager    MessengerManager
+	outbox              Outbox
+	peerFeatures        PeerFeatures
 	policy              Policy
 	bitcoinTxWatcher    TxWatcher
 	bitcoinValidator    Validator
@@ -135,6 +187,16 @@ type SwapServices struct {
 	liquidWallet        Wallet
 	liquidEnabled       bool
 	toService           TimeOutService
+	reorgs              *txNotifier
+	spends              *txNotifier
+
+	// claimBatchers queue the preimage claims per chain, claims on chains
+	// without a batcher are broadcast directly.
+	claimBatchersMu sync.Mutex
+	claimBatchers   map[string]*ClaimBatcher
+
+	// openingBatches collect the opening outputs of batched swap-ins.
+	openingBatches *openingBatches
 }
 
 func NewSwapServices(
@@ -142,7 +204,8 @@ func NewSwapServices(
 	requestedSwapsStore RequestedSwapsStore,
 	lightning LightningClient,
 	messenger Messenger,
-	messengerManager MessengerManager,
+	outbox Outbox,
+	peerFeatures PeerFeatures,
 	policy Policy,
 	bitcoinEnabled bool,
 	bitcoinWallet Wallet,
@@ -157,8 +220,11 @@ func NewSwapServices(
 		requestedSwapsStore: requestedSwapsStore,
 		lightning:           lightning,
 		messenger:           messenger,
-		messengerManager:    messengerManager,
+		outbox:              outbox,
+		peerFeatures:        peerFeatures,
 		policy:              policy,
+		reorgs:              newTxNotifier(),
+		spends:              newTxNotifier(),
 		bitcoinTxWatcher:    bitcoinTxWatcher,
 		bitcoinWallet:       bitcoinWallet,
 		bitcoinValidator:    bitcoinValidator,
@@ -167,6 +233,8 @@ func NewSwapServices(
 		liquidWallet:        liquidWallet,
 		liquidValidator:     liquidValidator,
 		liquidTxWatcher:     liquidTxWatcher,
+		claimBatchers:       map[string]*ClaimBatcher{},
+		openingBatches:      newOpeningBatches(),
 	}
 }
 
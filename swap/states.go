@@ -43,6 +43,9 @@ const (
 	State_SwapInSender_SendRequest              StateType = "State_SwapInSender_SendRequest"
 	State_SwapInSender_AwaitAgreement           StateType = "State_SwapInSender_AwaitAgreement"
 	State_SwapInSender_BroadcastOpeningTx       StateType = "State_SwapInSender_BroadcastOpeningTx"
+	State_SwapInSender_AwaitExternalFunding     StateType = "State_SwapInSender_AwaitExternalFunding"
+	State_SwapInSender_AwaitOpeningBatch        StateType = "State_SwapInSender_AwaitOpeningBatch"
+	State_SwapInSender_LeaveOpeningBatch        StateType = "State_SwapInSender_LeaveOpeningBatch"
 	State_SwapInSender_SendTxBroadcastedMessage StateType = "State_SwapInSender_SendTxBroadcastedMessage"
 	State_SwapInSender_AwaitClaimPayment        StateType = "State_SwapInSender_AwaitClaimPayment"
 	State_SwapInSender_ClaimSwapCsv             StateType = "State_SwapInSender_ClaimSwapCsv"
@@ -68,6 +71,12 @@ const (
 
 	Event_OnTxOpenedMessage EventType = "Event_OnTxOpenedMessage"
 	Event_OnTxConfirmed     EventType = "Event_OnTxConfirmed"
+	// Event_OnTxReorged is returned if the opening tx was reorged out of
+	// the chain before the claim invoice was paid.
+	Event_OnTxReorged EventType = "Event_OnTxReorged"
+	// Event_OnOpeningTxSpent is sent if the output of the opening tx is spent
+	// by the maker before the taker claimed it.
+	Event_OnOpeningTxSpent EventType = "Event_OnOpeningTxSpent"
 
 	// todo retrystate? failstate? refundstate?
 	Event_OnRetry      EventType = "Event_OnRetry"
@@ -80,6 +89,10 @@ const (
 	Event_OnCsvPassed         EventType = "Event_OnCsvPassed"
 	Event_OnCancelReceived    EventType = "Event_OnCancelReceived"
 	Event_OnCoopCloseReceived EventType = "Event_OnCoopCloseReceived"
+	// Event_OnClaimedPreimageOnchain is sent if the taker claimed the
+	// opening tx with the preimage. This settles the swap even if the claim
+	// invoice payment was not noticed.
+	Event_OnClaimedPreimageOnchain EventType = "Event_OnClaimedPreimageOnchain"
 
 	Event_OnTimeout = "Event_OnTimeout"
 
@@ -90,5 +103,18 @@ const (
 	Event_SwapInReceiver_OnRequestReceived EventType = "Event_SwapInReceiver_OnRequestReceived"
 	Event_Done                             EventType = "Event_Done"
 
+	// Event_SwapInSender_OnAwaitExternalFunding is returned if the opening
+	// tx of the swap is funded by an external wallet.
+	Event_SwapInSender_OnAwaitExternalFunding EventType = "Event_SwapInSender_OnAwaitExternalFunding"
+	// Event_SwapInSender_OnOpeningTxSubmitted is sent when the externally
+	// funded opening tx was submitted.
+	Event_SwapInSender_OnOpeningTxSubmitted EventType = "Event_SwapInSender_OnOpeningTxSubmitted"
+	// Event_SwapInSender_OnAwaitOpeningBatch is returned if the opening
+	// output of the swap is added to a batched opening tx.
+	Event_SwapInSender_OnAwaitOpeningBatch EventType = "Event_SwapInSender_OnAwaitOpeningBatch"
+	// Event_SwapInSender_OnOpeningBatchDone is sent when the batched opening
+	// tx was broadcast or the swap left the batch.
+	Event_SwapInSender_OnOpeningBatchDone EventType = "Event_SwapInSender_OnOpeningBatchDone"
+
 	Event_OnInvalid_Message EventType = "Event_Invalid_Message"
 )
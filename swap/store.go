@@ -4,6 +4,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"go.etcd.io/bbolt"
 )
@@ -240,6 +242,14 @@ func h2b(str string) []byte {
 	return buf
 }
 
+// Default retention of the requested swaps log. MaxRequestedSwapsPerPeer is
+// the hard ceiling of requests kept per peer, whatever is configured.
+const (
+	DefaultRequestedSwapsRetentionDays = 30
+	DefaultRequestedSwapsMaxPerPeer    = 100
+	MaxRequestedSwapsPerPeer           = 1000
+)
+
 type RequestedSwapsStore interface {
 	Add(id string, reqswap RequestedSwap) error
 	Get(id string) ([]RequestedSwap, error)
@@ -247,16 +257,70 @@ type RequestedSwapsStore interface {
 }
 
 type RequestedSwap struct {
-	Asset           string   `json:"asset"`
-	AmountSat       uint64   `json:"amount_sat"`
-	Type            SwapType `json:"swap_type"`
-	RejectionReason string   `json:"rejection_reason"`
+	Asset           string     `json:"asset"`
+	AmountSat       uint64     `json:"amount_sat"`
+	Type            SwapType   `json:"swap_type"`
+	RejectionReason string     `json:"rejection_reason"`
+	RejectionCode   CancelCode `json:"rejection_code,omitempty"`
+	// CreatedAt is the unix time the request was received. Requests stored
+	// by older versions have no timestamp.
+	CreatedAt int64 `json:"created_at,omitempty"`
+}
+
+// RequestedSwapsRetention limits how many requested swaps are kept.
+type RequestedSwapsRetention struct {
+	// MaxAge drops requests older than this, zero keeps them forever.
+	MaxAge time.Duration
+	// MaxPerPeer keeps only the latest requests of every peer. Zero or a
+	// value above MaxRequestedSwapsPerPeer is capped to that ceiling.
+	MaxPerPeer int
+}
+
+// DefaultRequestedSwapsRetention returns the default retention.
+func DefaultRequestedSwapsRetention() RequestedSwapsRetention {
+	return RequestedSwapsRetention{
+		MaxAge:     DefaultRequestedSwapsRetentionDays * 24 * time.Hour,
+		MaxPerPeer: DefaultRequestedSwapsMaxPerPeer,
+	}
+}
+
+// apply returns the requests that are within the retention at now. Requests
+// without a timestamp are only bounded by the per peer limit.
+func (r RequestedSwapsRetention) apply(reqswaps []RequestedSwap, now time.Time) []RequestedSwap {
+	if r.MaxAge > 0 {
+		cutoff := now.Add(-r.MaxAge).Unix()
+		kept := reqswaps[:0:0]
+		for _, reqswap := range reqswaps {
+			if reqswap.CreatedAt == 0 || reqswap.CreatedAt >= cutoff {
+				kept = append(kept, reqswap)
+			}
+		}
+		reqswaps = kept
+	}
+	maxPerPeer := r.MaxPerPeer
+	if maxPerPeer <= 0 || maxPerPeer > MaxRequestedSwapsPerPeer {
+		maxPerPeer = MaxRequestedSwapsPerPeer
+	}
+	if len(reqswaps) > maxPerPeer {
+		reqswaps = reqswaps[len(reqswaps)-maxPerPeer:]
+	}
+	return reqswaps
 }
+
+// requestedSwapsPruneInterval is how often the requests of all peers are
+// pruned when a new request is added.
+const requestedSwapsPruneInterval = time.Hour
+
 type requestedSwapsStore struct {
-	db *bbolt.DB
+	db        *bbolt.DB
+	retention RequestedSwapsRetention
+
+	sync.Mutex
+	// lastPrune is the time all requests were pruned last.
+	lastPrune time.Time
 }
 
-func NewRequestedSwapsStore(db *bbolt.DB) (*requestedSwapsStore, error) {
+func NewRequestedSwapsStore(db *bbolt.DB, retention RequestedSwapsRetention) (*requestedSwapsStore, error) {
 	tx, err := db.Begin(true)
 	if err != nil {
 		return nil, err
@@ -270,25 +334,34 @@ func NewRequestedSwapsStore(db *bbolt.DB) (*requestedSwapsStore, error) {
 		return nil, err
 	}
 
-	return &requestedSwapsStore{db: db}, nil
+	return &requestedSwapsStore{db: db, retention: retention}, nil
 }
 
+// Add stores the request of a peer. The requests of all peers are pruned
+// along once per requestedSwapsPruneInterval, so that the requests of peers
+// that stopped sending any do not pile up until the next restart.
 func (s *requestedSwapsStore) Add(id string, reqswap RequestedSwap) error {
+	now := time.Now()
+	s.Lock()
+	defer s.Unlock()
 	return s.db.Update(func(tx *bbolt.Tx) error {
 		b := tx.Bucket(requestedSwapsBucket)
+		if now.Sub(s.lastPrune) >= requestedSwapsPruneInterval {
+			if err := s.prune(b, now); err != nil {
+				return err
+			}
+			s.lastPrune = now
+		}
 		k := b.Get([]byte(id))
 
 		var reqswaps []RequestedSwap
-		if k == nil {
-			reqswaps = []RequestedSwap{reqswap}
-		} else {
+		if k != nil {
 			err := json.Unmarshal(k, &reqswaps)
 			if err != nil {
 				return err
 			}
-
-			reqswaps = append(reqswaps, reqswap)
 		}
+		reqswaps = s.retention.apply(append(reqswaps, reqswap), now)
 
 		buf, err := json.Marshal(reqswaps)
 		if err != nil {
@@ -299,7 +372,59 @@ func (s *requestedSwapsStore) Add(id string, reqswap RequestedSwap) error {
 	})
 }
 
+// Prune drops all requests that are outside of the retention and removes
+// peers without any requests left.
+func (s *requestedSwapsStore) Prune() error {
+	now := time.Now()
+	s.Lock()
+	defer s.Unlock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return s.prune(tx.Bucket(requestedSwapsBucket), now)
+	})
+	if err != nil {
+		return err
+	}
+	s.lastPrune = now
+	return nil
+}
+
+func (s *requestedSwapsStore) prune(b *bbolt.Bucket, now time.Time) error {
+	updates := map[string][]RequestedSwap{}
+	err := b.ForEach(func(k, v []byte) error {
+		var reqswaps []RequestedSwap
+		if err := json.Unmarshal(v, &reqswaps); err != nil {
+			return nil
+		}
+		kept := s.retention.apply(reqswaps, now)
+		if len(kept) != len(reqswaps) {
+			updates[string(k)] = kept
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for id, reqswaps := range updates {
+		if len(reqswaps) == 0 {
+			if err := b.Delete([]byte(id)); err != nil {
+				return err
+			}
+			continue
+		}
+		buf, err := json.Marshal(reqswaps)
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(id), buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *requestedSwapsStore) GetAll() (map[string][]RequestedSwap, error) {
+	now := time.Now()
 	reqswaps := map[string][]RequestedSwap{}
 	err := s.db.View(func(tx *bbolt.Tx) error {
 		b := tx.Bucket(requestedSwapsBucket)
@@ -307,7 +432,9 @@ func (s *requestedSwapsStore) GetAll() (map[string][]RequestedSwap, error) {
 			id := string(k)
 			var reqswap []RequestedSwap
 			json.Unmarshal(v, &reqswap)
-			reqswaps[id] = reqswap
+			if reqswap = s.retention.apply(reqswap, now); len(reqswap) > 0 {
+				reqswaps[id] = reqswap
+			}
 			return nil
 		})
 	})
@@ -329,5 +456,5 @@ func (s *requestedSwapsStore) Get(id string) ([]RequestedSwap, error) {
 		return nil, err
 	}
 
-	return reqswaps, nil
+	return s.retention.apply(reqswaps, time.Now()), nil
 }
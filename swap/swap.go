@@ -11,6 +11,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/elementsproject/peerswap/lightning"
+	"github.com/elementsproject/peerswap/log"
 )
 
 type SwapType int
@@ -102,7 +103,8 @@ type SwapData struct {
 	Cancel *CancelMessage `json:"cancel_message_obj"`
 
 	// cancel message
-	CancelMessage string `json:"cancel_message"`
+	CancelMessage string     `json:"cancel_message"`
+	CancelCode    CancelCode `json:"cancel_code,omitempty"`
 
 	PeerNodeId          string    `json:"peer_node_id"`
 	InitiatorNodeId     string    `json:"initiator_node_id"`
@@ -117,6 +119,45 @@ type SwapData struct {
 	ClaimTxId           string    `json:"claim_tx_id"`
 	ClaimPaymentHash    string    `json:"claim_payment_hash"`
 	ClaimPreimage       string    `json:"claim_preimage"`
+	// ClaimAddress is the address a swap-out is claimed to instead of a new
+	// wallet address.
+	ClaimAddress string `json:"claim_address,omitempty"`
+	// ClaimQueuedAt is the unix time at which the claim was first queued for
+	// a batched sweep.
+	ClaimQueuedAt int64 `json:"claim_queued_at,omitempty"`
+	// ClaimSweepFailed is set if the batched sweep of the claim failed. The
+	// claim is then spent on its own, so that it does not fail the next
+	// sweep again.
+	ClaimSweepFailed bool `json:"claim_sweep_failed,omitempty"`
+	// ExternalFunding is set if the opening tx of a swap-in is funded and
+	// signed by an external wallet instead of the node wallet.
+	ExternalFunding bool `json:"external_funding,omitempty"`
+	// OpeningPsbt is the unsigned PSBT (PSET on liquid) paying to the
+	// opening output that the external wallet funds.
+	OpeningPsbt string `json:"opening_psbt,omitempty"`
+	// ExternalOpeningTxHex and ExternalOpeningVout are the externally signed
+	// opening tx and its opening output.
+	ExternalOpeningTxHex string `json:"external_opening_tx_hex,omitempty"`
+	ExternalOpeningVout  uint32 `json:"external_opening_vout,omitempty"`
+	// OpeningBatchId is the batch of swap-ins whose opening outputs are
+	// paid by a single opening tx.
+	OpeningBatchId string `json:"opening_batch_id,omitempty"`
+	// BatchOpeningTxId, BatchOpeningTxHex and BatchOpeningVout are the
+	// broadcasted opening tx of the batch and the opening output of the swap.
+	BatchOpeningTxId  string `json:"batch_opening_tx_id,omitempty"`
+	BatchOpeningTxHex string `json:"batch_opening_tx_hex,omitempty"`
+	BatchOpeningVout  uint32 `json:"batch_opening_vout,omitempty"`
+	// BatchOpeningTxPublished is set once the node accepted the batch
+	// opening tx, a later broadcast may fail as the tx is mined already.
+	BatchOpeningTxPublished bool `json:"batch_opening_tx_published,omitempty"`
+	// DoubleSpendTxId is the id of a tx that spent the opening output
+	// competing with our claim.
+	DoubleSpendTxId string `json:"double_spend_tx_id,omitempty"`
+	// ExpectedOpeningTxFee is our own estimate of the opening tx fee of a
+	// swap-out that the fee invoice is checked against. FeeOvercharge is the
+	// amount by which a rejected fee invoice exceeded the accepted maximum.
+	ExpectedOpeningTxFee uint64 `json:"expected_opening_tx_fee,omitempty"`
+	FeeOvercharge        uint64 `json:"fee_overcharge,omitempty"`
 
 	BlindingKeyHex string `json:"blinding_key"`
 
@@ -128,6 +169,16 @@ type SwapData struct {
 	LastErr       error  `json:"-"`
 	LastErrString string `json:"last_err,omitempty"`
 
+	// TimeOutAt is the unix time at which the pending timeout fires. It is
+	// persisted so that the timeout can be re-armed on recovery.
+	TimeOutAt int64 `json:"timeout_at,omitempty"`
+	// PeerOfflineAt is the unix time at which the peer went offline while a
+	// timeout was pending. The timeout is paused until the peer reconnects.
+	PeerOfflineAt int64 `json:"peer_offline_at,omitempty"`
+	// TimeoutPausedSec is the total time in seconds the timeouts of the swap
+	// were paused, it is limited by maxTimeoutPause.
+	TimeoutPausedSec int64 `json:"timeout_paused_sec,omitempty"`
+
 	// TimeOut cancel func. If set and called cancels the timout context so that
 	// the TimeOut callback does not get called after cancel.
 	toCancel context.CancelFunc
@@ -338,6 +389,7 @@ func (s *SwapData) GetClaimParams() *ClaimParams {
 		Preimage:     s.ClaimPreimage,
 		Signer:       &Secp256k1Signer{key},
 		OpeningTxHex: s.OpeningTxHex,
+		ClaimAddress: s.ClaimAddress,
 	}
 
 	return claimParams
@@ -350,6 +402,15 @@ func (s *SwapData) GetOpeningTxId() string {
 	return ""
 }
 
+// GetCancelCode returns the code of the cancel message we received or the
+// code we canceled the swap with.
+func (s *SwapData) GetCancelCode() CancelCode {
+	if s.Cancel != nil {
+		return s.Cancel.Code
+	}
+	return s.CancelCode
+}
+
 func (s *SwapData) GetCancelMessage() string {
 	if s.Cancel != nil {
 		return s.Cancel.Message
@@ -366,10 +427,80 @@ func (s *SwapData) GetCancelMessage() string {
 	return ""
 }
 
+// startTimeout arms a timeout that fires after d and stores its absolute
+// deadline with the swap.
+func (s *SwapData) startTimeout(services *SwapServices, d time.Duration) {
+	s.TimeOutAt = time.Now().Add(d).Unix()
+	s.PeerOfflineAt = 0
+	s.armTimeout(services)
+}
+
+// armTimeout arms the timeout at the stored deadline.
+func (s *SwapData) armTimeout(services *SwapServices) {
+	toCtx, cancel := context.WithCancel(context.Background())
+	s.toCancel = cancel
+	services.toService.addNewTimeOut(toCtx, time.Unix(s.TimeOutAt, 0), s.GetId().String())
+}
+
 func (s *SwapData) cancelTimeout() {
 	if s.toCancel != nil {
 		s.toCancel()
 	}
+	s.TimeOutAt = 0
+	s.PeerOfflineAt = 0
+}
+
+// pauseTimeout pauses the pending timeout while the peer is offline. The
+// deadline is moved by the pause that is left, so that the timeout still
+// fires if the peer does not come back. Returns false if no timeout is
+// pending or it is already paused.
+func (s *SwapData) pauseTimeout(services *SwapServices, now time.Time) bool {
+	if s.TimeOutAt == 0 || s.PeerOfflineAt > 0 {
+		return false
+	}
+	s.PeerOfflineAt = now.Unix()
+	s.TimeOutAt += s.pauseLeft()
+	s.rearmTimeout(services)
+	return true
+}
+
+// resumeTimeout resumes a paused timeout when the peer reconnects. The
+// deadline is moved back by the part of the pause that was not used.
+// Returns false if the timeout was not paused.
+func (s *SwapData) resumeTimeout(services *SwapServices, now time.Time) bool {
+	if s.TimeOutAt == 0 || s.PeerOfflineAt == 0 {
+		return false
+	}
+	left := s.pauseLeft()
+	paused := now.Unix() - s.PeerOfflineAt
+	if paused > left {
+		paused = left
+	}
+	if paused < 0 {
+		paused = 0
+	}
+	s.TimeoutPausedSec += paused
+	s.TimeOutAt -= left - paused
+	s.PeerOfflineAt = 0
+	s.rearmTimeout(services)
+	return true
+}
+
+// pauseLeft returns the seconds the timeouts of the swap may still be paused.
+func (s *SwapData) pauseLeft() int64 {
+	left := int64(maxTimeoutPause/time.Second) - s.TimeoutPausedSec
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// rearmTimeout cancels the pending timeout and arms it at the stored deadline.
+func (s *SwapData) rearmTimeout(services *SwapServices) {
+	if s.toCancel != nil {
+		s.toCancel()
+	}
+	s.armTimeout(services)
 }
 
 func (s *SwapData) GetPrivkey() *btcec.PrivateKey {
@@ -377,6 +508,16 @@ func (s *SwapData) GetPrivkey() *btcec.PrivateKey {
 	return privkey
 }
 
+// Logger returns a log entry that carries the context of the swap.
+func (s *SwapData) Logger() *log.Entry {
+	return log.WithFields(log.Fields{
+		log.SwapIdField: s.GetId().String(),
+		log.PeerField:   s.PeerNodeId,
+		log.StateField:  string(s.FSMState),
+		log.ChainField:  s.GetChain(),
+	})
+}
+
 // NewSwapData returns a new swap with a random hex id and the given arguments
 func NewSwapData(swapId *SwapId, initiatorNodeId string, peerNodeId string) *SwapData {
 	return &SwapData{
@@ -487,3 +628,40 @@ func (s SwapErrorContext) ApplyToSwapData(data *SwapData) error {
 func (s *SwapErrorContext) Validate(data *SwapData) error {
 	return nil
 }
+
+// OpeningTxSpentContext holds a tx that spent the opening output and the
+// preimage that it revealed on-chain, if any. A preimage spend is the claim
+// of the taker. A spend that competes with a claim that we already know of is
+// recorded as a double-spend attempt.
+type OpeningTxSpentContext struct {
+	SpendingTxId string
+	Preimage     string
+}
+
+func (c OpeningTxSpentContext) ApplyToSwapData(data *SwapData) error {
+	if c.Preimage != "" && data.ClaimPreimage == "" {
+		data.ClaimPreimage = c.Preimage
+	}
+	if c.Preimage != "" && data.ClaimTxId == "" {
+		data.ClaimTxId = c.SpendingTxId
+		return nil
+	}
+	if c.SpendingTxId != data.ClaimTxId {
+		data.DoubleSpendTxId = c.SpendingTxId
+	}
+	return nil
+}
+
+func (c OpeningTxSpentContext) Validate(data *SwapData) error {
+	if c.Preimage == "" {
+		return nil
+	}
+	preimage, err := lightning.MakePreimageFromStr(c.Preimage)
+	if err != nil {
+		return err
+	}
+	if preimage.Hash().String() != data.GetPaymentHash() {
+		return fmt.Errorf("preimage does not match payment hash %s", data.GetPaymentHash())
+	}
+	return nil
+}
@@ -61,13 +61,16 @@ func getSwapInReceiverStates() States {
 				Event_OnTxConfirmed:    State_SwapInReceiver_ValidateTxAndPayClaimInvoice,
 				Event_ActionFailed:     State_SwapInReceiver_SendPrivkey,
 				Event_OnCancelReceived: State_SwapInReceiver_SendPrivkey,
+				Event_OnOpeningTxSpent: State_SwapInReceiver_SendPrivkey,
 			},
 		},
 		State_SwapInReceiver_ValidateTxAndPayClaimInvoice: {
 			Action: &ValidateTxAndPayClaimInvoiceAction{},
 			Events: Events{
-				Event_ActionSucceeded: State_SwapInReceiver_ClaimSwap,
-				Event_ActionFailed:    State_SwapInReceiver_SendPrivkey,
+				Event_OnTxReorged:      State_SwapInReceiver_AwaitTxConfirmation,
+				Event_OnOpeningTxSpent: State_SwapInReceiver_SendPrivkey,
+				Event_ActionSucceeded:  State_SwapInReceiver_ClaimSwap,
+				Event_ActionFailed:     State_SwapInReceiver_SendPrivkey,
 			},
 		},
 		State_SwapInReceiver_SendPrivkey: {
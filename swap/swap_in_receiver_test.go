@@ -168,4 +168,12 @@ func Test_SwapInReceiver_PeerIsSuspicious(t *testing.T) {
 	assert.Equal(t, messages.MESSAGETYPE_CANCELED, msg.MessageType())
 	assert.Equal(t, State_SwapCanceled, swap.Data.GetCurrentState())
 	assert.Equal(t, fmt.Sprintf("peer %s not allowed to request swaps", initiator), swap.Data.CancelMessage)
+	assert.Equal(t, CancelCode_PeerSuspicious, swap.Data.GetCancelCode())
+
+	// The rejected request is recorded with its code.
+	reqswaps, err := swapServices.requestedSwapsStore.Get(initiator)
+	assert.NoError(t, err)
+	if assert.Len(t, reqswaps, 1) {
+		assert.Equal(t, CancelCode_PeerSuspicious, reqswaps[0].RejectionCode)
+	}
 }
@@ -55,8 +55,36 @@ func getSwapInSenderStates() States {
 		State_SwapInSender_BroadcastOpeningTx: {
 			Action: &CreateAndBroadcastOpeningTransaction{},
 			Events: Events{
-				Event_ActionSucceeded: State_SwapInSender_SendTxBroadcastedMessage,
-				Event_ActionFailed:    State_SendCancel,
+				Event_ActionSucceeded:                     State_SwapInSender_SendTxBroadcastedMessage,
+				Event_ActionFailed:                        State_SendCancel,
+				Event_SwapInSender_OnAwaitExternalFunding: State_SwapInSender_AwaitExternalFunding,
+				Event_SwapInSender_OnAwaitOpeningBatch:    State_SwapInSender_AwaitOpeningBatch,
+			},
+		},
+		State_SwapInSender_AwaitExternalFunding: {
+			Action: &NoOpAction{},
+			Events: Events{
+				Event_SwapInSender_OnOpeningTxSubmitted: State_SwapInSender_BroadcastOpeningTx,
+				Event_OnCancelReceived:                  State_SwapCanceled,
+				Event_OnTimeout:                         State_SendCancel,
+				Event_OnInvalid_Message:                 State_SendCancel,
+			},
+		},
+		State_SwapInSender_AwaitOpeningBatch: {
+			Action: &AwaitOpeningBatchAction{},
+			Events: Events{
+				Event_SwapInSender_OnOpeningBatchDone: State_SwapInSender_BroadcastOpeningTx,
+				Event_OnCancelReceived:                State_SwapInSender_LeaveOpeningBatch,
+				Event_OnTimeout:                       State_SwapInSender_LeaveOpeningBatch,
+				Event_OnInvalid_Message:               State_SwapInSender_LeaveOpeningBatch,
+			},
+		},
+		State_SwapInSender_LeaveOpeningBatch: {
+			Action: &LeaveOpeningBatchAction{},
+			Events: Events{
+				Event_OnCancelReceived: State_SwapCanceled,
+				Event_ActionFailed:     State_SendCancel,
+				Event_ActionSucceeded:  State_WaitCsv,
 			},
 		},
 		State_SwapInSender_SendTxBroadcastedMessage: {
@@ -69,11 +97,12 @@ func getSwapInSenderStates() States {
 		State_SwapInSender_AwaitClaimPayment: {
 			Action: &AwaitPaymentOrCsvAction{},
 			Events: Events{
-				Event_OnClaimInvoicePaid:  State_ClaimedPreimage,
-				Event_OnCsvPassed:         State_SwapInSender_ClaimSwapCsv,
-				Event_OnCancelReceived:    State_WaitCsv,
-				Event_OnCoopCloseReceived: State_SwapInSender_ClaimSwapCoop,
-				Event_OnInvalid_Message:   State_WaitCsv,
+				Event_OnClaimInvoicePaid:       State_ClaimedPreimage,
+				Event_OnClaimedPreimageOnchain: State_ClaimedPreimage,
+				Event_OnCsvPassed:              State_SwapInSender_ClaimSwapCsv,
+				Event_OnCancelReceived:         State_WaitCsv,
+				Event_OnCoopCloseReceived:      State_SwapInSender_ClaimSwapCoop,
+				Event_OnInvalid_Message:        State_WaitCsv,
 			},
 		},
 		State_SwapInSender_ClaimSwapCsv: {
@@ -93,8 +122,9 @@ func getSwapInSenderStates() States {
 		State_WaitCsv: {
 			Action: &StopSendMessageWithRetryWrapperAction{next: &AwaitCsvAction{}},
 			Events: Events{
-				Event_OnCsvPassed:         State_SwapInSender_ClaimSwapCsv,
-				Event_OnCoopCloseReceived: State_SwapInSender_ClaimSwapCoop,
+				Event_OnCsvPassed:              State_SwapInSender_ClaimSwapCsv,
+				Event_OnCoopCloseReceived:      State_SwapInSender_ClaimSwapCoop,
+				Event_OnClaimedPreimageOnchain: State_ClaimedPreimage,
 			},
 		},
 		State_SendCancel: {
@@ -138,8 +138,8 @@ func getSwapServices(msgChan chan PeerMessage) *SwapServices {
 	chain := &dummyChain{returnGetCSVHeight: 1008}
 	chain.SetBalance(1000000)
 
-	mmgr := &MessengerManagerStub{}
-	swapServices := NewSwapServices(store, reqSwapsStore, lc, messenger, mmgr, policy, true, chain, chain, chain, true, chain, chain, chain)
+	outbox := &OutboxStub{}
+	swapServices := NewSwapServices(store, reqSwapsStore, lc, messenger, outbox, nil, policy, true, chain, chain, chain, true, chain, chain, chain)
 	swapServices.toService = &timeOutDummy{}
 	return swapServices
 }
@@ -71,11 +71,12 @@ func getSwapOutReceiverStates() States {
 		State_SwapOutReceiver_AwaitClaimInvoicePayment: {
 			Action: &AwaitPaymentOrCsvAction{},
 			Events: Events{
-				Event_OnClaimInvoicePaid:  State_ClaimedPreimage,
-				Event_OnCancelReceived:    State_WaitCsv,
-				Event_OnCoopCloseReceived: State_SwapOutReceiver_ClaimSwapCoop,
-				Event_OnCsvPassed:         State_SwapOutReceiver_ClaimSwapCsv,
-				Event_OnInvalid_Message:   State_WaitCsv,
+				Event_OnClaimInvoicePaid:       State_ClaimedPreimage,
+				Event_OnClaimedPreimageOnchain: State_ClaimedPreimage,
+				Event_OnCancelReceived:         State_WaitCsv,
+				Event_OnCoopCloseReceived:      State_SwapOutReceiver_ClaimSwapCoop,
+				Event_OnCsvPassed:              State_SwapOutReceiver_ClaimSwapCsv,
+				Event_OnInvalid_Message:        State_WaitCsv,
 			},
 		},
 		State_SwapOutReceiver_ClaimSwapCoop: {
@@ -88,8 +89,9 @@ func getSwapOutReceiverStates() States {
 		State_WaitCsv: {
 			Action: &StopSendMessageWithRetryWrapperAction{next: &AwaitCsvAction{}},
 			Events: Events{
-				Event_OnCsvPassed:         State_SwapOutReceiver_ClaimSwapCsv,
-				Event_OnCoopCloseReceived: State_SwapOutReceiver_ClaimSwapCoop,
+				Event_OnCsvPassed:              State_SwapOutReceiver_ClaimSwapCsv,
+				Event_OnCoopCloseReceived:      State_SwapOutReceiver_ClaimSwapCoop,
+				Event_OnClaimedPreimageOnchain: State_ClaimedPreimage,
 			},
 		},
 		State_SwapOutReceiver_ClaimSwapCsv: {
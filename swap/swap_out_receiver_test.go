@@ -221,4 +221,5 @@ func Test_SwapOutReceiver_PeerIsSuspicious(t *testing.T) {
 	assert.Equal(t, messages.MESSAGETYPE_CANCELED, msg.MessageType())
 	assert.Equal(t, State_SwapCanceled, swapFSM.Data.GetCurrentState())
 	assert.Equal(t, fmt.Sprintf("peer %s not allowed to request swaps", peer), swapFSM.Data.CancelMessage)
+	assert.Equal(t, CancelCode_PeerSuspicious, swapFSM.Data.GetCancelCode())
 }
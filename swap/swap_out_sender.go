@@ -85,15 +85,18 @@ func getSwapOutSenderStates() States {
 		State_SwapOutSender_AwaitTxConfirmation: {
 			Action: &AwaitTxConfirmationAction{},
 			Events: Events{
-				Event_ActionFailed:  State_SwapOutSender_SendPrivkey,
-				Event_OnTxConfirmed: State_SwapOutSender_ValidateTxAndPayClaimInvoice,
+				Event_ActionFailed:     State_SwapOutSender_SendPrivkey,
+				Event_OnTxConfirmed:    State_SwapOutSender_ValidateTxAndPayClaimInvoice,
+				Event_OnOpeningTxSpent: State_SwapOutSender_SendPrivkey,
 			},
 		},
 		State_SwapOutSender_ValidateTxAndPayClaimInvoice: {
 			Action: &ValidateTxAndPayClaimInvoiceAction{},
 			Events: Events{
-				Event_ActionFailed:    State_SwapOutSender_SendPrivkey,
-				Event_ActionSucceeded: State_SwapOutSender_ClaimSwap,
+				Event_OnTxReorged:      State_SwapOutSender_AwaitTxConfirmation,
+				Event_OnOpeningTxSpent: State_SwapOutSender_SendPrivkey,
+				Event_ActionFailed:     State_SwapOutSender_SendPrivkey,
+				Event_ActionSucceeded:  State_SwapOutSender_ClaimSwap,
 			},
 		},
 		State_SwapOutSender_ClaimSwap: {
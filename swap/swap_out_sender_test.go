@@ -3,11 +3,13 @@ package swap
 import (
 	"encoding/json"
 	"errors"
+	"sync/atomic"
 	"testing"
 
 	"github.com/elementsproject/peerswap/lightning"
 	"github.com/elementsproject/peerswap/messages"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_SwapMarshalling(t *testing.T) {
@@ -148,6 +150,66 @@ func Test_Cancel1(t *testing.T) {
 	assert.Equal(t, State_SwapCanceled, swapFSM.Data.GetCurrentState())
 }
 
+func Test_FeeInvoiceTooHigh(t *testing.T) {
+	swapAmount := uint64(100000)
+	initiator, peer, takerpubkeyhash, _, chanId := getTestParams()
+
+	// The estimated opening tx fee of the dummy chain is 100 sat.
+	tests := map[string]struct {
+		payreq         string
+		policy         *dummyPolicy
+		wantOvercharge uint64
+	}{
+		"default multiple": {
+			payreq:         "highfee",
+			policy:         &dummyPolicy{},
+			wantOvercharge: 700,
+		},
+		"multiple": {
+			payreq:         "fee",
+			policy:         &dummyPolicy{maxFeeInvoiceMultipleReturn: 0.5},
+			wantOvercharge: 50,
+		},
+		"absolute cap": {
+			payreq:         "fee",
+			policy:         &dummyPolicy{maxFeeInvoiceSatReturn: 80},
+			wantOvercharge: 20,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			msgChan := make(chan PeerMessage)
+			swapServices := getSwapServices(msgChan)
+			tt.policy.newSwapsAllowedReturn = true
+			swapServices.policy = tt.policy
+			swapFSM := newSwapOutSenderFSM(swapServices, initiator, peer)
+
+			_, err := swapFSM.SendEvent(Event_OnSwapOutStarted, &SwapOutRequestMessage{
+				Amount:          swapAmount,
+				Scid:            chanId,
+				SwapId:          swapFSM.SwapId,
+				Pubkey:          takerpubkeyhash,
+				Network:         "mainnet",
+				ProtocolVersion: PEERSWAP_PROTOCOL_VERSION,
+			})
+			require.NoError(t, err)
+			msg := <-msgChan
+			assert.Equal(t, messages.MESSAGETYPE_SWAPOUTREQUEST, msg.MessageType())
+
+			_, err = swapFSM.SendEvent(Event_OnFeeInvoiceReceived, &SwapOutAgreementMessage{Payreq: tt.payreq, Pubkey: peer})
+			require.NoError(t, err)
+			msg = <-msgChan
+			assert.Equal(t, messages.MESSAGETYPE_CANCELED, msg.MessageType())
+			assert.Equal(t, State_SwapCanceled, swapFSM.Data.GetCurrentState())
+			assert.Equal(t, CancelCode_FeeTooHigh, swapFSM.Data.CancelCode)
+			assert.Equal(t, uint64(100), swapFSM.Data.ExpectedOpeningTxFee)
+			assert.Equal(t, tt.wantOvercharge, swapFSM.Data.FeeOvercharge)
+			assert.Contains(t, swapFSM.Data.CancelMessage, "exceeds")
+			assert.Empty(t, swapFSM.Data.FeePreimage)
+		})
+	}
+}
+
 func Test_AbortCsvClaim(t *testing.T) {
 	swapAmount := uint64(100000)
 	initiator, peer, takerpubkeyhash, _, chanId := getTestParams()
@@ -208,7 +270,11 @@ type dummyStore struct {
 }
 
 func (d *dummyStore) ListAll() ([]*SwapStateMachine, error) {
-	panic("implement me")
+	var swaps []*SwapStateMachine
+	for _, swap := range d.dataMap {
+		swaps = append(swaps, swap)
+	}
+	return swaps, nil
 }
 
 func (d *dummyStore) ListAllByPeer(peer string) ([]*SwapStateMachine, error) {
@@ -254,6 +320,13 @@ type dummyLightningClient struct {
 
 	canSpendError  error
 	canSpendCalled int
+
+	connectPeerCalled int32
+}
+
+func (d *dummyLightningClient) ConnectPeer(peerId string) error {
+	atomic.AddInt32(&d.connectPeerCalled, 1)
+	return nil
 }
 
 func (d *dummyLightningClient) Implementation() string {
@@ -307,6 +380,9 @@ func (d *dummyLightningClient) DecodePayreq(payreq string) (string, uint64, int6
 	if payreq == "fee" {
 		return "foo", 100 * 1000, 10, nil
 	}
+	if payreq == "highfee" {
+		return "foo", 1000 * 1000, 10, nil
+	}
 	return "foo", 100000 * 1000, 10, nil
 }
 
@@ -351,6 +427,24 @@ type dummyPolicy struct {
 
 	newSwapsAllowedCalled int
 	newSwapsAllowedReturn bool
+
+	reserveOnchainMsatReturn uint64
+
+	maxFeeInvoiceMultipleReturn float64
+	maxFeeInvoiceSatReturn      uint64
+
+	// maxPendingIncomingSwapsReturn defaults to 3 if unset.
+	maxPendingIncomingSwapsReturn uint64
+
+	// externalFundingTimeoutSecReturn defaults to 540 if unset.
+	externalFundingTimeoutSecReturn uint64
+
+	addedSuspiciousPeers []string
+}
+
+func (d *dummyPolicy) AddToSuspiciousPeerList(peer string) error {
+	d.addedSuspiciousPeers = append(d.addedSuspiciousPeers, peer)
+	return nil
 }
 
 func (d *dummyPolicy) NewSwapsAllowed() bool {
@@ -359,7 +453,7 @@ func (d *dummyPolicy) NewSwapsAllowed() bool {
 }
 
 func (d *dummyPolicy) GetReserveOnchainMsat() uint64 {
-	return 1
+	return d.reserveOnchainMsatReturn
 }
 
 func (d *dummyPolicy) GetMinSwapAmountMsat() uint64 {
@@ -367,6 +461,31 @@ func (d *dummyPolicy) GetMinSwapAmountMsat() uint64 {
 	return d.getMinSwapAmountMsatReturn
 }
 
+func (d *dummyPolicy) GetMaxFeeInvoiceMultiple() float64 {
+	if d.maxFeeInvoiceMultipleReturn == 0 {
+		return 3
+	}
+	return d.maxFeeInvoiceMultipleReturn
+}
+
+func (d *dummyPolicy) GetMaxFeeInvoiceSat() uint64 {
+	return d.maxFeeInvoiceSatReturn
+}
+
+func (d *dummyPolicy) GetExternalFundingTimeoutSec() uint64 {
+	if d.externalFundingTimeoutSecReturn == 0 {
+		return 540
+	}
+	return d.externalFundingTimeoutSecReturn
+}
+
+func (d *dummyPolicy) GetMaxPendingIncomingSwaps() uint64 {
+	if d.maxPendingIncomingSwapsReturn == 0 {
+		return 3
+	}
+	return d.maxPendingIncomingSwapsReturn
+}
+
 func (d *dummyPolicy) IsPeerAllowed(peer string) bool {
 	return true
 }
@@ -386,9 +505,30 @@ func (d *dummyPolicy) ShouldPayFee(swapAmount, feeAmount uint64, peerId, channel
 
 type dummyChain struct {
 	txConfirmedFunc func(swapId string, txHex string) error
+	txReorgedFunc   func(swapId string) error
+	txSpentFunc     func(swapId string, txHex string) error
 	csvPassedFunc   func(swapId string) error
 	balance         uint64
 
+	// claimAddress is the claim address of the last preimage spending tx.
+	claimAddress string
+
+	// spendingPreimage is the preimage revealed by a spending tx.
+	spendingPreimage string
+
+	// broadcastedTx is the last tx passed to BroadcastTx, broadcastTxErr
+	// fails the broadcast.
+	broadcastedTx  string
+	broadcastTxErr error
+
+	// batchOpenings counts the batched opening txs, batchOpeningErr fails
+	// them. batchOpeningSize is the number of swaps of the last batch.
+	batchOpenings    int
+	batchOpeningSize int
+	batchOpeningErr  error
+	// releasedBatchTxs are the batch txs whose inputs were released.
+	releasedBatchTxs []string
+
 	calledGetCSVHeight int64
 	returnGetCSVHeight uint32
 }
@@ -425,6 +565,7 @@ func (d *dummyChain) TxIdFromHex(txHex string) (string, error) {
 }
 
 func (d *dummyChain) CreatePreimageSpendingTransaction(swapParams *OpeningParams, claimParams *ClaimParams) (string, string, error) {
+	d.claimAddress = claimParams.ClaimAddress
 	return getRandom32ByteHexString(), "txhex", nil
 }
 
@@ -460,6 +601,26 @@ func (d *dummyChain) CreateOpeningTransaction(swapParams *OpeningParams) (unprep
 	return "txhex", 0, 0, nil
 }
 
+const dummyBatchTxId = "b47c8d7e1f0f4bd3a4f3d6d0a3a0c1b2e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0"
+
+func (d *dummyChain) CreateBatchOpeningTransaction(swapParams []*OpeningParams) (string, string, []uint32, error) {
+	d.batchOpenings++
+	d.batchOpeningSize = len(swapParams)
+	if d.batchOpeningErr != nil {
+		return "", "", nil, d.batchOpeningErr
+	}
+	vouts := make([]uint32, len(swapParams))
+	for i := range swapParams {
+		vouts[i] = uint32(i)
+	}
+	return dummyBatchTxId, "batchtxhex", vouts, nil
+}
+
+func (d *dummyChain) ReleaseBatchOpeningTransaction(txHex string) error {
+	d.releasedBatchTxs = append(d.releasedBatchTxs, txHex)
+	return nil
+}
+
 func (d *dummyChain) AddCsvCallback(f func(swapId string) error) {
 	d.csvPassedFunc = f
 }
@@ -468,14 +629,52 @@ func (d *dummyChain) NewAddress() (string, error) {
 	return "addr", nil
 }
 
+func (d *dummyChain) ValidateAddress(address string) error {
+	if address == "invalid" {
+		return errors.New("invalid address")
+	}
+	return nil
+}
+
 func (d *dummyChain) BroadcastOpeningTx(unpreparedTxHex string) (txId, txHex string, error error) {
-	return getRandom32ByteHexString(), "txhex", nil
+	return getRandom32ByteHexString(), unpreparedTxHex, nil
+}
+
+func (d *dummyChain) CreateOpeningPsbt(swapParams *OpeningParams) (string, error) {
+	return "psbt", nil
+}
+
+func (d *dummyChain) ExtractOpeningTx(swapParams *OpeningParams, signedTx string) (txHex string, vout uint32, err error) {
+	if signedTx == "invalid" {
+		return "", 0, errors.New("tx does not pay to the opening output")
+	}
+	return signedTx, 1, nil
+}
+
+func (d *dummyChain) BroadcastTx(txHex string) (string, error) {
+	d.broadcastedTx = txHex
+	if d.broadcastTxErr != nil {
+		return "", d.broadcastTxErr
+	}
+	return getRandom32ByteHexString(), nil
 }
 
 func (d *dummyChain) AddConfirmationCallback(f func(swapId string, txHex string) error) {
 	d.txConfirmedFunc = f
 }
 
+func (d *dummyChain) AddReorgCallback(f func(swapId string) error) {
+	d.txReorgedFunc = f
+}
+
+func (d *dummyChain) AddSpendCallback(f func(swapId string, txHex string) error) {
+	d.txSpentFunc = f
+}
+
+func (d *dummyChain) GetPreimageFromSpendingTx(openingTxId string, vout uint32, txHex string) (string, string, error) {
+	return getRandom32ByteHexString(), d.spendingPreimage, nil
+}
+
 func (d *dummyChain) ValidateTx(swapParams *OpeningParams, openingTxId string) (bool, error) {
 	return true, nil
 }
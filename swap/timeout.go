@@ -18,8 +18,31 @@ func newTimeOutService(cbf callbackFactory) *timeOutService {
 	return &timeOutService{callbackFactory: cbf}
 }
 
-func (s *timeOutService) addNewTimeOut(ctx context.Context, d time.Duration, id string) {
-	go timer.TimedCallback(ctx, d, s.callbackFactory(id))
+// addNewTimeOut calls back at the deadline. A deadline that already passed
+// fires immediately.
+func (s *timeOutService) addNewTimeOut(ctx context.Context, deadline time.Time, id string) {
+	go timer.TimedCallback(ctx, time.Until(deadline), s.callbackFactory(id))
+}
+
+// timeoutContext cancels a swap because of a timeout. The swap is only marked
+// as timed out if its current state handles the timeout, a late timeout is
+// rejected by the state machine.
+type timeoutContext struct {
+	fsm *SwapStateMachine
+}
+
+func (t *timeoutContext) Validate(data *SwapData) error {
+	return nil
+}
+
+func (t *timeoutContext) ApplyToSwapData(data *SwapData) error {
+	// Called by SendEvent, the fsm is locked.
+	if _, ok := t.fsm.States[t.fsm.Current].Events[Event_OnTimeout]; !ok {
+		return nil
+	}
+	data.CancelMessage = "swap timed out"
+	data.CancelCode = CancelCode_Timeout
+	return nil
 }
 
 type timeOutDummy struct {
@@ -27,7 +50,7 @@ type timeOutDummy struct {
 	called int
 }
 
-func (t *timeOutDummy) addNewTimeOut(ctx context.Context, d time.Duration, id string) {
+func (t *timeOutDummy) addNewTimeOut(ctx context.Context, deadline time.Time, id string) {
 	t.Lock()
 	defer t.Unlock()
 	t.called++
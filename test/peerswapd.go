@@ -7,9 +7,11 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/elementsproject/peerswap/macaroons"
 	"github.com/elementsproject/peerswap/peerswaprpc"
 	"github.com/elementsproject/peerswap/testframework"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type PeerSwapd struct {
@@ -94,7 +96,7 @@ func (p *PeerSwapd) Run(waitForReady bool) error {
 		}
 	}
 
-	psClient, clientConn, err := getPeerswapClient(p.RpcPort)
+	psClient, clientConn, err := getPeerswapClient(p.RpcPort, filepath.Join(p.DataDir, macaroons.AdminMacaroonFile), filepath.Join(p.DataDir, "tls.cert"))
 	if err != nil {
 		return err
 	}
@@ -120,8 +122,18 @@ func (p *PeerSwapd) Kill() {
 	p.DaemonProcess.Kill()
 }
 
-func getPeerswapClient(rpcPort int) (peerswaprpc.PeerSwapClient, *grpc.ClientConn, error) {
-	conn, err := getClientConn(fmt.Sprintf("localhost:%v", rpcPort))
+func getPeerswapClient(rpcPort int, macaroonPath, tlsCertPath string) (peerswaprpc.PeerSwapClient, *grpc.ClientConn, error) {
+	cred, err := macaroons.NewCredentialFromFile(macaroonPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsCreds, err := credentials.NewClientTLSFromFile(tlsCertPath, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := getClientConn(fmt.Sprintf("localhost:%v", rpcPort), tlsCreds, cred)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -131,13 +143,14 @@ func getPeerswapClient(rpcPort int) (peerswaprpc.PeerSwapClient, *grpc.ClientCon
 	return psClient, conn, nil
 }
 
-func getClientConn(address string) (*grpc.ClientConn, error) {
+func getClientConn(address string, tlsCreds credentials.TransportCredentials, cred *macaroons.Credential) (*grpc.ClientConn, error) {
 
 	maxMsgRecvSize := grpc.MaxCallRecvMsgSize(1 * 1024 * 1024 * 200)
 	opts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(maxMsgRecvSize),
-		grpc.WithInsecure(),
+		grpc.WithTransportCredentials(tlsCreds),
 		grpc.WithBlock(),
+		grpc.WithPerRPCCredentials(cred),
 	}
 
 	conn, err := grpc.Dial(address, opts...)
@@ -1,12 +1,57 @@
 package txwatcher
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/elementsproject/glightning/gbitcoin"
 	"github.com/elementsproject/glightning/gelements"
+	"github.com/elementsproject/glightning/jrpc2"
+	"github.com/vulpemventures/go-elements/block"
+	"github.com/vulpemventures/go-elements/elementsutil"
+	"github.com/vulpemventures/go-elements/transaction"
 )
 
+// OutPoint is an output of a tx.
+type OutPoint struct {
+	TxId string
+	Vout uint32
+}
+
+// SpendFinder looks up the tx that spends an output. It is implemented by
+// the blockchain rpcs that can search the chain for spends.
+type SpendFinder interface {
+	// FindSpendingTx returns the raw tx that spends `txid:vout` in the
+	// blocks from `fromHeight` to `toHeight`, "" if the output is not spent
+	// in these blocks.
+	FindSpendingTx(txid string, vout uint32, fromHeight, toHeight uint32) (string, error)
+}
+
+// MempoolSpendFinder looks up the unconfirmed tx that spends an output. It
+// is implemented by the blockchain rpcs that can search the mempool.
+type MempoolSpendFinder interface {
+	// FindMempoolSpendingTx returns the raw tx in the mempool that spends
+	// `txid:vout`, "" if there is none.
+	FindMempoolSpendingTx(txid string, vout uint32) (string, error)
+}
+
+// RawRpc sends json rpc requests that are not covered by the node clients.
+// It is implemented by gbitcoin.Bitcoin, that can talk to elementsd as well.
+type RawRpc interface {
+	Request(m jrpc2.Method, resp interface{}) error
+	GetRawtransaction(txId string) (string, error)
+}
+
 type ElementsBlockChainRpc struct {
 	ecli *gelements.Elements
+
+	// rawRpc looks up the mempool txs, gelements can not list the mempool.
+	rawRpc  RawRpc
+	mempool *mempoolIndex
 }
 
 func (e *ElementsBlockChainRpc) GetBlockHeightByHash(blockhash string) (uint32, error) {
@@ -18,7 +63,13 @@ func (e *ElementsBlockChainRpc) GetBlockHeightByHash(blockhash string) (uint32,
 }
 
 func NewElementsCli(ecli *gelements.Elements) *ElementsBlockChainRpc {
-	return &ElementsBlockChainRpc{ecli: ecli}
+	return &ElementsBlockChainRpc{ecli: ecli, mempool: newMempoolIndex()}
+}
+
+// EnableMempoolLookups lets the txwatcher find spends of swap outputs in the
+// mempool of elementsd via rawRpc.
+func (e *ElementsBlockChainRpc) EnableMempoolLookups(rawRpc RawRpc) {
+	e.rawRpc = rawRpc
 }
 
 func (e *ElementsBlockChainRpc) GetBlockHeight() (uint64, error) {
@@ -52,12 +103,96 @@ func (e *ElementsBlockChainRpc) GetRawtransactionWithBlockHash(txId string, bloc
 	return e.ecli.GetRawtransactionWithBlockHash(txId, blockHash)
 }
 
+// GetTxBlock looks up the tx in the blocks from `fromHeight` to `toHeight`.
+func (e *ElementsBlockChainRpc) GetTxBlock(txid string, fromHeight, toHeight uint32) (uint32, string, error) {
+	return findTxBlock(e, txid, fromHeight, toHeight)
+}
+
+// DecodeBlock decodes a raw elements block as published by zmq.
+func (e *ElementsBlockChainRpc) DecodeBlock(rawBlock []byte) (string, []string, error) {
+	b, err := block.NewFromBuffer(bytes.NewBuffer(rawBlock))
+	if err != nil {
+		return "", nil, err
+	}
+	blockHash, err := b.Header.Hash()
+	if err != nil {
+		return "", nil, err
+	}
+	var txIds []string
+	if b.TransactionsData != nil {
+		for _, tx := range b.TransactionsData.Transactions {
+			txIds = append(txIds, tx.TxHash().String())
+		}
+	}
+	return blockHash.String(), txIds, nil
+}
+
+// DecodeTx decodes a raw elements transaction as published by zmq.
+func (e *ElementsBlockChainRpc) DecodeTx(rawTx []byte) (string, []OutPoint, error) {
+	tx, err := transaction.NewTxFromBuffer(bytes.NewBuffer(rawTx))
+	if err != nil {
+		return "", nil, err
+	}
+	var spends []OutPoint
+	for _, in := range tx.Inputs {
+		spends = append(spends, OutPoint{TxId: elementsutil.TxIDFromBytes(in.Hash), Vout: in.Index})
+	}
+	return tx.TxHash().String(), spends, nil
+}
+
+// FindSpendingTx scans the raw blocks from `fromHeight` to `toHeight` for a
+// tx that spends `txid:vout`.
+func (e *ElementsBlockChainRpc) FindSpendingTx(txid string, vout uint32, fromHeight, toHeight uint32) (string, error) {
+	txHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return "", err
+	}
+	for height := fromHeight; height <= toHeight; height++ {
+		blockHash, err := e.ecli.GetBlockHash(height)
+		if err != nil {
+			return "", err
+		}
+		rawBlock, err := e.ecli.GetRawBlock(blockHash)
+		if err != nil {
+			return "", err
+		}
+		b, err := block.NewFromHex(rawBlock)
+		if err != nil {
+			return "", err
+		}
+		if b.TransactionsData == nil {
+			continue
+		}
+		for _, tx := range b.TransactionsData.Transactions {
+			for _, in := range tx.Inputs {
+				if bytes.Equal(in.Hash, txHash[:]) && in.Index == vout {
+					return tx.ToHex()
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// FindMempoolSpendingTx looks up the mempool tx that spends `txid:vout`.
+// Mempool lookups must be enabled, otherwise no tx is found.
+func (e *ElementsBlockChainRpc) FindMempoolSpendingTx(txid string, vout uint32) (string, error) {
+	if e.rawRpc == nil {
+		return "", nil
+	}
+	return e.mempool.findSpendingTx(e.rawRpc, e.DecodeTx, txid, vout)
+}
+
 type BitcoinBlockchainRpc struct {
-	bcli *gbitcoin.Bitcoin
+	bcli    *gbitcoin.Bitcoin
+	mempool *mempoolIndex
 }
 
 func NewBitcoinRpc(bcli *gbitcoin.Bitcoin) *BitcoinBlockchainRpc {
-	return &BitcoinBlockchainRpc{bcli: bcli}
+	return &BitcoinBlockchainRpc{
+		bcli:    bcli,
+		mempool: newMempoolIndex(),
+	}
 }
 
 func (b *BitcoinBlockchainRpc) GetBlockHeight() (uint64, error) {
@@ -97,3 +232,238 @@ func (b *BitcoinBlockchainRpc) GetBlockHash(height uint32) (string, error) {
 func (b *BitcoinBlockchainRpc) GetRawtransactionWithBlockHash(txId string, blockHash string) (string, error) {
 	return b.bcli.GetRawtransactionWithBlockHash(txId, blockHash)
 }
+
+// GetTxBlock looks up the tx in the blocks from `fromHeight` to `toHeight`.
+func (b *BitcoinBlockchainRpc) GetTxBlock(txid string, fromHeight, toHeight uint32) (uint32, string, error) {
+	return findTxBlock(b, txid, fromHeight, toHeight)
+}
+
+// DecodeBlock decodes a raw bitcoin block as published by zmq.
+func (b *BitcoinBlockchainRpc) DecodeBlock(rawBlock []byte) (string, []string, error) {
+	msgBlock := &wire.MsgBlock{}
+	err := msgBlock.Deserialize(bytes.NewReader(rawBlock))
+	if err != nil {
+		return "", nil, err
+	}
+	var txIds []string
+	for _, tx := range msgBlock.Transactions {
+		txIds = append(txIds, tx.TxHash().String())
+	}
+	return msgBlock.BlockHash().String(), txIds, nil
+}
+
+// DecodeTx decodes a raw bitcoin transaction as published by zmq.
+func (b *BitcoinBlockchainRpc) DecodeTx(rawTx []byte) (string, []OutPoint, error) {
+	tx := &wire.MsgTx{}
+	err := tx.Deserialize(bytes.NewReader(rawTx))
+	if err != nil {
+		return "", nil, err
+	}
+	var spends []OutPoint
+	for _, in := range tx.TxIn {
+		spends = append(spends, OutPoint{TxId: in.PreviousOutPoint.Hash.String(), Vout: in.PreviousOutPoint.Index})
+	}
+	return tx.TxHash().String(), spends, nil
+}
+
+// FindSpendingTx scans the raw blocks from `fromHeight` to `toHeight` for a
+// tx that spends `txid:vout`.
+func (b *BitcoinBlockchainRpc) FindSpendingTx(txid string, vout uint32, fromHeight, toHeight uint32) (string, error) {
+	txHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return "", err
+	}
+	for height := fromHeight; height <= toHeight; height++ {
+		blockHash, err := b.bcli.GetBlockHash(height)
+		if err != nil {
+			return "", err
+		}
+		rawBlock, err := b.bcli.GetRawBlock(blockHash)
+		if err != nil {
+			return "", err
+		}
+		blockBytes, err := hex.DecodeString(rawBlock)
+		if err != nil {
+			return "", err
+		}
+		msgBlock := &wire.MsgBlock{}
+		err = msgBlock.Deserialize(bytes.NewReader(blockBytes))
+		if err != nil {
+			return "", err
+		}
+		for _, tx := range msgBlock.Transactions {
+			for _, in := range tx.TxIn {
+				if in.PreviousOutPoint.Hash == *txHash && in.PreviousOutPoint.Index == vout {
+					var buf bytes.Buffer
+					err = tx.Serialize(&buf)
+					if err != nil {
+						return "", err
+					}
+					return hex.EncodeToString(buf.Bytes()), nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+type getRawMempoolRequest struct{}
+
+func (r *getRawMempoolRequest) Name() string {
+	return "getrawmempool"
+}
+
+type getTxSpendingPrevoutRequest struct {
+	Outputs []txSpendingPrevout `json:"outputs"`
+}
+
+func (r *getTxSpendingPrevoutRequest) Name() string {
+	return "gettxspendingprevout"
+}
+
+type txSpendingPrevout struct {
+	TxId         string `json:"txid"`
+	Vout         uint32 `json:"vout"`
+	SpendingTxId string `json:"spendingtxid,omitempty"`
+}
+
+// rpcMethodNotFound is the json rpc error code of an unknown method.
+const rpcMethodNotFound = -32601
+
+// rpcInvalidAddressOrKey is the json rpc error code of getrawtransaction if
+// the tx is not in the given block.
+const rpcInvalidAddressOrKey = -5
+
+// blockTxRpc fetches a tx from a given block.
+type blockTxRpc interface {
+	GetBlockHash(height uint32) (string, error)
+	GetRawtransactionWithBlockHash(txId string, blockHash string) (string, error)
+}
+
+// findTxBlock asks the node for the tx in each block from `fromHeight` to
+// `toHeight`, so that the tx is found even if its outputs are spent and the
+// node has no tx index.
+func findTxBlock(rpc blockTxRpc, txid string, fromHeight, toHeight uint32) (uint32, string, error) {
+	for height := fromHeight; height <= toHeight; height++ {
+		blockHash, err := rpc.GetBlockHash(height)
+		if err != nil {
+			return 0, "", err
+		}
+		_, err = rpc.GetRawtransactionWithBlockHash(txid, blockHash)
+		var rpcErr *jrpc2.RpcError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpcInvalidAddressOrKey {
+			continue
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		return height, blockHash, nil
+	}
+	return 0, "", nil
+}
+
+// FindMempoolSpendingTx looks up the mempool tx that spends `txid:vout`.
+func (b *BitcoinBlockchainRpc) FindMempoolSpendingTx(txid string, vout uint32) (string, error) {
+	return b.mempool.findSpendingTx(b.bcli, b.DecodeTx, txid, vout)
+}
+
+// mempoolIndex indexes the outputs that are spent by the mempool txs that
+// were already fetched, so that only new mempool txs are fetched on a lookup.
+// The index is only used if the node does not support gettxspendingprevout.
+type mempoolIndex struct {
+	sync.Mutex
+	spends map[OutPoint]string
+	txs    map[string][]OutPoint
+	// noSpendingPrevout is set once the node rejected gettxspendingprevout.
+	noSpendingPrevout bool
+}
+
+func newMempoolIndex() *mempoolIndex {
+	return &mempoolIndex{
+		spends: make(map[OutPoint]string),
+		txs:    make(map[string][]OutPoint),
+	}
+}
+
+// findSpendingTx returns the raw mempool tx that spends `txid:vout`, "" if
+// there is none. The spending tx is looked up with gettxspendingprevout. If
+// the node does not support it, the mempool txs are fetched via
+// getrawmempool and getrawtransaction and decoded with decodeTx.
+func (m *mempoolIndex) findSpendingTx(rpc RawRpc, decodeTx func(rawTx []byte) (string, []OutPoint, error), txid string, vout uint32) (string, error) {
+	m.Lock()
+	noSpendingPrevout := m.noSpendingPrevout
+	m.Unlock()
+	if !noSpendingPrevout {
+		var res []txSpendingPrevout
+		err := rpc.Request(&getTxSpendingPrevoutRequest{
+			Outputs: []txSpendingPrevout{{TxId: txid, Vout: vout}},
+		}, &res)
+		var rpcErr *jrpc2.RpcError
+		switch {
+		case errors.As(err, &rpcErr) && rpcErr.Code == rpcMethodNotFound:
+			m.Lock()
+			m.noSpendingPrevout = true
+			m.Unlock()
+		case err != nil:
+			return "", err
+		default:
+			if len(res) == 0 || res[0].SpendingTxId == "" {
+				return "", nil
+			}
+			return rpc.GetRawtransaction(res[0].SpendingTxId)
+		}
+	}
+
+	var txIds []string
+	err := rpc.Request(&getRawMempoolRequest{}, &txIds)
+	if err != nil {
+		return "", err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	inMempool := make(map[string]struct{}, len(txIds))
+	for _, txId := range txIds {
+		inMempool[txId] = struct{}{}
+		if _, ok := m.txs[txId]; ok {
+			continue
+		}
+		rawTx, err := rpc.GetRawtransaction(txId)
+		if err != nil {
+			// The tx might have left the mempool in the meantime.
+			continue
+		}
+		txBytes, err := hex.DecodeString(rawTx)
+		if err != nil {
+			return "", err
+		}
+		_, spends, err := decodeTx(txBytes)
+		if err != nil {
+			return "", err
+		}
+		m.txs[txId] = spends
+		for _, o := range spends {
+			m.spends[o] = txId
+		}
+	}
+
+	// Forget the txs that left the mempool.
+	for txId, spends := range m.txs {
+		if _, ok := inMempool[txId]; ok {
+			continue
+		}
+		for _, o := range spends {
+			if m.spends[o] == txId {
+				delete(m.spends, o)
+			}
+		}
+		delete(m.txs, txId)
+	}
+
+	spendingTxId, ok := m.spends[OutPoint{TxId: txid, Vout: vout}]
+	if !ok {
+		return "", nil
+	}
+	return rpc.GetRawtransaction(spendingTxId)
+}
@@ -0,0 +1,144 @@
+package txwatcher
+
+import (
+	"errors"
+
+	"github.com/elementsproject/peerswap/esplora"
+)
+
+// EsploraBlockchainRpc implements BlockchainRpc on top of an esplora http
+// api, e.g. a self-hosted electrs. It can be used instead of a full bitcoind
+// or elementsd node to watch the swap transactions.
+type EsploraBlockchainRpc struct {
+	client *esplora.Client
+	chain  string
+}
+
+// NewEsploraBlockchainRpc returns a BlockchainRpc for `chain` ("btc" or
+// "lbtc") that queries the esplora api of `client`.
+func NewEsploraBlockchainRpc(client *esplora.Client, chain string) *EsploraBlockchainRpc {
+	return &EsploraBlockchainRpc{client: client, chain: chain}
+}
+
+func (e *EsploraBlockchainRpc) String() string {
+	return e.chain
+}
+
+func (e *EsploraBlockchainRpc) GetBlockHeight() (uint64, error) {
+	return e.client.GetTipHeight()
+}
+
+// GetTxOut mimics the gettxout rpc call: it returns nil if the output is
+// unknown or already spent. Value is 0 for confidential outputs.
+func (e *EsploraBlockchainRpc) GetTxOut(txid string, vout uint32) (*TxOutResp, error) {
+	tx, err := e.client.GetTx(txid)
+	if errors.Is(err, esplora.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if int(vout) >= len(tx.Vout) {
+		return nil, nil
+	}
+
+	outspend, err := e.client.GetOutspend(txid, vout)
+	if err != nil {
+		return nil, err
+	}
+	if outspend.Spent {
+		return nil, nil
+	}
+
+	// The best block hash and the confirmations are derived from the same
+	// tip height so that TxHexFromId finds the block of the tx.
+	tipHeight, err := e.client.GetTipHeight()
+	if err != nil {
+		return nil, err
+	}
+	bestBlockHash, err := e.client.GetBlockHash(uint32(tipHeight))
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmations uint32
+	if tx.Status.Confirmed && uint64(tx.Status.BlockHeight) <= tipHeight {
+		confirmations = uint32(tipHeight) - tx.Status.BlockHeight + 1
+	}
+
+	var value float64
+	if v := tx.Vout[vout].Value; v != nil {
+		value = float64(*v) / 1e8
+	}
+
+	return &TxOutResp{
+		BestBlockHash: bestBlockHash,
+		Confirmations: confirmations,
+		Value:         value,
+	}, nil
+}
+
+func (e *EsploraBlockchainRpc) GetBlockHash(height uint32) (string, error) {
+	return e.client.GetBlockHash(height)
+}
+
+// GetRawtransactionWithBlockHash returns the raw tx. The block hash is not
+// needed as esplora indexes all transactions.
+func (e *EsploraBlockchainRpc) GetRawtransactionWithBlockHash(txId string, blockHash string) (string, error) {
+	return e.client.GetTxHex(txId)
+}
+
+func (e *EsploraBlockchainRpc) GetBlockHeightByHash(blockhash string) (uint32, error) {
+	b, err := e.client.GetBlock(blockhash)
+	if err != nil {
+		return 0, err
+	}
+	return b.Height, nil
+}
+
+// GetTxBlock returns the block of the tx if it is confirmed in the blocks
+// from `fromHeight` to `toHeight`. Esplora only reports the blocks of the best
+// chain.
+func (e *EsploraBlockchainRpc) GetTxBlock(txid string, fromHeight, toHeight uint32) (uint32, string, error) {
+	tx, err := e.client.GetTx(txid)
+	if errors.Is(err, esplora.ErrNotFound) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	if !tx.Status.Confirmed || tx.Status.BlockHeight < fromHeight || tx.Status.BlockHeight > toHeight {
+		return 0, "", nil
+	}
+	return tx.Status.BlockHeight, tx.Status.BlockHash, nil
+}
+
+// FindSpendingTx returns the raw tx that spends `txid:vout` if the spending
+// tx is confirmed up to `toHeight`. There is no need to scan the blocks as
+// esplora indexes the spends, so `fromHeight` is ignored.
+func (e *EsploraBlockchainRpc) FindSpendingTx(txid string, vout uint32, _, toHeight uint32) (string, error) {
+	outspend, err := e.client.GetOutspend(txid, vout)
+	if err != nil {
+		return "", err
+	}
+	if !outspend.Spent || !outspend.Status.Confirmed {
+		return "", nil
+	}
+	if outspend.Status.BlockHeight > toHeight {
+		return "", nil
+	}
+	return e.client.GetTxHex(outspend.TxId)
+}
+
+// FindMempoolSpendingTx returns the raw tx that spends `txid:vout` if the
+// spending tx is not confirmed yet.
+func (e *EsploraBlockchainRpc) FindMempoolSpendingTx(txid string, vout uint32) (string, error) {
+	outspend, err := e.client.GetOutspend(txid, vout)
+	if err != nil {
+		return "", err
+	}
+	if !outspend.Spent || outspend.Status.Confirmed {
+		return "", nil
+	}
+	return e.client.GetTxHex(outspend.TxId)
+}
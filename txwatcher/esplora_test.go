@@ -0,0 +1,134 @@
+package txwatcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/elementsproject/peerswap/esplora"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// esploraStub serves a minimal esplora api with a single tx.
+type esploraStub struct {
+	sync.Mutex
+	tipHeight string
+	spent     bool
+}
+
+func (s *esploraStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	switch r.URL.Path {
+	case "/blocks/tip/height":
+		w.Write([]byte(s.tipHeight))
+	case "/block-height/" + s.tipHeight:
+		w.Write([]byte("tiphash"))
+	case "/block/tiphash":
+		w.Write([]byte(`{"id":"tiphash","height":` + s.tipHeight + `}`))
+	case "/block-height/100":
+		w.Write([]byte("blockhash100"))
+	case "/tx/txid":
+		w.Write([]byte(`{"txid":"txid","vout":[{"value":100000}],"status":{"confirmed":true,"block_height":100,"block_hash":"blockhash100"}}`))
+	case "/tx/txid/outspend/0":
+		if s.spent {
+			w.Write([]byte(`{"spent":true,"txid":"spender","status":{"confirmed":true,"block_height":101}}`))
+			return
+		}
+		w.Write([]byte(`{"spent":false}`))
+	case "/tx/txid/hex":
+		w.Write([]byte("txhex"))
+	case "/tx/spender/hex":
+		w.Write([]byte("spenderhex"))
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (s *esploraStub) setTipHeight(height string) {
+	s.Lock()
+	defer s.Unlock()
+	s.tipHeight = height
+}
+
+func Test_EsploraGetTxOut(t *testing.T) {
+	stub := &esploraStub{tipHeight: "101"}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+	rpc := NewEsploraBlockchainRpc(esplora.NewClient(srv.URL), "btc")
+
+	res, err := rpc.GetTxOut("txid", 0)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.EqualValues(t, 2, res.Confirmations)
+	assert.Equal(t, 0.001, res.Value)
+	assert.Equal(t, "tiphash", res.BestBlockHash)
+
+	// Unknown txs and outputs are nil.
+	res, err = rpc.GetTxOut("unknown", 0)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+	res, err = rpc.GetTxOut("txid", 1)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	// Spent outputs are nil.
+	stub.Lock()
+	stub.spent = true
+	stub.Unlock()
+	res, err = rpc.GetTxOut("txid", 0)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func Test_EsploraTxWatcherConfirmations(t *testing.T) {
+	stub := &esploraStub{tipHeight: "100"}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+	rpc := NewEsploraBlockchainRpc(esplora.NewClient(srv.URL), "btc")
+
+	txWatcher := NewBlockchainRpcTxWatcher(context.Background(), rpc, 3, 100)
+	require.NoError(t, txWatcher.StartWatchingTxs())
+
+	type confirmed struct{ swapId, txHex string }
+	confirmedChan := make(chan confirmed)
+	txWatcher.AddConfirmationCallback(func(swapId string, txHex string) error {
+		go func() { confirmedChan <- confirmed{swapId, txHex} }()
+		return nil
+	})
+	txWatcher.AddWaitForConfirmationTx("swap", "txid", 0, 0, nil)
+
+	stub.setTipHeight("102")
+	res := <-confirmedChan
+	assert.Equal(t, "swap", res.swapId)
+	assert.Equal(t, "txhex", res.txHex)
+}
+
+func Test_EsploraFindSpendingTx(t *testing.T) {
+	stub := &esploraStub{tipHeight: "101"}
+	srv := httptest.NewServer(stub)
+	defer srv.Close()
+	rpc := NewEsploraBlockchainRpc(esplora.NewClient(srv.URL), "btc")
+
+	txHex, err := rpc.FindSpendingTx("txid", 0, 100, 101)
+	require.NoError(t, err)
+	assert.Empty(t, txHex)
+
+	stub.Lock()
+	stub.spent = true
+	stub.Unlock()
+	txHex, err = rpc.FindSpendingTx("txid", 0, 100, 100)
+	require.NoError(t, err)
+	assert.Empty(t, txHex)
+	txHex, err = rpc.FindSpendingTx("txid", 0, 100, 101)
+	require.NoError(t, err)
+	assert.Equal(t, "spenderhex", txHex)
+
+	// The spending tx is confirmed, it is not in the mempool.
+	txHex, err = rpc.FindMempoolSpendingTx("txid", 0)
+	require.NoError(t, err)
+	assert.Empty(t, txHex)
+}
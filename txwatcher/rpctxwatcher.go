@@ -2,6 +2,7 @@ package txwatcher
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
@@ -15,6 +16,10 @@ type BlockchainRpc interface {
 	GetBlockHash(height uint32) (string, error)
 	GetRawtransactionWithBlockHash(txId string, blockHash string) (string, error)
 	GetBlockHeightByHash(blockhash string) (uint32, error)
+	// GetTxBlock returns the height and hash of the block of the best chain
+	// that includes the tx, searching the blocks from `fromHeight` to
+	// `toHeight`. It returns 0 and "" if the tx is not in these blocks.
+	GetTxBlock(txid string, fromHeight, toHeight uint32) (uint32, string, error)
 }
 
 type TxOutResp struct {
@@ -29,23 +34,61 @@ type SwapTxInfo struct {
 	TxVout              uint32
 	StartingBlockHeight uint32
 	Csv                 uint32
+	// BlockHeight is the height of the block that includes the tx, 0 if
+	// the tx is not known to be mined yet.
+	BlockHeight uint32
+	// BlockHash is the hash of the block that includes the tx. It is only
+	// set once the tx is confirmed.
+	BlockHash string
+
+	addedAt time.Time
+	// nextSpendHeight is the next block that is scanned for a tx that
+	// spends the output.
+	nextSpendHeight uint32
+	// spendingTx is the raw tx that spends the output, once it was found.
+	spendingTx string
+	// outputSeen is set once the output was found in the utxo set, so that a
+	// missing output means that it was spent.
+	outputSeen bool
 }
 
-// todo zmq notifications
+// minedAfter returns true if the tx is known to be mined but has less than
+// `confs` confirmations at `blockheight`.
+func (i *SwapTxInfo) minedAfter(blockheight uint64, confs uint32) bool {
+	return i.BlockHeight != 0 && uint64(i.BlockHeight)+uint64(confs) > blockheight+1
+}
 
-// BlockchainRpcTxWatcher handles notifications of confirmed and csv-passed events
+// BlockchainRpcTxWatcher handles notifications of confirmed, csv-passed and
+// spent events. New blocks are polled from the blockchain rpc or, if enabled,
+// received via zmq.
 type BlockchainRpcTxWatcher struct {
 	blockchain BlockchainRpc
 
 	txCallback        func(swapId string, txHex string) error
 	csvPassedCallback func(swapId string) error
+	reorgCallback     func(swapId string) error
+	spendCallback     func(swapId string, txHex string) error
 
 	txWatchList    map[string]*SwapTxInfo
 	csvtxWatchList map[string]*SwapTxInfo
-	newBlockChan   chan uint64
+	// confirmedWatchList holds the confirmed txs until they passed the csv
+	// limit in order to detect reorgs.
+	confirmedWatchList map[string]*SwapTxInfo
+	newBlockChan       chan uint64
 
 	requiredConfs uint32
 	csv           uint32
+	// mempoolPollInterval is the interval in which the mempool is checked
+	// for spends of the swap outputs between blocks.
+	mempoolPollInterval time.Duration
+
+	// zmq is nil if zmq notifications are disabled.
+	zmq          *zmqSubscriber
+	currentBlock uint64
+	blockMu      sync.Mutex
+	// spendMu serializes the spend lookups of new blocks and of the mempool
+	// polls, so that a spend is passed to the callback only once.
+	spendMu sync.Mutex
 
 	ctx context.Context
 	sync.Mutex
@@ -65,13 +108,15 @@ func (s *BlockchainRpcTxWatcher) GetBlockHeight() (uint32, error) {
 
 func NewBlockchainRpcTxWatcher(ctx context.Context, blockchain BlockchainRpc, requiredConfs uint32, csv uint32) *BlockchainRpcTxWatcher {
 	return &BlockchainRpcTxWatcher{
-		ctx:            ctx,
-		csv:            csv,
-		blockchain:     blockchain,
-		txWatchList:    make(map[string]*SwapTxInfo),
-		csvtxWatchList: make(map[string]*SwapTxInfo),
-		newBlockChan:   make(chan uint64),
-		requiredConfs:  requiredConfs,
+		ctx:                 ctx,
+		csv:                 csv,
+		blockchain:          blockchain,
+		txWatchList:         make(map[string]*SwapTxInfo),
+		csvtxWatchList:      make(map[string]*SwapTxInfo),
+		confirmedWatchList:  make(map[string]*SwapTxInfo),
+		newBlockChan:        make(chan uint64),
+		requiredConfs:       requiredConfs,
+		mempoolPollInterval: defaultMempoolPollInterval,
 	}
 }
 
@@ -86,6 +131,13 @@ func (s *BlockchainRpcTxWatcher) StartWatchingTxs() error {
 		return err
 	}
 
+	if s.zmq != nil {
+		err = s.startZmq()
+		if err != nil {
+			return err
+		}
+	}
+
 	go s.StartBlockWatcher(currentBlock)
 	go func() error {
 		for {
@@ -93,6 +145,7 @@ func (s *BlockchainRpcTxWatcher) StartWatchingTxs() error {
 			case <-s.ctx.Done():
 				return nil
 			case nb := <-s.newBlockChan:
+				s.HandleReorgs(nb)
 				// This is a blocking action so we need to spawn it in a separate go routine if we do not want to take
 				// risk of deadlocks.
 				// Todo: How to care about errors?
@@ -107,6 +160,7 @@ func (s *BlockchainRpcTxWatcher) StartWatchingTxs() error {
 				if err != nil {
 					return err
 				}
+				s.HandleSpentTx(nb)
 			default:
 				time.Sleep(100 * time.Millisecond)
 			}
@@ -115,35 +169,87 @@ func (s *BlockchainRpcTxWatcher) StartWatchingTxs() error {
 	return nil
 }
 
-// StartBlockWatcher starts listening for new blocks
+// defaultMempoolPollInterval is the default interval in which the mempool is
+// checked for spends of the swap outputs between blocks.
+const defaultMempoolPollInterval = time.Minute
+
+// StartBlockWatcher starts listening for new blocks. The block height is
+// polled every second. While zmq notifications are healthy it is only polled
+// every zmqSafetyPollInterval. Between blocks the mempool is checked for
+// spends of the swap outputs every mempoolPollInterval, unless healthy zmq
+// rawtx notifications report them.
 func (s *BlockchainRpcTxWatcher) StartBlockWatcher(currentBlock uint64) error {
+	s.blockMu.Lock()
+	s.currentBlock = currentBlock
+	s.blockMu.Unlock()
+
 	ticker := time.NewTicker(1000 * time.Millisecond)
 	defer ticker.Stop()
 
+	var lastPoll, lastMempoolPoll time.Time
 	for {
 		select {
 		case <-s.ctx.Done():
+			return nil
 		case <-ticker.C:
+			if (!s.zmq.healthy() || !s.zmq.tracksMempool()) && time.Since(lastMempoolPoll) >= s.mempoolPollInterval {
+				lastMempoolPoll = time.Now()
+				s.handleSpends(0, false)
+			}
+			if s.zmq.healthy() && time.Since(lastPoll) < zmqSafetyPollInterval {
+				continue
+			}
+			lastPoll = time.Now()
 			nextBlock, err := s.blockchain.GetBlockHeight()
 			if err != nil {
 				return err
 			}
-			if nextBlock > currentBlock {
-				currentBlock = nextBlock
-				s.Lock()
-				s.newBlockChan <- currentBlock
-				s.Unlock()
-			}
+			s.notifyBlock(nextBlock)
 		}
 	}
 }
 
-// HandleConfirmedTx looks for transactions that are confirmed
+// notifyBlock passes the block height to the tx handlers if it is higher
+// than the last known block height. The block lock is released before the
+// height is passed on, as the tx callbacks take it as well.
+func (s *BlockchainRpcTxWatcher) notifyBlock(height uint64) {
+	s.blockMu.Lock()
+	if height <= s.currentBlock {
+		s.blockMu.Unlock()
+		return
+	}
+	s.currentBlock = height
+	s.blockMu.Unlock()
+
+	select {
+	case s.newBlockChan <- height:
+	case <-s.ctx.Done():
+	}
+}
+
+// HandleConfirmedTx looks for transactions that are confirmed. Confirmed txs
+// are moved to the confirmedWatchList before the callback is called, as the
+// callback may block for a while.
 // fixme: why does this function return an error if no error ever is returned?
 func (s *BlockchainRpcTxWatcher) HandleConfirmedTx(blockheight uint64) error {
-	var toRemove []string
+	type confirmedTx struct {
+		swapId string
+		txHex  string
+		info   *SwapTxInfo
+	}
+	var confirmed []confirmedTx
+
 	s.Lock()
+	callback := s.txCallback
 	for k, v := range s.txWatchList {
+		// Skip the rpc call if zmq tells us that the tx can not have
+		// enough confirmations yet.
+		if v.minedAfter(blockheight, s.requiredConfs) {
+			continue
+		}
+		if v.BlockHeight == 0 && s.zmq.tracksMined() && s.zmq.trusts(v.addedAt) {
+			continue
+		}
 		// todo does vout matter here?
 		res, err := s.blockchain.GetTxOut(v.TxId, v.TxVout)
 		if err != nil {
@@ -157,24 +263,104 @@ func (s *BlockchainRpcTxWatcher) HandleConfirmedTx(blockheight uint64) error {
 			log.Debugf("tx does not have enough confirmations")
 			continue
 		}
-		if s.txCallback == nil {
+		if callback == nil {
 			continue
 		}
-		txHex, err := s.TxHexFromId(res, v.TxId)
+		txHex, blockHeight, blockHash, err := s.txFromId(res, v.TxId)
 		if err != nil {
-			return err
+			log.Infof("Watchlist tx from id err: %v", err)
+			continue
 		}
-		err = s.txCallback(k, txHex)
+		v.outputSeen = true
+		v.BlockHeight = blockHeight
+		v.BlockHash = blockHash
+		v.nextSpendHeight = blockHeight
+		delete(s.txWatchList, k)
+		s.confirmedWatchList[k] = v
+		confirmed = append(confirmed, confirmedTx{swapId: k, txHex: txHex, info: v})
+	}
+	s.Unlock()
+
+	for _, c := range confirmed {
+		err := callback(c.swapId, c.txHex)
 		if err != nil {
 			log.Infof("tx callback error %v", err)
+			s.unconfirm(c.swapId, c.info)
+		}
+	}
+	return nil
+}
+
+// unconfirm moves a tx back to the txWatchList if the swap did not add a
+// new tx to watch in the meantime.
+func (s *BlockchainRpcTxWatcher) unconfirm(swapId string, info *SwapTxInfo) {
+	s.Lock()
+	defer s.Unlock()
+	if s.confirmedWatchList[swapId] == info {
+		delete(s.confirmedWatchList, swapId)
+	}
+	if _, ok := s.txWatchList[swapId]; !ok {
+		info.BlockHash = ""
+		s.txWatchList[swapId] = info
+	}
+}
+
+// HandleReorgs checks that the confirmed txs are still part of the best
+// chain. If the block of a confirmed tx was replaced and the tx does not
+// have enough confirmations in the new chain, the reorg callback is called.
+// Confirmed txs are not checked anymore once they passed the csv limit.
+func (s *BlockchainRpcTxWatcher) HandleReorgs(blockheight uint64) {
+	var reorged []string
+
+	s.Lock()
+	callback := s.reorgCallback
+	for k, v := range s.confirmedWatchList {
+		if blockheight >= uint64(v.BlockHeight)+uint64(v.Csv) {
+			delete(s.confirmedWatchList, k)
+			continue
+		}
+		blockHash, err := s.blockchain.GetBlockHash(v.BlockHeight)
+		if err != nil {
+			log.Infof("[TxWatcher] get block hash: %v", err)
+			continue
+		}
+		if blockHash == v.BlockHash {
 			continue
 		}
 
-		toRemove = append(toRemove, k)
+		// The block was replaced, check if the tx made it into the new
+		// chain. The output may be spent already, so the tx is looked up
+		// in the blocks instead of the utxo set.
+		fromHeight := v.BlockHeight
+		if v.StartingBlockHeight != 0 && v.StartingBlockHeight < fromHeight {
+			fromHeight = v.StartingBlockHeight
+		}
+		height, hash, err := s.blockchain.GetTxBlock(v.TxId, fromHeight, uint32(blockheight))
+		if err != nil {
+			log.Infof("[TxWatcher] get tx block: %v", err)
+			continue
+		}
+		if height != 0 && uint64(height)+uint64(s.requiredConfs) <= blockheight+1 {
+			v.BlockHeight, v.BlockHash = height, hash
+			continue
+		}
+
+		log.WithFields(log.Fields{log.SwapIdField: k}).
+			Infof("[TxWatcher] tx %s was reorged out of block %s at height %d", v.TxId, v.BlockHash, v.BlockHeight)
+		delete(s.confirmedWatchList, k)
+		reorged = append(reorged, k)
 	}
 	s.Unlock()
-	s.TxClaimed(toRemove)
-	return nil
+
+	if callback == nil {
+		return
+	}
+	for _, swapId := range reorged {
+		err := callback(swapId)
+		if err != nil {
+			log.Infof("reorg callback error %v", err)
+		}
+	}
 }
 
 // HandleCsvTx looks for transactions that have enough confirmations to be spend using the csv path
@@ -182,6 +368,9 @@ func (s *BlockchainRpcTxWatcher) HandleCsvTx(blockheight uint64) error {
 	var toRemove []string
 	s.Lock()
 	for k, v := range s.csvtxWatchList {
+		if v.minedAfter(blockheight, v.Csv) {
+			continue
+		}
 		res, err := s.blockchain.GetTxOut(v.TxId, v.TxVout)
 		if err != nil {
 			log.Infof("watchlist fetchtx err: %v", err)
@@ -190,6 +379,7 @@ func (s *BlockchainRpcTxWatcher) HandleCsvTx(blockheight uint64) error {
 		if res == nil {
 			continue
 		}
+		v.outputSeen = true
 		if v.Csv > res.Confirmations {
 			continue
 		}
@@ -208,68 +398,255 @@ func (s *BlockchainRpcTxWatcher) HandleCsvTx(blockheight uint64) error {
 	return nil
 }
 
+// HandleSpentTx looks for the txs that spend the outputs of the swaps that
+// wait for the csv limit or for the claim of a confirmed tx. The blocks up to
+// `blockheight` are only scanned once, the spending tx is kept until the
+// spend callback succeeded. If the output is spent by a tx that is not
+// mined yet, the spend callback is called with the tx from the mempool.
+func (s *BlockchainRpcTxWatcher) HandleSpentTx(blockheight uint64) {
+	s.handleSpends(blockheight, true)
+}
+
+// handleSpends looks for the txs that spend the watched outputs in the
+// mempool and, if `scanBlocks` is set, in the blocks up to `blockheight`.
+// The mempool is only searched for outputs that were seen in the utxo set,
+// as a missing output might as well not have reached our node yet.
+func (s *BlockchainRpcTxWatcher) handleSpends(blockheight uint64, scanBlocks bool) {
+	finder, findsBlockSpends := s.blockchain.(SpendFinder)
+	findsBlockSpends = findsBlockSpends && scanBlocks
+	mempoolFinder, findsMempoolSpends := s.blockchain.(MempoolSpendFinder)
+	if !findsBlockSpends && !findsMempoolSpends {
+		return
+	}
+	s.spendMu.Lock()
+	defer s.spendMu.Unlock()
+
+	type spentOutput struct {
+		swapId string
+		info   *SwapTxInfo
+		txId   string
+		vout   uint32
+		from   uint32
+		txHex  string
+		seen   bool
+	}
+	var outputs []spentOutput
+	s.Lock()
+	callback := s.spendCallback
+	if callback == nil {
+		s.Unlock()
+		return
+	}
+	for _, list := range []map[string]*SwapTxInfo{s.csvtxWatchList, s.confirmedWatchList} {
+		for k, v := range list {
+			if v.spendingTx == "" {
+				res, err := s.blockchain.GetTxOut(v.TxId, v.TxVout)
+				if err != nil {
+					log.Infof("watchlist fetchtx err: %v", err)
+					continue
+				}
+				if res != nil {
+					v.outputSeen = true
+					continue
+				}
+			}
+			outputs = append(outputs, spentOutput{
+				swapId: k,
+				info:   v,
+				txId:   v.TxId,
+				vout:   v.TxVout,
+				from:   v.nextSpendHeight,
+				txHex:  v.spendingTx,
+				seen:   v.outputSeen,
+			})
+		}
+	}
+	s.Unlock()
+
+	var toRemove []string
+	for _, o := range outputs {
+		if o.txHex == "" && findsBlockSpends && o.from <= uint32(blockheight) {
+			txHex, err := finder.FindSpendingTx(o.txId, o.vout, o.from, uint32(blockheight))
+			if err != nil {
+				log.Infof("[TxWatcher] find spending tx: %v", err)
+				continue
+			}
+			s.Lock()
+			if txHex == "" {
+				o.info.nextSpendHeight = uint32(blockheight) + 1
+			}
+			o.info.spendingTx = txHex
+			s.Unlock()
+			o.txHex = txHex
+		}
+		if o.txHex == "" && findsMempoolSpends && o.seen {
+			txHex, err := mempoolFinder.FindMempoolSpendingTx(o.txId, o.vout)
+			if err != nil {
+				log.Infof("[TxWatcher] find mempool spending tx: %v", err)
+				continue
+			}
+			o.txHex = txHex
+		}
+		if o.txHex == "" {
+			continue
+		}
+
+		log.WithFields(log.Fields{log.SwapIdField: o.swapId}).
+			Infof("[TxWatcher] output %s:%d was spent", o.txId, o.vout)
+		err := callback(o.swapId, o.txHex)
+		if err != nil {
+			log.Infof("spend callback error %v", err)
+			continue
+		}
+		toRemove = append(toRemove, o.swapId)
+	}
+	s.TxClaimed(toRemove)
+}
+
+// handleMempoolTx calls the spend callback if a new mempool tx spends the
+// output of a swap that waits for the csv limit or for the claim of a
+// confirmed tx.
+func (s *BlockchainRpcTxWatcher) handleMempoolTx(rawTx []byte, spends []OutPoint) {
+	var spent []string
+	s.Lock()
+	callback := s.spendCallback
+	for _, list := range []map[string]*SwapTxInfo{s.csvtxWatchList, s.confirmedWatchList} {
+		for k, v := range list {
+			for _, o := range spends {
+				if v.TxId == o.TxId && v.TxVout == o.Vout {
+					spent = append(spent, k)
+				}
+			}
+		}
+	}
+	s.Unlock()
+	if callback == nil || len(spent) == 0 {
+		return
+	}
+
+	// The callback may block for a while, so it must not hold up the zmq
+	// receiver.
+	go func() {
+		txHex := hex.EncodeToString(rawTx)
+		var toRemove []string
+		for _, swapId := range spent {
+			log.WithFields(log.Fields{log.SwapIdField: swapId}).
+				Infof("[TxWatcher] watched output was spent in the mempool")
+			err := callback(swapId, txHex)
+			if err != nil {
+				log.Infof("spend callback error %v", err)
+				continue
+			}
+			toRemove = append(toRemove, swapId)
+		}
+		s.TxClaimed(toRemove)
+	}()
+}
+
 func (l *BlockchainRpcTxWatcher) AddWaitForConfirmationTx(swapId, txId string, vout, startingBlockheight uint32, _ []byte) {
-	hex := l.CheckTxConfirmed(swapId, txId, vout)
+	info := &SwapTxInfo{
+		TxId:                txId,
+		TxVout:              vout,
+		Csv:                 l.csv,
+		StartingBlockHeight: startingBlockheight,
+		addedAt:             time.Now(),
+	}
+
+	hex, blockHeight, blockHash := l.checkTxConfirmed(txId, vout)
 	if hex != "" {
+		info.BlockHeight = blockHeight
+		info.BlockHash = blockHash
+		info.nextSpendHeight = blockHeight
+		l.Lock()
+		l.confirmedWatchList[swapId] = info
+		l.Unlock()
 		go func() {
 			err := l.txCallback(swapId, hex)
 			if err != nil {
 				log.Infof("tx callback error %v", err)
+				l.unconfirm(swapId, info)
 				return
 			}
 		}()
 		return
 	}
+	// With zmq we only look up txs that are known to be mined, so we have
+	// to know if the tx was mined before we started watching it.
+	if l.zmq.tracksMined() {
+		res, err := l.blockchain.GetTxOut(txId, vout)
+		if err == nil && res != nil {
+			info.BlockHeight = l.minedAt(res.Confirmations)
+		}
+	}
+
 	l.Lock()
 	defer l.Unlock()
-	l.txWatchList[swapId] = &SwapTxInfo{
-		TxId:                txId,
-		TxVout:              vout,
-		Csv:                 l.csv,
-		StartingBlockHeight: startingBlockheight,
-	}
+	l.txWatchList[swapId] = info
 }
 
 func (s *BlockchainRpcTxWatcher) CheckTxConfirmed(swapId string, txId string, vout uint32) string {
+	txHex, _, _ := s.checkTxConfirmed(txId, vout)
+	return txHex
+}
+
+// checkTxConfirmed returns the tx hex and the block of the tx if it has the
+// required confirmations.
+func (s *BlockchainRpcTxWatcher) checkTxConfirmed(txId string, vout uint32) (string, uint32, string) {
 	res, err := s.blockchain.GetTxOut(txId, vout)
 	if err != nil {
 		log.Infof("watchlist fetchtx err: %v", err)
-		return ""
+		return "", 0, ""
 	}
 	if res == nil {
-		return ""
+		return "", 0, ""
 	}
 	if !(res.Confirmations >= s.requiredConfs) {
 		log.Infof("tx does not have enough confirmations")
-		return ""
+		return "", 0, ""
 	}
 	if s.txCallback == nil {
-		return ""
+		return "", 0, ""
 	}
-	txHex, err := s.TxHexFromId(res, txId)
+	txHex, blockHeight, blockHash, err := s.txFromId(res, txId)
 	if err != nil {
 		log.Infof("watchlist txfrom hex err: %v", err)
-		return ""
+		return "", 0, ""
 	}
 
-	return txHex
+	return txHex, blockHeight, blockHash
+}
+
+// minedAt estimates the height of the block that includes a tx with `confs`
+// confirmations, 0 if unknown or zmq is disabled. The estimate is one block
+// lower than the actual height as a new block might have arrived since the
+// confirmations were fetched.
+func (l *BlockchainRpcTxWatcher) minedAt(confs uint32) uint32 {
+	if l.zmq == nil {
+		return 0
+	}
+	l.blockMu.Lock()
+	defer l.blockMu.Unlock()
+	if confs == 0 || l.currentBlock <= uint64(confs) {
+		return 0
+	}
+	return uint32(l.currentBlock) - confs
 }
 
-func (l *BlockchainRpcTxWatcher) checkTxAboveCsvHight(txId string, vout uint32) (bool, error) {
+func (l *BlockchainRpcTxWatcher) checkTxAboveCsvHight(txId string, vout uint32) (uint32, bool, error) {
 	res, err := l.blockchain.GetTxOut(txId, vout)
 	if err != nil {
-		return false, err
+		return 0, false, err
 	}
 	if res == nil {
-		return false, fmt.Errorf("empty gettxout response")
+		return 0, false, fmt.Errorf("empty gettxout response")
 	}
-	return res.Confirmations >= l.csv, nil
+	return res.Confirmations, res.Confirmations >= l.csv, nil
 }
 
 func (l *BlockchainRpcTxWatcher) AddWaitForCsvTx(swapId, txId string, vout uint32, startingBlockheight uint32, _ []byte) {
 	// Before we add the tx to the watcher we check if the tx is already
 	// above the csv limit.
-	above, err := l.checkTxAboveCsvHight(txId, vout)
+	confs, above, err := l.checkTxAboveCsvHight(txId, vout)
 	if err != nil {
 		log.Infof("[TxWatcher] checkTxAboveCsvHeight returned: %s", err.Error())
 	}
@@ -282,6 +659,14 @@ func (l *BlockchainRpcTxWatcher) AddWaitForCsvTx(swapId, txId string, vout uint3
 		log.Infof("csv passed callback error: %v", err)
 	}
 
+	// The spending tx can not be in a block before the swap started.
+	nextSpendHeight := startingBlockheight
+	if nextSpendHeight == 0 {
+		l.blockMu.Lock()
+		nextSpendHeight = uint32(l.currentBlock)
+		l.blockMu.Unlock()
+	}
+
 	l.Lock()
 	defer l.Unlock()
 	l.csvtxWatchList[swapId] = &SwapTxInfo{
@@ -289,6 +674,9 @@ func (l *BlockchainRpcTxWatcher) AddWaitForCsvTx(swapId, txId string, vout uint3
 		TxVout:              vout,
 		Csv:                 l.csv,
 		StartingBlockHeight: startingBlockheight,
+		BlockHeight:         l.minedAt(confs),
+		addedAt:             time.Now(),
+		nextSpendHeight:     nextSpendHeight,
 	}
 }
 
@@ -298,6 +686,7 @@ func (l *BlockchainRpcTxWatcher) TxClaimed(swaps []string) {
 	for _, v := range swaps {
 		delete(l.txWatchList, v)
 		delete(l.csvtxWatchList, v)
+		delete(l.confirmedWatchList, v)
 	}
 }
 
@@ -313,20 +702,56 @@ func (l *BlockchainRpcTxWatcher) AddCsvCallback(f func(swapId string) error) {
 	l.csvPassedCallback = f
 }
 
+// AddReorgCallback adds a callback that is called if a confirmed tx is
+// reorged out of the chain.
+func (l *BlockchainRpcTxWatcher) AddReorgCallback(f func(swapId string) error) {
+	l.Lock()
+	defer l.Unlock()
+	l.reorgCallback = f
+}
+
+// AddSpendCallback adds a callback that is called with the spending tx if
+// the output of a tx that waits for the csv limit or of a confirmed tx is
+// spent. Spends are only detected if the blockchain rpc is a SpendFinder or a
+// MempoolSpendFinder, or if zmq rawtx notifications are enabled.
+func (l *BlockchainRpcTxWatcher) AddSpendCallback(f func(swapId string, txHex string) error) {
+	l.Lock()
+	defer l.Unlock()
+	l.spendCallback = f
+}
+
 func (l *BlockchainRpcTxWatcher) TxHexFromId(resp *TxOutResp, txId string) (string, error) {
-	blockheight, err := l.blockchain.GetBlockHeightByHash(resp.BestBlockHash)
+	rawTxHex, _, _, err := l.txFromId(resp, txId)
+	return rawTxHex, err
+}
+
+// txFromId returns the raw tx and the height and hash of the block that
+// includes the tx.
+func (l *BlockchainRpcTxWatcher) txFromId(resp *TxOutResp, txId string) (string, uint32, string, error) {
+	blockheight, blockhash, err := l.txBlock(resp)
 	if err != nil {
-		return "", err
+		return "", 0, "", err
 	}
 
-	blockhash, err := l.blockchain.GetBlockHash(uint32(blockheight) - resp.Confirmations + 1)
+	rawTxHex, err := l.blockchain.GetRawtransactionWithBlockHash(txId, blockhash)
 	if err != nil {
-		return "", err
+		return "", 0, "", err
 	}
+	return rawTxHex, blockheight, blockhash, nil
+}
 
-	rawTxHex, err := l.blockchain.GetRawtransactionWithBlockHash(txId, blockhash)
+// txBlock returns the height and hash of the block that includes the tx of
+// the gettxout response.
+func (l *BlockchainRpcTxWatcher) txBlock(resp *TxOutResp) (uint32, string, error) {
+	bestHeight, err := l.blockchain.GetBlockHeightByHash(resp.BestBlockHash)
+	if err != nil {
+		return 0, "", err
+	}
+
+	blockheight := bestHeight - resp.Confirmations + 1
+	blockhash, err := l.blockchain.GetBlockHash(blockheight)
 	if err != nil {
-		return "", err
+		return 0, "", err
 	}
-	return rawTxHex, nil
+	return blockheight, blockhash, nil
 }
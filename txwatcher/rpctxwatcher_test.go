@@ -2,9 +2,14 @@ package txwatcher
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/elementsproject/glightning/jrpc2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,10 +77,279 @@ func Test_RpcTxWatcherCsv(t *testing.T) {
 	assert.Equal(t, swapId, txConfirmedId)
 }
 
+func Test_RpcTxWatcherReorg(t *testing.T) {
+	swapId := "foo"
+	txId := "bar"
+
+	db := &DummyBlockchain{}
+	confirmedChan := make(chan string)
+	reorgChan := make(chan string)
+
+	txWatcher := NewBlockchainRpcTxWatcher(context.Background(), db, 2, 100)
+	err := txWatcher.StartWatchingTxs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txWatcher.AddConfirmationCallback(func(swapId string, txHex string) error {
+		go func() { confirmedChan <- swapId }()
+		return nil
+	})
+	txWatcher.AddReorgCallback(func(swapId string) error {
+		go func() { reorgChan <- swapId }()
+		return nil
+	})
+	txWatcher.AddWaitForConfirmationTx(swapId, txId, 0, 0, nil)
+
+	db.SetNextTxOutResp(&TxOutResp{
+		Confirmations: 2,
+	})
+	db.SetBlockHeight(1)
+	assert.Equal(t, swapId, <-confirmedChan)
+
+	// A new block on the same chain does not trigger the reorg callback.
+	db.SetBlockHeight(2)
+	time.Sleep(1500 * time.Millisecond)
+	select {
+	case <-reorgChan:
+		t.Fatal("unexpected reorg")
+	default:
+	}
+
+	// The block of the tx is replaced but the tx is mined again. Its output
+	// is spent already, which does not trigger the reorg callback.
+	db.SetBlockHash("otherhash")
+	db.SetNextTxOutResp(nil)
+	db.SetTxBlockHeight(1)
+	db.SetBlockHeight(3)
+	time.Sleep(1500 * time.Millisecond)
+	select {
+	case <-reorgChan:
+		t.Fatal("unexpected reorg")
+	default:
+	}
+
+	// The block of the tx is replaced and the tx is unconfirmed.
+	db.SetBlockHash("thirdhash")
+	db.SetTxBlockHeight(0)
+	db.SetBlockHeight(4)
+	assert.Equal(t, swapId, <-reorgChan)
+}
+
+func Test_RpcTxWatcherSpend(t *testing.T) {
+	swapId := "foo"
+	txid := "bar"
+	db := &spendingBlockchain{
+		DummyBlockchain: &DummyBlockchain{
+			nextBlockheight: 10,
+			nextTxOutResp:   &TxOutResp{Confirmations: 1},
+		},
+		spendHeight: 12,
+	}
+
+	txWatcher := NewBlockchainRpcTxWatcher(context.Background(), db, 2, 100)
+	var spent []string
+	txWatcher.AddSpendCallback(func(swapId string, txHex string) error {
+		spent = append(spent, txHex)
+		return nil
+	})
+	txWatcher.AddWaitForCsvTx(swapId, txid, 0, 10, nil)
+
+	// The output is not spent in a block yet.
+	db.SetNextTxOutResp(nil)
+	txWatcher.HandleSpentTx(11)
+	assert.Empty(t, spent)
+
+	// Blocks are only scanned once.
+	txWatcher.HandleSpentTx(12)
+	assert.Equal(t, []string{"spendinghex"}, spent)
+	assert.Equal(t, [][2]uint32{{10, 11}, {12, 12}}, db.scans)
+
+	// The output is not watched anymore once the spend was handled.
+	txWatcher.HandleSpentTx(13)
+	assert.Len(t, spent, 1)
+}
+
+func Test_RpcTxWatcherMempoolSpend(t *testing.T) {
+	swapId := "foo"
+	txid := "bar"
+	db := &mempoolBlockchain{
+		DummyBlockchain: &DummyBlockchain{
+			nextBlockheight: 10,
+		},
+	}
+
+	txWatcher := NewBlockchainRpcTxWatcher(context.Background(), db, 2, 100)
+	txWatcher.mempoolPollInterval = 0
+	spentChan := make(chan string)
+	txWatcher.AddSpendCallback(func(swapId string, txHex string) error {
+		go func() { spentChan <- txHex }()
+		return nil
+	})
+	txWatcher.AddWaitForCsvTx(swapId, txid, 0, 10, nil)
+
+	// The mempool is not searched for an output that was never seen.
+	txWatcher.handleSpends(0, false)
+	assert.Zero(t, db.getLookups())
+
+	db.SetNextTxOutResp(&TxOutResp{Confirmations: 1})
+	txWatcher.handleSpends(0, false)
+	err := txWatcher.StartWatchingTxs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The spend is found in the mempool without a new block.
+	db.SetNextTxOutResp(nil)
+	db.setMempoolSpend("spendinghex")
+	select {
+	case txHex := <-spentChan:
+		assert.Equal(t, "spendinghex", txHex)
+	case <-time.After(5 * time.Second):
+		t.Fatal("mempool spend was not detected")
+	}
+}
+
+// mempoolBlockchain is a MempoolSpendFinder that finds the spend of any
+// output once it was set.
+type mempoolBlockchain struct {
+	*DummyBlockchain
+	spendingTx string
+	lookups    int
+}
+
+func (m *mempoolBlockchain) setMempoolSpend(txHex string) {
+	m.Lock()
+	defer m.Unlock()
+	m.spendingTx = txHex
+}
+
+func (m *mempoolBlockchain) FindMempoolSpendingTx(txid string, vout uint32) (string, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.lookups++
+	return m.spendingTx, nil
+}
+
+func (m *mempoolBlockchain) getLookups() int {
+	m.RLock()
+	defer m.RUnlock()
+	return m.lookups
+}
+
+func Test_MempoolIndexFindSpendingTx(t *testing.T) {
+	decodeTx := func(rawTx []byte) (string, []OutPoint, error) {
+		return "", []OutPoint{{TxId: "bar", Vout: 1}}, nil
+	}
+
+	// The spend is looked up with gettxspendingprevout.
+	rpc := &mempoolRpc{spendingTxId: "spending"}
+	txHex, err := newMempoolIndex().findSpendingTx(rpc, decodeTx, "bar", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString([]byte("rawspending")), txHex)
+	assert.Equal(t, []string{"gettxspendingprevout"}, rpc.requests)
+
+	// Without gettxspendingprevout the mempool is scanned, and only once the
+	// node rejected it.
+	rpc = &mempoolRpc{noSpendingPrevout: true, mempool: []string{"spending"}}
+	index := newMempoolIndex()
+	for i := 0; i < 2; i++ {
+		txHex, err = index.findSpendingTx(rpc, decodeTx, "bar", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, hex.EncodeToString([]byte("rawspending")), txHex)
+	}
+	assert.Equal(t, []string{"gettxspendingprevout", "getrawmempool", "getrawmempool"}, rpc.requests)
+}
+
+func Test_FindTxBlock(t *testing.T) {
+	rpc := &blockTxStub{txHeight: 12}
+
+	height, hash, err := findTxBlock(rpc, "bar", 10, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12), height)
+	assert.Equal(t, "block12", hash)
+
+	// The tx is not in the blocks.
+	height, hash, err = findTxBlock(rpc, "bar", 13, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0), height)
+	assert.Equal(t, "", hash)
+
+	// Other errors are returned.
+	rpc.err = errors.New("connection refused")
+	_, _, err = findTxBlock(rpc, "bar", 10, 14)
+	assert.Error(t, err)
+}
+
+// blockTxStub is a blockTxRpc that has the tx in the block at txHeight.
+type blockTxStub struct {
+	txHeight uint32
+	err      error
+}
+
+func (b *blockTxStub) GetBlockHash(height uint32) (string, error) {
+	return fmt.Sprintf("block%d", height), nil
+}
+
+func (b *blockTxStub) GetRawtransactionWithBlockHash(txId string, blockHash string) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if blockHash != fmt.Sprintf("block%d", b.txHeight) {
+		return "", &jrpc2.RpcError{Code: rpcInvalidAddressOrKey, Message: "No such transaction found in the provided block"}
+	}
+	return "txhex", nil
+}
+
+// mempoolRpc is a RawRpc with a mempool of the given txs.
+type mempoolRpc struct {
+	noSpendingPrevout bool
+	spendingTxId      string
+	mempool           []string
+	requests          []string
+}
+
+func (m *mempoolRpc) Request(method jrpc2.Method, resp interface{}) error {
+	m.requests = append(m.requests, method.Name())
+	switch method.(type) {
+	case *getTxSpendingPrevoutRequest:
+		if m.noSpendingPrevout {
+			return &jrpc2.RpcError{Code: rpcMethodNotFound, Message: "Method not found"}
+		}
+		*resp.(*[]txSpendingPrevout) = []txSpendingPrevout{{SpendingTxId: m.spendingTxId}}
+	case *getRawMempoolRequest:
+		*resp.(*[]string) = m.mempool
+	}
+	return nil
+}
+
+func (m *mempoolRpc) GetRawtransaction(txId string) (string, error) {
+	return hex.EncodeToString([]byte("raw" + txId)), nil
+}
+
+// spendingBlockchain is a SpendFinder that finds a spend of the output at
+// spendHeight.
+type spendingBlockchain struct {
+	*DummyBlockchain
+	spendHeight uint32
+	scans       [][2]uint32
+}
+
+func (s *spendingBlockchain) FindSpendingTx(txid string, vout uint32, fromHeight, toHeight uint32) (string, error) {
+	s.scans = append(s.scans, [2]uint32{fromHeight, toHeight})
+	if toHeight >= s.spendHeight {
+		return "spendinghex", nil
+	}
+	return "", nil
+}
+
 type DummyBlockchain struct {
 	sync.RWMutex
 	nextBlockheight uint64
 	nextTxOutResp   *TxOutResp
+	txOutCalls      int
+	blockHash       string
+	txBlockHeight   uint32
 }
 
 func (d *DummyBlockchain) GetBlockHeightByHash(blockhash string) (uint32, error) {
@@ -83,13 +357,42 @@ func (d *DummyBlockchain) GetBlockHeightByHash(blockhash string) (uint32, error)
 }
 
 func (d *DummyBlockchain) GetBlockHash(height uint32) (string, error) {
+	d.RLock()
+	defer d.RUnlock()
+	if d.blockHash != "" {
+		return d.blockHash, nil
+	}
 	return "blockhash", nil
 }
 
+func (d *DummyBlockchain) SetBlockHash(hash string) {
+	d.Lock()
+	defer d.Unlock()
+	d.blockHash = hash
+}
+
 func (d *DummyBlockchain) GetRawtransactionWithBlockHash(txId string, blockHash string) (string, error) {
 	return "txhex", nil
 }
 
+func (d *DummyBlockchain) GetTxBlock(txid string, fromHeight, toHeight uint32) (uint32, string, error) {
+	d.RLock()
+	defer d.RUnlock()
+	if d.txBlockHeight < fromHeight || d.txBlockHeight > toHeight {
+		return 0, "", nil
+	}
+	if d.blockHash != "" {
+		return d.txBlockHeight, d.blockHash, nil
+	}
+	return d.txBlockHeight, "blockhash", nil
+}
+
+func (d *DummyBlockchain) SetTxBlockHeight(height uint32) {
+	d.Lock()
+	defer d.Unlock()
+	d.txBlockHeight = height
+}
+
 func (d *DummyBlockchain) SetBlockHeight(height uint64) {
 	d.Lock()
 	defer d.Unlock()
@@ -109,7 +412,14 @@ func (d *DummyBlockchain) GetBlockHeight() (uint64, error) {
 }
 
 func (d *DummyBlockchain) GetTxOut(txid string, vout uint32) (*TxOutResp, error) {
+	d.Lock()
+	defer d.Unlock()
+	d.txOutCalls++
+	return d.nextTxOutResp, nil
+}
+
+func (d *DummyBlockchain) TxOutCalls() int {
 	d.RLock()
 	defer d.RUnlock()
-	return d.nextTxOutResp, nil
+	return d.txOutCalls
 }
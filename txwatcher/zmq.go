@@ -0,0 +1,272 @@
+package txwatcher
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/elementsproject/peerswap/log"
+	"github.com/lightninglabs/gozmq"
+)
+
+const (
+	zmqTopicRawBlock  = "rawblock"
+	zmqTopicHashBlock = "hashblock"
+	zmqTopicRawTx     = "rawtx"
+
+	// zmqReadTimeout is the read timeout of the zmq socket. If the socket
+	// drops it is reconnected after this timeout.
+	zmqReadTimeout = 5 * time.Second
+
+	// ZmqQuietTimeout is the duration after which the zmq subscription is
+	// considered dead if no message was received. The txwatcher falls back
+	// to polling until the next message is received.
+	ZmqQuietTimeout = 2 * time.Minute
+
+	// zmqSafetyPollInterval is the interval in which the block height is
+	// still polled while zmq is healthy.
+	zmqSafetyPollInterval = time.Minute
+)
+
+// ZmqConfig holds the zmq publisher endpoints of bitcoind or elementsd, e.g.
+// `tcp://127.0.0.1:28332`. Topics that share an endpoint are subscribed over
+// the same connection. Empty endpoints are not subscribed to.
+type ZmqConfig struct {
+	RawBlock  string
+	HashBlock string
+	RawTx     string
+}
+
+// Enabled returns true if at least one endpoint is set.
+func (c *ZmqConfig) Enabled() bool {
+	return c != nil && (c.RawBlock != "" || c.HashBlock != "" || c.RawTx != "")
+}
+
+// RawDecoder decodes the raw blocks and transactions that are published by
+// zmq.
+type RawDecoder interface {
+	// DecodeBlock returns the block hash and the txids of all transactions
+	// of a raw block.
+	DecodeBlock(rawBlock []byte) (blockHash string, txIds []string, err error)
+	// DecodeTx returns the txid of a raw transaction and the outputs that
+	// it spends.
+	DecodeTx(rawTx []byte) (txId string, spends []OutPoint, err error)
+}
+
+// zmqSubscriber subscribes to the zmq topics and keeps track of the health
+// of the subscription.
+type zmqSubscriber struct {
+	conf    *ZmqConfig
+	decoder RawDecoder
+	conns   []*gozmq.Conn
+	// rawBlocks is set if the rawblock topic is subscribed. Only then the
+	// txs of new blocks are known.
+	rawBlocks bool
+	// rawTxs is set if the rawtx topic is subscribed, so that new mempool
+	// txs are published.
+	rawTxs bool
+
+	sync.Mutex
+	lastMessage  time.Time
+	sessionStart time.Time
+}
+
+// topicsByAddr groups the configured topics by endpoint.
+func (c *ZmqConfig) topicsByAddr() map[string][]string {
+	topics := map[string][]string{}
+	if c.RawBlock != "" {
+		topics[c.RawBlock] = append(topics[c.RawBlock], zmqTopicRawBlock)
+	}
+	if c.HashBlock != "" {
+		topics[c.HashBlock] = append(topics[c.HashBlock], zmqTopicHashBlock)
+	}
+	if c.RawTx != "" {
+		topics[c.RawTx] = append(topics[c.RawTx], zmqTopicRawTx)
+	}
+	return topics
+}
+
+// received marks the subscription as healthy. A new session starts if the
+// subscription was quiet before.
+func (z *zmqSubscriber) received() {
+	z.Lock()
+	defer z.Unlock()
+	now := time.Now()
+	if z.lastMessage.IsZero() || now.Sub(z.lastMessage) > ZmqQuietTimeout {
+		z.sessionStart = now
+	}
+	z.lastMessage = now
+}
+
+// interrupted marks the subscription as broken, notifications may have been
+// missed.
+func (z *zmqSubscriber) interrupted() {
+	z.Lock()
+	defer z.Unlock()
+	z.lastMessage = time.Time{}
+}
+
+// healthy returns true if a message was received within the quiet timeout.
+func (z *zmqSubscriber) healthy() bool {
+	if z == nil {
+		return false
+	}
+	z.Lock()
+	defer z.Unlock()
+	return !z.lastMessage.IsZero() && time.Since(z.lastMessage) <= ZmqQuietTimeout
+}
+
+// trusts returns true if the subscription was healthy without interruption
+// since `t`, so that no block since `t` was missed.
+func (z *zmqSubscriber) trusts(t time.Time) bool {
+	if !z.healthy() {
+		return false
+	}
+	z.Lock()
+	defer z.Unlock()
+	return !t.Before(z.sessionStart)
+}
+
+// tracksMined returns true if the txs of new blocks are published, so that
+// watched txs are marked as mined without polling them.
+func (z *zmqSubscriber) tracksMined() bool {
+	return z != nil && z.rawBlocks
+}
+
+// tracksMempool returns true if new mempool txs are published, so that the
+// spends of watched outputs are found without polling the mempool.
+func (z *zmqSubscriber) tracksMempool() bool {
+	return z != nil && z.rawTxs
+}
+
+func (z *zmqSubscriber) close() {
+	for _, conn := range z.conns {
+		conn.Close()
+	}
+}
+
+// EnableZmq subscribes the txwatcher to the zmq notifications of the node.
+// Must be called before StartWatchingTxs. The blockchain rpc must implement
+// RawDecoder.
+func (s *BlockchainRpcTxWatcher) EnableZmq(conf *ZmqConfig) error {
+	if !conf.Enabled() {
+		return errors.New("no zmq endpoint set")
+	}
+	decoder, ok := s.blockchain.(RawDecoder)
+	if !ok {
+		return fmt.Errorf("zmq is not supported by %T", s.blockchain)
+	}
+	s.zmq = &zmqSubscriber{conf: conf, decoder: decoder, rawBlocks: conf.RawBlock != "", rawTxs: conf.RawTx != ""}
+	return nil
+}
+
+// startZmq connects to the zmq endpoints and handles the notifications
+// until the context is done.
+func (s *BlockchainRpcTxWatcher) startZmq() error {
+	for addr, topics := range s.zmq.conf.topicsByAddr() {
+		conn, err := gozmq.Subscribe(addr, topics, zmqReadTimeout)
+		if err != nil {
+			s.zmq.close()
+			return fmt.Errorf("zmq subscribe to %s: %w", addr, err)
+		}
+		s.zmq.conns = append(s.zmq.conns, conn)
+		log.Infof("[TxWatcher] subscribed to zmq %v on %s", topics, addr)
+		go s.receiveZmq(conn)
+	}
+
+	go func() {
+		<-s.ctx.Done()
+		s.zmq.close()
+	}()
+	return nil
+}
+
+func (s *BlockchainRpcTxWatcher) receiveZmq(conn *gozmq.Conn) {
+	for {
+		msg, err := conn.Receive(nil)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// Read timeouts are expected on quiet chains. Any other
+			// error means that the socket dropped and is reconnected,
+			// notifications might have been missed in between.
+			if !errors.Is(err, os.ErrDeadlineExceeded) {
+				log.Debugf("[TxWatcher] zmq receive: %v", err)
+				s.zmq.interrupted()
+			}
+			continue
+		}
+		if len(msg) < 2 {
+			continue
+		}
+		s.zmq.received()
+		s.handleZmqMessage(string(msg[0]), msg[1])
+	}
+}
+
+func (s *BlockchainRpcTxWatcher) handleZmqMessage(topic string, body []byte) {
+	switch topic {
+	case zmqTopicRawBlock:
+		blockHash, txIds, err := s.zmq.decoder.DecodeBlock(body)
+		if err != nil {
+			log.Infof("[TxWatcher] decode zmq block: %v", err)
+			return
+		}
+		height, err := s.blockchain.GetBlockHeightByHash(blockHash)
+		if err != nil {
+			log.Infof("[TxWatcher] get block height: %v", err)
+			return
+		}
+		s.markMined(txIds, height)
+		s.notifyBlock(uint64(height))
+
+	case zmqTopicHashBlock:
+		log.Tracef("[TxWatcher] zmq hashblock %s", hex.EncodeToString(body))
+		height, err := s.blockchain.GetBlockHeight()
+		if err != nil {
+			log.Infof("[TxWatcher] get block height: %v", err)
+			return
+		}
+		s.notifyBlock(height)
+
+	case zmqTopicRawTx:
+		txId, spends, err := s.zmq.decoder.DecodeTx(body)
+		if err != nil {
+			log.Tracef("[TxWatcher] decode zmq tx: %v", err)
+			return
+		}
+		s.Lock()
+		for swapId, v := range s.txWatchList {
+			if v.TxId == txId {
+				log.WithFields(log.Fields{log.SwapIdField: swapId}).
+					Debugf("[TxWatcher] watched tx %s entered the mempool", txId)
+			}
+		}
+		s.Unlock()
+		s.handleMempoolTx(body, spends)
+	}
+}
+
+// markMined sets the block height of the watched txs that are included in
+// a new block.
+func (s *BlockchainRpcTxWatcher) markMined(txIds []string, height uint32) {
+	mined := make(map[string]struct{}, len(txIds))
+	for _, txId := range txIds {
+		mined[txId] = struct{}{}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	for _, list := range []map[string]*SwapTxInfo{s.txWatchList, s.csvtxWatchList} {
+		for _, v := range list {
+			if _, ok := mined[v.TxId]; ok {
+				v.BlockHeight = height
+			}
+		}
+	}
+}
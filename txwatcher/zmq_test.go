@@ -0,0 +1,202 @@
+package txwatcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ZmqConfirmations(t *testing.T) {
+	swapId := "foo"
+	txId := "bar"
+
+	db := &zmqDummyBlockchain{
+		DummyBlockchain: &DummyBlockchain{nextBlockheight: 10},
+		minedHeight:     12,
+		blockTxIds:      []string{"other", txId},
+	}
+	txWatcherChan := make(chan string)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	txWatcher := NewBlockchainRpcTxWatcher(ctx, db, 2, 100)
+	// No endpoints, we feed the messages directly.
+	txWatcher.zmq = &zmqSubscriber{conf: &ZmqConfig{}, decoder: db, rawBlocks: true}
+	txWatcher.zmq.received()
+
+	err := txWatcher.StartWatchingTxs()
+	require.NoError(t, err)
+
+	txWatcher.AddConfirmationCallback(func(swapId string, txHex string) error {
+		go func() { txWatcherChan <- swapId }()
+		return nil
+	})
+	txWatcher.AddWaitForConfirmationTx(swapId, txId, 0, 0, nil)
+	calls := db.TxOutCalls()
+
+	// The tx is not mined, no rpc call is needed.
+	db.SetBlockHeight(11)
+	txWatcher.handleZmqMessage(zmqTopicHashBlock, []byte{0x01})
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, calls, db.TxOutCalls())
+
+	// The tx is mined in block 12 but has only 1 confirmation.
+	txWatcher.handleZmqMessage(zmqTopicRawBlock, []byte{0x01})
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, calls, db.TxOutCalls())
+
+	// With block 13 the tx has enough confirmations.
+	db.SetNextTxOutResp(&TxOutResp{Confirmations: 2})
+	db.SetBlockHeight(13)
+	txWatcher.handleZmqMessage(zmqTopicHashBlock, []byte{0x02})
+
+	select {
+	case txConfirmedId := <-txWatcherChan:
+		assert.Equal(t, swapId, txConfirmedId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tx was not confirmed")
+	}
+	assert.Equal(t, calls+1, db.TxOutCalls())
+}
+
+func Test_ZmqHashBlockOnly(t *testing.T) {
+	swapId := "foo"
+	txId := "bar"
+
+	db := &zmqDummyBlockchain{
+		DummyBlockchain: &DummyBlockchain{nextBlockheight: 10},
+	}
+	txWatcherChan := make(chan string)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	txWatcher := NewBlockchainRpcTxWatcher(ctx, db, 2, 100)
+	// Without rawblock the mined txs are unknown, they are polled.
+	txWatcher.zmq = &zmqSubscriber{conf: &ZmqConfig{}, decoder: db}
+	txWatcher.zmq.received()
+
+	err := txWatcher.StartWatchingTxs()
+	require.NoError(t, err)
+
+	txWatcher.AddConfirmationCallback(func(swapId string, txHex string) error {
+		go func() { txWatcherChan <- swapId }()
+		return nil
+	})
+	txWatcher.AddWaitForConfirmationTx(swapId, txId, 0, 0, nil)
+
+	db.SetNextTxOutResp(&TxOutResp{Confirmations: 2})
+	db.SetBlockHeight(11)
+	txWatcher.handleZmqMessage(zmqTopicHashBlock, []byte{0x01})
+
+	select {
+	case txConfirmedId := <-txWatcherChan:
+		assert.Equal(t, swapId, txConfirmedId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tx was not confirmed")
+	}
+}
+
+func Test_ZmqQuiet(t *testing.T) {
+	z := &zmqSubscriber{}
+	assert.False(t, z.healthy())
+
+	z.received()
+	assert.True(t, z.healthy())
+	assert.True(t, z.trusts(time.Now()))
+	assert.False(t, z.trusts(time.Now().Add(-time.Hour)))
+
+	// After an interruption blocks might have been missed.
+	z.interrupted()
+	assert.False(t, z.healthy())
+	addedAt := time.Now()
+	z.received()
+	assert.False(t, z.trusts(addedAt))
+
+	var disabled *zmqSubscriber
+	assert.False(t, disabled.healthy())
+}
+
+func Test_ZmqMempoolSpend(t *testing.T) {
+	db := &zmqDummyBlockchain{
+		DummyBlockchain: &DummyBlockchain{
+			nextBlockheight: 10,
+			nextTxOutResp:   &TxOutResp{Confirmations: 1},
+		},
+	}
+	txWatcher := NewBlockchainRpcTxWatcher(context.Background(), db, 2, 100)
+	txWatcher.zmq = &zmqSubscriber{conf: &ZmqConfig{}, decoder: db}
+
+	spentChan := make(chan string)
+	txWatcher.AddSpendCallback(func(swapId string, txHex string) error {
+		spentChan <- txHex
+		return nil
+	})
+	txWatcher.AddWaitForCsvTx("foo", "bar", 1, 10, nil)
+
+	// Txs that spend other outputs are ignored.
+	db.txSpends = []OutPoint{{TxId: "bar", Vout: 0}}
+	txWatcher.handleZmqMessage(zmqTopicRawTx, []byte{0x01})
+
+	db.txSpends = []OutPoint{{TxId: "other", Vout: 1}, {TxId: "bar", Vout: 1}}
+	txWatcher.handleZmqMessage(zmqTopicRawTx, []byte{0x02})
+	select {
+	case txHex := <-spentChan:
+		assert.Equal(t, "02", txHex)
+	case <-time.After(time.Second):
+		t.Fatal("spend callback was not called")
+	}
+	require.Eventually(t, func() bool {
+		txWatcher.Lock()
+		defer txWatcher.Unlock()
+		return len(txWatcher.csvtxWatchList) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_BitcoinDecodeBlock(t *testing.T) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	msgBlock := wire.NewMsgBlock(&wire.BlockHeader{Version: 1})
+	require.NoError(t, msgBlock.AddTransaction(tx))
+
+	var buf bytes.Buffer
+	require.NoError(t, msgBlock.Serialize(&buf))
+
+	b := &BitcoinBlockchainRpc{}
+	blockHash, txIds, err := b.DecodeBlock(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, msgBlock.BlockHash().String(), blockHash)
+	assert.Equal(t, []string{tx.TxHash().String()}, txIds)
+
+	buf.Reset()
+	require.NoError(t, tx.Serialize(&buf))
+	txId, spends, err := b.DecodeTx(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, tx.TxHash().String(), txId)
+	assert.Equal(t, []OutPoint{{TxId: (&chainhash.Hash{}).String(), Vout: 1}}, spends)
+}
+
+type zmqDummyBlockchain struct {
+	*DummyBlockchain
+	minedHeight uint32
+	blockTxIds  []string
+	txSpends    []OutPoint
+}
+
+func (d *zmqDummyBlockchain) GetBlockHeightByHash(blockhash string) (uint32, error) {
+	return d.minedHeight, nil
+}
+
+func (d *zmqDummyBlockchain) DecodeBlock(rawBlock []byte) (string, []string, error) {
+	return "blockhash", d.blockTxIds, nil
+}
+
+func (d *zmqDummyBlockchain) DecodeTx(rawTx []byte) (string, []OutPoint, error) {
+	return "", d.txSpends, nil
+}